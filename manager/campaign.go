@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/infra"
+	"touchgift-job-manager/injector"
+
+	"github.com/urfave/cli"
+)
+
+var campaignIDFlag = cli.IntFlag{
+	Name:  "campaign, c",
+	Usage: "campaign id (required)",
+}
+
+// campaignCommand キャンペーンの配信開始・終了・同期をサーバーを介さずに実行する
+// ex) ./manager campaign start --campaign 1
+func campaignCommand(logger *infra.Logger) cli.Command {
+	return cli.Command{
+		Name:  "campaign",
+		Usage: "start/stop/resync campaign delivery",
+		Subcommands: []cli.Command{
+			{
+				Name:  "start",
+				Usage: "run delivery start for a warmup campaign",
+				Flags: []cli.Flag{campaignIDFlag},
+				Action: campaignAction(logger, func(ctx context.Context, campaignID int) error {
+					// 開始処理はwarmupのキャンペーンのみ処理される
+					return injector.InjectDeliveryStartUsecase(logger).Execute(ctx, &models.Campaign{ID: campaignID})
+				}),
+			},
+			{
+				Name:  "stop",
+				Usage: "stop delivery of a started campaign",
+				Flags: []cli.Flag{campaignIDFlag},
+				Action: campaignAction(logger, func(ctx context.Context, campaignID int) error {
+					// 停止処理はstartedのキャンペーンのみ処理される
+					return injector.InjectDeliveryEndUsecase(logger).StopStarted(ctx, campaignID)
+				}),
+			},
+			{
+				Name:  "resync",
+				Usage: "rebuild delivery data from the current campaign in RDB",
+				Flags: []cli.Flag{campaignIDFlag},
+				Action: campaignAction(logger, func(ctx context.Context, campaignID int) error {
+					err := injector.InjectAdminCampaignUsecase(logger).Sync(ctx, campaignID)
+					if err == codes.ErrDoNothing {
+						// 同期対象外のステータス
+						logger.Info().Int("campaign_id", campaignID).Msg("Campaign is not a target of resync")
+						return nil
+					}
+					return err
+				}),
+			},
+		},
+	}
+}
+
+func campaignAction(logger *infra.Logger, fn func(ctx context.Context, campaignID int) error) func(c *cli.Context) error {
+	return func(c *cli.Context) error {
+		campaignID := c.Int("campaign")
+		if campaignID == 0 {
+			return cli.NewExitError("--campaign is required", 1)
+		}
+		ctx, cancel := SignalContext(context.Background(), logger)
+		defer cancel()
+		if err := fn(ctx, campaignID); err != nil {
+			return cli.NewExitError(err.Error(), 1)
+		}
+		logger.Info().Int("campaign_id", campaignID).Str("command", c.Command.Name).Msg("Campaign command finished")
+		return nil
+	}
+}
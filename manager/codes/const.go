@@ -11,6 +11,12 @@ const TypeDeliveryControl = "delivery_control"
 const DetailShortage = "shortage"
 const DetailExpended = "expended"
 
+// 配信上限数(ユーザー/日, 店舗/日, クーポン合計)に達した場合の配信制御ログのイベント
+const DetailCapReached = "cap_reached"
+
+// 配信時間帯(dayparting)の境界で一時停止/再開した場合の配信制御イベントの詳細
+const DetailDaypart = "daypart"
+
 const StatusStart = "start"
 const StatusStarted = "started"
 const StatusWarmup = "warmup"
@@ -24,3 +30,91 @@ const StatusSuspend = "suspend"
 const StatusConfigured = "configured"
 const StatusStop = "stop"
 const StatusTerminate = "terminate"
+
+// 配信開始前の検証でキャンペーンの配信を開始できなかったイベント (ステータスはwarmupのまま)
+const EventWarmupFailed = "warmup_failed"
+
+// 入稿物(クーポン等)の審査ステータス
+const ReviewStatusApproved = "2" // 審査OK(公開中)
+
+// 審査ステータスを持つ入稿物の種別
+const AssetKindCreative = "creative"
+const AssetKindCoupon = "coupon"
+const AssetKindGimmick = "gimmick"
+
+// リーダー選出(リース)のバックエンド
+const LeaseBackendMySQL = "mysql"
+const LeaseBackendDynamoDB = "dynamodb"
+
+// SQSメッセージの重複排除の状態
+const MessageDedupNew = "new"                // 未処理 (処理中として登録した)
+const MessageDedupInProgress = "in_progress" // 他のタスクが処理中
+const MessageDedupDone = "done"              // 処理済み
+
+// SQSメッセージの重複排除のバックエンド
+const MessageDedupBackendMySQL = "mysql"
+const MessageDedupBackendDynamoDB = "dynamodb"
+
+// DeadLetterの保存先
+const DeadLetterSinkFile = "file"
+const DeadLetterSinkSQS = "sqs"
+
+// キャッシュイベントの通知先
+const NotificationSinkSNS = "sns"
+const NotificationSinkWebhook = "webhook"
+const NotificationSinkFile = "file" // ローカル実行用
+
+// キューのバックエンド
+const QueueBackendSQS = "sqs"
+const QueueBackendMemory = "memory" // ローカル実行・テスト用 (プロセス内のメモリ)
+const QueueBackendFile = "file"     // ローカル実行用 (ディレクトリに置いたファイルを1メッセージとして受信する)
+
+// 予約(Timer)の処理種別
+const ReservationActionStart = "start"
+const ReservationActionEnd = "end"
+
+// 配信データのplan modeで確認する処理
+const PlanActionStart = "start"
+const PlanActionEnd = "end"
+
+// plan modeで記録するDynamoDBのテーブルと操作
+const PlanTableCampaign = "campaign"
+const PlanTableContent = "content"
+const PlanTableCreative = "creative"
+const PlanTableTouchPoint = "touch_point"
+const PlanOperationPut = "put"
+const PlanOperationDelete = "delete"
+const PlanOperationUpdateTTL = "update_ttl"
+
+// 配信データの差分(リコンサイル)の種類
+const DriftCampaignMissing = "campaign_missing"
+const DriftCampaignMismatch = "campaign_mismatch"
+const DriftContentMissing = "content_missing"
+const DriftContentMismatch = "content_mismatch"
+const DriftCreativeMissing = "creative_missing"
+const DriftTouchPointMissing = "touch_point_missing"
+const DriftOrphanCampaign = "orphan_campaign"
+const DriftOrphanTouchPoint = "orphan_touch_point"
+const DriftOrphanCreative = "orphan_creative"
+
+// 配信制御イベント(outbox)の種別
+const OutboxEventCampaign = "campaign"
+const OutboxEventCreative = "creative"
+const OutboxEventDelivery = "delivery"
+
+// DeliveryCacheLogのメッセージのバージョン
+const DeliveryCacheLogVersionSingle = 1 // 1メッセージ1件 (DeliveryCacheLog)
+const DeliveryCacheLogVersionBatch = 2  // 1メッセージ複数件 (DeliveryCacheBatchLog)
+
+// 配信データ(content)のアイテムの形式のバージョン
+const DeliveryContentVersionSingleGimmick = 1 // ギミックは1件のみ (gimmicks)
+const DeliveryContentVersionGimmickList = 2   // 全てのギミック (gimmick_list)
+
+// 店舗/タッチポイントのCSVアップロード履歴の種別
+const UploadKindStore = "store"
+const UploadKindTouchPoint = "touch_point"
+
+// アップロード履歴の反映状態
+const UploadStatusInProgress = "in_progress"
+const UploadStatusDone = "done"
+const UploadStatusFailed = "failed"
@@ -15,3 +15,9 @@ var ErrConditionFailed = errors.New("conditional check failed")
 
 // ErrDoNothing　is do nothing
 var ErrDoNothing = errors.New("do nothing")
+
+// ErrInvalidCampaign is error when campaign contents are invalid for delivery
+var ErrInvalidCampaign = errors.New("invalid campaign")
+
+// ErrInvalidStatus is error when campaign status does not allow the operation
+var ErrInvalidStatus = errors.New("invalid campaign status")
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"fmt"
 	"log"
 	"os"
 	"time"
@@ -30,6 +31,7 @@ type Db struct {
 	MaxOpenConns    int           `envconfig:"DB_MAX_OPEN_CONNS" default:"5"`
 	MaxIdleConns    int           `envconfig:"DB_MAX_IDLE_CONNS" default:"5"`
 	ConnMaxLifetime time.Duration `envconfig:"DB_CONN_MAX_LIFETIME" default:"1h"`
+	Location        string        `envconfig:"DB_LOCATION" default:"UTC"` // DATETIME/TIMESTAMPの読み書きに使用するタイムゾーン (DBのtime_zoneと合わせる)
 }
 
 type SNS struct {
@@ -39,16 +41,50 @@ type SNS struct {
 	CreativeCacheTopicArn string `envconfig:"SNS_CREATIVE_CACHE_TOPIC_ARN" default:"arn:aws:sns:ap-northeast-1:000000000000:touchgift-server-creative-cache-local"` // デフォルトはローカル用
 }
 
+// Notification キャッシュイベントの通知先
+// Sinks/Routesにはsns, webhook, fileを指定でき、カンマ区切りで複数指定すると全てに送信する
+type Notification struct {
+	Sinks          string        `envconfig:"NOTIFICATION_SINKS" default:"sns"`                     // Routesに一致しないトピックの通知先
+	Routes         string        `envconfig:"NOTIFICATION_ROUTES" default:""`                       // トピック(ARNまたはトピック名)ごとの通知先 ex) touchgift-server-delivery-cache=sns,webhook;touchgift-server-creative-cache=webhook
+	WebhookURLs    string        `envconfig:"NOTIFICATION_WEBHOOK_URLS" default:""`                 // カンマ区切りで複数指定すると全てに送信する
+	WebhookSecret  string        `envconfig:"NOTIFICATION_WEBHOOK_SECRET" default:""`               // HMAC-SHA256の署名に使用する (空の場合は署名しない)
+	WebhookTimeout time.Duration `envconfig:"NOTIFICATION_WEBHOOK_TIMEOUT" default:"5s"`            // 1リクエストのタイムアウト
+	FilePath       string        `envconfig:"NOTIFICATION_FILE_PATH" default:"notification.ndjson"` // fileの場合の出力先 (NDJSON)
+}
+
+// DeliveryEventBatch タッチポイントのキャッシュイベント(DeliveryCacheLog)を複数件まとめて1メッセージにする
+type DeliveryEventBatch struct {
+	Enabled       bool          `envconfig:"DELIVERY_EVENT_BATCH_ENABLED" default:"false"`     // falseの場合は1件ずつ送信する (version 1の形式)。受信側がversion 2に対応してから有効にする
+	MaxBytes      int           `envconfig:"DELIVERY_EVENT_BATCH_MAX_BYTES" default:"240000"`  // 1メッセージの最大サイズ (SNSの上限256KBからMessageAttributes分を引いた値より小さくする)
+	MaxRecords    int           `envconfig:"DELIVERY_EVENT_BATCH_MAX_RECORDS" default:"1000"`  // 1メッセージの最大件数
+	FlushInterval time.Duration `envconfig:"DELIVERY_EVENT_BATCH_FLUSH_INTERVAL" default:"1s"` // 最初のイベントを追加してからこの時間が経過したら送信する
+}
+
+// SNSの1メッセージの上限 (job_outbox.message(mediumtext)の上限より小さい)
+const snsMaxMessageBytes = 256 * 1024
+
+// Validate 1メッセージの最大サイズがSNSとoutboxに登録できるサイズか検証する
+func (d *DeliveryEventBatch) Validate() error {
+	if d.MaxBytes <= 0 || d.MaxBytes >= snsMaxMessageBytes {
+		return fmt.Errorf("DELIVERY_EVENT_BATCH_MAX_BYTES must be between 1 and %d: %d", snsMaxMessageBytes-1, d.MaxBytes)
+	}
+	return nil
+}
+
 type DeliveryStart struct {
 	TaskInterval       time.Duration `envconfig:"DELIVERY_START_TASK_INTERVAL" default:"1m"`
 	TaskLimit          int           `envconfig:"DELIVERY_START_WORKER_TASK_LIMIT" default:"10"` // 1回のSQLで取得する数
 	NumberOfConcurrent int           `envconfig:"DELIVERY_START_WORKER_NUMBER_OF_CONCURRENT" default:"5"`
 	NumberOfQueue      int           `envconfig:"DELIVERY_START_WORKER_NUMBER_OF_QUEUE" default:"5"`
+	// 開始時間のこの時間前にwarmupにしてキャンペーン以外の配信データを事前作成する (0の場合は事前作成しない)
+	PrewarmDuration time.Duration `envconfig:"DELIVERY_START_PREWARM_DURATION" default:"0s"`
 }
 
 type DeliveryStartUsecase struct {
 	NumberOfConcurrent int `envconfig:"DELIVERY_START_USECASE_WORKER_NUMBER_OF_CONCURRENT" default:"5"`
 	NumberOfQueue      int `envconfig:"DELIVERY_START_USECASE_WORKER_NUMBER_OF_QUEUE" default:"5"`
+	// クーポン/クリエイティブの配信割合の合計 (合計が一致しないキャンペーンは配信を開始しない)
+	RateTotal int `envconfig:"DELIVERY_START_RATE_TOTAL" default:"100"`
 }
 
 type DeliveryEnd struct {
@@ -64,15 +100,24 @@ type DeliveryEndUsecase struct {
 }
 
 type DynamoDB struct {
-	EndPoint            string `envconfig:"DYNAMODB_ENDPOINT" default:"http://localhost:4566"` // デフォルトはローカル用
-	TableNamePrefix     string `envconfig:"TABLE_NAME_PREFIX" default:""`                      // デフォルトはローカル/CI用
-	CampaignTableName   string `envconfig:"CAMPAIGN_TABLE_NAME" default:"touchgift_campaign_data"`
-	CreativeTableName   string `envconfig:"CREATIVE_TABLE_NAME" default:"touchgift_creative_data"`
-	TouchPointTableName string `envconfig:"TOUCH_POINT_TABLE_NAME" default:"touchgift_delivery_data"`
-	ContentTableName    string `envconfig:"CONTENT_TABLE_NAME" default:"touchgift_content_data"`
+	EndPoint              string `envconfig:"DYNAMODB_ENDPOINT" default:"http://localhost:4566"` // デフォルトはローカル用
+	TableNamePrefix       string `envconfig:"TABLE_NAME_PREFIX" default:""`                      // デフォルトはローカル/CI用
+	CampaignTableName     string `envconfig:"CAMPAIGN_TABLE_NAME" default:"touchgift_campaign_data"`
+	CreativeTableName     string `envconfig:"CREATIVE_TABLE_NAME" default:"touchgift_creative_data"`
+	TouchPointTableName   string `envconfig:"TOUCH_POINT_TABLE_NAME" default:"touchgift_delivery_data"`
+	ContentTableName      string `envconfig:"CONTENT_TABLE_NAME" default:"touchgift_content_data"`
+	LeaseTableName        string `envconfig:"LEASE_TABLE_NAME" default:"touchgift_job_lease"`
+	MessageDedupTableName string `envconfig:"MESSAGE_DEDUP_TABLE_NAME" default:"touchgift_job_message_dedup"`
+	// BatchWriteItem
+	BatchWriteParallelism       int           `envconfig:"DYNAMODB_BATCH_WRITE_PARALLELISM" default:"4"`            // 25件単位のチャンクを同時に書き込む数
+	BatchWriteMaxRetry          int           `envconfig:"DYNAMODB_BATCH_WRITE_MAX_RETRY" default:"8"`              // UnprocessedItemsの最大再送回数
+	BatchWriteRetryBaseInterval time.Duration `envconfig:"DYNAMODB_BATCH_WRITE_RETRY_BASE_INTERVAL" default:"50ms"` // UnprocessedItemsの再送間隔(指数バックオフの初期値)
+	BatchWriteRetryMaxInterval  time.Duration `envconfig:"DYNAMODB_BATCH_WRITE_RETRY_MAX_INTERVAL" default:"5s"`    // UnprocessedItemsの再送間隔の上限
 }
 
 type SQS struct {
+	Backend                   string `envconfig:"SQS_BACKEND" default:"sqs"`           // sqs or memory or file (memory/fileはローカル実行用)
+	SpoolDir                  string `envconfig:"SQS_SPOOL_DIR" default:"queue_spool"` // backendがfileの場合にメッセージのファイルを置くディレクトリ (キューごとにサブディレクトリを作成する)
 	Region                    string `envconfig:"AWS_REGION" default:"us-east-1"`
 	EndPoint                  string `envconfig:"SQS_ENDPOINT" default:"http://localhost:4566"` // デフォルトはローカル用
 	DeliveryOperationQueueURL string `envconfig:"SQS_DELIVERY_OPERATION_QUEUE_URL" default:"http://localhost:4566/000000000000/touchgift-delivery-operation"`
@@ -80,6 +125,84 @@ type SQS struct {
 	VisibilityTimeoutSeconds  int64  `envconfig:"SQS_VISIBILITY_TIMEOUT_SECONDS" default:"60"` // 取得したメッセージを処理する時間(これを過ぎると別のアプリがメッセージを取得してしまう)
 	WaitTimeSeconds           int64  `envconfig:"SQS_WAIT_TIME_SECONDS" default:"20"`          // SQSからメッセージを取得する待ち時間
 	MaxMessages               int64  `envconfig:"SQS_MAX_MESSAGES" default:"10"`               // 一度に取得するメッセージ数
+	// 処理中のメッセージの可視性タイムアウトを定期的に延長し、処理が長引いた場合に別のアプリが取得しないようにする
+	VisibilityHeartbeatEnabled     bool          `envconfig:"SQS_VISIBILITY_HEARTBEAT_ENABLED" default:"true"`
+	VisibilityHeartbeatInterval    time.Duration `envconfig:"SQS_VISIBILITY_HEARTBEAT_INTERVAL" default:"20s"`    // 延長する間隔 (VisibilityTimeoutSecondsより短くすること)
+	VisibilityHeartbeatMaxDuration time.Duration `envconfig:"SQS_VISIBILITY_HEARTBEAT_MAX_DURATION" default:"1h"` // 延長を続ける最大時間 (SQSの上限は12時間)
+	WorkerPoolSize                 int           `envconfig:"SQS_WORKER_POOL_SIZE" default:"4"`                   // メッセージを並列に処理するワーカー数 (同じキャンペーンのメッセージは順番に処理する)
+	WorkerDrainTimeout             time.Duration `envconfig:"SQS_WORKER_DRAIN_TIMEOUT" default:"30s"`             // 終了時に処理中のメッセージの完了を待つ最大時間
+}
+
+type LeaderElection struct {
+	Enabled        bool          `envconfig:"LEADER_ELECTION_ENABLED" default:"false"`                    // falseの場合は常にリーダーとして動作する(1タスク構成用)
+	Backend        string        `envconfig:"LEADER_ELECTION_BACKEND" default:"mysql"`                    // mysql or dynamodb
+	LeaseName      string        `envconfig:"LEADER_ELECTION_LEASE_NAME" default:"touchgift-job-manager"` // リース(ロック)名
+	LeaseDuration  time.Duration `envconfig:"LEADER_ELECTION_LEASE_DURATION" default:"30s"`               // リースの有効期間(dynamodbのみ)
+	RetryInterval  time.Duration `envconfig:"LEADER_ELECTION_RETRY_INTERVAL" default:"5s"`                // リースの取得/延長を行う間隔
+	RenewMargin    time.Duration `envconfig:"LEADER_ELECTION_RENEW_MARGIN" default:"2s"`                  // リースの期限切れより前にリーダーを辞める余裕(時刻のずれ・処理の遅れ)
+	ReleaseTimeout time.Duration `envconfig:"LEADER_ELECTION_RELEASE_TIMEOUT" default:"5s"`               // 終了時にリースを解放する際のタイムアウト
+}
+
+type Timer struct {
+	PersistentReservation bool `envconfig:"TIMER_PERSISTENT_RESERVATION" default:"true"` // 開始/終了の予約をRDBに保存して再起動後に読み込み直す
+}
+
+type Reconcile struct {
+	Enabled      bool          `envconfig:"RECONCILE_ENABLED" default:"false"`
+	TaskInterval time.Duration `envconfig:"RECONCILE_TASK_INTERVAL" default:"10m"`
+	Repair       bool          `envconfig:"RECONCILE_REPAIR" default:"false"` // trueの場合は差分を修復する(配信データの再作成/不要データの削除)
+}
+
+type TouchPointSync struct {
+	Enabled          bool          `envconfig:"TOUCH_POINT_SYNC_ENABLED" default:"true"`
+	TaskInterval     time.Duration `envconfig:"TOUCH_POINT_SYNC_TASK_INTERVAL" default:"1m"`
+	Lookback         time.Duration `envconfig:"TOUCH_POINT_SYNC_LOOKBACK" default:"1m"`           // 前回実行時刻からさかのぼって変更を検出する時間 (コミットの遅延を考慮する)
+	FullSyncInterval time.Duration `envconfig:"TOUCH_POINT_SYNC_FULL_SYNC_INTERVAL" default:"1h"` // 全ての配信中の店舗グループを比較する間隔 (物理削除は変更を検出できないため)
+}
+
+// Timezone ログ・配信制御イベントの日時の表示と、配信時間帯の判定に使用するタイムゾーン
+type Timezone struct {
+	Default string            `envconfig:"DISPLAY_TIMEZONE" default:"Asia/Tokyo"`
+	ByOrg   map[string]string `envconfig:"DISPLAY_TIMEZONE_BY_ORG"` // 組織コード:タイムゾーン (例: org1:Asia/Tokyo,org2:America/New_York)
+}
+
+type Daypart struct {
+	Enabled      bool          `envconfig:"DAYPART_ENABLED" default:"true"`
+	TaskInterval time.Duration `envconfig:"DAYPART_TASK_INTERVAL" default:"1m"`
+}
+
+type UploadHistory struct {
+	Enabled      bool          `envconfig:"UPLOAD_HISTORY_ENABLED" default:"true"`
+	TaskInterval time.Duration `envconfig:"UPLOAD_HISTORY_TASK_INTERVAL" default:"30s"`
+	Lookback     time.Duration `envconfig:"UPLOAD_HISTORY_LOOKBACK" default:"24h"`   // この期間より前のアップロード履歴は対象にしない
+	BatchSize    int           `envconfig:"UPLOAD_HISTORY_BATCH_SIZE" default:"10"`  // 1回の処理で反映する履歴の数(種別ごと)
+	MaxAttempts  int           `envconfig:"UPLOAD_HISTORY_MAX_ATTEMPTS" default:"5"` // 反映に失敗した履歴を再試行する回数
+}
+
+type Outbox struct {
+	RelayInterval     time.Duration `envconfig:"OUTBOX_RELAY_INTERVAL" default:"1s"`
+	RelayBatchSize    int           `envconfig:"OUTBOX_RELAY_BATCH_SIZE" default:"100"`    // 1回のSQLで取得する数
+	RetryBaseInterval time.Duration `envconfig:"OUTBOX_RETRY_BASE_INTERVAL" default:"1s"`  // 送信失敗時の再送間隔(失敗ごとに倍にする)
+	RetryMaxInterval  time.Duration `envconfig:"OUTBOX_RETRY_MAX_INTERVAL" default:"5m"`   // 再送間隔の上限
+	MaxAttempts       int           `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"10"`         // この回数失敗したイベントは送信失敗にする
+	LeaseDuration     time.Duration `envconfig:"OUTBOX_LEASE_DURATION" default:"30s"`      // 取得したイベントを他のタスクが送信しない時間
+	Retention         time.Duration `envconfig:"OUTBOX_DELIVERED_RETENTION" default:"72h"` // 送信済みイベントの保持期間
+}
+
+type MessageDedup struct {
+	Enabled           bool          `envconfig:"MESSAGE_DEDUP_ENABLED" default:"true"`
+	Backend           string        `envconfig:"MESSAGE_DEDUP_BACKEND" default:"mysql"`           // mysql or dynamodb
+	TTL               time.Duration `envconfig:"MESSAGE_DEDUP_TTL" default:"24h"`                 // 処理済みのメッセージを重複とみなす期間
+	ProcessingTimeout time.Duration `envconfig:"MESSAGE_DEDUP_PROCESSING_TIMEOUT" default:"5m"`   // 処理中のまま残った登録を無効とみなすまでの時間(タスク停止時など)
+	CleanupInterval   time.Duration `envconfig:"MESSAGE_DEDUP_CLEANUP_INTERVAL" default:"10m"`    // 期限切れの登録を削除する間隔(mysqlのみ)
+	CleanupBatchSize  int           `envconfig:"MESSAGE_DEDUP_CLEANUP_BATCH_SIZE" default:"1000"` // 1回で削除する数
+}
+
+type DeadLetter struct {
+	Sink            string `envconfig:"DEAD_LETTER_SINK" default:"file"`                                                              // file or sqs
+	FilePath        string `envconfig:"DEAD_LETTER_FILE_PATH" default:"dead_letter.ndjson"`                                           // sinkがfileの場合の出力先
+	QueueURL        string `envconfig:"DEAD_LETTER_QUEUE_URL" default:"http://localhost:4566/000000000000/touchgift-job-dead-letter"` // sinkがsqsの場合の送信先
+	MaxReceiveCount int    `envconfig:"DEAD_LETTER_MAX_RECEIVE_COUNT" default:"5"`                                                    // 処理に失敗したメッセージをDeadLetterに移すまでの受信回数
 }
 
 var Env = EnvConfig{}
@@ -93,11 +216,23 @@ type EnvConfig struct {
 	DeliveryStartUsecase
 	DeliveryEnd
 	DeliveryEndUsecase
+	LeaderElection
+	Timer
+	Reconcile
+	TouchPointSync
+	Timezone
+	Daypart
+	UploadHistory
+	Outbox
+	MessageDedup
+	DeadLetter
 	Server
 	SQS
 	Db
 	DynamoDB
 	SNS
+	Notification
+	DeliveryEventBatch
 }
 
 func init() {
@@ -105,6 +240,9 @@ func init() {
 	if err != nil {
 		log.Fatalf("Fail to load env config : %v", err)
 	}
+	if err := Env.DeliveryEventBatch.Validate(); err != nil {
+		log.Fatalf("Invalid env config : %v", err)
+	}
 	if len(Env.AwsProfile) > 0 {
 		os.Setenv("AWS_PROFILE", Env.AwsProfile)
 	}
@@ -21,6 +21,8 @@ func TestEnvConfig(t *testing.T) {
 		os.Setenv("DB_MAX_OPEN_CONNS", "10")
 		os.Setenv("DB_MAX_IDLE_CONNS", "10")
 		os.Setenv("DB_CONN_MAX_LIFETIME", "30m")
+		os.Setenv("DB_LOCATION", "Asia/Tokyo")
+		os.Setenv("DISPLAY_TIMEZONE_BY_ORG", "org1:Asia/Tokyo,org2:America/New_York")
 
 		// 環境変数の読み込み
 		var env EnvConfig
@@ -38,6 +40,8 @@ func TestEnvConfig(t *testing.T) {
 		assert.Equal(t, 10, env.Db.MaxOpenConns)
 		assert.Equal(t, 10, env.Db.MaxIdleConns)
 		assert.Equal(t, time.Duration(30)*time.Minute, env.Db.ConnMaxLifetime)
+		assert.Equal(t, "Asia/Tokyo", env.Db.Location)
+		assert.Equal(t, map[string]string{"org1": "Asia/Tokyo", "org2": "America/New_York"}, env.Timezone.ByOrg)
 
 		// 環境変数のクリーンアップ
 		os.Unsetenv("DB_DRIVER_NAME")
@@ -50,6 +54,8 @@ func TestEnvConfig(t *testing.T) {
 		os.Unsetenv("DB_MAX_OPEN_CONNS")
 		os.Unsetenv("DB_MAX_IDLE_CONNS")
 		os.Unsetenv("DB_CONN_MAX_LIFETIME")
+		os.Unsetenv("DB_LOCATION")
+		os.Unsetenv("DISPLAY_TIMEZONE_BY_ORG")
 	})
 	t.Run("環境変数を設定しなければdefaultの値を返すことを確認する", func(t *testing.T) {
 		var env EnvConfig
@@ -66,7 +72,30 @@ func TestEnvConfig(t *testing.T) {
 		assert.Equal(t, 5, env.Db.MaxOpenConns)
 		assert.Equal(t, 5, env.Db.MaxIdleConns)
 		assert.Equal(t, time.Duration(60)*time.Minute, env.Db.ConnMaxLifetime)
+		assert.Equal(t, "UTC", env.Db.Location)
+		assert.Equal(t, "Asia/Tokyo", env.Timezone.Default)
+		// 受信側がversion 2の形式に対応するまでは1件ずつ送信する
+		assert.False(t, env.DeliveryEventBatch.Enabled)
 
 	})
 
 }
+
+func TestDeliveryEventBatch_Validate(t *testing.T) {
+	t.Run("デフォルトの最大サイズはSNSとoutboxに登録できるサイズ", func(t *testing.T) {
+		var env EnvConfig
+		err := envconfig.Process("", &env)
+		assert.Nil(t, err)
+		assert.NoError(t, env.DeliveryEventBatch.Validate())
+	})
+
+	t.Run("最大サイズがSNSの上限以上の場合はエラーを返す", func(t *testing.T) {
+		config := DeliveryEventBatch{MaxBytes: 256 * 1024}
+		assert.EqualError(t, config.Validate(), "DELIVERY_EVENT_BATCH_MAX_BYTES must be between 1 and 262143: 262144")
+	})
+
+	t.Run("最大サイズが0以下の場合はエラーを返す", func(t *testing.T) {
+		config := DeliveryEventBatch{MaxBytes: 0}
+		assert.Error(t, config.Validate())
+	})
+}
@@ -4,33 +4,48 @@ import (
 	"database/sql"
 	"strconv"
 	"time"
+	"touchgift-job-manager/codes"
 )
 
 // Campaign RDBから取得した配信開始・終了に必要なデータ
 type Campaign struct {
-	ID                      int          `db:"id" json:"id"`
-	StartAt                 time.Time    `db:"start_at" json:"start_at"`
-	EndAt                   sql.NullTime `db:"end_at" json:"end_at"`
-	UpdatedAt               time.Time    `db:"updated_at"`
-	GroupID                 int          `db:"group_id" json:"group_id"`
-	OrgCode                 string       `db:"org_code" json:"org_code"`
-	DailyCouponLimitPerUser int          `db:"daily_coupon_limit_per_user" json:"daily_coupon_limit_per_user"`
-	Status                  string       `db:"status" json:"status"`
+	ID                       int          `db:"id" json:"id"`
+	StartAt                  time.Time    `db:"start_at" json:"start_at"`
+	EndAt                    sql.NullTime `db:"end_at" json:"end_at"`
+	UpdatedAt                time.Time    `db:"updated_at"`
+	GroupID                  int          `db:"group_id" json:"group_id"`
+	OrgCode                  string       `db:"org_code" json:"org_code"`
+	DailyCouponLimitPerUser  int          `db:"daily_coupon_limit_per_user" json:"daily_coupon_limit_per_user"`
+	DailyCouponLimitPerStore int          `db:"daily_coupon_limit_per_store" json:"daily_coupon_limit_per_store"` // 0は上限なし
+	Status                   string       `db:"status" json:"status"`
 }
 
 func (c *Campaign) CreateDeliveryDataCampaign(cc []*CampaignCreative) *DeliveryDataCampaign {
 	return &DeliveryDataCampaign{
-		ID:         strconv.Itoa(c.ID),
-		GroupID:    strconv.Itoa(c.GroupID),
-		OrgCode:    c.OrgCode,
-		DailyLimit: c.DailyCouponLimitPerUser,
-		Creatives:  cc,
-		Status:     c.Status,
+		ID:              strconv.Itoa(c.ID),
+		GroupID:         strconv.Itoa(c.GroupID),
+		OrgCode:         c.OrgCode,
+		DailyLimit:      c.DailyCouponLimitPerUser,
+		DailyStoreLimit: c.DailyCouponLimitPerStore,
+		Creatives:       cc,
+		Status:          c.Status,
 	}
 }
 
 type CampaignCreative struct {
-	ID         int `db:"id" json:"id"`
-	Rate       int `db:"rate" json:"rate"`
-	SkipOffset int `db:"skip_offset" json:"skip_offset"`
+	ID         int    `db:"id" json:"id"`
+	Rate       int    `db:"rate" json:"rate"`
+	SkipOffset int    `db:"skip_offset" json:"skip_offset"`
+	Status     string `db:"status" json:"-"` // クリエイティブの審査ステータス (配信データには含めない)
+}
+
+// ApprovedCampaignCreatives 審査OKのクリエイティブの配信設定のみを返す
+func ApprovedCampaignCreatives(cc []*CampaignCreative) []*CampaignCreative {
+	approved := make([]*CampaignCreative, 0, len(cc))
+	for _, creative := range cc {
+		if creative.Status == codes.ReviewStatusApproved {
+			approved = append(approved, creative)
+		}
+	}
+	return approved
 }
@@ -0,0 +1,22 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"touchgift-job-manager/codes"
+)
+
+// CampaignValidationError 配信開始前の検証で配信内容に不備があったキャンペーン
+type CampaignValidationError struct {
+	CampaignID int
+	Reasons    []string
+}
+
+func (e *CampaignValidationError) Error() string {
+	return fmt.Sprintf("invalid campaign. id: %d, reasons: %s", e.CampaignID, strings.Join(e.Reasons, ", "))
+}
+
+// Unwrap errors.Is(err, codes.ErrInvalidCampaign) で判定できるようにする
+func (e *CampaignValidationError) Unwrap() error {
+	return codes.ErrInvalidCampaign
+}
@@ -1,33 +1,50 @@
 package models
 
-import "strconv"
+import (
+	"strconv"
+	"touchgift-job-manager/codes"
+
+	"github.com/pkg/errors"
+)
 
 type Coupon struct {
-	ID       int    `db:"coupon_id" json:"id"`
-	Name     string `db:"coupon_name" json:"name"`
-	Code     string `db:"coupon_code" json:"code"`
-	ImageURL string `db:"coupon_image_url" json:"image_url"`
-	Rate     string `db:"coupon_rate" json:"rate"`
+	ID         int    `db:"coupon_id" json:"id"`
+	Name       string `db:"coupon_name" json:"name"`
+	Code       string `db:"coupon_code" json:"code"`
+	ImageURL   string `db:"coupon_image_url" json:"image_url"`
+	Rate       string `db:"coupon_rate" json:"rate"`
+	TotalLimit int    `db:"coupon_total_limit" json:"total_limit"` // 配信上限数 (合計, 0は上限なし)
+	Status     string `db:"coupon_status" json:"-"`                // 審査ステータス
 }
 
-func (c *Coupon) CreateDeliveryCouponData() *DeliveryCouponData {
-	rate, _ := strconv.Atoi(c.Rate)
-	return &DeliveryCouponData{
-		ID:       c.ID,
-		Name:     c.Name,
-		Code:     c.Code,
-		ImageURL: c.ImageURL,
-		Rate:     rate,
+// ApprovedCoupons 審査OKのクーポンのみを返す
+func ApprovedCoupons(coupons []*Coupon) []*Coupon {
+	approved := make([]*Coupon, 0, len(coupons))
+	for _, coupon := range coupons {
+		if coupon.Status == codes.ReviewStatusApproved {
+			approved = append(approved, coupon)
+		}
+	}
+	return approved
+}
+
+func (c *Coupon) CreateDeliveryCouponData() (*DeliveryCouponData, error) {
+	rate, err := strconv.Atoi(c.Rate)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid coupon rate. coupon_id: %d, rate: %s", c.ID, c.Rate)
 	}
+	return &DeliveryCouponData{
+		ID:         c.ID,
+		Name:       c.Name,
+		Code:       c.Code,
+		ImageURL:   c.ImageURL,
+		Rate:       rate,
+		TotalLimit: c.TotalLimit,
+	}, nil
 }
 
 type Gimmick struct {
+	ID   int     `db:"gimmick_id" json:"id,omitempty"`
 	URL  *string `db:"gimmick_url" json:"gimmick_url,omitempty"`
 	Code *string `db:"gimmick_code" json:"gimmick_code,omitempty"`
 }
-
-type Content struct {
-	CampaignID string    `db:"campaign_id" json:"campaign_id"`
-	Coupons    []Coupon  `json:"coupons"`
-	Gimmicks   []Gimmick `json:"gimmicks"`
-}
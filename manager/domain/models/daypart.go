@@ -0,0 +1,74 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Daypart キャンペーンの配信時間帯 (campaign_daypart)
+// 終了時刻が開始時刻以前の場合は日付をまたぐ時間帯 (例: 22:00〜02:00)
+type Daypart struct {
+	CampaignID int           `db:"campaign_id" json:"campaign_id"`
+	DayOfWeek  sql.NullInt32 `db:"day_of_week" json:"-"`         // 0:日曜日 〜 6:土曜日 (NULLの場合は毎日)
+	StartTime  string        `db:"start_time" json:"start_time"` // HH:MM:SS
+	EndTime    string        `db:"end_time" json:"end_time"`     // HH:MM:SS
+}
+
+// Contains 指定した日時が配信時間帯に含まれるか
+func (d *Daypart) Contains(t time.Time) (bool, error) {
+	start, err := parseTimeOfDay(d.StartTime)
+	if err != nil {
+		return false, errors.Wrapf(err, "Invalid start_time. campaign_id: %d", d.CampaignID)
+	}
+	end, err := parseTimeOfDay(d.EndTime)
+	if err != nil {
+		return false, errors.Wrapf(err, "Invalid end_time. campaign_id: %d", d.CampaignID)
+	}
+	seconds := t.Hour()*60*60 + t.Minute()*60 + t.Second()
+	weekday := t.Weekday()
+	if start < end {
+		return d.onDay(weekday) && start <= seconds && seconds < end, nil
+	}
+	// 日付をまたぐ場合は当日の開始時刻以降と、前日から続く終了時刻より前
+	yesterday := (weekday + 6) % 7
+	return (d.onDay(weekday) && start <= seconds) || (d.onDay(yesterday) && seconds < end), nil
+}
+
+func (d *Daypart) onDay(weekday time.Weekday) bool {
+	return !d.DayOfWeek.Valid || time.Weekday(d.DayOfWeek.Int32) == weekday
+}
+
+// InDayparts 指定した日時がいずれかの配信時間帯に含まれるか (配信時間帯がない場合は常に配信する)
+func InDayparts(dayparts []*Daypart, t time.Time) (bool, error) {
+	if len(dayparts) == 0 {
+		return true, nil
+	}
+	for _, daypart := range dayparts {
+		ok, err := daypart.Contains(t)
+		if err != nil {
+			return false, err
+		}
+		if ok {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// HH:MM:SS を0時からの秒数にする
+func parseTimeOfDay(value string) (int, error) {
+	t, err := time.Parse("15:04:05", value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60*60 + t.Minute()*60 + t.Second(), nil
+}
+
+// DaypartResult 配信時間帯による一時停止/再開の結果
+type DaypartResult struct {
+	Paused  int `json:"paused"`
+	Resumed int `json:"resumed"`
+	Failed  int `json:"failed"`
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// DeadLetter 処理できなかったキューのメッセージ
+// 再処理(replay)できるように受信した本文をそのまま保持する
+type DeadLetter struct {
+	QueueURL  string    `json:"queue_url"`
+	MessageID string    `json:"message_id"`
+	Body      string    `json:"body"`              // SQSから受信した本文 (SNSメッセージ)
+	Message   string    `json:"message,omitempty"` // SNSメッセージのMessage (パースできた場合のみ)
+	Error     string    `json:"error"`
+	Attempts  int       `json:"attempts"` // 受信回数 (ApproximateReceiveCount)
+	FailedAt  time.Time `json:"failed_at"`
+}
+
+// ReplayResult DeadLetterの再処理結果
+type ReplayResult struct {
+	Total     int  `json:"total"`
+	Processed int  `json:"processed"`
+	Skipped   int  `json:"skipped"` // 再処理の対象外 (他のキューのメッセージなど)
+	Failed    int  `json:"failed"`
+	DryRun    bool `json:"dry_run"`
+}
@@ -1,30 +1,44 @@
 package models
 
-import "strconv"
+import (
+	"strconv"
+	"touchgift-job-manager/codes"
+)
 
 // Dynamoに入れるデータ構造体はここに定義していく
 
 type DeliveryDataCampaign struct {
-	ID         string              `json:"id"`
-	GroupID    string              `json:"group_id"`
-	OrgCode    string              `json:"org_code"`
-	DailyLimit int                 `json:"daily_limit"`
-	Creatives  []*CampaignCreative `json:"creatives,omitempty"`
-	Status     string              `json:"status"`
+	ID         string `json:"id"`
+	GroupID    string `json:"group_id"`
+	OrgCode    string `json:"org_code"`
+	DailyLimit int    `json:"daily_limit"` // 同一ユーザーへのクーポン配信上限数 / 日
+	// 同一店舗でのクーポン配信上限数 / 日 (0は上限なし)
+	DailyStoreLimit int                 `json:"daily_store_limit,omitempty"`
+	Creatives       []*CampaignCreative `json:"creatives,omitempty"`
+	Status          string              `json:"status"`
 }
 
 func (d *DeliveryDataCampaign) CreateCampaign() *Campaign {
 	ID, _ := strconv.Atoi(d.ID)
 	groupID, _ := strconv.Atoi(d.GroupID)
 	return &Campaign{
-		ID:                      ID,
-		GroupID:                 groupID,
-		OrgCode:                 d.OrgCode,
-		DailyCouponLimitPerUser: d.DailyLimit,
-		Status:                  d.Status,
+		ID:                       ID,
+		GroupID:                  groupID,
+		OrgCode:                  d.OrgCode,
+		DailyCouponLimitPerUser:  d.DailyLimit,
+		DailyCouponLimitPerStore: d.DailyStoreLimit,
+		Status:                   d.Status,
 	}
 }
 
+// DeliveryDataSet キャンペーンの配信に必要なDynamoDBのデータ一式
+type DeliveryDataSet struct {
+	Campaign    *DeliveryDataCampaign   `json:"campaign"`
+	Content     *DeliveryDataContent    `json:"content"`
+	Creatives   []*DeliveryDataCreative `json:"creatives"`
+	TouchPoints []*DeliveryTouchPoint   `json:"touch_points"`
+}
+
 type DeliveryTouchPoint struct {
 	GroupID int    `json:"group_id"`
 	StoreID string `json:"store_id"`
@@ -51,15 +65,48 @@ type DeliveryDataCreative struct {
 }
 
 type DeliveryDataContent struct {
-	CampaignID string               `json:"campaign_id"`
-	Coupons    []DeliveryCouponData `json:"coupons"`
-	Gimmicks   Gimmick              `json:"gimmicks"`
+	CampaignID string `json:"campaign_id"`
+	// アイテムの形式のバージョン (codes.DeliveryContentVersion*、version 1のアイテムには設定されていない)
+	Version int                  `json:"version,omitempty"`
+	Coupons []DeliveryCouponData `json:"coupons"`
+	// 最初にURL/コードが設定されているギミックのURL/コード (version 1の形式。配信サーバーの移行後に削除する)
+	Gimmicks Gimmick `json:"gimmicks"`
+	// キャンペーンに紐づく全てのギミック (version 2)
+	GimmickList []Gimmick `json:"gimmick_list,omitempty"`
+}
+
+// NewDeliveryDataContent クーポン一覧とギミック一覧から配信用のコンテンツを作成する
+func NewDeliveryDataContent(campaignID int, coupons []*Coupon, gimmicks []*Gimmick) (*DeliveryDataContent, error) {
+	content := &DeliveryDataContent{
+		CampaignID:  strconv.Itoa(campaignID),
+		Version:     codes.DeliveryContentVersionGimmickList,
+		Coupons:     make([]DeliveryCouponData, 0, len(coupons)),
+		GimmickList: make([]Gimmick, 0, len(gimmicks)),
+	}
+	for _, coupon := range coupons {
+		deliveryCouponData, err := coupon.CreateDeliveryCouponData()
+		if err != nil {
+			return nil, err
+		}
+		content.Coupons = append(content.Coupons, *deliveryCouponData)
+	}
+	for _, gimmick := range gimmicks {
+		content.GimmickList = append(content.GimmickList, *gimmick)
+		if content.Gimmicks.URL == nil && gimmick.URL != nil {
+			content.Gimmicks.URL = gimmick.URL
+		}
+		if content.Gimmicks.Code == nil && gimmick.Code != nil {
+			content.Gimmicks.Code = gimmick.Code
+		}
+	}
+	return content, nil
 }
 
 type DeliveryCouponData struct {
-	ID       int    `json:"id"`
-	Name     string `json:"name"`
-	Code     string `json:"code"`
-	ImageURL string `json:"image_url"`
-	Rate     int    `json:"rate"`
+	ID         int    `json:"id"`
+	Name       string `json:"name"`
+	Code       string `json:"code"`
+	ImageURL   string `json:"image_url"`
+	Rate       int    `json:"rate"`
+	TotalLimit int    `json:"total_limit,omitempty"` // 配信上限数 (合計, 0は上限なし)
 }
@@ -38,3 +38,26 @@ type DeliveryCacheLog struct {
 	OrgCode    string `json:"org_code"`
 	CampaignID int    `json:"campaign_id"`
 }
+
+// DeliveryCacheBatchLog
+// 複数のDeliveryCacheLogをまとめたメッセージ (version 2)
+// 1メッセージに含まれるレコードのactionは全て同じ
+type DeliveryCacheBatchLog struct {
+	Version int                `json:"version"`
+	Action  string             `json:"action"` // PUT or DELETE
+	Records []DeliveryCacheLog `json:"records"`
+}
+
+// DeliveryControlLog
+// 予算管理・配信から送られる配信制御ログ (expended: 予算消化, shortage: 予算不足, cap_reached: 配信上限到達)
+type DeliveryControlLog struct {
+	TraceID    string `json:"trace_id"`
+	Time       string `json:"time"`
+	Version    int    `json:"version"`
+	Event      string `json:"event"`
+	Source     string `json:"source"`
+	OrgCode    string `json:"org_code"`
+	CampaignID int    `json:"campaign_id"`
+	Cap        string `json:"cap,omitempty"`       // 到達した配信上限 (cap_reachedの場合のみ)
+	CouponID   int    `json:"coupon_id,omitempty"` // 上限に達したクーポン (クーポン合計の上限の場合のみ)
+}
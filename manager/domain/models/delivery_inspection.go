@@ -0,0 +1,29 @@
+package models
+
+// DeliveryInspection 管理API用のキャンペーン配信状態
+// RDBのキャンペーンとDynamoDBの配信データを並べて返す
+type DeliveryInspection struct {
+	Campaign             *Campaign               `json:"campaign"`
+	DeliveryCampaign     *DeliveryDataCampaign   `json:"delivery_campaign"`
+	DeliveryContent      *DeliveryDataContent    `json:"delivery_content"`
+	DeliveryTouchPoints  []*DeliveryTouchPoint   `json:"delivery_touch_points"`
+	DeliveryCreatives    []*DeliveryDataCreative `json:"delivery_creatives"`
+	MissingTouchPointIDs []string                `json:"missing_touch_point_ids"` // RDBにあるがDynamoDBにないタッチポイント
+	MissingCreativeIDs   []int                   `json:"missing_creative_ids"`    // RDBにあるがDynamoDBにないクリエイティブ
+}
+
+// GroupInspection 店舗グループのタッチポイントの配信状態
+type GroupInspection struct {
+	GroupID              int                   `json:"group_id"`
+	DeliveryTouchPoints  []*DeliveryTouchPoint `json:"delivery_touch_points"`
+	MissingTouchPointIDs []string              `json:"missing_touch_point_ids"` // RDBにあるがDynamoDBにないタッチポイント
+}
+
+// CreativePurgeResult どのキャンペーンにも紐付かないクリエイティブ配信データの削除結果
+type CreativePurgeResult struct {
+	DryRun  bool     `json:"dry_run"`
+	Scanned int      `json:"scanned"`
+	Orphans []string `json:"orphans"` // 削除対象のクリエイティブID
+	Purged  int      `json:"purged"`
+	Failed  int      `json:"failed"`
+}
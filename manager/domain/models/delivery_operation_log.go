@@ -19,6 +19,15 @@ type CreativeLog struct {
 	Event   string `json:"event"`
 }
 
+// AssetLog
+// 入稿物(クリエイティブ/クーポン/ギミック)の審査ステータス変更ログ
+type AssetLog struct {
+	Kind    string `json:"kind"`
+	ID      int    `json:"id"`
+	OrgCode string `json:"org_code"`
+	Status  string `json:"status"`
+}
+
 // DeliveryOperationLog
 // 同期対象配信データログ
 type DeliveryOperationLog struct {
@@ -26,4 +35,5 @@ type DeliveryOperationLog struct {
 	Type         string        `json:"type"`
 	RequestID    string        `json:"request_id"`
 	CampaignLogs []CampaignLog `json:"campaigns,omitempty"`
+	AssetLogs    []AssetLog    `json:"assets,omitempty"`
 }
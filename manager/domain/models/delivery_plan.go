@@ -0,0 +1,29 @@
+package models
+
+import "encoding/json"
+
+// DeliveryPlan 配信開始/終了処理で登録・削除する配信データと発行する配信制御イベント
+// DynamoDBへの登録とSNSへの送信はせず、キャンペーンのステータスの更新はロールバックする (plan mode)
+type DeliveryPlan struct {
+	Action      string                 `json:"action"` // start, end
+	CampaignID  int                    `json:"campaign_id"`
+	Status      string                 `json:"status"`       // RDBのキャンペーンのステータス
+	AfterStatus string                 `json:"after_status"` // 処理後のキャンペーンのステータス
+	Items       []*DeliveryPlanItem    `json:"items"`
+	Messages    []*DeliveryPlanMessage `json:"messages"`
+}
+
+// DeliveryPlanItem DynamoDBに登録・削除するアイテム
+type DeliveryPlanItem struct {
+	Table     string      `json:"table"`     // campaign, content, creative, touch_point
+	Operation string      `json:"operation"` // put, delete, update_ttl
+	Item      interface{} `json:"item"`      // 削除の場合はキー
+}
+
+// DeliveryPlanMessage SNSに送信するメッセージ (outboxに登録する内容)
+type DeliveryPlanMessage struct {
+	EventType  string            `json:"event_type"`
+	TopicArn   string            `json:"topic_arn"`
+	Attributes map[string]string `json:"attributes"`
+	Message    json.RawMessage   `json:"message"`
+}
@@ -0,0 +1,43 @@
+package models
+
+import (
+	"database/sql"
+	"encoding/json"
+	"time"
+)
+
+// OutboxEvent 送信待ちの配信制御イベント (RDBの更新と同じトランザクションで登録する)
+type OutboxEvent struct {
+	ID             int64          `db:"id" json:"id"`
+	EventType      string         `db:"event_type" json:"event_type"` // campaign, creative, delivery
+	TopicArn       string         `db:"topic_arn" json:"topic_arn"`
+	Message        string         `db:"message" json:"message"`
+	Attributes     string         `db:"attributes" json:"attributes"` // SNSのMessageAttributes(JSON)
+	Attempts       int            `db:"attempts" json:"attempts"`
+	NextAttemptAt  time.Time      `db:"next_attempt_at" json:"next_attempt_at"`
+	LockedUntil    sql.NullTime   `db:"locked_until" json:"-"` // 送信中のタスクのLease期限
+	LastError      sql.NullString `db:"last_error" json:"-"`
+	CreatedAt      time.Time      `db:"created_at" json:"created_at"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at" json:"-"`
+	FailedAt       sql.NullTime   `db:"failed_at" json:"-"`        // 最大送信回数を超えて送信しないことにした日時
+	HeldCampaignID sql.NullInt64  `db:"held_campaign_id" json:"-"` // 配信開始まで送信を保留するキャンペーンのID
+}
+
+// MessageAttributes SNSのMessageAttributesを返す
+func (o *OutboxEvent) MessageAttributes() (map[string]string, error) {
+	attributes := map[string]string{}
+	if o.Attributes == "" {
+		return attributes, nil
+	}
+	if err := json.Unmarshal([]byte(o.Attributes), &attributes); err != nil {
+		return nil, err
+	}
+	return attributes, nil
+}
+
+// OutboxBacklog 送信待ちイベントの状況
+type OutboxBacklog struct {
+	Count           int          `db:"count"`
+	OldestCreatedAt sql.NullTime `db:"oldest_created_at"`
+	Failed          int          `db:"failed"` // 送信失敗にしたイベントの件数
+}
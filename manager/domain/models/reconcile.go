@@ -0,0 +1,15 @@
+package models
+
+// ReconcileDrift RDBとDynamoDBの配信データの差分
+type ReconcileDrift struct {
+	CampaignID int    `json:"campaign_id"`
+	Kind       string `json:"kind"`
+	Detail     string `json:"detail,omitempty"`
+	Repaired   bool   `json:"repaired"`
+}
+
+// ReconcileResult リコンサイルの結果
+type ReconcileResult struct {
+	Checked int               `json:"checked"` // 確認した配信中のキャンペーン数
+	Drifts  []*ReconcileDrift `json:"drifts"`
+}
@@ -0,0 +1,11 @@
+package models
+
+import "time"
+
+// Reservation 開始/終了処理の予約
+type Reservation struct {
+	CampaignID int       `db:"campaign_id" json:"campaign_id"`
+	Action     string    `db:"action" json:"action"`
+	FireAt     time.Time `db:"fire_at" json:"fire_at"`
+	CreatedAt  time.Time `db:"created_at" json:"created_at"`
+}
@@ -5,3 +5,10 @@ type TouchPoint struct {
 	StoreID string `db:"store_id" json:"store_id"`
 	ID      string `db:"id" json:"id"`
 }
+
+// TouchPointSyncResult タッチポイント差分同期の結果
+type TouchPointSyncResult struct {
+	Groups  int `json:"groups"`  // 同期した店舗グループ数
+	Put     int `json:"put"`     // 登録/更新したタッチポイント数
+	Deleted int `json:"deleted"` // 削除したタッチポイント数
+}
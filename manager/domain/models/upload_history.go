@@ -0,0 +1,33 @@
+package models
+
+import (
+	"database/sql"
+	"time"
+)
+
+// UploadHistory 店舗/タッチポイントのCSVアップロード履歴 (store_update_history, touch_point_update_history)
+type UploadHistory struct {
+	Kind      string    `db:"kind" json:"kind"` // store, touch_point
+	ID        int       `db:"id" json:"id"`
+	Xid       string    `db:"xid" json:"xid"`
+	OrgCode   string    `db:"org_code" json:"org_code"`
+	FileName  string    `db:"file_name" json:"file_name"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+	Attempts  int       `db:"attempts" json:"attempts"` // これまでに反映を試みた回数
+}
+
+// UploadProcess アップロード履歴ごとの配信データへの反映状況
+type UploadProcess struct {
+	Kind        string         `db:"kind" json:"kind"`
+	HistoryID   int            `db:"history_id" json:"history_id"`
+	Xid         string         `db:"xid" json:"xid"`
+	OrgCode     string         `db:"organization_code" json:"org_code"`
+	Status      string         `db:"status" json:"status"` // in_progress, done, failed
+	StoreGroups int            `db:"store_groups" json:"store_groups"`
+	PutCount    int            `db:"put_count" json:"put_count"`
+	DeleteCount int            `db:"deleted_count" json:"deleted_count"`
+	Attempts    int            `db:"attempts" json:"attempts"`
+	LastError   sql.NullString `db:"last_error" json:"-"`
+	CreatedAt   time.Time      `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time      `db:"updated_at" json:"updated_at"`
+}
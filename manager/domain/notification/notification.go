@@ -1,8 +1,30 @@
 //go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
 package notification
 
-import "context"
+import (
+	"context"
+	"strconv"
+)
 
 type NotificationHandler interface {
 	Publish(ctx context.Context, message string, messageAttributes map[string]string, topicArn string) (*string, error)
 }
+
+type messageIDKey struct{}
+
+// WithMessageID 送信先で重複を判定するためのMessageIdを指定する
+// 同じイベントを再送した場合も同じMessageIdで送信される (SNSは自身でMessageIdを採番するため使用しない)
+func WithMessageID(ctx context.Context, messageID string) context.Context {
+	return context.WithValue(ctx, messageIDKey{}, messageID)
+}
+
+// MessageIDFromContext WithMessageIDで指定したMessageIdを返す
+func MessageIDFromContext(ctx context.Context) (string, bool) {
+	messageID, ok := ctx.Value(messageIDKey{}).(string)
+	return messageID, ok && messageID != ""
+}
+
+// OutboxMessageID outboxのイベントのMessageId
+func OutboxMessageID(outboxID int64) string {
+	return "outbox-" + strconv.FormatInt(outboxID, 10)
+}
@@ -27,6 +27,16 @@ type CampaignDataToEndCondition struct {
 	Limit  int
 }
 
+type CampaignByStatusCondition struct {
+	Status []string
+}
+
+type CampaignByAssetCondition struct {
+	Kind    string // codes.AssetKind*
+	AssetID int
+	Status  []string // 空の場合はステータスで絞り込まない
+}
+
 type CampaignCondition struct {
 	CampaignID int
 	Status     string
@@ -45,4 +55,12 @@ type CampaignRepository interface {
 	GetCampaignCreative(ctx context.Context, tx Transaction, args *CampaignCondition) ([]*models.CampaignCreative, error)
 	// groupIDに紐づく配信中のキャンペーン数を取得する
 	GetDeliveryCampaignCountByGroupID(ctx context.Context, groupID int) (int, error)
+	// 指定したステータスのキャンペーン情報を取得する
+	GetCampaignByStatus(ctx context.Context, args *CampaignByStatusCondition) ([]*models.Campaign, error)
+	// 入稿物(クリエイティブ/クーポン/ギミック)が紐づく、指定したステータスのキャンペーン情報を取得する
+	GetCampaignByAsset(ctx context.Context, args *CampaignByAssetCondition) ([]*models.Campaign, error)
+	// 予算消化(budgetExpended=true)・予算不足・配信上限到達で停止するキャンペーン情報をロックして取得する (対象がない場合はErrNoData)
+	GetCampaignToExpendedOrShortage(ctx context.Context, tx Transaction, campaignID int, budgetExpended bool) (*models.Campaign, error)
+	// GetStatusForUpdate キャンペーンをロックして現在のステータスを取得する (対象がない場合はErrNoData)
+	GetStatusForUpdate(ctx context.Context, tx Transaction, campaignID int) (string, error)
 }
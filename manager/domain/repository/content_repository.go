@@ -10,19 +10,9 @@ type ContentByCampaignIDCondition struct {
 	CampaignID int
 }
 
-type GenerateContentCondition struct {
-	Coupons    []*models.Coupon
-	GimmickURL *string
-}
-
 type ContentRepository interface {
-	// GetCouponsByCampaignID  キャンペーンIDからクーポンデータを取得する
+	// GetCouponsByCampaignID  キャンペーンIDからクーポンデータを取得する (審査OKでないクーポンも含む)
 	GetCouponsByCampaignID(ctx context.Context, tx Transaction, args *ContentByCampaignIDCondition) ([]*models.Coupon, error)
-	// GetGimmickURLByCampaignID キャンペーンIDからギミックURLを取得する
-	GetGimmicksByCampaignID(ctx context.Context, tx Transaction, campaignID *ContentByCampaignIDCondition) (*string, *string, error)
-}
-
-// ContentsHelper gimmick_urlとクーポン一覧からコンテンツを作成する
-type ContentHelper interface {
-	GenerateContent(ctx context.Context, args *GenerateContentCondition) ([]*models.Content, error)
+	// GetGimmicksByCampaignID キャンペーンIDからギミック一覧を取得する (URL/コードが未設定の場合はnil)
+	GetGimmicksByCampaignID(ctx context.Context, tx Transaction, args *ContentByCampaignIDCondition) ([]*models.Gimmick, error)
 }
@@ -0,0 +1,19 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/domain/models"
+)
+
+type DaypartRepository interface {
+	// GetByCampaignIDs キャンペーンの配信時間帯を取得する
+	GetByCampaignIDs(ctx context.Context, campaignIDs []int) ([]*models.Daypart, error)
+	// GetPausedCampaignIDs 配信時間帯外のため一時停止したキャンペーンIDを取得する
+	GetPausedCampaignIDs(ctx context.Context) ([]int, error)
+	// SavePause 配信時間帯外のため一時停止したことを記録する (txがnilの場合は単独で登録する)
+	SavePause(ctx context.Context, tx Transaction, campaignID int, pausedAt time.Time) error
+	// DeletePause 配信時間帯外による一時停止の記録を削除する (txがnilの場合は単独で削除する)
+	DeletePause(ctx context.Context, tx Transaction, campaignID int) error
+}
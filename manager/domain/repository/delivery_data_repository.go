@@ -17,6 +17,8 @@ type CreativeCondition struct {
 type DeliveryDataCampaignRepository interface {
 	// 取得する
 	Get(ctx context.Context, id *string) (*models.DeliveryDataCampaign, error)
+	// 全件取得する
+	GetAll(ctx context.Context) ([]*models.DeliveryDataCampaign, error)
 	//	登録/更新する
 	Put(ctx context.Context, updateData *models.DeliveryDataCampaign) error
 	// まとめて登録更新する
@@ -30,6 +32,10 @@ type DeliveryDataCampaignRepository interface {
 type DeliveryDataTouchPointRepository interface {
 	// 取得する
 	Get(ctx context.Context, id *string, groupID *string) (*models.DeliveryTouchPoint, error)
+	// まとめて取得する (存在しないものは含まない)
+	BatchGet(ctx context.Context, keys *[]models.DeliveryTouchPoint) ([]*models.DeliveryTouchPoint, error)
+	// 全件取得する
+	GetAll(ctx context.Context) ([]*models.DeliveryTouchPoint, error)
 	//	登録/更新する
 	Put(ctx context.Context, updateData *models.DeliveryTouchPoint) error
 	// まとめて登録更新する
@@ -45,6 +51,10 @@ type DeliveryDataTouchPointRepository interface {
 type DeliveryDataCreativeRepository interface {
 	// 取得する
 	Get(ctx context.Context, id *string) (*models.DeliveryDataCreative, error)
+	// まとめて取得する (存在しないものは含まない)
+	BatchGet(ctx context.Context, ids []string) ([]*models.DeliveryDataCreative, error)
+	// 全件取得する
+	GetAll(ctx context.Context) ([]*models.DeliveryDataCreative, error)
 	//	登録/更新する
 	Put(ctx context.Context, updateData *models.DeliveryDataCreative) error
 	// まとめて登録更新する
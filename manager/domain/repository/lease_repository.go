@@ -0,0 +1,17 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// LeaseRepository リーダー選出用のリースを操作する
+type LeaseRepository interface {
+	// Acquire リースを取得する (他のownerが保持している場合はfalse)
+	Acquire(ctx context.Context, name string, owner string, duration time.Duration) (bool, error)
+	// Renew 保持しているリースを延長する (リースを失っている場合はfalse)
+	Renew(ctx context.Context, name string, owner string, duration time.Duration) (bool, error)
+	// Release 保持しているリースを解放する
+	Release(ctx context.Context, name string, owner string) error
+}
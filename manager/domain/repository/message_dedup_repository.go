@@ -0,0 +1,20 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+// MessageDedupRepository SQSメッセージの重複排除用に処理状態を保存する
+type MessageDedupRepository interface {
+	// Claim 未登録または期限切れの場合は処理中として登録してcodes.MessageDedupNewを返す
+	// 登録済みの場合は現在の状態(codes.MessageDedupInProgress, codes.MessageDedupDone)を返す
+	Claim(ctx context.Context, key string, now time.Time, expiresAt time.Time) (string, error)
+	// Complete 処理済みにする
+	Complete(ctx context.Context, key string, expiresAt time.Time) error
+	// Release 登録を削除して再処理できるようにする
+	Release(ctx context.Context, key string) error
+	// DeleteExpired 期限切れの登録を削除する (TTLで削除されるバックエンドの場合は何もしない)
+	DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error)
+}
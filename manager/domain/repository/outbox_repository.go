@@ -0,0 +1,35 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/domain/models"
+)
+
+type OutboxRepository interface {
+	// Save イベントを登録する (txがnilの場合は単独で登録する。HeldCampaignIDを指定した場合は送信を保留する)
+	Save(ctx context.Context, tx Transaction, event *models.OutboxEvent) error
+	// ReleaseHeld 配信開始まで保留したキャンペーンのイベントを送信待ちにし、件数を返す (txがnilの場合は単独で更新する)
+	ReleaseHeld(ctx context.Context, tx Transaction, campaignID int, nextAttemptAt time.Time) (int64, error)
+	// DeleteHeld 配信開始まで保留したキャンペーンのイベントを削除し、件数を返す (txがnilの場合は単独で削除する)
+	DeleteHeld(ctx context.Context, tx Transaction, campaignID int) (int64, error)
+	// GetPending 送信待ちのイベントを登録順にロックして取得する (他のタスクがロック中の行は読み飛ばす)
+	GetPending(ctx context.Context, tx Transaction, limit int) ([]*models.OutboxEvent, error)
+	// GetOldestPendingID 送信待ちのイベントで最も古いIDを取得する (ロックしない)
+	GetOldestPendingID(ctx context.Context, tx Transaction) (int64, error)
+	// Lease 送信中のイベントをlockedUntilまで他のタスクが送信しないようにする
+	Lease(ctx context.Context, tx Transaction, ids []int64, lockedUntil time.Time) error
+	// Release 送信しなかったイベントのLeaseを解除する
+	Release(ctx context.Context, ids []int64) error
+	// MarkDelivered 送信済みにする
+	MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error
+	// MarkFailed 送信失敗を記録して次回の送信日時を設定する
+	MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error
+	// MarkDead 最大送信回数を超えたイベントを送信失敗にする (以降は送信しない)
+	MarkDead(ctx context.Context, id int64, failedAt time.Time, lastError string) error
+	// GetBacklog 送信待ちイベントの件数と最も古い登録日時、送信失敗にしたイベントの件数を取得する
+	GetBacklog(ctx context.Context) (*models.OutboxBacklog, error)
+	// DeleteDelivered 送信済みのイベントを削除する
+	DeleteDelivered(ctx context.Context, before time.Time, limit int) (int64, error)
+}
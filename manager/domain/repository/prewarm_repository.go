@@ -0,0 +1,14 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+)
+
+type PrewarmRepository interface {
+	// Save 配信開始前に配信データを作成したことを記録する
+	Save(ctx context.Context, tx Transaction, campaignID int, prewarmedAt time.Time) error
+	// Delete 配信データを事前作成した記録を削除し、削除した件数を返す (txがnilの場合は単独で削除する)
+	Delete(ctx context.Context, tx Transaction, campaignID int) (int, error)
+}
@@ -0,0 +1,16 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
+package repository
+
+import (
+	"context"
+	"touchgift-job-manager/domain/models"
+)
+
+type ReservationRepository interface {
+	// Save 予約を登録する (同じキャンペーン・処理種別の予約は上書きする)
+	Save(ctx context.Context, reservation *models.Reservation) error
+	// Delete 予約を削除する
+	Delete(ctx context.Context, campaignID int, action string) error
+	// GetAll 未実行の予約を実行時間順に取得する
+	GetAll(ctx context.Context) ([]*models.Reservation, error)
+}
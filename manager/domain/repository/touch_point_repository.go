@@ -3,6 +3,7 @@ package repository
 
 import (
 	"context"
+	"time"
 	"touchgift-job-manager/domain/models"
 )
 
@@ -14,4 +15,6 @@ type TouchPointByGroupIDCondition struct {
 type TouchPointRepository interface {
 	// GetTouchPointByGroupID グループIDからタッチポイントデータを取得する
 	GetTouchPointByGroupID(ctx context.Context, args *TouchPointByGroupIDCondition) ([]*models.TouchPoint, error)
+	// GetChangedGroupIDs since以降に店舗グループ・店舗・タッチポイントが更新された(CSVアップロードを含む)グループIDを取得する (物理削除は検出できない)
+	GetChangedGroupIDs(ctx context.Context, since time.Time) ([]int, error)
 }
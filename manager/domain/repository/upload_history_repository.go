@@ -0,0 +1,22 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../../mock/$GOPACKAGE/$GOFILE
+package repository
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/domain/models"
+)
+
+type UnprocessedUploadCondition struct {
+	Kind        string    // store, touch_point
+	Since       time.Time // この日時より後にアップロードされた履歴を対象にする
+	MaxAttempts int       // 反映に失敗した履歴はこの回数まで再試行する
+	Limit       int
+}
+
+type UploadHistoryRepository interface {
+	// GetUnprocessed 未反映(反映に失敗したものを含む)のアップロード履歴を古い順に取得する
+	GetUnprocessed(ctx context.Context, args *UnprocessedUploadCondition) ([]*models.UploadHistory, error)
+	// SaveProcess 反映状況を登録/更新する
+	SaveProcess(ctx context.Context, process *models.UploadProcess) error
+}
@@ -0,0 +1,54 @@
+package main
+
+import (
+	"context"
+	"touchgift-job-manager/infra"
+	"touchgift-job-manager/injector"
+
+	"github.com/urfave/cli"
+)
+
+// dumpCommand キャンペーンまたは店舗グループの配信データをJSONで出力する
+// ex) ./manager dump --campaign 1
+// ex) ./manager dump --group 1
+func dumpCommand(logger *infra.Logger) cli.Command {
+	return cli.Command{
+		Name:  "dump",
+		Usage: "print delivery data of a campaign or a store group",
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "campaign, c",
+				Usage: "campaign id",
+			},
+			cli.IntFlag{
+				Name:  "group, g",
+				Usage: "store group id",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			campaignID := c.Int("campaign")
+			groupID := c.Int("group")
+			if (campaignID == 0) == (groupID == 0) {
+				return cli.NewExitError("either --campaign or --group is required", 1)
+			}
+
+			ctx, cancel := SignalContext(context.Background(), logger)
+			defer cancel()
+			adminCampaign := injector.InjectAdminCampaignUsecase(logger)
+			var result interface{}
+			var err error
+			if campaignID != 0 {
+				result, err = adminCampaign.Inspect(ctx, campaignID)
+			} else {
+				result, err = adminCampaign.InspectGroup(ctx, groupID)
+			}
+			if err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			if err := printJSON(result); err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			return nil
+		},
+	}
+}
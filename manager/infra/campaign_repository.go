@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"time"
+	"touchgift-job-manager/codes"
 	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/domain/repository"
 )
@@ -28,7 +29,9 @@ func (c *CampaignRepository) GetCampaignToStart(ctx context.Context, args *repos
     sg.id as group_id,
     c.organization_code as org_code,
 		IFNULL(c.daily_coupon_limit_per_user, 0) as daily_coupon_limit_per_user,
+		IFNULL(c.daily_coupon_limit_per_store, 0) as daily_coupon_limit_per_store,
     c.status as status,
+    c.start_at as start_at,
 	c.updated_at as updated_at
 FROM campaign c
 INNER JOIN store_group sg ON c.store_group_id = sg.id
@@ -46,7 +49,7 @@ WHERE
 	}()
 	var campaigns []*models.Campaign
 	err = stmt.SelectContext(ctx, &campaigns, map[string]interface{}{
-		"to":     args.To.Format("2006-01-02 15:04:05"),
+		"to":     args.To.UTC(),
 		"status": args.Status,
 	})
 	if err != nil {
@@ -63,14 +66,16 @@ func (c *CampaignRepository) GetCampaignToEnd(ctx context.Context, args *reposit
     c.store_group_id as group_id,
     c.organization_code as org_code,
     IFNULL(c.daily_coupon_limit_per_user, 0) as daily_coupon_limit_per_user,
+    IFNULL(c.daily_coupon_limit_per_store, 0) as daily_coupon_limit_per_store,
     c.status as status,
+    c.end_at as end_at,
 		c.updated_at as updated_at
 FROM campaign c
 WHERE
     c.end_at < :end AND
 		c.status IN (:status)`
 	params := map[string]interface{}{
-		"end":    args.End.Format("2006-01-02 15:04:05"),
+		"end":    args.End.UTC(),
 		"status": args.Status,
 	}
 	_query, _params, err := c.sqlHandler.In(query, params)
@@ -100,7 +105,8 @@ func (c *CampaignRepository) GetDeliveryToStart(ctx context.Context,
 		sg.id as group_id,
 		c.status as status,
 		c.organization_code as org_code,
-		IFNULL(c.daily_coupon_limit_per_user, 0) as daily_coupon_limit_per_user
+		IFNULL(c.daily_coupon_limit_per_user, 0) as daily_coupon_limit_per_user,
+		IFNULL(c.daily_coupon_limit_per_store, 0) as daily_coupon_limit_per_store
 	FROM campaign c
 	INNER JOIN store_group sg ON c.store_group_id = sg.id
 	WHERE
@@ -125,6 +131,156 @@ func (c *CampaignRepository) GetDeliveryToStart(ctx context.Context,
 	return &campaign, nil
 }
 
+// 指定したステータスのキャンペーン情報を取得する
+func (c *CampaignRepository) GetCampaignByStatus(ctx context.Context, args *repository.CampaignByStatusCondition) ([]*models.Campaign, error) {
+	query := `SELECT
+    c.id as id,
+    c.store_group_id as group_id,
+    c.organization_code as org_code,
+    IFNULL(c.daily_coupon_limit_per_user, 0) as daily_coupon_limit_per_user,
+    IFNULL(c.daily_coupon_limit_per_store, 0) as daily_coupon_limit_per_store,
+    c.status as status,
+    c.start_at as start_at,
+    c.end_at as end_at,
+		c.updated_at as updated_at
+FROM campaign c
+WHERE
+		c.status IN (:status)
+ORDER BY c.id`
+	params := map[string]interface{}{
+		"status": args.Status,
+	}
+	_query, _params, err := c.sqlHandler.In(query, params)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := c.sqlHandler.PrepareContext(ctx, *_query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err = stmt.Close(); err != nil {
+			c.logger.Error().Err(err).Msg("Failed to close statement")
+		}
+	}()
+	dest := []*models.Campaign{}
+	err = stmt.SelectContext(ctx, &dest, _params...)
+	return dest, err
+}
+
+// 入稿物の種別ごとのキャンペーンとの紐付けテーブルとカラム
+var campaignAssetTables = map[string][2]string{
+	codes.AssetKindCreative: {"campaign_creative", "creative_id"},
+	codes.AssetKindCoupon:   {"campaign_coupon", "coupon_id"},
+	codes.AssetKindGimmick:  {"campaign_gimmick", "gimmick_id"},
+}
+
+func (c *CampaignRepository) GetCampaignByAsset(ctx context.Context, args *repository.CampaignByAssetCondition) ([]*models.Campaign, error) {
+	table, ok := campaignAssetTables[args.Kind]
+	if !ok {
+		return nil, fmt.Errorf("unknown asset kind: %s", args.Kind)
+	}
+	query := `SELECT
+    c.id as id,
+    c.store_group_id as group_id,
+    c.organization_code as org_code,
+    IFNULL(c.daily_coupon_limit_per_user, 0) as daily_coupon_limit_per_user,
+    IFNULL(c.daily_coupon_limit_per_store, 0) as daily_coupon_limit_per_store,
+    c.status as status,
+    c.start_at as start_at,
+    c.end_at as end_at,
+		c.updated_at as updated_at
+FROM campaign c
+INNER JOIN ` + table[0] + ` ca ON ca.campaign_id = c.id
+WHERE
+		ca.` + table[1] + ` = :asset_id`
+	params := map[string]interface{}{
+		"asset_id": args.AssetID,
+	}
+	if len(args.Status) > 0 {
+		query += `
+		AND c.status IN (:status)`
+		params["status"] = args.Status
+	}
+	query += `
+ORDER BY c.id`
+	_query, _params, err := c.sqlHandler.In(query, params)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := c.sqlHandler.PrepareContext(ctx, *_query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err = stmt.Close(); err != nil {
+			c.logger.Error().Err(err).Msg("Failed to close statement")
+		}
+	}()
+	dest := []*models.Campaign{}
+	err = stmt.SelectContext(ctx, &dest, _params...)
+	return dest, err
+}
+
+// 予算消化(budgetExpended=true)・予算不足・配信上限到達で停止するキャンペーン情報をロックして取得する
+// 予算消化は一時停止中のキャンペーンも終了させる
+func (c *CampaignRepository) GetCampaignToExpendedOrShortage(ctx context.Context, tx repository.Transaction, campaignID int, budgetExpended bool) (*models.Campaign, error) {
+	query := `SELECT
+		c.id as id,
+		c.store_group_id as group_id,
+		c.organization_code as org_code,
+		IFNULL(c.daily_coupon_limit_per_user, 0) as daily_coupon_limit_per_user,
+		IFNULL(c.daily_coupon_limit_per_store, 0) as daily_coupon_limit_per_store,
+		c.status as status,
+		c.start_at as start_at,
+		c.end_at as end_at,
+		c.updated_at as updated_at
+	FROM campaign c
+	WHERE
+		c.id = :id
+		AND c.status IN (:status)
+	FOR UPDATE`
+	status := []string{codes.StatusStarted}
+	if budgetExpended {
+		status = append(status, codes.StatusPaused)
+	}
+	_query, _params, err := c.sqlHandler.In(query, map[string]interface{}{
+		"id":     campaignID,
+		"status": status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := tx.(*Transaction).Tx.PreparexContext(ctx, *_query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	campaigns := []*models.Campaign{}
+	err = stmt.SelectContext(ctx, &campaigns, _params...)
+	if err != nil {
+		return nil, err
+	}
+	if len(campaigns) == 0 {
+		return nil, codes.ErrNoData
+	}
+	return campaigns[0], nil
+}
+
+// キャンペーンをロックして現在のステータスを取得する
+// 配信開始処理等のステータスの更新と並行して処理しないために使用する
+func (c *CampaignRepository) GetStatusForUpdate(ctx context.Context, tx repository.Transaction, campaignID int) (string, error) {
+	query := `SELECT status FROM campaign WHERE id = ? FOR UPDATE`
+	statuses := []string{}
+	if err := tx.(*Transaction).Tx.SelectContext(ctx, &statuses, query, campaignID); err != nil {
+		return "", err
+	}
+	if len(statuses) == 0 {
+		return "", codes.ErrNoData
+	}
+	return statuses[0], nil
+}
+
 // 指定されたGrouoIDに紐づくキャンペーンの配信数を取得する
 func (c *CampaignRepository) GetDeliveryCampaignCountByGroupID(ctx context.Context, groupID int) (int, error) {
 	query := `SELECT count(*) FROM campaign
@@ -147,14 +303,17 @@ func (c *CampaignRepository) GetDeliveryCampaignCountByGroupID(ctx context.Conte
 }
 
 // キャンペーンに紐づくクリエイティブの配信レートやスキップオフセットを取得する
+// 配信割合の検証のため審査OKでないクリエイティブも含めて取得する (配信データに含めるかはstatusで判定する)
 func (c *CampaignRepository) GetCampaignCreative(ctx context.Context,
 	tx repository.Transaction, args *repository.CampaignCondition,
 ) ([]*models.CampaignCreative, error) {
 	query := `SELECT
 		cc.creative_id as id,
 		cc.delivery_rate as rate,
-		cc.skip_offset as skip_offset
+		cc.skip_offset as skip_offset,
+		creative.status as status
 	FROM campaign_creative cc
+	INNER JOIN creative ON cc.creative_id = creative.id
 	WHERE
 		cc.campaign_id = :id`
 	stmt, err := tx.(*Transaction).Tx.PrepareNamedContext(ctx, query)
@@ -233,7 +392,7 @@ func (c *CampaignRepository) UpdateStatus(ctx context.Context, tx repository.Tra
 	defer stmt.Close()
 
 	// ExecContextを使用してクエリを実行し、結果を確認します
-	result, err := stmt.ExecContext(ctx, target.Status, time.Now(), target.CampaignID)
+	result, err := stmt.ExecContext(ctx, target.Status, time.Now().UTC(), target.CampaignID)
 	if err != nil {
 		return 0, err
 	}
@@ -3,8 +3,12 @@ package infra
 import (
 	"context"
 	"fmt"
+	"sort"
 	"testing"
 	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/domain/repository"
 	mock_infra "touchgift-job-manager/mock/infra"
 
@@ -168,6 +172,60 @@ func TestCampaignRepository_GetCampaignToStart(t *testing.T) {
 		})
 		assert.Len(t, campaigns, 0)
 	})
+	t.Run("00:00(JST)に開始するキャンペーンは15:00(UTC)のtickで取得する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		// トランザクションを開始する(トランザクション内でテストする)
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+		sqlHandler := mock_infra.NewMockSQLHandler(ctrl)
+		sqlHandler.EXPECT().PrepareNamedContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, query string) (*sqlx.NamedStmt, error) {
+			return tx.(*Transaction).Tx.PrepareNamedContext(ctx, query)
+		}).Times(3)
+
+		// start_atが2026-04-01 00:00(JST)のテストデータを登録する (DBにはDB_LOCATIONの日時で保存される)
+		startAt := time.Date(2026, 4, 1, 0, 0, 0, 0, testJST(t))
+		id, err := createStartCampaignData(ctx, t, tx, testDBTime(t, startAt))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		repo := NewCampaignRepository(logger, sqlHandler)
+		// 1秒前のtickでは取得しない
+		campaigns, err := repo.GetCampaignToStart(ctx, &repository.CampaignToStartCondition{
+			To:     time.Date(2026, 3, 31, 14, 59, 59, 0, time.UTC),
+			Status: "configured",
+		})
+		if assert.NoError(t, err) {
+			assert.False(t, containsCampaign(campaigns, *id))
+		}
+		// 2026-03-31 15:00(UTC)のtickで取得する
+		campaigns, err = repo.GetCampaignToStart(ctx, &repository.CampaignToStartCondition{
+			To:     time.Date(2026, 3, 31, 15, 0, 0, 0, time.UTC),
+			Status: "configured",
+		})
+		if assert.NoError(t, err) {
+			assert.True(t, containsCampaign(campaigns, *id))
+		}
+		// tickがJSTの場合も同じ時刻として取得する
+		campaigns, err = repo.GetCampaignToStart(ctx, &repository.CampaignToStartCondition{
+			To:     startAt,
+			Status: "configured",
+		})
+		if assert.NoError(t, err) {
+			assert.True(t, containsCampaign(campaigns, *id))
+		}
+	})
 }
 func TestCampaignRepository_GetCampaignToEnd(t *testing.T) {
 	logger := GetLogger()
@@ -238,6 +296,63 @@ func TestCampaignRepository_GetCampaignToEnd(t *testing.T) {
 			assert.Equal(t, 1, len(actuals))
 		}
 	})
+	t.Run("00:00(JST)に終了するキャンペーンは15:00(UTC)を過ぎたtickで取得する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		// トランザクションを開始する(トランザクション内でテストする)
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+		_sqlHandler := mock_infra.NewMockSQLHandler(ctrl)
+		_sqlHandler.EXPECT().PrepareContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, query string) (*sqlx.Stmt, error) {
+			return tx.(*Transaction).Tx.PreparexContext(ctx, query)
+		}).Times(3)
+		_sqlHandler.EXPECT().In(gomock.Any(), gomock.Any()).DoAndReturn(func(query string, arg interface{}) (*string, []interface{}, error) {
+			return sqlHandler.In(query, arg)
+		}).Times(3)
+
+		// end_atが2026-04-01 00:00(JST)のテストデータを登録する (DBにはDB_LOCATIONの日時で保存される)
+		endAt := time.Date(2026, 4, 1, 0, 0, 0, 0, testJST(t))
+		id, err := createEndedCampaignData(ctx, t, tx, testDBTime(t, endAt))
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		campaignRepository := NewCampaignRepository(logger, _sqlHandler)
+		// 終了日時ちょうどのtickでは取得しない (end_at < :end)
+		actuals, err := campaignRepository.GetCampaignToEnd(ctx, &repository.CampaignDataToEndCondition{
+			End:    time.Date(2026, 3, 31, 15, 0, 0, 0, time.UTC),
+			Status: []string{"started"},
+		})
+		if assert.NoError(t, err) {
+			assert.False(t, containsCampaign(actuals, *id))
+		}
+		// 2026-03-31 15:00(UTC)を過ぎたtickで取得する
+		actuals, err = campaignRepository.GetCampaignToEnd(ctx, &repository.CampaignDataToEndCondition{
+			End:    time.Date(2026, 3, 31, 15, 0, 1, 0, time.UTC),
+			Status: []string{"started"},
+		})
+		if assert.NoError(t, err) {
+			assert.True(t, containsCampaign(actuals, *id))
+		}
+		// tickがJSTの場合も同じ時刻として取得する
+		actuals, err = campaignRepository.GetCampaignToEnd(ctx, &repository.CampaignDataToEndCondition{
+			End:    endAt.Add(time.Second),
+			Status: []string{"started"},
+		})
+		if assert.NoError(t, err) {
+			assert.True(t, containsCampaign(actuals, *id))
+		}
+	})
 }
 
 func TestCampaignRepository_UpdateStatus(t *testing.T) {
@@ -300,10 +415,126 @@ func TestCampaignRepository_UpdateStatus(t *testing.T) {
 	})
 }
 
+func TestCampaignRepository_GetCampaignByAsset(t *testing.T) {
+	logger := GetLogger()
+	sqlHandler := NewSQLHandler(logger)
+	defer sqlHandler.Close()
+
+	t.Run("クーポンが紐づく指定したステータスのキャンペーンのみ返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+
+		_sqlHandler := mock_infra.NewMockSQLHandler(ctrl)
+		_sqlHandler.EXPECT().PrepareContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, query string) (*sqlx.Stmt, error) {
+			return tx.(*Transaction).Tx.PreparexContext(ctx, query)
+		}).Times(2)
+		_sqlHandler.EXPECT().In(gomock.Any(), gomock.Any()).DoAndReturn(func(query string, arg interface{}) (*string, []interface{}, error) {
+			return sqlHandler.In(query, arg)
+		}).Times(2)
+
+		// テストデータを登録する
+		rdbUtil := NewTouchGiftRDBUtil(ctx, t, tx)
+		storeGroupID := rdbUtil.InsertStoreGroup("グループA", "ORG001", 1)
+		couponID := rdbUtil.InsertCoupon("Summer Sale", "ORG001", "2", "SUMMER2024", "https://example.com/summer-sale.jpg", "XID1234", 1)
+		startedID, _ := rdbUtil.InsertCampaign("ORG001", "started", "Project X", "2024-06-01 18:41:11", "2024-06-29 18:41:11", 1, storeGroupID)
+		pausedID, _ := rdbUtil.InsertCampaign("ORG001", "paused", "Project Y", "2024-06-01 18:41:11", "2024-06-29 18:41:11", 1, storeGroupID)
+		// クーポンが紐づかないキャンペーン
+		_, _ = rdbUtil.InsertCampaign("ORG001", "started", "Project Z", "2024-06-01 18:41:11", "2024-06-29 18:41:11", 1, storeGroupID)
+		rdbUtil.InsertCampaignCoupon(startedID, couponID, 100)
+		rdbUtil.InsertCampaignCoupon(pausedID, couponID, 100)
+
+		campaignRepository := NewCampaignRepository(logger, _sqlHandler)
+		actuals, err := campaignRepository.GetCampaignByAsset(ctx, &repository.CampaignByAssetCondition{
+			Kind:    codes.AssetKindCoupon,
+			AssetID: couponID,
+			Status:  []string{"started"},
+		})
+		if assert.NoError(t, err) && assert.Equal(t, 1, len(actuals)) {
+			assert.Equal(t, startedID, actuals[0].ID)
+			assert.Equal(t, storeGroupID, actuals[0].GroupID)
+		}
+
+		// ステータスを指定しない場合は紐づく全てのキャンペーンを返す
+		actuals, err = campaignRepository.GetCampaignByAsset(ctx, &repository.CampaignByAssetCondition{
+			Kind:    codes.AssetKindCoupon,
+			AssetID: couponID,
+		})
+		if assert.NoError(t, err) && assert.Equal(t, 2, len(actuals)) {
+			assert.Equal(t, startedID, actuals[0].ID)
+			assert.Equal(t, pausedID, actuals[1].ID)
+		}
+	})
+
+	t.Run("不明な種別の場合はエラーを返す", func(t *testing.T) {
+		campaignRepository := NewCampaignRepository(logger, sqlHandler)
+		_, err := campaignRepository.GetCampaignByAsset(context.Background(), &repository.CampaignByAssetCondition{
+			Kind: "unknown", AssetID: 1, Status: []string{"started"},
+		})
+		assert.Error(t, err)
+	})
+}
+
 func TestCampaignRepository_GetCampaignCreative(t *testing.T) {
 	logger := GetLogger()
 	sqlHandler := NewSQLHandler(logger)
 	defer sqlHandler.Close()
+
+	t.Run("審査OK以外のクリエイティブも審査ステータス付きで取得する", func(t *testing.T) {
+		ctx := context.Background()
+		// トランザクションを開始(トランザクション内でテストする)
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+
+		// テストデータを登録する
+		rdbUtil := NewTouchGiftRDBUtil(ctx, t, tx)
+		storeGroupID := rdbUtil.InsertStoreGroup("グループA", "ORG001", 1)
+		campaignID, _ := rdbUtil.InsertCampaign("ORG001", "configured", "Project X", "2024-06-01 18:41:11", "2024-06-29 18:41:11", 1, storeGroupID)
+		videoID, err := rdbUtil.InsertVideo("https://example.com/video.mp4", "https://example.com/endcard.jpg", "video_xid01", "endcard_xid01",
+			100, 200, "mp4", 100, 200, "jpg", 1, 10, "https://example.com/endcard.jpg")
+		if !assert.NoError(t, err) {
+			return
+		}
+		// 審査OK・停止中のクリエイティブを登録
+		approvedID, err := rdbUtil.InsertCreative("ORG001", "2", "creative_a", "click_url", 1, videoID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		stoppedID, err := rdbUtil.InsertCreative("ORG001", "4", "creative_b", "click_url", 1, videoID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		_, err = rdbUtil.InsertCampaignCreative(campaignID, approvedID, 60, 5)
+		assert.NoError(t, err)
+		_, err = rdbUtil.InsertCampaignCreative(campaignID, stoppedID, 40, 5)
+		assert.NoError(t, err)
+
+		campaignRepository := NewCampaignRepository(logger, sqlHandler)
+		actuals, err := campaignRepository.GetCampaignCreative(ctx, tx, &repository.CampaignCondition{CampaignID: campaignID})
+		// 配信割合の検証に使用するため、審査OK以外のクリエイティブも返す
+		if assert.NoError(t, err) && assert.Equal(t, 2, len(actuals)) {
+			sort.Slice(actuals, func(i, j int) bool { return actuals[i].ID < actuals[j].ID })
+			assert.Equal(t, models.CampaignCreative{ID: approvedID, Rate: 60, SkipOffset: 5, Status: "2"}, *actuals[0])
+			assert.Equal(t, models.CampaignCreative{ID: stoppedID, Rate: 40, SkipOffset: 5, Status: "4"}, *actuals[1])
+		}
+	})
 }
 
 func TestCampaignRepository_DeliveryCampaignCountByGroupID(t *testing.T) {
@@ -430,6 +661,50 @@ func TestCampaignRepository_GetDeliveryToStart(t *testing.T) {
 	})
 }
 
+func TestCampaignRepository_GetStatusForUpdate(t *testing.T) {
+	logger := GetLogger()
+	sqlHandler := NewSQLHandler(logger)
+	defer sqlHandler.Close()
+
+	t.Run("存在しないキャンペーンはErrNoDataを返す", func(t *testing.T) {
+		ctx := context.Background()
+		// トランザクションを開始(トランザクション内でテストする)
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+		campaignRepository := NewCampaignRepository(logger, sqlHandler)
+		_, err = campaignRepository.GetStatusForUpdate(ctx, tx, 0)
+		assert.ErrorIs(t, err, codes.ErrNoData)
+	})
+
+	t.Run("キャンペーンの現在のステータスを返す", func(t *testing.T) {
+		ctx := context.Background()
+		// トランザクションを開始(トランザクション内でテストする)
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+		id, err := createStartCampaignData(ctx, t, tx, time.Now().Local().Add(time.Duration(-1)*time.Hour).Format("2006-01-02 15:04:05"))
+		assert.NoError(t, err)
+		campaignRepository := NewCampaignRepository(logger, sqlHandler)
+		status, err := campaignRepository.GetStatusForUpdate(ctx, tx, *id)
+		if assert.NoError(t, err) {
+			assert.Equal(t, "configured", status)
+		}
+	})
+}
+
 // TODO: 時間を現在時刻を基準に
 func createStartCampaignData(ctx context.Context, t testing.TB, tx repository.Transaction, startAt string) (*int, error) {
 	rdbUtil := NewTouchGiftRDBUtil(ctx, t, tx)
@@ -474,3 +749,30 @@ func createEndedCampaignData(ctx context.Context, t testing.TB, tx repository.Tr
 	}
 	return &id, err
 }
+
+// 日本時間 (テスト環境にタイムゾーンのデータがない場合はスキップする)
+func testJST(t testing.TB) *time.Location {
+	location, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skipf("Failed to load location: %v", err)
+	}
+	return location
+}
+
+// DBに登録する日時の文字列 (DB_LOCATIONの日時)
+func testDBTime(t testing.TB, value time.Time) string {
+	location, err := time.LoadLocation(config.Env.Db.Location)
+	if err != nil {
+		t.Fatalf("Failed to load DB location: %v", err)
+	}
+	return value.In(location).Format("2006-01-02 15:04:05")
+}
+
+func containsCampaign(campaigns []*models.Campaign, id int) bool {
+	for _, campaign := range campaigns {
+		if campaign.ID == id {
+			return true
+		}
+	}
+	return false
+}
@@ -2,8 +2,7 @@ package infra
 
 import (
 	"context"
-	"database/sql"
-	"errors"
+	"touchgift-job-manager/codes"
 	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/domain/repository"
 )
@@ -26,7 +25,9 @@ func (c *ContentsRepository) GetCouponsByCampaignID(ctx context.Context, tx repo
     coupon.name AS coupon_name,
     coupon.code AS coupon_code,
     coupon.img_url AS coupon_image_url,
-    campaign_coupon.delivery_rate AS coupon_rate
+    campaign_coupon.delivery_rate AS coupon_rate,
+    IFNULL(campaign_coupon.total_limit, 0) AS coupon_total_limit,
+    coupon.status AS coupon_status
 FROM campaign
 JOIN campaign_coupon ON campaign.id = campaign_coupon.campaign_id
 JOIN coupon ON campaign_coupon.coupon_id = coupon.id
@@ -51,43 +52,33 @@ WHERE campaign.id = :campaign_id`
 	return coupons, nil
 }
 
-func (c *ContentsRepository) GetGimmicksByCampaignID(ctx context.Context, tx repository.Transaction, args *repository.ContentByCampaignIDCondition) (*string, *string, error) {
+func (c *ContentsRepository) GetGimmicksByCampaignID(ctx context.Context, tx repository.Transaction, args *repository.ContentByCampaignIDCondition) ([]*models.Gimmick, error) {
 	query := `SELECT
-    IFNULL(gimmick.img_url, '') AS gimmick_url,
-		IFNULL(gimmick.code, '') AS gimmick_code
+    gimmick.id AS gimmick_id,
+    NULLIF(gimmick.img_url, '') AS gimmick_url,
+    NULLIF(gimmick.code, '') AS gimmick_code
 FROM campaign
 JOIN campaign_gimmick ON campaign.id = campaign_gimmick.campaign_id
 JOIN gimmick ON campaign_gimmick.gimmick_id = gimmick.id
-WHERE campaign.id = :campaign_id`
+WHERE campaign.id = :campaign_id
+  AND gimmick.status = :approved
+ORDER BY gimmick.id`
 
 	stmt, err := tx.(*Transaction).Tx.PrepareNamedContext(ctx, query)
 
 	if err != nil {
-		return nil, nil, err
+		return nil, err
 	}
 
-	var gimmicks []*models.Gimmick
+	gimmicks := []*models.Gimmick{}
 	err = stmt.SelectContext(ctx, &gimmicks, map[string]interface{}{
 		"campaign_id": args.CampaignID,
+		"approved":    codes.ReviewStatusApproved,
 	})
 	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			// No rows found, return nil without error
-			return nil, nil, nil
-		}
-		c.logger.Error().Msgf("Error getting gimmick URL: %v", err)
-		return nil, nil, err
-	}
-	var gimmickURL *string
-	var gimmickCode *string
-	for _, gimmick := range gimmicks {
-		if *gimmick.URL != "" {
-			gimmickURL = gimmick.URL
-		}
-		if *gimmick.Code != "" {
-			gimmickCode = gimmick.Code
-		}
+		c.logger.Error().Msgf("Error getting gimmicks: %v", err)
+		return nil, err
 	}
 
-	return gimmickURL, gimmickCode, nil
+	return gimmicks, nil
 }
@@ -33,12 +33,12 @@ func TestContentsRepository_GetGimmickURLByCampaignID(t *testing.T) {
 
 		sqlHandler := mock_infra.NewMockSQLHandler(ctrl)
 		contentsRepository := NewContentRepository(logger, sqlHandler)
-		actuals, _, err := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
+		actuals, err := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
 			CampaignID: 0,
 		})
 
 		if assert.NoError(t, err) {
-			assert.Nil(t, actuals)
+			assert.Empty(t, actuals)
 		}
 
 	})
@@ -82,13 +82,16 @@ func TestContentsRepository_GetGimmickURLByCampaignID(t *testing.T) {
 
 		contentsRepository := NewContentRepository(logger, sqlHandler)
 
-		gimmickURL, gimmickCode, _ := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
+		gimmicks, err := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
 			CampaignID: id,
 		})
 
-		assert.NotNil(t, gimmickURL)
-		assert.Nil(t, gimmickCode)
-		assert.Equal(t, "https://gimmck.jpg", *gimmickURL)
+		if assert.NoError(t, err) && assert.Len(t, gimmicks, 1) {
+			assert.Equal(t, gimmickID, gimmicks[0].ID)
+			assert.NotNil(t, gimmicks[0].URL)
+			assert.Nil(t, gimmicks[0].Code)
+			assert.Equal(t, "https://gimmck.jpg", *gimmicks[0].URL)
+		}
 	})
 
 	t.Run("Campaignに紐づいたGimmickが存在する時Codeを返却する", func(t *testing.T) {
@@ -131,16 +134,18 @@ func TestContentsRepository_GetGimmickURLByCampaignID(t *testing.T) {
 
 		contentsRepository := NewContentRepository(logger, sqlHandler)
 
-		gimmickURL, gimmickCode, _ := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
+		gimmicks, err := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
 			CampaignID: id,
 		})
 
-		assert.Nil(t, gimmickURL)
-		assert.NotNil(t, gimmickCode)
-		assert.Equal(t, "Code1", *gimmickCode)
+		if assert.NoError(t, err) && assert.Len(t, gimmicks, 1) {
+			assert.Nil(t, gimmicks[0].URL)
+			assert.NotNil(t, gimmicks[0].Code)
+			assert.Equal(t, "Code1", *gimmicks[0].Code)
+		}
 	})
 
-	t.Run("Campaignに2件Gimmickが存在する時CodeとURL両方を返却する", func(t *testing.T) {
+	t.Run("Campaignに複数のGimmickが存在する時、審査OKのGimmickを全て返却する", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
@@ -177,19 +182,26 @@ func TestContentsRepository_GetGimmickURLByCampaignID(t *testing.T) {
 		)
 		gimmickID := rdbUtil.InsertGimmick("gimmick1", "https://gimmck.jpg", "ORG001", "2", "", time.Now().Format("15:04:05"), 1)
 		gimmickID2 := rdbUtil.InsertGimmick("gimmick2", "", "ORG001", "2", "Code1", time.Now().Add(time.Minute).Format("15:04:05"), 1)
+		// 停止中のギミック
+		gimmickID3 := rdbUtil.InsertGimmick("gimmick3", "https://stopped.jpg", "ORG001", "4", "", time.Now().Add(2*time.Minute).Format("15:04:05"), 1)
 		rdbUtil.InsertCampaignGimmick(id, gimmickID)
 		rdbUtil.InsertCampaignGimmick(id, gimmickID2)
+		rdbUtil.InsertCampaignGimmick(id, gimmickID3)
 
 		contentsRepository := NewContentRepository(logger, sqlHandler)
 
-		gimmickURL, gimmickCode, _ := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
+		gimmicks, err := contentsRepository.GetGimmicksByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
 			CampaignID: id,
 		})
 
-		assert.NotNil(t, gimmickURL)
-		assert.NotNil(t, gimmickCode)
-		assert.Equal(t, "https://gimmck.jpg", *gimmickURL)
-		assert.Equal(t, "Code1", *gimmickCode)
+		if assert.NoError(t, err) && assert.Len(t, gimmicks, 2) {
+			assert.Equal(t, gimmickID, gimmicks[0].ID)
+			assert.Equal(t, "https://gimmck.jpg", *gimmicks[0].URL)
+			assert.Nil(t, gimmicks[0].Code)
+			assert.Equal(t, gimmickID2, gimmicks[1].ID)
+			assert.Nil(t, gimmicks[1].URL)
+			assert.Equal(t, "Code1", *gimmicks[1].Code)
+		}
 	})
 }
 
@@ -282,9 +294,48 @@ func TestContentsRepository_GetCouponsByCampaignID(t *testing.T) {
 			assert.Equal(t, "SUMMER2024", actuals[0].Code)
 			assert.Equal(t, "https://example.com/summer-sale.jpg", actuals[0].ImageURL)
 			assert.Equal(t, "100", actuals[0].Rate)
+			assert.Equal(t, "2", actuals[0].Status)
+			// 配信上限数が未設定の場合は0(上限なし)
+			assert.Equal(t, 0, actuals[0].TotalLimit)
 		}
 
 	})
+
+	t.Run("クーポンの配信上限数(合計)が設定されている場合は上限数を取得できる", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		// トランザクションを開始(トランザクション内でテストする)
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+		sqlHandler := mock_infra.NewMockSQLHandler(ctrl)
+
+		// テストデータを登録する
+		rdbUtil := NewTouchGiftRDBUtil(ctx, t, tx)
+		rdbUtil.InsertStore("ORG001", "S001", "東京本店", "100-0001", "13", "東京都千代田区丸の内1-1-1")
+		storeGroupID := rdbUtil.InsertStoreGroup("グループA", "ORG001", 1)
+		couponID := rdbUtil.InsertCoupon("Summer Sale", "ORG123", "2", "SUMMER2024", "https://example.com/summer-sale.jpg", "XID1234", 1)
+		campaignID, _ := rdbUtil.InsertCampaign("ORG001", "configured", "Project X", "2024-06-01 18:41:11", "2024-06-29 18:41:11", 1, storeGroupID)
+		campaignCouponID := rdbUtil.InsertCampaignCoupon(campaignID, couponID, 100)
+		rdbUtil.UpdateCampaignCouponTotalLimit(campaignCouponID, 500)
+
+		contentsRepository := NewContentRepository(logger, sqlHandler)
+		actuals, err := contentsRepository.GetCouponsByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
+			CampaignID: campaignID,
+		})
+		if assert.NoError(t, err) && assert.Equal(t, 1, len(actuals)) {
+			assert.Equal(t, 500, actuals[0].TotalLimit)
+		}
+	})
 	t.Run("キャンペーンに複数のクーポンデータが存在する場合、全てのクーポンデータを取得できる", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
@@ -346,5 +397,53 @@ func TestContentsRepository_GetCouponsByCampaignID(t *testing.T) {
 			assert.Equal(t, "50", actuals[1].Rate)
 		}
 	})
+	t.Run("審査OK以外のクーポンも審査ステータス付きで取得する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := context.Background()
+		// トランザクションを開始
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		// ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+		sqlHandler := mock_infra.NewMockSQLHandler(ctrl)
+
+		// テストデータを登録する
+		rdbUtil := NewTouchGiftRDBUtil(ctx, t, tx)
+		store_group_id := rdbUtil.InsertStoreGroup("グループA", "ORG001", 1)
+		// 審査OK・審査中・停止中のクーポンを登録
+		couponIDs := []int{
+			rdbUtil.InsertCoupon("Summer Sale", "ORG123", "2", "SUMMER2024", "https://example.com/summer-sale.jpg", "XID1234", 1),
+			rdbUtil.InsertCoupon("Winter Sale", "ORG123", "1", "WINTER2024", "https://example.com/winter-sale.jpg", "XID5678", 1),
+			rdbUtil.InsertCoupon("Spring Sale", "ORG123", "4", "SPRING2024", "https://example.com/spring-sale.jpg", "XID9012", 1),
+		}
+		campaignID, _ := rdbUtil.InsertCampaign(
+			"ORG001", "configured", "Project X", "2024-06-01 18:41:11", "2024-06-29 18:41:11", 1, store_group_id)
+		for _, couponID := range couponIDs {
+			rdbUtil.InsertCampaignCoupon(campaignID, couponID, 30)
+		}
+
+		contentsRepository := NewContentRepository(logger, sqlHandler)
+		actuals, err := contentsRepository.GetCouponsByCampaignID(ctx, tx, &repository.ContentByCampaignIDCondition{
+			CampaignID: campaignID,
+		})
+
+		// 配信割合の検証に使用するため、審査OK以外のクーポンも返す
+		if assert.NoError(t, err) && assert.Equal(t, 3, len(actuals)) {
+			assert.Equal(t, couponIDs[0], actuals[0].ID)
+			assert.Equal(t, "2", actuals[0].Status)
+			assert.Equal(t, couponIDs[1], actuals[1].ID)
+			assert.Equal(t, "1", actuals[1].Status)
+			assert.Equal(t, couponIDs[2], actuals[2].ID)
+			assert.Equal(t, "4", actuals[2].Status)
+		}
+	})
 
 }
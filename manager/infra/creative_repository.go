@@ -3,6 +3,7 @@ package infra
 import (
 	"context"
 	"fmt"
+	"touchgift-job-manager/codes"
 	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/domain/repository"
 )
@@ -47,6 +48,7 @@ func (c *CreativeRepository) GetCreativeByCampaignID(ctx context.Context, tx rep
 			 LEFT JOIN banner ON creative.banner_id = banner.id
 			 LEFT JOIN video ON creative.video_id = video.id
 	WHERE campaign.id = :campaign_id
+		AND creative.status = :approved
 	GROUP BY
 	  creative.id, creative.click_url, banner.id, video.id, video.endcard_url, video.endcard_link, video.endcard_width, video.endcard_height, video.endcard_extension
 	LIMIT :limit
@@ -59,6 +61,7 @@ func (c *CreativeRepository) GetCreativeByCampaignID(ctx context.Context, tx rep
 	err = stmt.SelectContext(ctx, &creatives, map[string]interface{}{
 		"campaign_id": args.CampaignID,
 		"limit":       args.Limit,
+		"approved":    codes.ReviewStatusApproved,
 	})
 	if err != nil {
 		c.logger.Error().Msgf("Error getting deliveries: %v", err)
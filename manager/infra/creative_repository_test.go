@@ -100,7 +100,7 @@ func TestCreativeRepository_GetCreativeByCampaignID(t *testing.T) {
 		// creative
 		creative_id, err := rdbUtil.InsertCreative(
 			"ORG001",
-			"0",
+			"2", // 審査OK
 			"creative_name",
 			"click_url",
 			1,
@@ -0,0 +1,110 @@
+package infra
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// DaypartRepository キャンペーンの配信時間帯と、配信時間帯外による一時停止の記録
+type DaypartRepository struct {
+	logger     *Logger
+	sqlHandler SQLHandler
+}
+
+func NewDaypartRepository(logger *Logger, sqlHandler SQLHandler) repository.DaypartRepository {
+	return &DaypartRepository{
+		logger:     logger,
+		sqlHandler: sqlHandler,
+	}
+}
+
+// GetByCampaignIDs キャンペーンの配信時間帯を取得する
+func (d *DaypartRepository) GetByCampaignIDs(ctx context.Context, campaignIDs []int) ([]*models.Daypart, error) {
+	dayparts := []*models.Daypart{}
+	if len(campaignIDs) == 0 {
+		return dayparts, nil
+	}
+	query := `SELECT
+		campaign_id,
+		day_of_week,
+		start_time,
+		end_time
+	FROM campaign_daypart
+	WHERE
+		campaign_id IN (:campaign_ids)
+	ORDER BY campaign_id, id`
+	_query, _params, err := d.sqlHandler.In(query, map[string]interface{}{
+		"campaign_ids": campaignIDs,
+	})
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := d.sqlHandler.PrepareContext(ctx, *_query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err = stmt.Close(); err != nil {
+			d.logger.Error().Err(err).Msg("Failed to close statement")
+		}
+	}()
+	err = stmt.SelectContext(ctx, &dayparts, _params...)
+	if err != nil {
+		return nil, err
+	}
+	return dayparts, nil
+}
+
+// GetPausedCampaignIDs 配信時間帯外のため一時停止したキャンペーンIDを取得する
+func (d *DaypartRepository) GetPausedCampaignIDs(ctx context.Context) ([]int, error) {
+	query := `SELECT campaign_id FROM job_daypart_pause ORDER BY campaign_id`
+	campaignIDs := []int{}
+	err := d.sqlHandler.Select(ctx, &campaignIDs, query)
+	if err != nil {
+		return nil, err
+	}
+	return campaignIDs, nil
+}
+
+// SavePause 配信時間帯外のため一時停止したことを記録する (txがnilの場合は単独で登録する)
+func (d *DaypartRepository) SavePause(ctx context.Context, tx repository.Transaction, campaignID int, pausedAt time.Time) error {
+	query := `INSERT INTO job_daypart_pause (campaign_id, paused_at)
+	VALUES (:campaign_id, :paused_at)
+	ON DUPLICATE KEY UPDATE paused_at = VALUES(paused_at)`
+	return d.exec(ctx, tx, query, map[string]interface{}{
+		"campaign_id": campaignID,
+		"paused_at":   pausedAt,
+	})
+}
+
+// DeletePause 配信時間帯外による一時停止の記録を削除する (txがnilの場合は単独で削除する)
+func (d *DaypartRepository) DeletePause(ctx context.Context, tx repository.Transaction, campaignID int) error {
+	query := `DELETE FROM job_daypart_pause WHERE campaign_id = :campaign_id`
+	return d.exec(ctx, tx, query, map[string]interface{}{
+		"campaign_id": campaignID,
+	})
+}
+
+func (d *DaypartRepository) exec(ctx context.Context, tx repository.Transaction, query string, params map[string]interface{}) error {
+	var stmt *sqlx.NamedStmt
+	var err error
+	if tx == nil {
+		stmt, err = d.sqlHandler.PrepareNamedContext(ctx, query)
+	} else {
+		stmt, err = tx.(*Transaction).Tx.PrepareNamedContext(ctx, query)
+	}
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := stmt.Close(); cerr != nil {
+			d.logger.Error().Err(cerr).Msg("Failed to close statement")
+		}
+	}()
+	_, err = stmt.ExecContext(ctx, params)
+	return err
+}
@@ -0,0 +1,85 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"sync"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
+
+// DeadLetterSink 処理できなかったメッセージの保存先
+type DeadLetterSink interface {
+	Put(ctx context.Context, deadLetter *models.DeadLetter) error
+}
+
+type fileDeadLetterSink struct {
+	logger *Logger
+	path   string
+	mutex  sync.Mutex
+}
+
+// NewFileDeadLetterSink 1行1メッセージのJSON(NDJSON)としてファイルに追記する
+func NewFileDeadLetterSink(logger *Logger, path string) DeadLetterSink {
+	return &fileDeadLetterSink{
+		logger: logger,
+		path:   path,
+	}
+}
+
+func (f *fileDeadLetterSink) Put(ctx context.Context, deadLetter *models.DeadLetter) error {
+	line, err := json.Marshal(deadLetter)
+	if err != nil {
+		return err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+type sqsDeadLetterSink struct {
+	logger   *Logger
+	svc      *sqs.SQS
+	queueURL *string
+}
+
+// NewSQSDeadLetterSink DeadLetterのJSONを本文としてDLQに送信する
+func NewSQSDeadLetterSink(logger *Logger, region Region, queueURL *string) DeadLetterSink {
+	sqsSession := session.Must(session.NewSessionWithOptions(session.Options{
+		Config:            *aws.NewConfig().WithEndpoint(config.Env.SQS.EndPoint),
+		SharedConfigState: session.SharedConfigEnable,
+	}))
+	if config.Env.RegionFromEC2Metadata {
+		sqsSession.Config.Region = region.Get()
+	}
+	return &sqsDeadLetterSink{
+		logger:   logger,
+		svc:      sqs.New(sqsSession),
+		queueURL: queueURL,
+	}
+}
+
+func (s *sqsDeadLetterSink) Put(ctx context.Context, deadLetter *models.DeadLetter) error {
+	body, err := json.Marshal(deadLetter)
+	if err != nil {
+		return err
+	}
+	_, err = s.svc.SendMessageWithContext(ctx, &sqs.SendMessageInput{
+		QueueUrl:    s.queueURL,
+		MessageBody: aws.String(string(body)),
+	})
+	return err
+}
@@ -20,6 +20,7 @@ type CampaignDataRepository struct {
 	dynamoDBHandler *DynamoDBHandler
 	tableName       *string
 	monitor         *metrics.Monitor
+	batchWriter     *dynamoDBBatchWriter
 }
 
 func NewCampaignDataRepository(handler *DynamoDBHandler, logger *Logger, monitor *metrics.Monitor) repository.DeliveryDataCampaignRepository {
@@ -34,6 +35,7 @@ func NewCampaignDataRepository(handler *DynamoDBHandler, logger *Logger, monitor
 		dynamoDBHandler: handler,
 		tableName:       &tableName,
 		monitor:         monitor,
+		batchWriter:     newDynamoDBBatchWriter(handler, logger, monitor, &config.Env.DynamoDB, &tableName, "id"),
 	}
 
 	return &campaignDataRepository
@@ -63,6 +65,30 @@ func (c *CampaignDataRepository) Get(ctx context.Context, id *string) (*models.D
 	return &item, nil
 }
 
+// GetAll キャンペーン配信データを全件取得する
+func (c *CampaignDataRepository) GetAll(ctx context.Context) ([]*models.DeliveryDataCampaign, error) {
+	items := []*models.DeliveryDataCampaign{}
+	var unmarshalErr error
+	err := c.dynamoDBHandler.Svc.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName:      c.tableName,
+		ConsistentRead: aws.Bool(true),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		pageItems := []*models.DeliveryDataCampaign{}
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageItems); unmarshalErr != nil {
+			return false
+		}
+		items = append(items, pageItems...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return items, nil
+}
+
 // Put キャンペーン配信データの登録/更新を行う
 // TODO: メトリクス項目を考える(成功時、失敗時)
 func (c *CampaignDataRepository) Put(ctx context.Context, updateData *models.DeliveryDataCampaign) error {
@@ -84,14 +110,15 @@ func (c *CampaignDataRepository) Put(ctx context.Context, updateData *models.Del
 }
 
 func (c *CampaignDataRepository) PutAll(ctx context.Context, updateData *[]models.DeliveryDataCampaign) error {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(*updateData))
 	for i := range *updateData {
-		updateData := (*updateData)[i]
-		err := c.Put(ctx, &updateData)
+		item, err := dynamodbattribute.MarshalMap((*updateData)[i])
 		if err != nil {
-			return nil
+			return err
 		}
+		items = append(items, item)
 	}
-	return nil
+	return c.batchWriter.Write(ctx, "put", putRequests(items))
 }
 
 func (c *CampaignDataRepository) Delete(ctx context.Context, campaignID *string) error {
@@ -110,13 +137,13 @@ func (c *CampaignDataRepository) Delete(ctx context.Context, campaignID *string)
 }
 
 func (c *CampaignDataRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryDataCampaign) error {
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(*deleteDatas))
 	for i := range *deleteDatas {
-		deleteData := (*deleteDatas)[i]
-		ID := &deleteData.ID
-		err := c.Delete(ctx, ID)
-		if err != nil {
-			return err
-		}
+		keys = append(keys, map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String((*deleteDatas)[i].ID),
+			},
+		})
 	}
-	return nil
+	return c.batchWriter.Write(ctx, "delete", deleteRequests(keys))
 }
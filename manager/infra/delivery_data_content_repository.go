@@ -19,6 +19,7 @@ type DeliveryContentRepository struct {
 	dynamoDBHandler *DynamoDBHandler
 	tableName       *string
 	monitor         *metrics.Monitor
+	batchWriter     *dynamoDBBatchWriter
 }
 
 // NewDeliveryContentRepository is function
@@ -38,6 +39,7 @@ func NewDeliveryDataContentRepository(handler *DynamoDBHandler, logger *Logger,
 		dynamoDBHandler: handler,
 		tableName:       &tableName,
 		monitor:         monitor,
+		batchWriter:     newDynamoDBBatchWriter(handler, logger, monitor, &config.Env.DynamoDB, &tableName, "campaign_id"),
 	}
 	return &DeliveryContentRepository
 }
@@ -92,14 +94,16 @@ func (r *DeliveryContentRepository) Put(ctx context.Context, updateData *models.
 
 // PutAll is function
 func (r *DeliveryContentRepository) PutAll(ctx context.Context, updateDatas *[]models.DeliveryDataContent) error {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(*updateDatas))
 	for i := range *updateDatas {
-		updateData := (*updateDatas)[i]
-		err := r.Put(ctx, &updateData)
+		item, err := dynamodbattribute.MarshalMap((*updateDatas)[i])
 		if err != nil {
+			r.monitor.Metrics.GetCounter(metricDynamodbPutTotal).WithLabelValues(*r.tableName, "marshal_error").Inc()
 			return err
 		}
+		items = append(items, item)
 	}
-	return nil
+	return r.batchWriter.Write(ctx, "put", putRequests(items))
 }
 
 // Delete is function
@@ -124,12 +128,13 @@ func (r *DeliveryContentRepository) Delete(ctx context.Context, id *string) erro
 
 // DeleteAll is function
 func (r *DeliveryContentRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryDataContent) error {
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(*deleteDatas))
 	for i := range *deleteDatas {
-		deleteData := (*deleteDatas)[i]
-		err := r.Delete(ctx, &deleteData.CampaignID)
-		if err != nil {
-			return err
-		}
+		keys = append(keys, map[string]*dynamodb.AttributeValue{
+			"campaign_id": {
+				S: aws.String((*deleteDatas)[i].CampaignID),
+			},
+		})
 	}
-	return nil
+	return r.batchWriter.Write(ctx, "delete", deleteRequests(keys))
 }
@@ -33,10 +33,13 @@ func TestContentDataRepository_Get(t *testing.T) {
 		contentDataRepository := NewDeliveryDataContentRepository(dynamodbHandler, logger, monitor)
 		ID := "1"
 		URL := "URL1"
+		code := "code1"
 		expected := models.DeliveryDataContent{
-			CampaignID: ID,
-			Coupons:    []models.DeliveryCouponData{{ID: 1}},
-			Gimmicks:   models.Gimmick{URL: &URL},
+			CampaignID:  ID,
+			Version:     codes.DeliveryContentVersionGimmickList,
+			Coupons:     []models.DeliveryCouponData{{ID: 1}},
+			Gimmicks:    models.Gimmick{URL: &URL, Code: &code},
+			GimmickList: []models.Gimmick{{ID: 1, URL: &URL}, {ID: 2, Code: &code}},
 		}
 		// データを用意
 		if err := contentDataRepository.Put(ctx, &expected); !assert.NoError(t, err) {
@@ -36,6 +36,8 @@ type DeliveryDataCreativeRepository struct {
 	dynamoDBHandler *DynamoDBHandler
 	tableName       *string
 	monitor         *metrics.Monitor
+	batchWriter     *dynamoDBBatchWriter
+	batchReader     *dynamoDBBatchReader
 }
 
 // NewDeliveryDataCreativeRepository is function
@@ -55,6 +57,8 @@ func NewDeliveryDataCreativeRepository(handler *DynamoDBHandler, logger *Logger,
 		dynamoDBHandler: handler,
 		tableName:       &tableName,
 		monitor:         monitor,
+		batchWriter:     newDynamoDBBatchWriter(handler, logger, monitor, &config.Env.DynamoDB, &tableName, "id"),
+		batchReader:     newDynamoDBBatchReader(handler, &config.Env.DynamoDB, &tableName, "id"),
 	}
 	return &DeliveryDataCreativeRepository
 }
@@ -84,6 +88,51 @@ func (r *DeliveryDataCreativeRepository) Get(ctx context.Context, id *string) (*
 	return &item, nil
 }
 
+// GetAll クリエイティブ配信データを全件取得する
+func (r *DeliveryDataCreativeRepository) GetAll(ctx context.Context) ([]*models.DeliveryDataCreative, error) {
+	items := []*models.DeliveryDataCreative{}
+	var unmarshalErr error
+	err := r.dynamoDBHandler.Svc.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName:      r.tableName,
+		ConsistentRead: aws.Bool(true),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		pageItems := []*models.DeliveryDataCreative{}
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageItems); unmarshalErr != nil {
+			return false
+		}
+		items = append(items, pageItems...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return items, nil
+}
+
+// BatchGet クリエイティブ配信データをまとめて取得する (存在しないものは含まない)
+func (r *DeliveryDataCreativeRepository) BatchGet(ctx context.Context, ids []string) ([]*models.DeliveryDataCreative, error) {
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(id),
+			},
+		})
+	}
+	results, err := r.batchReader.Read(ctx, keys)
+	if err != nil {
+		return nil, err
+	}
+	items := []*models.DeliveryDataCreative{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(results, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 // Put is function
 func (r *DeliveryDataCreativeRepository) Put(ctx context.Context, updateData *models.DeliveryDataCreative) error {
 	defer func() {
@@ -110,14 +159,16 @@ func (r *DeliveryDataCreativeRepository) Put(ctx context.Context, updateData *mo
 
 // PutAll is function
 func (r *DeliveryDataCreativeRepository) PutAll(ctx context.Context, updateDatas *[]models.DeliveryDataCreative) error {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(*updateDatas))
 	for i := range *updateDatas {
-		updateData := (*updateDatas)[i]
-		err := r.Put(ctx, &updateData)
+		item, err := dynamodbattribute.MarshalMap((*updateDatas)[i])
 		if err != nil {
+			r.monitor.Metrics.GetCounter(metricDynamodbPutTotal).WithLabelValues(*r.tableName, "marshal_error").Inc()
 			return err
 		}
+		items = append(items, item)
 	}
-	return nil
+	return r.batchWriter.Write(ctx, "put", putRequests(items))
 }
 
 // Delete is function
@@ -142,14 +193,15 @@ func (r *DeliveryDataCreativeRepository) Delete(ctx context.Context, id *string)
 
 // DeleteAll is function
 func (r *DeliveryDataCreativeRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryDataCreative) error {
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(*deleteDatas))
 	for i := range *deleteDatas {
-		deleteData := (*deleteDatas)[i]
-		err := r.Delete(ctx, &deleteData.ID)
-		if err != nil {
-			return err
-		}
+		keys = append(keys, map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String((*deleteDatas)[i].ID),
+			},
+		})
 	}
-	return nil
+	return r.batchWriter.Write(ctx, "delete", deleteRequests(keys))
 }
 
 // UpdateTTL is function
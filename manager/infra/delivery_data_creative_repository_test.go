@@ -210,6 +210,46 @@ func TestCreativeDataRepository_PutAll(t *testing.T) {
 	})
 }
 
+// CreativeDataRepository の BatchGet のテスト
+func TestCreativeDataRepository_BatchGet(t *testing.T) {
+	ctx := context.Background()
+	logger := GetLogger()
+	monitor := metrics.GetMonitor()
+	region := NewRegion(logger)
+	dynamodbHandler := NewDynamoDBHandler(logger, region)
+
+	t.Run("creative_dataをまとめて取得し、存在しないものは含まない", func(t *testing.T) {
+		creativeDataRepository := NewDeliveryDataCreativeRepository(dynamodbHandler, logger, monitor)
+		expected := []models.DeliveryDataCreative{
+			{
+				ID:  "1",
+				URL: "id_batchget1_url",
+				TTL: time.Now().Unix(),
+			},
+			{
+				ID:  "2",
+				URL: "id_batchget2_url",
+				TTL: time.Now().Unix(),
+			},
+		}
+		// データを用意
+		if err := creativeDataRepository.PutAll(ctx, &expected); !assert.NoError(t, err) {
+			return
+		}
+		// 用意したデータを削除
+		defer func() {
+			if err := creativeDataRepository.DeleteAll(ctx, &expected); err != nil {
+				assert.NoError(t, err)
+			}
+		}()
+		// テスト実行する
+		actuals, err := creativeDataRepository.BatchGet(ctx, []string{"1", "2", "100", "1"})
+		if assert.NoError(t, err) {
+			assert.ElementsMatch(t, []*models.DeliveryDataCreative{&expected[0], &expected[1]}, actuals)
+		}
+	})
+}
+
 // CreativeDataRepository の Delete のテスト
 func TestCreativeDataRepository_Delete(t *testing.T) {
 	ctx := context.Background()
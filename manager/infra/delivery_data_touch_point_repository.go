@@ -20,6 +20,8 @@ type DeliveryTouchPointRepository struct {
 	dynamoDBHandler *DynamoDBHandler
 	tableName       *string
 	monitor         *metrics.Monitor
+	batchWriter     *dynamoDBBatchWriter
+	batchReader     *dynamoDBBatchReader
 }
 
 // NewDeliveryTouchPointRepository is function
@@ -39,6 +41,8 @@ func NewDeliveryDataTouchPointRepository(handler *DynamoDBHandler, logger *Logge
 		dynamoDBHandler: handler,
 		tableName:       &tableName,
 		monitor:         monitor,
+		batchWriter:     newDynamoDBBatchWriter(handler, logger, monitor, &config.Env.DynamoDB, &tableName, "id", "group_id"),
+		batchReader:     newDynamoDBBatchReader(handler, &config.Env.DynamoDB, &tableName, "id", "group_id"),
 	}
 	return &DeliveryTouchPointRepository
 }
@@ -71,6 +75,55 @@ func (r *DeliveryTouchPointRepository) Get(ctx context.Context, id *string, grou
 	return &item, nil
 }
 
+// GetAll タッチポイント配信データを全件取得する
+func (r *DeliveryTouchPointRepository) GetAll(ctx context.Context) ([]*models.DeliveryTouchPoint, error) {
+	items := []*models.DeliveryTouchPoint{}
+	var unmarshalErr error
+	err := r.dynamoDBHandler.Svc.ScanPagesWithContext(ctx, &dynamodb.ScanInput{
+		TableName:      r.tableName,
+		ConsistentRead: aws.Bool(true),
+	}, func(page *dynamodb.ScanOutput, lastPage bool) bool {
+		pageItems := []*models.DeliveryTouchPoint{}
+		if unmarshalErr = dynamodbattribute.UnmarshalListOfMaps(page.Items, &pageItems); unmarshalErr != nil {
+			return false
+		}
+		items = append(items, pageItems...)
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if unmarshalErr != nil {
+		return nil, unmarshalErr
+	}
+	return items, nil
+}
+
+// BatchGet タッチポイント配信データをまとめて取得する (存在しないものは含まない)
+func (r *DeliveryTouchPointRepository) BatchGet(ctx context.Context, keys *[]models.DeliveryTouchPoint) ([]*models.DeliveryTouchPoint, error) {
+	requestKeys := make([]map[string]*dynamodb.AttributeValue, 0, len(*keys))
+	for i := range *keys {
+		key := (*keys)[i]
+		requestKeys = append(requestKeys, map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(key.ID),
+			},
+			"group_id": {
+				N: aws.String(strconv.Itoa(key.GroupID)),
+			},
+		})
+	}
+	results, err := r.batchReader.Read(ctx, requestKeys)
+	if err != nil {
+		return nil, err
+	}
+	items := []*models.DeliveryTouchPoint{}
+	if err := dynamodbattribute.UnmarshalListOfMaps(results, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 // Put is function
 func (r *DeliveryTouchPointRepository) Put(ctx context.Context, updateData *models.DeliveryTouchPoint) error {
 	defer func() {
@@ -96,14 +149,16 @@ func (r *DeliveryTouchPointRepository) Put(ctx context.Context, updateData *mode
 
 // PutAll is function
 func (r *DeliveryTouchPointRepository) PutAll(ctx context.Context, updateDatas *[]models.DeliveryTouchPoint) error {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(*updateDatas))
 	for i := range *updateDatas {
-		updateData := (*updateDatas)[i]
-		err := r.Put(ctx, &updateData)
+		item, err := dynamodbattribute.MarshalMap((*updateDatas)[i])
 		if err != nil {
+			r.monitor.Metrics.GetCounter(metricDynamodbPutTotal).WithLabelValues(*r.tableName, "marshal_error").Inc()
 			return err
 		}
+		items = append(items, item)
 	}
-	return nil
+	return r.batchWriter.Write(ctx, "put", putRequests(items))
 }
 
 // Delete is function
@@ -131,14 +186,17 @@ func (r *DeliveryTouchPointRepository) Delete(ctx context.Context, id *string, g
 
 // DeleteAll is function
 func (r *DeliveryTouchPointRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryTouchPoint) error {
+	keys := make([]map[string]*dynamodb.AttributeValue, 0, len(*deleteDatas))
 	for i := range *deleteDatas {
 		deleteData := (*deleteDatas)[i]
-		ID := &deleteData.ID
-		groupID := strconv.Itoa(deleteData.GroupID)
-		err := r.Delete(ctx, ID, &groupID)
-		if err != nil {
-			return err
-		}
+		keys = append(keys, map[string]*dynamodb.AttributeValue{
+			"id": {
+				S: aws.String(deleteData.ID),
+			},
+			"group_id": {
+				N: aws.String(strconv.Itoa(deleteData.GroupID)),
+			},
+		})
 	}
-	return nil
+	return r.batchWriter.Write(ctx, "delete", deleteRequests(keys))
 }
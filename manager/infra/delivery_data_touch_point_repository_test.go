@@ -219,6 +219,88 @@ func TestTouchPointDataRepository_PutAll(t *testing.T) {
 	})
 }
 
+// TouchPointDataRepository の DeleteAll のテスト
+func TestTouchPointDataRepository_DeleteAll(t *testing.T) {
+	ctx := context.Background()
+	logger := GetLogger()
+	monitor := metrics.GetMonitor()
+	region := NewRegion(logger)
+	dynamodbHandler := NewDynamoDBHandler(logger, region)
+
+	t.Run("25件を超えるtouchpoint_dataを一括で登録・削除", func(t *testing.T) {
+		touchPointDataRepository := NewDeliveryDataTouchPointRepository(dynamodbHandler, logger, monitor)
+		datas := make([]models.DeliveryTouchPoint, 0, 60)
+		for i := 0; i < 60; i++ {
+			datas = append(datas, models.DeliveryTouchPoint{
+				GroupID: 1,
+				ID:      "batch" + strconv.Itoa(i),
+				StoreID: "store1",
+			})
+		}
+		if err := touchPointDataRepository.PutAll(ctx, &datas); !assert.NoError(t, err) {
+			return
+		}
+		for i := range datas {
+			ID := datas[i].ID
+			groupID := strconv.Itoa(datas[i].GroupID)
+			actual, err := touchPointDataRepository.Get(ctx, &ID, &groupID)
+			if assert.NoError(t, err) {
+				assert.Exactly(t, datas[i], *actual)
+			}
+		}
+		if err := touchPointDataRepository.DeleteAll(ctx, &datas); !assert.NoError(t, err) {
+			return
+		}
+		for i := range datas {
+			ID := datas[i].ID
+			groupID := strconv.Itoa(datas[i].GroupID)
+			_, err := touchPointDataRepository.Get(ctx, &ID, &groupID)
+			assert.EqualError(t, err, codes.ErrNoData.Error())
+		}
+	})
+}
+
+// TouchPointDataRepository の BatchGet のテスト
+func TestTouchPointDataRepository_BatchGet(t *testing.T) {
+	ctx := context.Background()
+	logger := GetLogger()
+	monitor := metrics.GetMonitor()
+	region := NewRegion(logger)
+	dynamodbHandler := NewDynamoDBHandler(logger, region)
+
+	t.Run("100件を超えるtouchpoint_dataをまとめて取得し、存在しないものは含まない", func(t *testing.T) {
+		touchPointDataRepository := NewDeliveryDataTouchPointRepository(dynamodbHandler, logger, monitor)
+		datas := make([]models.DeliveryTouchPoint, 0, 150)
+		for i := 0; i < 150; i++ {
+			datas = append(datas, models.DeliveryTouchPoint{
+				GroupID: 1,
+				ID:      "batchget" + strconv.Itoa(i),
+				StoreID: "store1",
+			})
+		}
+		if err := touchPointDataRepository.PutAll(ctx, &datas); !assert.NoError(t, err) {
+			return
+		}
+		// 用意したデータを削除
+		defer func() {
+			if err := touchPointDataRepository.DeleteAll(ctx, &datas); err != nil {
+				assert.NoError(t, err)
+			}
+		}()
+		keys := append([]models.DeliveryTouchPoint{}, datas...)
+		keys = append(keys, models.DeliveryTouchPoint{GroupID: 1, ID: "notfound"}, datas[0])
+		// テスト実行する
+		actuals, err := touchPointDataRepository.BatchGet(ctx, &keys)
+		if assert.NoError(t, err) {
+			expected := make([]*models.DeliveryTouchPoint, 0, len(datas))
+			for i := range datas {
+				expected = append(expected, &datas[i])
+			}
+			assert.ElementsMatch(t, expected, actuals)
+		}
+	})
+}
+
 // TouchPointDataRepository の Delete のテスト
 func TestTouchPointDataRepository_Delete(t *testing.T) {
 	ctx := context.Background()
@@ -0,0 +1,113 @@
+package infra
+
+import (
+	"context"
+	"strings"
+	"time"
+	"touchgift-job-manager/config"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/pkg/errors"
+)
+
+// BatchGetItemで一度に取得できる最大件数
+const dynamoDBBatchGetMaxItems = 100
+
+// dynamoDBBatchReader BatchGetItemで100件ずつ取得する
+type dynamoDBBatchReader struct {
+	dynamoDBHandler *DynamoDBHandler
+	config          *config.DynamoDB
+	tableName       *string
+	keyNames        []string
+}
+
+func newDynamoDBBatchReader(
+	handler *DynamoDBHandler,
+	config *config.DynamoDB,
+	tableName *string,
+	keyNames ...string,
+) *dynamoDBBatchReader {
+	return &dynamoDBBatchReader{
+		dynamoDBHandler: handler,
+		config:          config,
+		tableName:       tableName,
+		keyNames:        keyNames,
+	}
+}
+
+// Read キーを100件ずつに分割して取得する (存在しないキーは結果に含まれない)
+// UnprocessedKeysは書き込みと同じ設定の指数バックオフで再取得し、再取得上限を超えた場合はエラーを返す
+func (r *dynamoDBBatchReader) Read(ctx context.Context, keys []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, error) {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(keys))
+	keys = r.uniqueKeys(keys)
+	for start := 0; start < len(keys); start += dynamoDBBatchGetMaxItems {
+		end := start + dynamoDBBatchGetMaxItems
+		if end > len(keys) {
+			end = len(keys)
+		}
+		chunkItems, err := r.readChunk(ctx, keys[start:end])
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, chunkItems...)
+	}
+	return items, nil
+}
+
+// 1チャンク分(最大100件)を取得する
+func (r *dynamoDBBatchReader) readChunk(ctx context.Context, chunk []map[string]*dynamodb.AttributeValue) ([]map[string]*dynamodb.AttributeValue, error) {
+	items := make([]map[string]*dynamodb.AttributeValue, 0, len(chunk))
+	pending := chunk
+	for attempt := 0; ; attempt++ {
+		output, err := r.dynamoDBHandler.Svc.BatchGetItemWithContext(ctx, &dynamodb.BatchGetItemInput{
+			RequestItems: map[string]*dynamodb.KeysAndAttributes{
+				*r.tableName: {
+					Keys:           pending,
+					ConsistentRead: aws.Bool(true),
+				},
+			},
+		})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to batch get. table: %s", *r.tableName)
+		}
+		items = append(items, output.Responses[*r.tableName]...)
+		unprocessed := output.UnprocessedKeys[*r.tableName]
+		if unprocessed == nil || len(unprocessed.Keys) == 0 {
+			return items, nil
+		}
+		if attempt >= r.config.BatchWriteMaxRetry {
+			return nil, errors.Errorf("Failed to batch get unprocessed keys. table: %s, count: %d", *r.tableName, len(unprocessed.Keys))
+		}
+		pending = unprocessed.Keys
+		select {
+		case <-time.After(batchRetryBackoff(r.config, attempt)):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// 同一キーが同じバッチに含まれるとエラーになるため重複を除く
+func (r *dynamoDBBatchReader) uniqueKeys(keys []map[string]*dynamodb.AttributeValue) []map[string]*dynamodb.AttributeValue {
+	exists := make(map[string]struct{}, len(keys))
+	unique := make([]map[string]*dynamodb.AttributeValue, 0, len(keys))
+	for _, key := range keys {
+		values := make([]string, 0, len(r.keyNames))
+		for _, name := range r.keyNames {
+			attribute := key[name]
+			if attribute == nil {
+				values = append(values, "")
+				continue
+			}
+			values = append(values, aws.StringValue(attribute.S)+aws.StringValue(attribute.N))
+		}
+		value := strings.Join(values, "\x00")
+		if _, ok := exists[value]; ok {
+			continue
+		}
+		exists[value] = struct{}{}
+		unique = append(unique, key)
+	}
+	return unique
+}
@@ -0,0 +1,33 @@
+package infra
+
+import (
+	"testing"
+	"touchgift-job-manager/config"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBBatchReader_uniqueKeys(t *testing.T) {
+	tableName := "test"
+	reader := newDynamoDBBatchReader(nil, &config.DynamoDB{}, &tableName, "id", "group_id")
+	key := func(id, groupID string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{
+			"id":       {S: aws.String(id)},
+			"group_id": {N: aws.String(groupID)},
+		}
+	}
+
+	t.Run("同一キーは1件にまとめる", func(t *testing.T) {
+		actual := reader.uniqueKeys([]map[string]*dynamodb.AttributeValue{
+			key("1", "1"),
+			key("1", "2"),
+			key("1", "1"),
+		})
+		if assert.Len(t, actual, 2) {
+			assert.Equal(t, "1", *actual[0]["group_id"].N)
+			assert.Equal(t, "2", *actual[1]["group_id"].N)
+		}
+	})
+}
@@ -0,0 +1,235 @@
+package infra
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/pkg/errors"
+)
+
+// BatchWriteItemで一度に書き込める最大件数
+const dynamoDBBatchWriteMaxItems = 25
+
+var (
+	metricDynamodbBatchWriteTotal       = "dynamodb_batch_write_total"
+	metricDynamodbBatchWriteTotalDesc   = "batch write item count to dynamodb"
+	metricDynamodbBatchWriteTotalLabels = []string{"table_name", "operation", "kind"}
+
+	metricDynamodbBatchWriteUnprocessed       = "dynamodb_batch_write_unprocessed_total"
+	metricDynamodbBatchWriteUnprocessedDesc   = "unprocessed item count returned by dynamodb batch write"
+	metricDynamodbBatchWriteUnprocessedLabels = []string{"table_name", "operation"}
+
+	metricDynamodbBatchWriteDuration        = "dynamodb_batch_write_duration_seconds"
+	metricDynamodbBatchWriteDurationDesc    = "dynamodb batch write processing time (seconds)"
+	metricDynamodbBatchWriteDurationLabels  = []string{"table_name", "operation"}
+	metricDynamodbBatchWriteDurationBuckets = []float64{0.01, 0.05, 0.1, 0.3, 0.5, 1, 3, 5, 10}
+)
+
+// dynamoDBBatchWriter BatchWriteItemで25件ずつ並列に書き込む
+type dynamoDBBatchWriter struct {
+	logger          *Logger
+	dynamoDBHandler *DynamoDBHandler
+	monitor         *metrics.Monitor
+	config          *config.DynamoDB
+	tableName       *string
+	keyNames        []string
+}
+
+func newDynamoDBBatchWriter(
+	handler *DynamoDBHandler,
+	logger *Logger,
+	monitor *metrics.Monitor,
+	config *config.DynamoDB,
+	tableName *string,
+	keyNames ...string,
+) *dynamoDBBatchWriter {
+	monitor.Metrics.AddCounter(metricDynamodbBatchWriteTotal, metricDynamodbBatchWriteTotalDesc, metricDynamodbBatchWriteTotalLabels)
+	monitor.Metrics.AddCounter(metricDynamodbBatchWriteUnprocessed, metricDynamodbBatchWriteUnprocessedDesc, metricDynamodbBatchWriteUnprocessedLabels)
+	monitor.Metrics.AddHistogram(metricDynamodbBatchWriteDuration, metricDynamodbBatchWriteDurationDesc,
+		metricDynamodbBatchWriteDurationLabels, metricDynamodbBatchWriteDurationBuckets)
+	return &dynamoDBBatchWriter{
+		logger:          logger,
+		dynamoDBHandler: handler,
+		monitor:         monitor,
+		config:          config,
+		tableName:       tableName,
+		keyNames:        keyNames,
+	}
+}
+
+// putRequests 登録用のWriteRequestを作成する
+func putRequests(items []map[string]*dynamodb.AttributeValue) []*dynamodb.WriteRequest {
+	requests := make([]*dynamodb.WriteRequest, 0, len(items))
+	for _, item := range items {
+		requests = append(requests, &dynamodb.WriteRequest{PutRequest: &dynamodb.PutRequest{Item: item}})
+	}
+	return requests
+}
+
+// deleteRequests 削除用のWriteRequestを作成する
+func deleteRequests(keys []map[string]*dynamodb.AttributeValue) []*dynamodb.WriteRequest {
+	requests := make([]*dynamodb.WriteRequest, 0, len(keys))
+	for _, key := range keys {
+		requests = append(requests, &dynamodb.WriteRequest{DeleteRequest: &dynamodb.DeleteRequest{Key: key}})
+	}
+	return requests
+}
+
+// Write WriteRequestを25件ずつに分割して書き込む
+// UnprocessedItemsは指数バックオフで再送し、再送上限を超えた場合はエラーを返す
+func (w *dynamoDBBatchWriter) Write(ctx context.Context, operation string, requests []*dynamodb.WriteRequest) error {
+	if len(requests) == 0 {
+		return nil
+	}
+	startTime := time.Now()
+	defer func() {
+		w.monitor.Metrics.GetHistogram(metricDynamodbBatchWriteDuration).
+			WithLabelValues(*w.tableName, operation).Observe(time.Since(startTime).Seconds())
+	}()
+
+	chunks := chunkWriteRequests(w.uniqueRequests(requests), dynamoDBBatchWriteMaxItems)
+	parallelism := w.config.BatchWriteParallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	sem := make(chan struct{}, parallelism)
+	wg := sync.WaitGroup{}
+	var once sync.Once
+	var firstErr error
+	for _, chunk := range chunks {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		wg.Add(1)
+		go func(chunk []*dynamodb.WriteRequest) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if err := w.writeChunk(ctx, operation, chunk); err != nil {
+				once.Do(func() {
+					firstErr = err
+					// 1つでも失敗した場合は残りのチャンクは書き込まない
+					cancel()
+				})
+			}
+		}(chunk)
+	}
+	wg.Wait()
+	if firstErr != nil {
+		return firstErr
+	}
+	return ctx.Err()
+}
+
+// 1チャンク分(最大25件)を書き込む
+func (w *dynamoDBBatchWriter) writeChunk(ctx context.Context, operation string, chunk []*dynamodb.WriteRequest) error {
+	pending := map[string][]*dynamodb.WriteRequest{*w.tableName: chunk}
+	for attempt := 0; ; attempt++ {
+		output, err := w.dynamoDBHandler.Svc.BatchWriteItemWithContext(ctx, &dynamodb.BatchWriteItemInput{
+			RequestItems: pending,
+		})
+		if err != nil {
+			w.monitor.Metrics.GetCounter(metricDynamodbBatchWriteTotal).
+				WithLabelValues(*w.tableName, operation, "error").Add(float64(len(pending[*w.tableName])))
+			return errors.Wrapf(err, "Failed to batch write. table: %s", *w.tableName)
+		}
+		unprocessed := output.UnprocessedItems[*w.tableName]
+		w.monitor.Metrics.GetCounter(metricDynamodbBatchWriteTotal).
+			WithLabelValues(*w.tableName, operation, "success").Add(float64(len(pending[*w.tableName]) - len(unprocessed)))
+		if len(unprocessed) == 0 {
+			return nil
+		}
+		w.monitor.Metrics.GetCounter(metricDynamodbBatchWriteUnprocessed).
+			WithLabelValues(*w.tableName, operation).Add(float64(len(unprocessed)))
+		if attempt >= w.config.BatchWriteMaxRetry {
+			w.monitor.Metrics.GetCounter(metricDynamodbBatchWriteTotal).
+				WithLabelValues(*w.tableName, operation, "error").Add(float64(len(unprocessed)))
+			return errors.Errorf("Failed to batch write unprocessed items. table: %s, count: %d", *w.tableName, len(unprocessed))
+		}
+		pending = map[string][]*dynamodb.WriteRequest{*w.tableName: unprocessed}
+		select {
+		case <-time.After(w.backoff(attempt)):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// 再送までの待ち時間 (RetryBaseInterval * 2^attempt)
+func (w *dynamoDBBatchWriter) backoff(attempt int) time.Duration {
+	return batchRetryBackoff(w.config, attempt)
+}
+
+// BatchWriteItem/BatchGetItemの未処理分を再送するまでの待ち時間
+func batchRetryBackoff(config *config.DynamoDB, attempt int) time.Duration {
+	interval := config.BatchWriteRetryBaseInterval
+	for i := 0; i < attempt; i++ {
+		interval *= 2
+		if interval >= config.BatchWriteRetryMaxInterval {
+			return config.BatchWriteRetryMaxInterval
+		}
+	}
+	return interval
+}
+
+// 同一キーのリクエストが同じバッチに含まれるとエラーになるため、キーごとに最後のリクエストのみ残す
+func (w *dynamoDBBatchWriter) uniqueRequests(requests []*dynamodb.WriteRequest) []*dynamodb.WriteRequest {
+	indexes := make(map[string]int, len(requests))
+	unique := make([]*dynamodb.WriteRequest, 0, len(requests))
+	for _, request := range requests {
+		key := w.requestKey(request)
+		if i, ok := indexes[key]; ok {
+			unique[i] = request
+			continue
+		}
+		indexes[key] = len(unique)
+		unique = append(unique, request)
+	}
+	return unique
+}
+
+func (w *dynamoDBBatchWriter) requestKey(request *dynamodb.WriteRequest) string {
+	var attributes map[string]*dynamodb.AttributeValue
+	if request.PutRequest != nil {
+		attributes = request.PutRequest.Item
+	} else if request.DeleteRequest != nil {
+		attributes = request.DeleteRequest.Key
+	}
+	values := make([]string, 0, len(w.keyNames))
+	for _, name := range w.keyNames {
+		attribute := attributes[name]
+		if attribute == nil {
+			values = append(values, "")
+			continue
+		}
+		values = append(values, aws.StringValue(attribute.S)+aws.StringValue(attribute.N))
+	}
+	return strings.Join(values, "\x00")
+}
+
+// WriteRequestをsize件ずつに分割する
+func chunkWriteRequests(requests []*dynamodb.WriteRequest, size int) [][]*dynamodb.WriteRequest {
+	chunks := make([][]*dynamodb.WriteRequest, 0, (len(requests)+size-1)/size)
+	for start := 0; start < len(requests); start += size {
+		end := start + size
+		if end > len(requests) {
+			end = len(requests)
+		}
+		chunks = append(chunks, requests[start:end])
+	}
+	return chunks
+}
@@ -0,0 +1,75 @@
+package infra
+
+import (
+	"testing"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDynamoDBBatchWriter_chunkWriteRequests(t *testing.T) {
+	createRequests := func(n int) []*dynamodb.WriteRequest {
+		requests := make([]*dynamodb.WriteRequest, 0, n)
+		for i := 0; i < n; i++ {
+			requests = append(requests, &dynamodb.WriteRequest{})
+		}
+		return requests
+	}
+
+	t.Run("25件ずつに分割する", func(t *testing.T) {
+		chunks := chunkWriteRequests(createRequests(60), dynamoDBBatchWriteMaxItems)
+		if assert.Len(t, chunks, 3) {
+			assert.Len(t, chunks[0], 25)
+			assert.Len(t, chunks[1], 25)
+			assert.Len(t, chunks[2], 10)
+		}
+	})
+
+	t.Run("空の場合は分割しない", func(t *testing.T) {
+		assert.Empty(t, chunkWriteRequests(createRequests(0), dynamoDBBatchWriteMaxItems))
+	})
+}
+
+func TestDynamoDBBatchWriter_uniqueRequests(t *testing.T) {
+	tableName := "test"
+	writer := newDynamoDBBatchWriter(nil, GetLogger(), metrics.GetMonitor(), &config.DynamoDB{}, &tableName, "id", "group_id")
+	item := func(id, groupID, storeID string) map[string]*dynamodb.AttributeValue {
+		return map[string]*dynamodb.AttributeValue{
+			"id":       {S: aws.String(id)},
+			"group_id": {N: aws.String(groupID)},
+			"store_id": {S: aws.String(storeID)},
+		}
+	}
+
+	t.Run("同一キーのリクエストは後勝ちで1件にまとめる", func(t *testing.T) {
+		requests := putRequests([]map[string]*dynamodb.AttributeValue{
+			item("1", "1", "old"),
+			item("1", "2", "store"),
+			item("1", "1", "new"),
+		})
+		actual := writer.uniqueRequests(requests)
+		if assert.Len(t, actual, 2) {
+			assert.Equal(t, "new", *actual[0].PutRequest.Item["store_id"].S)
+			assert.Equal(t, "2", *actual[1].PutRequest.Item["group_id"].N)
+		}
+	})
+}
+
+func TestDynamoDBBatchWriter_backoff(t *testing.T) {
+	tableName := "test"
+	writer := newDynamoDBBatchWriter(nil, GetLogger(), metrics.GetMonitor(), &config.DynamoDB{
+		BatchWriteRetryBaseInterval: 50 * time.Millisecond,
+		BatchWriteRetryMaxInterval:  time.Second,
+	}, &tableName, "id")
+
+	t.Run("再送回数ごとに倍にして上限で止める", func(t *testing.T) {
+		assert.Equal(t, 50*time.Millisecond, writer.backoff(0))
+		assert.Equal(t, 100*time.Millisecond, writer.backoff(1))
+		assert.Equal(t, 400*time.Millisecond, writer.backoff(3))
+		assert.Equal(t, time.Second, writer.backoff(5))
+	})
+}
@@ -0,0 +1,127 @@
+package infra
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DynamoDBLeaseRepository DynamoDBの条件付き書き込みを使ったリース
+// item: name(HASH), owner, expires_at(ミリ秒), ttl(秒)
+type DynamoDBLeaseRepository struct {
+	logger          *Logger
+	dynamoDBHandler *DynamoDBHandler
+	tableName       *string
+}
+
+// NewDynamoDBLeaseRepository is function
+func NewDynamoDBLeaseRepository(handler *DynamoDBHandler, logger *Logger) repository.LeaseRepository {
+	tableName := config.Env.DynamoDB.LeaseTableName
+	if len(config.Env.DynamoDB.TableNamePrefix) > 0 {
+		// CIやローカル用
+		tableName = config.Env.DynamoDB.TableNamePrefix + tableName
+	}
+	return &DynamoDBLeaseRepository{
+		logger:          logger,
+		dynamoDBHandler: handler,
+		tableName:       &tableName,
+	}
+}
+
+// Acquire リースが存在しない/期限切れ/自分が保持している場合に取得する
+func (r *DynamoDBLeaseRepository) Acquire(ctx context.Context, name string, owner string, duration time.Duration) (bool, error) {
+	now := time.Now()
+	expiresAt := now.Add(duration)
+	_, err := r.dynamoDBHandler.Svc.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: r.tableName,
+		Item: map[string]*dynamodb.AttributeValue{
+			"name":       {S: aws.String(name)},
+			"owner":      {S: aws.String(owner)},
+			"expires_at": {N: aws.String(strconv.FormatInt(expiresAt.UnixMilli(), 10))},
+			"ttl":        {N: aws.String(strconv.FormatInt(expiresAt.Add(time.Hour).Unix(), 10))},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#name":       aws.String("name"),
+			"#owner":      aws.String("owner"),
+			"#expires_at": aws.String("expires_at"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner": {S: aws.String(owner)},
+			":now":   {N: aws.String(strconv.FormatInt(now.UnixMilli(), 10))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#name) OR #expires_at < :now OR #owner = :owner"),
+		ReturnValues:        aws.String("NONE"),
+	})
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Renew 自分が保持しているリースの期限を延長する
+func (r *DynamoDBLeaseRepository) Renew(ctx context.Context, name string, owner string, duration time.Duration) (bool, error) {
+	expiresAt := time.Now().Add(duration)
+	_, err := r.dynamoDBHandler.Svc.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: r.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"name": {S: aws.String(name)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#owner":      aws.String("owner"),
+			"#expires_at": aws.String("expires_at"),
+			"#ttl":        aws.String("ttl"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner":      {S: aws.String(owner)},
+			":expires_at": {N: aws.String(strconv.FormatInt(expiresAt.UnixMilli(), 10))},
+			":ttl":        {N: aws.String(strconv.FormatInt(expiresAt.Add(time.Hour).Unix(), 10))},
+		},
+		UpdateExpression:    aws.String("SET #expires_at = :expires_at, #ttl = :ttl"),
+		ConditionExpression: aws.String("#owner = :owner"),
+		ReturnValues:        aws.String("NONE"),
+	})
+	if err != nil {
+		if isConditionalCheckFailed(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// Release 自分が保持しているリースを削除する
+func (r *DynamoDBLeaseRepository) Release(ctx context.Context, name string, owner string) error {
+	_, err := r.dynamoDBHandler.Svc.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: r.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"name": {S: aws.String(name)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#owner": aws.String("owner"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":owner": {S: aws.String(owner)},
+		},
+		ConditionExpression: aws.String("#owner = :owner"),
+	})
+	if err != nil && !isConditionalCheckFailed(err) {
+		return err
+	}
+	return nil
+}
+
+func isConditionalCheckFailed(err error) bool {
+	if awserr, ok := err.(awserr.RequestFailure); ok && awserr.Code() == "ConditionalCheckFailedException" {
+		return true
+	}
+	return false
+}
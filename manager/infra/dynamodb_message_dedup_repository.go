@@ -0,0 +1,117 @@
+package infra
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/dynamodb"
+)
+
+// DynamoDBMessageDedupRepository SQSメッセージの処理状態をDynamoDBに保存する
+// item: dedup_key(HASH), status, expires_at(ミリ秒), ttl(秒)
+type DynamoDBMessageDedupRepository struct {
+	logger          *Logger
+	dynamoDBHandler *DynamoDBHandler
+	tableName       *string
+}
+
+// NewDynamoDBMessageDedupRepository is function
+func NewDynamoDBMessageDedupRepository(handler *DynamoDBHandler, logger *Logger) repository.MessageDedupRepository {
+	tableName := config.Env.DynamoDB.MessageDedupTableName
+	if len(config.Env.DynamoDB.TableNamePrefix) > 0 {
+		// CIやローカル用
+		tableName = config.Env.DynamoDB.TableNamePrefix + tableName
+	}
+	return &DynamoDBMessageDedupRepository{
+		logger:          logger,
+		dynamoDBHandler: handler,
+		tableName:       &tableName,
+	}
+}
+
+// Claim 未登録または期限切れの場合のみ処理中として登録する
+func (r *DynamoDBMessageDedupRepository) Claim(ctx context.Context, key string, now time.Time, expiresAt time.Time) (string, error) {
+	_, err := r.dynamoDBHandler.Svc.PutItemWithContext(ctx, &dynamodb.PutItemInput{
+		TableName: r.tableName,
+		Item: map[string]*dynamodb.AttributeValue{
+			"dedup_key":  {S: aws.String(key)},
+			"status":     {S: aws.String(codes.MessageDedupInProgress)},
+			"expires_at": {N: aws.String(strconv.FormatInt(expiresAt.UnixMilli(), 10))},
+			"ttl":        {N: aws.String(strconv.FormatInt(expiresAt.Unix(), 10))},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#dedup_key":  aws.String("dedup_key"),
+			"#expires_at": aws.String("expires_at"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":now": {N: aws.String(strconv.FormatInt(now.UnixMilli(), 10))},
+		},
+		ConditionExpression: aws.String("attribute_not_exists(#dedup_key) OR #expires_at < :now"),
+		ReturnValues:        aws.String("NONE"),
+	})
+	if err == nil {
+		return codes.MessageDedupNew, nil
+	}
+	if !isConditionalCheckFailed(err) {
+		return "", err
+	}
+	result, err := r.dynamoDBHandler.Svc.GetItemWithContext(ctx, &dynamodb.GetItemInput{
+		TableName: r.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"dedup_key": {S: aws.String(key)},
+		},
+		ConsistentRead: aws.Bool(true),
+	})
+	if err != nil {
+		return "", err
+	}
+	if result.Item == nil || result.Item["status"] == nil {
+		// 登録後すぐに削除された場合
+		return "", codes.ErrNoData
+	}
+	return aws.StringValue(result.Item["status"].S), nil
+}
+
+// Complete 処理済みにする
+func (r *DynamoDBMessageDedupRepository) Complete(ctx context.Context, key string, expiresAt time.Time) error {
+	_, err := r.dynamoDBHandler.Svc.UpdateItemWithContext(ctx, &dynamodb.UpdateItemInput{
+		TableName: r.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"dedup_key": {S: aws.String(key)},
+		},
+		ExpressionAttributeNames: map[string]*string{
+			"#status":     aws.String("status"),
+			"#expires_at": aws.String("expires_at"),
+			"#ttl":        aws.String("ttl"),
+		},
+		ExpressionAttributeValues: map[string]*dynamodb.AttributeValue{
+			":status":     {S: aws.String(codes.MessageDedupDone)},
+			":expires_at": {N: aws.String(strconv.FormatInt(expiresAt.UnixMilli(), 10))},
+			":ttl":        {N: aws.String(strconv.FormatInt(expiresAt.Unix(), 10))},
+		},
+		UpdateExpression: aws.String("SET #status = :status, #expires_at = :expires_at, #ttl = :ttl"),
+		ReturnValues:     aws.String("NONE"),
+	})
+	return err
+}
+
+// Release 登録を削除する
+func (r *DynamoDBMessageDedupRepository) Release(ctx context.Context, key string) error {
+	_, err := r.dynamoDBHandler.Svc.DeleteItemWithContext(ctx, &dynamodb.DeleteItemInput{
+		TableName: r.tableName,
+		Key: map[string]*dynamodb.AttributeValue{
+			"dedup_key": {S: aws.String(key)},
+		},
+	})
+	return err
+}
+
+// DeleteExpired DynamoDBのTTLで削除されるため何もしない
+func (r *DynamoDBMessageDedupRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,327 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+	"github.com/pkg/errors"
+)
+
+// メッセージがない場合に再度受信(スプールの読み込み)を行うまでの最大間隔
+const localQueuePollInterval = time.Second
+
+// LocalQueueHandler SQSの代わりにプロセス内で動作するキュー (ローカル実行・テスト用)
+// SQSと同様にSNSメッセージを受信し、削除されるまでは可視性タイムアウト後に再受信する
+type LocalQueueHandler interface {
+	SQSHandler
+	// Send 本文(SNSメッセージ)をキューに追加する
+	Send(ctx context.Context, body string) error
+	// Publish メッセージをSNSメッセージに包んでキューに追加する
+	Publish(ctx context.Context, message string) error
+}
+
+type localQueueHandler struct {
+	logger                   *Logger
+	queueURL                 *string
+	visibilityTimeoutSeconds *int64
+	waitTimeSeconds          *int64
+	monitor                  *metrics.Monitor
+	deadLetterSink           DeadLetterSink
+	spoolDir                 string // 空の場合はメモリのみで保持する
+	mutex                    sync.Mutex
+	entries                  []*localQueueEntry
+	loaded                   map[string]struct{} // 読み込み済みのスプールファイル
+	notify                   chan struct{}
+	sequence                 int64
+}
+
+type localQueueEntry struct {
+	messageID     string
+	body          string
+	file          string
+	receiptHandle string
+	receiveCount  int
+	visibleAt     time.Time
+	inFlight      bool // 削除または処理を諦めるまで可視性タイムアウトを延長している状態
+}
+
+// NewLocalQueueHandler spoolDirを指定した場合はディレクトリ内のファイル(*.json)を1メッセージとして受信し、削除時にファイルも削除する
+func NewLocalQueueHandler(
+	logger *Logger,
+	queueURL *string,
+	visibilityTimeoutSeconds *int64,
+	waitTimeSeconds *int64,
+	monitor *metrics.Monitor,
+	deadLetterSink DeadLetterSink,
+	spoolDir string,
+) LocalQueueHandler {
+	monitor.Metrics.AddCounter(metricSqsReceivedMessageTotal, metricSqsReceivedMessageTotalDesc, metricSqsReceivedMessageTotalLabels)
+	monitor.Metrics.AddCounter(metricSqsUnprocessableMessageTotal, metricSqsUnprocessableMessageTotalDesc, metricSqsUnprocessableMessageTotalLabels)
+	monitor.Metrics.AddCounter(metricSqsDeletedMessageTotal, metricSqsDeletedMessageTotalDesc, metricSqsDeletedMessageTotalLabels)
+	monitor.Metrics.AddCounter(metricSqsDeadLetterTotal, metricSqsDeadLetterTotalDesc, metricSqsDeadLetterTotalLabels)
+	return &localQueueHandler{
+		logger:                   logger,
+		queueURL:                 queueURL,
+		visibilityTimeoutSeconds: visibilityTimeoutSeconds,
+		waitTimeSeconds:          waitTimeSeconds,
+		monitor:                  monitor,
+		deadLetterSink:           deadLetterSink,
+		spoolDir:                 spoolDir,
+		loaded:                   map[string]struct{}{},
+		notify:                   make(chan struct{}, 1),
+	}
+}
+
+// LocalQueueSpoolDir キューごとのスプールのディレクトリ (キューURLの最後のパスをディレクトリ名にする)
+func LocalQueueSpoolDir(spoolDir string, queueURL string) string {
+	return filepath.Join(spoolDir, path.Base(queueURL))
+}
+
+func (l *localQueueHandler) Poll(ctx context.Context, wg *sync.WaitGroup, ch chan QueueMessage, sqsMaxMessages int64) {
+	defer func() {
+		wg.Done()
+	}()
+	wg.Add(1)
+	l.logger.Info().Str("queue_url", *l.queueURL).Str("spool_dir", l.spoolDir).Msg("Start local queue polling")
+	for {
+		messages := l.receive(sqsMaxMessages)
+		if len(messages) == 0 {
+			// SQSのロングポーリングと同様に、メッセージが追加されるまで待つ
+			timer := time.NewTimer(l.pollInterval())
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				l.logger.Info().Str("queue_url", *l.queueURL).Msg("Stop local queue polling")
+				return
+			case <-l.notify:
+			case <-timer.C:
+			}
+			timer.Stop()
+			continue
+		}
+		for _, message := range messages {
+			var snsMessage SnsMessage
+			decoder := json.NewDecoder(strings.NewReader(*message.Body))
+			if err := decoder.Decode(&snsMessage); err != nil {
+				l.logger.Error().Err(err).Str("queue_url", *l.queueURL).Str("body", *message.Body).Msg("Failed to parse sns message")
+				queueMessage := NewMessage(message, &SnsMessage{})
+				if err := l.putDeadLetter(ctx, newDeadLetter(*l.queueURL, queueMessage, err)); err != nil {
+					l.logger.Error().Err(err).Str("queue_url", *l.queueURL).Str("message_id", *message.MessageId).Msg("Failed to put dead letter")
+				}
+				l.DeleteMessage(ctx, queueMessage)
+			} else {
+				select {
+				case ch <- NewMessage(message, &snsMessage):
+				case <-ctx.Done():
+					l.Abandon(NewMessage(message, &snsMessage))
+				}
+			}
+			l.monitor.Metrics.GetCounter(metricSqsReceivedMessageTotal).WithLabelValues(*l.queueURL).Inc()
+		}
+		if ctx.Err() != nil {
+			l.logger.Info().Str("queue_url", *l.queueURL).Msg("Stop local queue polling")
+			return
+		}
+	}
+}
+
+func (l *localQueueHandler) pollInterval() time.Duration {
+	wait := time.Duration(*l.waitTimeSeconds) * time.Second
+	if wait <= 0 || wait > localQueuePollInterval {
+		return localQueuePollInterval
+	}
+	return wait
+}
+
+// 受信可能なメッセージを最大max件受信する
+func (l *localQueueHandler) receive(max int64) []*sqs.Message {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.spoolDir != "" {
+		if err := l.loadSpool(); err != nil {
+			l.logger.Error().Err(err).Str("queue_url", *l.queueURL).Str("spool_dir", l.spoolDir).Msg("Failed to load spool")
+		}
+	}
+	now := time.Now()
+	messages := make([]*sqs.Message, 0, max)
+	for _, entry := range l.entries {
+		if int64(len(messages)) >= max {
+			break
+		}
+		if entry.inFlight || now.Before(entry.visibleAt) {
+			continue
+		}
+		l.sequence++
+		entry.receiveCount++
+		entry.receiptHandle = fmt.Sprintf("%s-%d", entry.messageID, l.sequence)
+		entry.visibleAt = now.Add(time.Duration(*l.visibilityTimeoutSeconds) * time.Second)
+		// SQSでは可視性タイムアウトの延長を行うため、処理中は再受信しない
+		entry.inFlight = config.Env.SQS.VisibilityHeartbeatEnabled
+		messages = append(messages, &sqs.Message{
+			MessageId:     aws.String(entry.messageID),
+			ReceiptHandle: aws.String(entry.receiptHandle),
+			Body:          aws.String(entry.body),
+			Attributes: map[string]*string{
+				sqs.MessageSystemAttributeNameApproximateReceiveCount: aws.String(strconv.Itoa(entry.receiveCount)),
+			},
+		})
+	}
+	return messages
+}
+
+// スプールのディレクトリから未読み込みのファイルをファイル名順に読み込む
+func (l *localQueueHandler) loadSpool() error {
+	files, err := os.ReadDir(l.spoolDir)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	names := make([]string, 0, len(files))
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		if _, ok := l.loaded[file.Name()]; ok {
+			continue
+		}
+		names = append(names, file.Name())
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		body, err := os.ReadFile(filepath.Join(l.spoolDir, name))
+		if err != nil {
+			return err
+		}
+		l.loaded[name] = struct{}{}
+		l.entries = append(l.entries, &localQueueEntry{
+			messageID: strings.TrimSuffix(name, ".json"),
+			body:      string(body),
+			file:      name,
+		})
+	}
+	return nil
+}
+
+func (l *localQueueHandler) Send(ctx context.Context, body string) error {
+	l.mutex.Lock()
+	l.sequence++
+	messageID := fmt.Sprintf("%d-%06d", time.Now().UnixNano(), l.sequence)
+	if l.spoolDir == "" {
+		l.entries = append(l.entries, &localQueueEntry{messageID: messageID, body: body})
+		l.mutex.Unlock()
+	} else {
+		l.mutex.Unlock()
+		// 読み込み途中のファイルを受信しないよう、一時ファイルに書き込んでからリネームする
+		if err := os.MkdirAll(l.spoolDir, 0o755); err != nil {
+			return errors.Wrap(err, "Failed to create spool dir")
+		}
+		file := filepath.Join(l.spoolDir, messageID+".json")
+		if err := os.WriteFile(file+".tmp", []byte(body), 0o644); err != nil {
+			return errors.Wrap(err, "Failed to write spool file")
+		}
+		if err := os.Rename(file+".tmp", file); err != nil {
+			return errors.Wrap(err, "Failed to write spool file")
+		}
+	}
+	select {
+	case l.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+func (l *localQueueHandler) Publish(ctx context.Context, message string) error {
+	l.mutex.Lock()
+	l.sequence++
+	sequence := l.sequence
+	l.mutex.Unlock()
+	body, err := json.Marshal(&SnsMessage{
+		Type:      "Notification",
+		MessageID: fmt.Sprintf("local-%d-%06d", time.Now().UnixNano(), sequence),
+		TopicArn:  "local",
+		Message:   message,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return err
+	}
+	return l.Send(ctx, string(body))
+}
+
+func (l *localQueueHandler) UnprocessableMessage() {
+	l.monitor.Metrics.GetCounter(metricSqsUnprocessableMessageTotal).WithLabelValues(*l.queueURL).Inc()
+}
+
+func (l *localQueueHandler) OutputDeleteCliLog(message QueueMessage) {
+	l.logger.Warn().Str("queue_url", *l.queueURL).Str("message_id", *message.MessageID()).Str("spool_dir", l.spoolDir).
+		Msg(`If you want to delete, remove the spool file or restart`)
+}
+
+func (l *localQueueHandler) DeleteMessage(ctx context.Context, message QueueMessage) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for i, entry := range l.entries {
+		if entry.receiptHandle != *message.ReceiptHandle() {
+			continue
+		}
+		if entry.file != "" {
+			if err := os.Remove(filepath.Join(l.spoolDir, entry.file)); err != nil && !os.IsNotExist(err) {
+				l.logger.Error().Err(err).Str("message_id", entry.messageID).Str("file", entry.file).Msg("Failed to delete message.")
+				return
+			}
+			delete(l.loaded, entry.file)
+		}
+		l.entries = append(l.entries[:i], l.entries[i+1:]...)
+		l.monitor.Metrics.GetCounter(metricSqsDeletedMessageTotal).WithLabelValues(*l.queueURL).Inc()
+		return
+	}
+	// 再受信されて受信ハンドルが変わっている場合
+	l.logger.Error().Str("message_id", *message.MessageID()).Str("receipt_handle", *message.ReceiptHandle()).Msg("Failed to delete message.")
+}
+
+func (l *localQueueHandler) Abandon(message QueueMessage) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	for _, entry := range l.entries {
+		if entry.receiptHandle == *message.ReceiptHandle() && entry.inFlight {
+			// 延長を止めた時点から可視性タイムアウト後に再受信する
+			entry.inFlight = false
+			entry.visibleAt = time.Now().Add(time.Duration(*l.visibilityTimeoutSeconds) * time.Second)
+			return
+		}
+	}
+}
+
+func (l *localQueueHandler) DeadLetter(ctx context.Context, message QueueMessage, cause error) error {
+	if err := l.putDeadLetter(ctx, newDeadLetter(*l.queueURL, message, cause)); err != nil {
+		return err
+	}
+	l.DeleteMessage(ctx, message)
+	return nil
+}
+
+func (l *localQueueHandler) putDeadLetter(ctx context.Context, deadLetter *models.DeadLetter) error {
+	if err := l.deadLetterSink.Put(ctx, deadLetter); err != nil {
+		l.monitor.Metrics.GetCounter(metricSqsDeadLetterTotal).WithLabelValues(*l.queueURL, "error").Inc()
+		return err
+	}
+	l.monitor.Metrics.GetCounter(metricSqsDeadLetterTotal).WithLabelValues(*l.queueURL, "success").Inc()
+	l.logger.Warn().Str("queue_url", *l.queueURL).Str("message_id", deadLetter.MessageID).Int("attempts", deadLetter.Attempts).Str("error", deadLetter.Error).Msg("Put dead letter")
+	return nil
+}
@@ -0,0 +1,106 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLocalQueueHandler_Poll(t *testing.T) {
+	logger := GetLogger()
+	monitor := metrics.GetMonitor()
+	queueURL := "http://localhost:4566/000000000000/touchgift-delivery-operation"
+	waitTimeSeconds := int64(1)
+
+	receive := func(t *testing.T, ch chan QueueMessage) QueueMessage {
+		select {
+		case message := <-ch:
+			return message
+		case <-time.After(3 * time.Second):
+			t.Fatal("message is not received")
+			return nil
+		}
+	}
+
+	t.Run("SNSメッセージに包んだメッセージを受信し、削除するまで再受信する", func(t *testing.T) {
+		visibilityTimeoutSeconds := int64(0)
+		handler := NewLocalQueueHandler(logger, &queueURL, &visibilityTimeoutSeconds, &waitTimeSeconds, monitor,
+			NewFileDeadLetterSink(logger, filepath.Join(t.TempDir(), "dead_letter.ndjson")), "")
+		ctx, cancel := context.WithCancel(context.Background())
+		wg := sync.WaitGroup{}
+		ch := make(chan QueueMessage, 1)
+		go handler.Poll(ctx, &wg, ch, 10)
+
+		assert.NoError(t, handler.Publish(ctx, `{"type":"delivery_operation"}`))
+		message := receive(t, ch)
+		assert.Equal(t, `{"type":"delivery_operation"}`, *message.Message())
+		assert.NotEmpty(t, *message.SnsMessageID())
+		assert.Equal(t, 1, message.ReceiveCount())
+
+		// 処理を諦めた場合は可視性タイムアウト後に再受信する
+		handler.Abandon(message)
+		message = receive(t, ch)
+		assert.Equal(t, 2, message.ReceiveCount())
+
+		handler.DeleteMessage(ctx, message)
+		select {
+		case message := <-ch:
+			t.Fatalf("deleted message is received. %s", *message.MessageID())
+		case <-time.After(1500 * time.Millisecond):
+		}
+		cancel()
+		wg.Wait()
+	})
+
+	t.Run("スプールのファイルを受信し、削除時にファイルも削除する", func(t *testing.T) {
+		visibilityTimeoutSeconds := int64(60)
+		spoolDir := t.TempDir()
+		deadLetterPath := filepath.Join(t.TempDir(), "dead_letter.ndjson")
+		handler := NewLocalQueueHandler(logger, &queueURL, &visibilityTimeoutSeconds, &waitTimeSeconds, monitor,
+			NewFileDeadLetterSink(logger, deadLetterPath), spoolDir)
+
+		body, _ := json.Marshal(&SnsMessage{MessageID: "sns1", Message: `{"type":"delivery_operation"}`})
+		assert.NoError(t, os.WriteFile(filepath.Join(spoolDir, "0001.json"), body, 0o644))
+		assert.NoError(t, os.WriteFile(filepath.Join(spoolDir, "0002.json"), []byte("invalid"), 0o644))
+
+		ctx, cancel := context.WithCancel(context.Background())
+		wg := sync.WaitGroup{}
+		ch := make(chan QueueMessage, 1)
+		go handler.Poll(ctx, &wg, ch, 10)
+
+		message := receive(t, ch)
+		assert.Equal(t, "0001", *message.MessageID())
+		assert.Equal(t, "sns1", *message.SnsMessageID())
+		handler.DeleteMessage(ctx, message)
+		_, err := os.Stat(filepath.Join(spoolDir, "0001.json"))
+		assert.True(t, os.IsNotExist(err))
+
+		// SNSメッセージとしてパースできないファイルはDeadLetterに移して削除する
+		assert.Eventually(t, func() bool {
+			_, err := os.Stat(filepath.Join(spoolDir, "0002.json"))
+			return os.IsNotExist(err)
+		}, 3*time.Second, 50*time.Millisecond)
+		deadLetter, err := os.ReadFile(deadLetterPath)
+		assert.NoError(t, err)
+		var letter models.DeadLetter
+		assert.NoError(t, json.Unmarshal(deadLetter, &letter))
+		assert.Equal(t, "0002", letter.MessageID)
+		assert.Equal(t, "invalid", letter.Body)
+
+		// Sendで追加したメッセージもファイルとして保存する
+		assert.NoError(t, handler.Send(ctx, string(body)))
+		message = receive(t, ch)
+		_, err = os.Stat(filepath.Join(spoolDir, *message.MessageID()+".json"))
+		assert.NoError(t, err)
+		cancel()
+		wg.Wait()
+	})
+}
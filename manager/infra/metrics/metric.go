@@ -5,12 +5,14 @@ import "github.com/prometheus/client_golang/prometheus"
 type Metrics struct {
 	counters   map[string]*prometheus.CounterVec
 	histograms map[string]*prometheus.HistogramVec
+	gauges     map[string]*prometheus.GaugeVec
 }
 
 func NewMetrics() *Metrics {
 	return &Metrics{
 		counters:   make(map[string]*prometheus.CounterVec),
 		histograms: make(map[string]*prometheus.HistogramVec),
+		gauges:     make(map[string]*prometheus.GaugeVec),
 	}
 }
 
@@ -45,3 +47,19 @@ func (m *Metrics) AddHistogram(name string, description string, labels []string,
 		m.histograms[name] = vec
 	}
 }
+
+func (m *Metrics) GetGauge(name string) *prometheus.GaugeVec {
+	return m.gauges[name]
+}
+
+func (m *Metrics) AddGauge(name string, description string, labels []string) {
+	_, ok := m.gauges[name]
+	if !ok {
+		vec := prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{Name: name, Help: description},
+			labels,
+		)
+		prometheus.MustRegister(vec)
+		m.gauges[name] = vec
+	}
+}
@@ -0,0 +1,237 @@
+package infra
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"os"
+	"strings"
+	"sync"
+	"time"
+	"touchgift-job-manager/domain/notification"
+
+	"github.com/pkg/errors"
+)
+
+type fileNotificationHandler struct {
+	logger  *Logger
+	path    string
+	mutex   sync.Mutex
+	written map[string]struct{} // 追記済みのMessageId (初回の追記時にファイルから読み込む)
+}
+
+// NewFileNotificationHandler 1行1メッセージのJSON(NDJSON)としてファイルに追記する (ローカル実行用)
+// 再送された場合に重複して追記しないよう、追記済みのMessageIdのメッセージは追記しない
+func NewFileNotificationHandler(logger *Logger, path string) notification.NotificationHandler {
+	return &fileNotificationHandler{
+		logger: logger,
+		path:   path,
+	}
+}
+
+func (f *fileNotificationHandler) Publish(ctx context.Context, message string, messageAttributes map[string]string, topicArn string) (*string, error) {
+	envelope, err := newNotificationEnvelope(ctx, message, messageAttributes, topicArn)
+	if err != nil {
+		return nil, err
+	}
+	line, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.written == nil {
+		if f.written, err = f.readMessageIDs(); err != nil {
+			return nil, err
+		}
+	}
+	if _, ok := f.written[envelope.MessageID]; ok {
+		f.logger.Debug().Str("message_id", envelope.MessageID).Str("path", f.path).Msg("Skip already published message.")
+		return &envelope.MessageID, nil
+	}
+	file, err := os.OpenFile(f.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := file.Write(append(line, '\n')); err != nil {
+		file.Close()
+		return nil, err
+	}
+	if err := file.Close(); err != nil {
+		return nil, err
+	}
+	f.written[envelope.MessageID] = struct{}{}
+	f.logger.Debug().Str("message_id", envelope.MessageID).Str("path", f.path).Msg("Publish message to file.")
+	return &envelope.MessageID, nil
+}
+
+// 追記済みのMessageIdをファイルから読み込む (ファイルがない場合は空)
+func (f *fileNotificationHandler) readMessageIDs() (map[string]struct{}, error) {
+	written := map[string]struct{}{}
+	file, err := os.Open(f.path)
+	if os.IsNotExist(err) {
+		return written, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		var envelope notificationEnvelope
+		if err := json.Unmarshal(scanner.Bytes(), &envelope); err != nil {
+			// 書き込み途中で終了した行などは読み飛ばす
+			continue
+		}
+		written[envelope.MessageID] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, errors.Wrapf(err, "Failed to read notification file. path: %s", f.path)
+	}
+	return written, nil
+}
+
+// 一部の通知先に失敗したメッセージの送信済みの通知先を保持する期間
+// outboxの再送が続く間は保持し、送信失敗になり再送されなくなったものは破棄する
+const fanoutDeliveredRetention = 24 * time.Hour
+
+type fanoutNotificationHandler struct {
+	handlers  []notification.NotificationHandler
+	mutex     sync.Mutex
+	delivered map[string]*fanoutDelivery // key: MessageId
+}
+
+// 一部の通知先に失敗したメッセージの送信済みの通知先
+type fanoutDelivery struct {
+	messageIDs map[int]*string // key: handlersのインデックス
+	updatedAt  time.Time
+}
+
+// NewFanoutNotificationHandler 全ての通知先に送信する
+// 1つでも失敗した場合はエラーを返す
+// WithMessageIDでMessageIdが指定されている場合は、outboxから再送された時に送信済みの通知先には送信しない
+// 送信済みの通知先はメモリに保持するため、再起動やリーダーの交代をまたいだ再送では重複して送信される (受信側はMessageIdで重複を除くこと)
+func NewFanoutNotificationHandler(handlers ...notification.NotificationHandler) notification.NotificationHandler {
+	return &fanoutNotificationHandler{
+		handlers:  handlers,
+		delivered: map[string]*fanoutDelivery{},
+	}
+}
+
+func (f *fanoutNotificationHandler) Publish(ctx context.Context, message string, messageAttributes map[string]string, topicArn string) (*string, error) {
+	key, tracked := notification.MessageIDFromContext(ctx)
+	delivered := map[int]*string{}
+	if tracked {
+		f.mutex.Lock()
+		if delivery, ok := f.delivered[key]; ok {
+			for i, id := range delivery.messageIDs {
+				delivered[i] = id
+			}
+		}
+		f.mutex.Unlock()
+	}
+	var errs []error
+	for i, handler := range f.handlers {
+		if _, ok := delivered[i]; ok {
+			continue
+		}
+		id, err := handler.Publish(ctx, message, messageAttributes, topicArn)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		delivered[i] = id
+	}
+	if tracked {
+		f.record(key, delivered, len(errs) == 0)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Wrapf(errs[0], "Failed to publish to %d of %d sinks", len(errs), len(f.handlers))
+	}
+	// 最初の通知先のMessageIdを返す
+	for i := range f.handlers {
+		if id := delivered[i]; id != nil {
+			return id, nil
+		}
+	}
+	return nil, nil
+}
+
+// 送信済みの通知先を記録する (全ての通知先に送信した場合は破棄する)
+func (f *fanoutNotificationHandler) record(key string, delivered map[int]*string, completed bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if completed {
+		delete(f.delivered, key)
+		return
+	}
+	now := time.Now()
+	for k, delivery := range f.delivered {
+		if now.Sub(delivery.updatedAt) > fanoutDeliveredRetention {
+			delete(f.delivered, k)
+		}
+	}
+	f.delivered[key] = &fanoutDelivery{messageIDs: delivered, updatedAt: now}
+}
+
+type notificationRouter struct {
+	routes         map[string]notification.NotificationHandler
+	defaultHandler notification.NotificationHandler
+}
+
+// NewNotificationRouter トピックごとに通知先を切り替える
+// routesのキーはトピックのARNまたはトピック名で、一致しない場合はdefaultHandlerに送信する
+func NewNotificationRouter(
+	routes map[string]notification.NotificationHandler,
+	defaultHandler notification.NotificationHandler,
+) notification.NotificationHandler {
+	return &notificationRouter{
+		routes:         routes,
+		defaultHandler: defaultHandler,
+	}
+}
+
+func (n *notificationRouter) Publish(ctx context.Context, message string, messageAttributes map[string]string, topicArn string) (*string, error) {
+	if handler, ok := n.routes[topicArn]; ok {
+		return handler.Publish(ctx, message, messageAttributes, topicArn)
+	}
+	if handler, ok := n.routes[topicName(topicArn)]; ok {
+		return handler.Publish(ctx, message, messageAttributes, topicArn)
+	}
+	return n.defaultHandler.Publish(ctx, message, messageAttributes, topicArn)
+}
+
+// arn:aws:sns:region:account:name -> name
+func topicName(topicArn string) string {
+	return topicArn[strings.LastIndex(topicArn, ":")+1:]
+}
+
+// ParseNotificationSinks カンマ区切りの通知先を分割する
+func ParseNotificationSinks(sinks string) []string {
+	names := []string{}
+	for _, name := range strings.Split(sinks, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// ParseNotificationRoutes "topic=sink,sink;topic=sink" 形式のルーティングを解析する
+func ParseNotificationRoutes(routes string) (map[string][]string, error) {
+	parsed := map[string][]string{}
+	for _, route := range strings.Split(routes, ";") {
+		if route = strings.TrimSpace(route); route == "" {
+			continue
+		}
+		topic, sinks, ok := strings.Cut(route, "=")
+		topic = strings.TrimSpace(topic)
+		names := ParseNotificationSinks(sinks)
+		if !ok || topic == "" || len(names) == 0 {
+			return nil, errors.Errorf("Invalid notification route: %s", route)
+		}
+		parsed[topic] = names
+	}
+	return parsed, nil
+}
@@ -0,0 +1,216 @@
+package infra
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/notification"
+	"touchgift-job-manager/infra/metrics"
+	mock_notification "touchgift-job-manager/mock/notification"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWebhookNotificationHandler_Publish(t *testing.T) {
+	logger := GetLogger()
+	monitor := metrics.GetMonitor()
+	webhookConfig := &config.Notification{
+		WebhookSecret:  "secret",
+		WebhookTimeout: time.Second,
+	}
+	topicArn := "arn:aws:sns:ap-northeast-1:000000000000:touchgift-server-delivery-cache-local"
+
+	t.Run("署名付きでSNSメッセージと同じ形式の本文を送信する", func(t *testing.T) {
+		var body []byte
+		var header http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			header = r.Header
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		handler := NewWebhookNotificationHandler(logger, monitor, webhookConfig, server.URL)
+		messageID, err := handler.Publish(context.Background(), `{"id":1}`, map[string]string{"org_code": "org"}, topicArn)
+		assert.NoError(t, err)
+
+		var snsMessage SnsMessage
+		assert.NoError(t, json.Unmarshal(body, &snsMessage))
+		assert.Equal(t, *messageID, snsMessage.MessageID)
+		assert.Equal(t, `{"id":1}`, snsMessage.Message)
+		assert.Equal(t, topicArn, snsMessage.TopicArn)
+		assert.Contains(t, string(body), `"MessageAttributes":{"org_code":{"Type":"String","Value":"org"}}`)
+		assert.Equal(t, *messageID, header.Get(WebhookHeaderMessageID))
+		assert.Equal(t, "sha256="+SignWebhook("secret", header.Get(WebhookHeaderTimestamp), body), header.Get(WebhookHeaderSignature))
+	})
+
+	t.Run("指定されたMessageIdで送信する", func(t *testing.T) {
+		var body []byte
+		var header http.Header
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ = io.ReadAll(r.Body)
+			header = r.Header
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		handler := NewWebhookNotificationHandler(logger, monitor, webhookConfig, server.URL)
+		ctx := notification.WithMessageID(context.Background(), notification.OutboxMessageID(10))
+		messageID, err := handler.Publish(ctx, `{"id":1}`, nil, topicArn)
+		assert.NoError(t, err)
+		assert.Equal(t, "outbox-10", *messageID)
+
+		var snsMessage SnsMessage
+		assert.NoError(t, json.Unmarshal(body, &snsMessage))
+		assert.Equal(t, "outbox-10", snsMessage.MessageID)
+		assert.Equal(t, "outbox-10", header.Get(WebhookHeaderMessageID))
+	})
+
+	t.Run("5xxの場合は再送せずにエラーを返す", func(t *testing.T) {
+		var count int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&count, 1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		handler := NewWebhookNotificationHandler(logger, monitor, webhookConfig, server.URL)
+		_, err := handler.Publish(context.Background(), `{"id":1}`, nil, topicArn)
+		assert.Error(t, err)
+		// 再送はoutboxで行う
+		assert.Equal(t, int32(1), atomic.LoadInt32(&count))
+	})
+}
+
+func TestFileNotificationHandler_Publish(t *testing.T) {
+	logger := GetLogger()
+
+	t.Run("NDJSONとしてファイルに追記する", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notification.ndjson")
+		handler := NewFileNotificationHandler(logger, path)
+		_, err := handler.Publish(context.Background(), `{"id":1}`, nil, "arn:aws:sns:ap-northeast-1:000000000000:topic")
+		assert.NoError(t, err)
+		_, err = handler.Publish(context.Background(), `{"id":2}`, nil, "arn:aws:sns:ap-northeast-1:000000000000:topic")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+		assert.Len(t, lines, 2)
+		var snsMessage SnsMessage
+		assert.NoError(t, json.Unmarshal([]byte(lines[1]), &snsMessage))
+		assert.Equal(t, `{"id":2}`, snsMessage.Message)
+	})
+
+	t.Run("追記済みのMessageIdのメッセージは追記しない", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "notification.ndjson")
+		ctx := notification.WithMessageID(context.Background(), notification.OutboxMessageID(10))
+		handler := NewFileNotificationHandler(logger, path)
+		_, err := handler.Publish(ctx, `{"id":1}`, nil, "arn:aws:sns:ap-northeast-1:000000000000:topic")
+		assert.NoError(t, err)
+		messageID, err := handler.Publish(ctx, `{"id":1}`, nil, "arn:aws:sns:ap-northeast-1:000000000000:topic")
+		assert.NoError(t, err)
+		assert.Equal(t, "outbox-10", *messageID)
+		// 再起動後もファイルに追記済みのものは追記しない
+		_, err = NewFileNotificationHandler(logger, path).Publish(ctx, `{"id":1}`, nil, "arn:aws:sns:ap-northeast-1:000000000000:topic")
+		assert.NoError(t, err)
+
+		data, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 1)
+	})
+}
+
+func TestNotificationRouter_Publish(t *testing.T) {
+	ctx := context.Background()
+	deliveryTopic := "arn:aws:sns:ap-northeast-1:000000000000:touchgift-server-delivery-cache-local"
+	creativeTopic := "arn:aws:sns:ap-northeast-1:000000000000:touchgift-server-creative-cache-local"
+	campaignTopic := "arn:aws:sns:ap-northeast-1:000000000000:touchgift-server-campaign-cache-local"
+	messageID := "message_id"
+
+	t.Run("トピックのARNまたはトピック名に一致する通知先に送信する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sns := mock_notification.NewMockNotificationHandler(ctrl)
+		webhook := mock_notification.NewMockNotificationHandler(ctrl)
+		file := mock_notification.NewMockNotificationHandler(ctrl)
+		webhook.EXPECT().Publish(gomock.Eq(ctx), gomock.Eq("delivery"), gomock.Nil(), gomock.Eq(deliveryTopic)).Return(&messageID, nil)
+		sns.EXPECT().Publish(gomock.Eq(ctx), gomock.Eq("delivery"), gomock.Nil(), gomock.Eq(deliveryTopic)).Return(&messageID, nil)
+		file.EXPECT().Publish(gomock.Eq(ctx), gomock.Eq("creative"), gomock.Nil(), gomock.Eq(creativeTopic)).Return(&messageID, nil)
+		sns.EXPECT().Publish(gomock.Eq(ctx), gomock.Eq("campaign"), gomock.Nil(), gomock.Eq(campaignTopic)).Return(&messageID, nil)
+
+		router := NewNotificationRouter(map[string]notification.NotificationHandler{
+			"touchgift-server-delivery-cache-local": NewFanoutNotificationHandler(webhook, sns),
+			creativeTopic:                           file,
+		}, sns)
+		for message, topic := range map[string]string{"delivery": deliveryTopic, "creative": creativeTopic, "campaign": campaignTopic} {
+			id, err := router.Publish(ctx, message, nil, topic)
+			assert.NoError(t, err)
+			assert.Equal(t, &messageID, id)
+		}
+	})
+
+	t.Run("fan-outの場合は1つでも失敗したらエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		sns := mock_notification.NewMockNotificationHandler(ctrl)
+		webhook := mock_notification.NewMockNotificationHandler(ctrl)
+		webhook.EXPECT().Publish(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+		sns.EXPECT().Publish(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(&messageID, nil)
+
+		_, err := NewFanoutNotificationHandler(webhook, sns).Publish(ctx, "delivery", nil, deliveryTopic)
+		assert.Error(t, err)
+	})
+
+	t.Run("fan-outの再送時は送信済みの通知先には送信しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		ctx := notification.WithMessageID(ctx, notification.OutboxMessageID(10))
+		sns := mock_notification.NewMockNotificationHandler(ctrl)
+		webhook := mock_notification.NewMockNotificationHandler(ctrl)
+		gomock.InOrder(
+			webhook.EXPECT().Publish(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")),
+			webhook.EXPECT().Publish(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(&messageID, nil),
+		)
+		sns.EXPECT().Publish(gomock.Eq(ctx), gomock.Any(), gomock.Any(), gomock.Any()).Return(&messageID, nil)
+
+		handler := NewFanoutNotificationHandler(webhook, sns)
+		_, err := handler.Publish(ctx, "delivery", nil, deliveryTopic)
+		assert.Error(t, err)
+		id, err := handler.Publish(ctx, "delivery", nil, deliveryTopic)
+		assert.NoError(t, err)
+		assert.Equal(t, &messageID, id)
+		// 全ての通知先に送信した後は記録を破棄する
+		assert.Empty(t, handler.(*fanoutNotificationHandler).delivered)
+	})
+}
+
+func TestParseNotificationRoutes(t *testing.T) {
+	t.Run("トピックごとの通知先を解析する", func(t *testing.T) {
+		routes, err := ParseNotificationRoutes(" touchgift-server-delivery-cache=sns, webhook ; arn:aws:sns:ap-northeast-1:000000000000:creative=file;")
+		assert.NoError(t, err)
+		assert.Equal(t, map[string][]string{
+			"touchgift-server-delivery-cache":                  {"sns", "webhook"},
+			"arn:aws:sns:ap-northeast-1:000000000000:creative": {"file"},
+		}, routes)
+	})
+
+	t.Run("通知先がない場合はエラーを返す", func(t *testing.T) {
+		_, err := ParseNotificationRoutes("touchgift-server-delivery-cache=")
+		assert.Error(t, err)
+	})
+}
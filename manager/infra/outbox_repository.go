@@ -0,0 +1,267 @@
+package infra
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// OutboxRepository 送信待ちの配信制御イベントをRDBに保存する
+type OutboxRepository struct {
+	logger     *Logger
+	sqlHandler SQLHandler
+}
+
+func NewOutboxRepository(logger *Logger, sqlHandler SQLHandler) repository.OutboxRepository {
+	return &OutboxRepository{
+		logger:     logger,
+		sqlHandler: sqlHandler,
+	}
+}
+
+// Save イベントを登録する (txがnilの場合は単独で登録する)
+// HeldCampaignIDを指定した場合はReleaseHeldするまで送信しない
+func (o *OutboxRepository) Save(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) (err error) {
+	query := `INSERT INTO job_outbox (event_type, topic_arn, message, attributes, next_attempt_at, held_campaign_id)
+	VALUES (:event_type, :topic_arn, :message, :attributes, :next_attempt_at, :held_campaign_id)`
+	stmt, err := o.prepareNamed(ctx, tx, query)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := stmt.Close(); cerr != nil {
+			o.logger.Error().Err(cerr).Msg("Failed to close statement")
+		}
+	}()
+	result, err := stmt.ExecContext(ctx, map[string]interface{}{
+		"event_type":       event.EventType,
+		"topic_arn":        event.TopicArn,
+		"message":          event.Message,
+		"attributes":       event.Attributes,
+		"next_attempt_at":  event.NextAttemptAt,
+		"held_campaign_id": event.HeldCampaignID,
+	})
+	if err != nil {
+		return err
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	event.ID = id
+	return nil
+}
+
+// ReleaseHeld 配信開始まで保留したキャンペーンのイベントを送信待ちにし、件数を返す (txがnilの場合は単独で更新する)
+func (o *OutboxRepository) ReleaseHeld(ctx context.Context, tx repository.Transaction, campaignID int, nextAttemptAt time.Time) (int64, error) {
+	query := `UPDATE job_outbox
+	SET
+		held_campaign_id = NULL,
+		next_attempt_at = :next_attempt_at
+	WHERE held_campaign_id = :campaign_id`
+	return o.execNamed(ctx, tx, query, map[string]interface{}{
+		"campaign_id":     campaignID,
+		"next_attempt_at": nextAttemptAt,
+	})
+}
+
+// DeleteHeld 配信開始まで保留したキャンペーンのイベントを削除し、件数を返す (txがnilの場合は単独で削除する)
+func (o *OutboxRepository) DeleteHeld(ctx context.Context, tx repository.Transaction, campaignID int) (int64, error) {
+	query := `DELETE FROM job_outbox WHERE held_campaign_id = :campaign_id`
+	return o.execNamed(ctx, tx, query, map[string]interface{}{
+		"campaign_id": campaignID,
+	})
+}
+
+// GetPending 送信待ちのイベントを登録順にロックして取得する
+// 他のタスクが取得中(Leaseの更新前)の行はSKIP LOCKEDで待たずに読み飛ばす
+// Lease中の行も返すため、送信順を保つかどうかは呼び出し元で判断する
+// 配信開始まで保留しているイベントは返さない
+func (o *OutboxRepository) GetPending(ctx context.Context, tx repository.Transaction, limit int) ([]*models.OutboxEvent, error) {
+	query := `SELECT
+		id,
+		event_type,
+		topic_arn,
+		message,
+		attributes,
+		attempts,
+		next_attempt_at,
+		locked_until,
+		last_error,
+		created_at,
+		delivered_at,
+		failed_at,
+		held_campaign_id
+	FROM job_outbox
+	WHERE
+		delivered_at IS NULL
+		AND failed_at IS NULL
+		AND held_campaign_id IS NULL
+	ORDER BY id
+	LIMIT :limit
+	FOR UPDATE SKIP LOCKED`
+	stmt, err := tx.(*Transaction).Tx.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	events := []*models.OutboxEvent{}
+	err = stmt.SelectContext(ctx, &events, map[string]interface{}{
+		"limit": limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+// GetOldestPendingID 送信待ちのイベントで最も古いIDを取得する
+// ロックしない読み取りのため、他のタスクが取得中の行も含む
+func (o *OutboxRepository) GetOldestPendingID(ctx context.Context, tx repository.Transaction) (int64, error) {
+	query := `SELECT COALESCE(MIN(id), 0) FROM job_outbox WHERE delivered_at IS NULL AND failed_at IS NULL AND held_campaign_id IS NULL`
+	var id int64
+	if err := tx.(*Transaction).Tx.GetContext(ctx, &id, query); err != nil {
+		return 0, err
+	}
+	return id, nil
+}
+
+// Lease 送信中のイベントをlockedUntilまで他のタスクが送信しないようにする
+func (o *OutboxRepository) Lease(ctx context.Context, tx repository.Transaction, ids []int64, lockedUntil time.Time) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := o.sqlHandler.In(`UPDATE job_outbox SET locked_until = :locked_until WHERE id IN (:ids)`,
+		map[string]interface{}{
+			"locked_until": lockedUntil,
+			"ids":          ids,
+		})
+	if err != nil {
+		return err
+	}
+	_, err = tx.(*Transaction).Tx.ExecContext(ctx, *query, args...)
+	return err
+}
+
+// Release 送信しなかったイベントのLeaseを解除する
+func (o *OutboxRepository) Release(ctx context.Context, ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	query, args, err := o.sqlHandler.In(`UPDATE job_outbox SET locked_until = NULL WHERE id IN (:ids)`,
+		map[string]interface{}{
+			"ids": ids,
+		})
+	if err != nil {
+		return err
+	}
+	return o.exec(ctx, *query, args...)
+}
+
+// MarkDelivered 送信済みにする
+func (o *OutboxRepository) MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	query := `UPDATE job_outbox SET delivered_at = ?, locked_until = NULL WHERE id = ?`
+	return o.exec(ctx, query, deliveredAt, id)
+}
+
+// MarkFailed 送信失敗を記録して次回の送信日時を設定する
+func (o *OutboxRepository) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	query := `UPDATE job_outbox
+	SET
+		attempts = attempts + 1,
+		next_attempt_at = ?,
+		locked_until = NULL,
+		last_error = ?
+	WHERE id = ?`
+	return o.exec(ctx, query, nextAttemptAt, lastError, id)
+}
+
+// MarkDead 最大送信回数を超えたイベントを送信失敗にする (以降は送信しない)
+func (o *OutboxRepository) MarkDead(ctx context.Context, id int64, failedAt time.Time, lastError string) error {
+	query := `UPDATE job_outbox
+	SET
+		attempts = attempts + 1,
+		failed_at = ?,
+		locked_until = NULL,
+		last_error = ?
+	WHERE id = ?`
+	return o.exec(ctx, query, failedAt, lastError, id)
+}
+
+// GetBacklog 送信待ちイベントの件数と最も古い登録日時、送信失敗にしたイベントの件数を取得する
+// 配信開始まで保留しているイベントは含めない
+func (o *OutboxRepository) GetBacklog(ctx context.Context) (*models.OutboxBacklog, error) {
+	query := `SELECT
+		COUNT(failed_at IS NULL OR NULL) as count,
+		MIN(CASE WHEN failed_at IS NULL THEN created_at END) as oldest_created_at,
+		COUNT(failed_at) as failed
+	FROM job_outbox
+	WHERE
+		delivered_at IS NULL
+		AND held_campaign_id IS NULL`
+	backlogs := []*models.OutboxBacklog{}
+	err := o.sqlHandler.Select(ctx, &backlogs, query)
+	if err != nil {
+		return nil, err
+	}
+	if len(backlogs) == 0 {
+		return &models.OutboxBacklog{}, nil
+	}
+	return backlogs[0], nil
+}
+
+// DeleteDelivered 送信済みのイベントを削除する
+func (o *OutboxRepository) DeleteDelivered(ctx context.Context, before time.Time, limit int) (int64, error) {
+	query := `DELETE FROM job_outbox
+	WHERE
+		delivered_at IS NOT NULL
+		AND delivered_at < :before
+	ORDER BY id
+	LIMIT :limit`
+	stmt, err := o.sqlHandler.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, map[string]interface{}{
+		"before": before,
+		"limit":  limit,
+	})
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (o *OutboxRepository) exec(ctx context.Context, query string, args ...interface{}) error {
+	stmt, err := o.sqlHandler.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.ExecContext(ctx, args...)
+	return err
+}
+
+func (o *OutboxRepository) execNamed(ctx context.Context, tx repository.Transaction, query string, params map[string]interface{}) (int64, error) {
+	stmt, err := o.prepareNamed(ctx, tx, query)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (o *OutboxRepository) prepareNamed(ctx context.Context, tx repository.Transaction, query string) (*sqlx.NamedStmt, error) {
+	if tx == nil {
+		return o.sqlHandler.PrepareNamedContext(ctx, query)
+	}
+	return tx.(*Transaction).Tx.PrepareNamedContext(ctx, query)
+}
@@ -0,0 +1,69 @@
+package infra
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// PrewarmRepository 配信開始前に配信データを作成したキャンペーンの記録
+type PrewarmRepository struct {
+	logger     *Logger
+	sqlHandler SQLHandler
+}
+
+func NewPrewarmRepository(logger *Logger, sqlHandler SQLHandler) repository.PrewarmRepository {
+	return &PrewarmRepository{
+		logger:     logger,
+		sqlHandler: sqlHandler,
+	}
+}
+
+// Save 配信開始前に配信データを作成したことを記録する
+func (p *PrewarmRepository) Save(ctx context.Context, tx repository.Transaction, campaignID int, prewarmedAt time.Time) error {
+	query := `INSERT INTO job_campaign_prewarm (campaign_id, prewarmed_at)
+	VALUES (:campaign_id, :prewarmed_at)
+	ON DUPLICATE KEY UPDATE prewarmed_at = VALUES(prewarmed_at)`
+	_, err := p.exec(ctx, tx, query, map[string]interface{}{
+		"campaign_id":  campaignID,
+		"prewarmed_at": prewarmedAt,
+	})
+	return err
+}
+
+// Delete 配信データを事前作成した記録を削除し、削除した件数を返す (txがnilの場合は単独で削除する)
+func (p *PrewarmRepository) Delete(ctx context.Context, tx repository.Transaction, campaignID int) (int, error) {
+	query := `DELETE FROM job_campaign_prewarm WHERE campaign_id = :campaign_id`
+	return p.exec(ctx, tx, query, map[string]interface{}{
+		"campaign_id": campaignID,
+	})
+}
+
+func (p *PrewarmRepository) exec(ctx context.Context, tx repository.Transaction, query string, params map[string]interface{}) (int, error) {
+	var stmt *sqlx.NamedStmt
+	var err error
+	if tx == nil {
+		stmt, err = p.sqlHandler.PrepareNamedContext(ctx, query)
+	} else {
+		stmt, err = tx.(*Transaction).Tx.PrepareNamedContext(ctx, query)
+	}
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if cerr := stmt.Close(); cerr != nil {
+			p.logger.Error().Err(cerr).Msg("Failed to close statement")
+		}
+	}()
+	result, err := stmt.ExecContext(ctx, params)
+	if err != nil {
+		return 0, err
+	}
+	count, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(count), nil
+}
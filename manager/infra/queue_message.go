@@ -2,7 +2,12 @@
 
 package infra
 
-import "github.com/aws/aws-sdk-go/service/sqs"
+import (
+	"strconv"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
+)
 
 // SnsMessage
 // SNS -> SQSのため、SNSメッセージの形式で受け取る
@@ -23,7 +28,13 @@ type SnsMessage struct {
 type QueueMessage interface {
 	Message() *string
 	MessageID() *string
+	// SnsMessageID SNSのMessageId (SNSからの再送時も同じ値になる)
+	SnsMessageID() *string
 	ReceiptHandle() *string
+	// Body SQSから受信した本文 (SNSメッセージ)
+	Body() *string
+	// ReceiveCount 受信回数 (ApproximateReceiveCount)
+	ReceiveCount() int
 }
 
 type queueMessage struct {
@@ -43,6 +54,22 @@ func (q *queueMessage) MessageID() *string {
 	return q.sqsMessage.MessageId
 }
 
+func (q *queueMessage) SnsMessageID() *string {
+	return &q.snsMessage.MessageID
+}
+
 func (q *queueMessage) ReceiptHandle() *string {
 	return q.sqsMessage.ReceiptHandle
 }
+
+func (q *queueMessage) Body() *string {
+	return q.sqsMessage.Body
+}
+
+func (q *queueMessage) ReceiveCount() int {
+	count, err := strconv.Atoi(aws.StringValue(q.sqsMessage.Attributes[sqs.MessageSystemAttributeNameApproximateReceiveCount]))
+	if err != nil {
+		return 0
+	}
+	return count
+}
@@ -0,0 +1,103 @@
+package infra
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// RDBLeaseRepository MySQLのアドバイザリロック(GET_LOCK)を使ったリース
+// ロックはコネクションに紐づくため、取得したコネクションを解放まで保持する
+// (プロセスが落ちた場合はコネクション切断によりロックが解放される)
+type RDBLeaseRepository struct {
+	logger     *Logger
+	sqlHandler SQLHandler
+	mutex      sync.Mutex
+	conns      map[string]*sqlx.Conn
+}
+
+// NewRDBLeaseRepository is function
+func NewRDBLeaseRepository(logger *Logger, sqlHandler SQLHandler) repository.LeaseRepository {
+	return &RDBLeaseRepository{
+		logger:     logger,
+		sqlHandler: sqlHandler,
+		conns:      map[string]*sqlx.Conn{},
+	}
+}
+
+// Acquire ロックを取得する(待たずに結果を返す)
+// MySQLのロックには有効期限がないためdurationは使用しない
+func (r *RDBLeaseRepository) Acquire(ctx context.Context, name string, owner string, duration time.Duration) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.conns[name]; ok {
+		return r.isHeld(ctx, name)
+	}
+	conn, err := r.sqlHandler.Conn(ctx)
+	if err != nil {
+		return false, err
+	}
+	var result *int
+	err = conn.GetContext(ctx, &result, "SELECT GET_LOCK(?, 0)", name)
+	if err != nil {
+		r.closeConn(conn)
+		return false, err
+	}
+	if result == nil || *result != 1 {
+		r.closeConn(conn)
+		return false, nil
+	}
+	r.conns[name] = conn
+	return true, nil
+}
+
+// Renew ロックを保持し続けているか確認する
+func (r *RDBLeaseRepository) Renew(ctx context.Context, name string, owner string, duration time.Duration) (bool, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	if _, ok := r.conns[name]; !ok {
+		return false, nil
+	}
+	return r.isHeld(ctx, name)
+}
+
+// Release ロックを解放してコネクションを返却する
+func (r *RDBLeaseRepository) Release(ctx context.Context, name string, owner string) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	conn, ok := r.conns[name]
+	if !ok {
+		return nil
+	}
+	delete(r.conns, name)
+	defer r.closeConn(conn)
+	var result *int
+	return conn.GetContext(ctx, &result, "SELECT RELEASE_LOCK(?)", name)
+}
+
+// isHeld 保持しているコネクションでロックを持っているか確認する
+// コネクションが切れている場合はロックを失っているので破棄する
+func (r *RDBLeaseRepository) isHeld(ctx context.Context, name string) (bool, error) {
+	conn := r.conns[name]
+	var held bool
+	err := conn.GetContext(ctx, &held, "SELECT IFNULL(IS_USED_LOCK(?) = CONNECTION_ID(), 0)", name)
+	if err != nil {
+		delete(r.conns, name)
+		r.closeConn(conn)
+		return false, err
+	}
+	if !held {
+		delete(r.conns, name)
+		r.closeConn(conn)
+	}
+	return held, nil
+}
+
+func (r *RDBLeaseRepository) closeConn(conn *sqlx.Conn) {
+	if err := conn.Close(); err != nil {
+		r.logger.Error().Err(err).Msg("Failed to close connection")
+	}
+}
@@ -0,0 +1,94 @@
+package infra
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/repository"
+)
+
+// RDBMessageDedupRepository SQSメッセージの処理状態をRDB(job_message_dedup)に保存する
+type RDBMessageDedupRepository struct {
+	logger     *Logger
+	sqlHandler SQLHandler
+}
+
+// NewRDBMessageDedupRepository is function
+func NewRDBMessageDedupRepository(logger *Logger, sqlHandler SQLHandler) repository.MessageDedupRepository {
+	return &RDBMessageDedupRepository{
+		logger:     logger,
+		sqlHandler: sqlHandler,
+	}
+}
+
+// Claim 未登録または期限切れの場合のみ処理中として登録する
+// 登録・更新されなかった場合(affected rows = 0)は登録済みの状態を返す
+func (r *RDBMessageDedupRepository) Claim(ctx context.Context, key string, now time.Time, expiresAt time.Time) (string, error) {
+	query := `INSERT INTO job_message_dedup (dedup_key, status, expires_at)
+	VALUES (?, ?, ?)
+	ON DUPLICATE KEY UPDATE
+		status = IF(expires_at < ?, VALUES(status), status),
+		expires_at = IF(expires_at < ?, VALUES(expires_at), expires_at)`
+	stmt, err := r.sqlHandler.PrepareContext(ctx, query)
+	if err != nil {
+		return "", err
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, key, codes.MessageDedupInProgress, expiresAt, now, now)
+	if err != nil {
+		return "", err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return "", err
+	}
+	if affected > 0 {
+		return codes.MessageDedupNew, nil
+	}
+	var status []string
+	if err := r.sqlHandler.Select(ctx, &status, `SELECT status FROM job_message_dedup WHERE dedup_key = ?`, key); err != nil {
+		return "", err
+	}
+	if len(status) == 0 {
+		// 登録後すぐに削除された場合
+		return "", codes.ErrNoData
+	}
+	return status[0], nil
+}
+
+// Complete 処理済みにする
+func (r *RDBMessageDedupRepository) Complete(ctx context.Context, key string, expiresAt time.Time) error {
+	query := `UPDATE job_message_dedup SET status = ?, expires_at = ? WHERE dedup_key = ?`
+	stmt, err := r.sqlHandler.PrepareContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.ExecContext(ctx, codes.MessageDedupDone, expiresAt, key)
+	return err
+}
+
+// Release 登録を削除する
+func (r *RDBMessageDedupRepository) Release(ctx context.Context, key string) error {
+	stmt, err := r.sqlHandler.PrepareContext(ctx, `DELETE FROM job_message_dedup WHERE dedup_key = ?`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.ExecContext(ctx, key)
+	return err
+}
+
+// DeleteExpired 期限切れの登録を削除する
+func (r *RDBMessageDedupRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	stmt, err := r.sqlHandler.PrepareContext(ctx, `DELETE FROM job_message_dedup WHERE expires_at < ? LIMIT ?`)
+	if err != nil {
+		return 0, err
+	}
+	defer stmt.Close()
+	result, err := stmt.ExecContext(ctx, before, limit)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -221,6 +221,14 @@ func (r *RDBUtil) InsertCampaignCoupon(campaignID int, couponID int, deliveryRat
 	return id
 }
 
+// キャンペーンとクーポンの紐付けにクーポンの配信上限数(合計)を設定する
+func (r *RDBUtil) UpdateCampaignCouponTotalLimit(id int, totalLimit int) {
+	_, err := r.tx.ExecContext(r.ctx, `UPDATE campaign_coupon SET total_limit = ? WHERE id = ?`, totalLimit, id)
+	if !assert.NoError(r.t, err) {
+		r.t.Fatal(err)
+	}
+}
+
 func (r *RDBUtil) InsertCampaignGimmick(campaignID int, gimmickID int) int {
 	_, err := r.tx.ExecContext(r.ctx,
 		`INSERT INTO campaign_gimmick (
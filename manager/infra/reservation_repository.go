@@ -0,0 +1,78 @@
+package infra
+
+import (
+	"context"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+)
+
+// ReservationRepository 開始/終了処理の予約をRDBに保存する
+type ReservationRepository struct {
+	logger     *Logger
+	sqlHandler SQLHandler
+}
+
+func NewReservationRepository(logger *Logger, sqlHandler SQLHandler) repository.ReservationRepository {
+	return &ReservationRepository{
+		logger:     logger,
+		sqlHandler: sqlHandler,
+	}
+}
+
+// Save 予約を登録する (同じキャンペーン・処理種別の予約は上書きする)
+func (r *ReservationRepository) Save(ctx context.Context, reservation *models.Reservation) error {
+	query := `INSERT INTO job_reservation (campaign_id, action, fire_at)
+	VALUES (:campaign_id, :action, :fire_at)
+	ON DUPLICATE KEY UPDATE fire_at = VALUES(fire_at)`
+	stmt, err := r.sqlHandler.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err = stmt.Close(); err != nil {
+			r.logger.Error().Err(err).Msg("Failed to close statement")
+		}
+	}()
+	_, err = stmt.ExecContext(ctx, map[string]interface{}{
+		"campaign_id": reservation.CampaignID,
+		"action":      reservation.Action,
+		"fire_at":     reservation.FireAt,
+	})
+	return err
+}
+
+// Delete 予約を削除する
+func (r *ReservationRepository) Delete(ctx context.Context, campaignID int, action string) error {
+	query := `DELETE FROM job_reservation WHERE campaign_id = :campaign_id AND action = :action`
+	stmt, err := r.sqlHandler.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err = stmt.Close(); err != nil {
+			r.logger.Error().Err(err).Msg("Failed to close statement")
+		}
+	}()
+	_, err = stmt.ExecContext(ctx, map[string]interface{}{
+		"campaign_id": campaignID,
+		"action":      action,
+	})
+	return err
+}
+
+// GetAll 未実行の予約を実行時間順に取得する
+func (r *ReservationRepository) GetAll(ctx context.Context) ([]*models.Reservation, error) {
+	query := `SELECT
+		campaign_id,
+		action,
+		fire_at,
+		created_at
+	FROM job_reservation
+	ORDER BY fire_at`
+	reservations := []*models.Reservation{}
+	err := r.sqlHandler.Select(ctx, &reservations, query)
+	if err != nil {
+		return nil, err
+	}
+	return reservations, nil
+}
@@ -5,10 +5,14 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
-	_ "github.com/go-sql-driver/mysql"
-	"github.com/jmoiron/sqlx"
+	"net/url"
+	"time"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/domain/repository"
+
+	_ "github.com/go-sql-driver/mysql"
+	"github.com/jmoiron/sqlx"
+	"github.com/pkg/errors"
 )
 
 // SQLHandler TODO: repository層にあるべきでは？？
@@ -18,6 +22,7 @@ type SQLHandler interface {
 	PrepareContext(ctx context.Context, query string) (*sqlx.Stmt, error)
 	Select(ctx context.Context, dest interface{}, query string, args ...interface{}) error
 	In(query string, arg interface{}) (*string, []interface{}, error)
+	Conn(ctx context.Context) (*sqlx.Conn, error)
 	Close()
 }
 
@@ -27,8 +32,10 @@ type sqlHandler struct {
 }
 
 func NewSQLHandler(logger *Logger) SQLHandler {
-	connectionString := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True",
-		config.Env.Db.User, config.Env.Db.Password, config.Env.Db.Host, config.Env.Db.Port, config.Env.Db.Database)
+	connectionString, err := dataSourceName(&config.Env.Db)
+	if err != nil {
+		panic(err)
+	}
 	db, err := sqlx.Open(config.Env.Db.DriverName, connectionString)
 	if err != nil {
 		panic(err.Error)
@@ -48,6 +55,22 @@ func NewSQLHandler(logger *Logger) SQLHandler {
 	return &handler
 }
 
+// DATETIME/TIMESTAMPはDB_LOCATIONのタイムゾーンで読み書きする (コンテナ・DBサーバーのタイムゾーンに依存させない)
+// TIMESTAMPの変換に使用するセッションのtime_zoneも同じタイムゾーンにする
+func dataSourceName(db *config.Db) (string, error) {
+	if _, err := time.LoadLocation(db.Location); err != nil {
+		return "", errors.Wrapf(err, "Invalid DB location: %s", db.Location)
+	}
+	sessionTimeZone := db.Location
+	if db.Location == "UTC" {
+		// タイムゾーンのテーブルが読み込まれていないDBでも使用できるようにする
+		sessionTimeZone = "+00:00"
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=%s&time_zone=%s",
+		db.User, db.Password, db.Host, db.Port, db.Database,
+		url.QueryEscape(db.Location), url.QueryEscape("'"+sessionTimeZone+"'")), nil
+}
+
 // Close is function
 func (s *sqlHandler) Close() {
 	s.DB.Close()
@@ -86,6 +109,12 @@ func (s *sqlHandler) In(query string, arg interface{}) (*string, []interface{},
 	return &query, args, nil
 }
 
+// Conn is function
+// コネクション単位の状態(GET_LOCK等)を扱う場合に使用する
+func (s *sqlHandler) Conn(ctx context.Context) (*sqlx.Conn, error) {
+	return s.DB.Connx(ctx)
+}
+
 // Begin is function
 func (s *sqlHandler) Begin(ctx context.Context) (repository.Transaction, error) {
 	tx, err := s.DB.BeginTxx(ctx, &sql.TxOptions{})
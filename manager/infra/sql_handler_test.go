@@ -3,6 +3,7 @@ package infra
 import (
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"touchgift-job-manager/config"
 )
 
 // WARNING: ローカルでテストする場合はrdsを起動してから実行してください(mockだと確立テストができないです)
@@ -34,3 +35,20 @@ func TestNewSQLHandler(t *testing.T) {
 	//	NewSQLHandler(logger)
 	//})
 }
+
+func TestDataSourceName(t *testing.T) {
+	t.Run("DB_LOCATIONのタイムゾーンで日時を読み書きする", func(t *testing.T) {
+		dsn, err := dataSourceName(&config.Db{User: "user", Password: "pass", Host: "localhost", Port: 3306, Database: "retail", Location: "Asia/Tokyo"})
+		assert.NoError(t, err)
+		assert.Equal(t, "user:pass@tcp(localhost:3306)/retail?charset=utf8mb4&parseTime=True&loc=Asia%2FTokyo&time_zone=%27Asia%2FTokyo%27", dsn)
+	})
+	t.Run("UTCの場合はセッションのtime_zoneをオフセットで指定する", func(t *testing.T) {
+		dsn, err := dataSourceName(&config.Db{User: "user", Password: "pass", Host: "localhost", Port: 3306, Database: "retail", Location: "UTC"})
+		assert.NoError(t, err)
+		assert.Equal(t, "user:pass@tcp(localhost:3306)/retail?charset=utf8mb4&parseTime=True&loc=UTC&time_zone=%27%2B00%3A00%27", dsn)
+	})
+	t.Run("存在しないタイムゾーンの場合はエラーを返す", func(t *testing.T) {
+		_, err := dataSourceName(&config.Db{Location: "Invalid/Zone"})
+		assert.Error(t, err)
+	})
+}
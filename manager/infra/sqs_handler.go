@@ -5,11 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/sqs"
 	"strings"
 	"sync"
+	"time"
 	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/infra/metrics"
 )
 
@@ -25,6 +28,18 @@ var (
 	metricSqsDeletedMessageTotal       = "sqs_deleted_message_total"
 	metricSqsDeletedMessageTotalDesc   = "all deleted message count from sqs"
 	metricSqsDeletedMessageTotalLabels = []string{"url"}
+
+	metricSqsDeadLetterTotal       = "sqs_dead_letter_total"
+	metricSqsDeadLetterTotalDesc   = "all dead letter count from sqs"
+	metricSqsDeadLetterTotalLabels = []string{"url", "result"}
+
+	metricSqsVisibilityExtendedTotal       = "sqs_visibility_extended_total"
+	metricSqsVisibilityExtendedTotalDesc   = "visibility timeout extension count of in-flight sqs message"
+	metricSqsVisibilityExtendedTotalLabels = []string{"url", "result"}
+
+	metricSqsVisibilityHeartbeatInFlight       = "sqs_visibility_heartbeat_in_flight"
+	metricSqsVisibilityHeartbeatInFlightDesc   = "in-flight sqs message count extending visibility timeout"
+	metricSqsVisibilityHeartbeatInFlightLabels = []string{"url"}
 )
 
 type sqsHandler struct {
@@ -34,6 +49,9 @@ type sqsHandler struct {
 	visibilityTimeoutSeconds *int64
 	waitTimeSeconds          *int64
 	monitor                  *metrics.Monitor
+	deadLetterSink           DeadLetterSink
+	heartbeatMutex           sync.Mutex
+	heartbeats               map[string]context.CancelFunc // key: ReceiptHandle
 }
 
 type SQSHandler interface {
@@ -41,6 +59,10 @@ type SQSHandler interface {
 	UnprocessableMessage()
 	OutputDeleteCliLog(message QueueMessage)
 	DeleteMessage(ctx context.Context, message QueueMessage)
+	// DeadLetter メッセージをDeadLetterSinkに保存してキューから削除する (保存に失敗した場合は削除しない)
+	DeadLetter(ctx context.Context, message QueueMessage, cause error) error
+	// Abandon 処理を諦めたメッセージの可視性タイムアウトの延長を停止する (キューには残す)
+	Abandon(message QueueMessage)
 }
 
 func NewSQSHandler(
@@ -50,10 +72,14 @@ func NewSQSHandler(
 	visibilityTimeoutSeconds *int64,
 	waitTimeSeconds *int64,
 	monitor *metrics.Monitor,
+	deadLetterSink DeadLetterSink,
 ) SQSHandler {
 	monitor.Metrics.AddCounter(metricSqsReceivedMessageTotal, metricSqsReceivedMessageTotalDesc, metricSqsReceivedMessageTotalLabels)
 	monitor.Metrics.AddCounter(metricSqsUnprocessableMessageTotal, metricSqsUnprocessableMessageTotalDesc, metricSqsUnprocessableMessageTotalLabels)
 	monitor.Metrics.AddCounter(metricSqsDeletedMessageTotal, metricSqsDeletedMessageTotalDesc, metricSqsDeletedMessageTotalLabels)
+	monitor.Metrics.AddCounter(metricSqsDeadLetterTotal, metricSqsDeadLetterTotalDesc, metricSqsDeadLetterTotalLabels)
+	monitor.Metrics.AddCounter(metricSqsVisibilityExtendedTotal, metricSqsVisibilityExtendedTotalDesc, metricSqsVisibilityExtendedTotalLabels)
+	monitor.Metrics.AddGauge(metricSqsVisibilityHeartbeatInFlight, metricSqsVisibilityHeartbeatInFlightDesc, metricSqsVisibilityHeartbeatInFlightLabels)
 
 	sqsSession := session.Must(session.NewSessionWithOptions(session.Options{
 		Config:            *aws.NewConfig().WithEndpoint(config.Env.SQS.EndPoint),
@@ -69,6 +95,8 @@ func NewSQSHandler(
 		visibilityTimeoutSeconds: visibilityTimeoutSeconds,
 		waitTimeSeconds:          waitTimeSeconds,
 		monitor:                  monitor,
+		deadLetterSink:           deadLetterSink,
+		heartbeats:               map[string]context.CancelFunc{},
 	}
 }
 
@@ -89,6 +117,7 @@ func (s *sqsHandler) Poll(ctx context.Context, wg *sync.WaitGroup, ch chan Queue
 				MaxNumberOfMessages: &sqsMaxMessages,
 				VisibilityTimeout:   s.visibilityTimeoutSeconds,
 				WaitTimeSeconds:     s.waitTimeSeconds,
+				AttributeNames:      []*string{aws.String(sqs.MessageSystemAttributeNameApproximateReceiveCount)},
 			})
 			if err != nil {
 				s.logger.Error().Err(err).Str("queue_url", *s.queueURL).Msg("Failed to fetch sqs message")
@@ -98,9 +127,27 @@ func (s *sqsHandler) Poll(ctx context.Context, wg *sync.WaitGroup, ch chan Queue
 				decoder := json.NewDecoder(strings.NewReader(*message.Body))
 				if err := decoder.Decode(&snsMessage); err != nil {
 					s.logger.Error().Err(err).Str("queue_url", *s.queueURL).Str("body", *message.Body).Msg("Failed to parse sns message")
+					deadLetter := &models.DeadLetter{
+						QueueURL:  *s.queueURL,
+						MessageID: aws.StringValue(message.MessageId),
+						Body:      aws.StringValue(message.Body),
+						Error:     err.Error(),
+						Attempts:  NewMessage(message, nil).ReceiveCount(),
+						FailedAt:  time.Now(),
+					}
+					if err := s.putDeadLetter(ctx, deadLetter); err != nil {
+						s.logger.Error().Err(err).Str("queue_url", *s.queueURL).Str("message_id", deadLetter.MessageID).Msg("Failed to put dead letter")
+					}
 					s.deleteMessage(ctx, message.ReceiptHandle, message.MessageId)
 				} else {
-					ch <- NewMessage(message, &snsMessage)
+					// チャネルで処理待ちの間も可視性タイムアウトを延長する
+					s.startHeartbeat(message)
+					select {
+					case ch <- NewMessage(message, &snsMessage):
+					case <-ctx.Done():
+						// 受け渡す前に終了した場合はキューに残す
+						s.Abandon(NewMessage(message, &snsMessage))
+					}
 				}
 				s.monitor.Metrics.GetCounter(metricSqsReceivedMessageTotal).WithLabelValues(*s.queueURL).Inc()
 			}
@@ -121,7 +168,31 @@ func (s *sqsHandler) DeleteMessage(ctx context.Context, message QueueMessage) {
 	s.deleteMessage(ctx, message.ReceiptHandle(), message.MessageID())
 }
 
+func (s *sqsHandler) Abandon(message QueueMessage) {
+	s.stopHeartbeat(message.ReceiptHandle())
+}
+
+func (s *sqsHandler) DeadLetter(ctx context.Context, message QueueMessage, cause error) error {
+	deadLetter := newDeadLetter(*s.queueURL, message, cause)
+	if err := s.putDeadLetter(ctx, deadLetter); err != nil {
+		return err
+	}
+	s.deleteMessage(ctx, message.ReceiptHandle(), message.MessageID())
+	return nil
+}
+
+func (s *sqsHandler) putDeadLetter(ctx context.Context, deadLetter *models.DeadLetter) error {
+	if err := s.deadLetterSink.Put(ctx, deadLetter); err != nil {
+		s.monitor.Metrics.GetCounter(metricSqsDeadLetterTotal).WithLabelValues(*s.queueURL, "error").Inc()
+		return err
+	}
+	s.monitor.Metrics.GetCounter(metricSqsDeadLetterTotal).WithLabelValues(*s.queueURL, "success").Inc()
+	s.logger.Warn().Str("queue_url", *s.queueURL).Str("message_id", deadLetter.MessageID).Int("attempts", deadLetter.Attempts).Str("error", deadLetter.Error).Msg("Put dead letter")
+	return nil
+}
+
 func (s *sqsHandler) deleteMessage(ctx context.Context, receiptHandle *string, messageID *string) {
+	s.stopHeartbeat(receiptHandle)
 	_, err := s.svc.DeleteMessageWithContext(ctx, &sqs.DeleteMessageInput{
 		QueueUrl:      s.queueURL,
 		ReceiptHandle: receiptHandle,
@@ -132,3 +203,87 @@ func (s *sqsHandler) deleteMessage(ctx context.Context, receiptHandle *string, m
 	}
 	s.monitor.Metrics.GetCounter(metricSqsDeletedMessageTotal).WithLabelValues(*s.queueURL).Inc()
 }
+
+// 受信したメッセージの可視性タイムアウトを、削除・DeadLetterへの保存または処理を諦めるまで定期的に延長する
+// ポーリングの終了後もワーカーは処理中のメッセージを処理し続けるため、ポーリングのcontextとは切り離したメッセージごとのcontextで延長する
+func (s *sqsHandler) startHeartbeat(message *sqs.Message) {
+	if !config.Env.SQS.VisibilityHeartbeatEnabled || message.ReceiptHandle == nil {
+		return
+	}
+	receiptHandle := *message.ReceiptHandle
+	heartbeatCtx, cancel := context.WithCancel(context.Background())
+	s.heartbeatMutex.Lock()
+	s.heartbeats[receiptHandle] = cancel
+	s.heartbeatMutex.Unlock()
+	s.monitor.Metrics.GetGauge(metricSqsVisibilityHeartbeatInFlight).WithLabelValues(*s.queueURL).Inc()
+
+	interval := config.Env.SQS.VisibilityHeartbeatInterval
+	deadline := time.Now().Add(config.Env.SQS.VisibilityHeartbeatMaxDuration)
+	go func() {
+		defer s.stopHeartbeat(&receiptHandle)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-heartbeatCtx.Done():
+				return
+			case <-ticker.C:
+				if time.Now().After(deadline) {
+					s.monitor.Metrics.GetCounter(metricSqsVisibilityExtendedTotal).WithLabelValues(*s.queueURL, "expired").Inc()
+					s.logger.Warn().Str("queue_url", *s.queueURL).Str("message_id", aws.StringValue(message.MessageId)).Msg("Stop extending visibility timeout")
+					return
+				}
+				_, err := s.svc.ChangeMessageVisibilityWithContext(heartbeatCtx, &sqs.ChangeMessageVisibilityInput{
+					QueueUrl:          s.queueURL,
+					ReceiptHandle:     &receiptHandle,
+					VisibilityTimeout: s.visibilityTimeoutSeconds,
+				})
+				if heartbeatCtx.Err() != nil {
+					// 延長中に削除された場合
+					return
+				}
+				if err != nil {
+					s.monitor.Metrics.GetCounter(metricSqsVisibilityExtendedTotal).WithLabelValues(*s.queueURL, "error").Inc()
+					s.logger.Error().Err(err).Str("queue_url", *s.queueURL).Str("message_id", aws.StringValue(message.MessageId)).Msg("Failed to extend visibility timeout")
+					if aerr, ok := err.(awserr.Error); ok &&
+						(aerr.Code() == sqs.ErrCodeReceiptHandleIsInvalid || aerr.Code() == sqs.ErrCodeMessageNotInflight) {
+						// 既に削除されたか、他のアプリが受信しているため延長できない
+						return
+					}
+					continue
+				}
+				s.monitor.Metrics.GetCounter(metricSqsVisibilityExtendedTotal).WithLabelValues(*s.queueURL, "success").Inc()
+			}
+		}
+	}()
+}
+
+func (s *sqsHandler) stopHeartbeat(receiptHandle *string) {
+	if receiptHandle == nil {
+		return
+	}
+	s.heartbeatMutex.Lock()
+	cancel, ok := s.heartbeats[*receiptHandle]
+	delete(s.heartbeats, *receiptHandle)
+	s.heartbeatMutex.Unlock()
+	if !ok {
+		return
+	}
+	cancel()
+	s.monitor.Metrics.GetGauge(metricSqsVisibilityHeartbeatInFlight).WithLabelValues(*s.queueURL).Dec()
+}
+
+func newDeadLetter(queueURL string, message QueueMessage, cause error) *models.DeadLetter {
+	deadLetter := &models.DeadLetter{
+		QueueURL:  queueURL,
+		MessageID: aws.StringValue(message.MessageID()),
+		Body:      aws.StringValue(message.Body()),
+		Message:   aws.StringValue(message.Message()),
+		Attempts:  message.ReceiveCount(),
+		FailedAt:  time.Now(),
+	}
+	if cause != nil {
+		deadLetter.Error = cause.Error()
+	}
+	return deadLetter
+}
@@ -1,9 +1,12 @@
 package infra
 
 import (
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/service/sqs"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"testing"
+	"time"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/infra/metrics"
 )
@@ -18,8 +21,59 @@ func TestSQSHandler_NewSQSHandler(t *testing.T) {
 
 	t.Run("正常にsqsとの通信が確立される", func(t *testing.T) {
 		handler := NewSQSHandler(
-			logger, region, &config.Env.SQS.DeliveryControlQueueURL, &config.Env.SQS.VisibilityTimeoutSeconds, &config.Env.SQS.WaitTimeSeconds, monitor)
+			logger, region, &config.Env.SQS.DeliveryControlQueueURL, &config.Env.SQS.VisibilityTimeoutSeconds, &config.Env.SQS.WaitTimeSeconds, monitor,
+			NewFileDeadLetterSink(logger, config.Env.DeadLetter.FilePath))
 		assert.NotNil(t, handler)
 	})
 
 }
+
+func TestSQSHandler_Heartbeat(t *testing.T) {
+	logger := GetLogger()
+	region := NewRegion(logger)
+	monitor := metrics.GetMonitor()
+
+	newHandler := func() *sqsHandler {
+		return NewSQSHandler(
+			logger, region, &config.Env.SQS.DeliveryOperationQueueURL, &config.Env.SQS.VisibilityTimeoutSeconds, &config.Env.SQS.WaitTimeSeconds, monitor,
+			NewFileDeadLetterSink(logger, config.Env.DeadLetter.FilePath)).(*sqsHandler)
+	}
+	heartbeats := func(handler *sqsHandler) int {
+		handler.heartbeatMutex.Lock()
+		defer handler.heartbeatMutex.Unlock()
+		return len(handler.heartbeats)
+	}
+	newMessage := func(receiptHandle string) *sqs.Message {
+		return &sqs.Message{MessageId: aws.String("message_id"), ReceiptHandle: aws.String(receiptHandle), Body: aws.String("{}")}
+	}
+
+	t.Run("処理を諦めた場合は可視性タイムアウトの延長を停止する", func(t *testing.T) {
+		handler := newHandler()
+		message := newMessage("receipt1")
+		handler.startHeartbeat(message)
+		assert.Equal(t, 1, heartbeats(handler))
+
+		handler.Abandon(NewMessage(message, &SnsMessage{}))
+		assert.Equal(t, 0, heartbeats(handler))
+		// 2回目は何もしない
+		handler.Abandon(NewMessage(message, &SnsMessage{}))
+		assert.Equal(t, 0, heartbeats(handler))
+	})
+
+	t.Run("最大延長時間を過ぎた場合は可視性タイムアウトの延長を停止する", func(t *testing.T) {
+		interval, maxDuration := config.Env.SQS.VisibilityHeartbeatInterval, config.Env.SQS.VisibilityHeartbeatMaxDuration
+		defer func() {
+			config.Env.SQS.VisibilityHeartbeatInterval, config.Env.SQS.VisibilityHeartbeatMaxDuration = interval, maxDuration
+		}()
+		config.Env.SQS.VisibilityHeartbeatInterval, config.Env.SQS.VisibilityHeartbeatMaxDuration = 10*time.Millisecond, 0
+
+		// ポーリングのcontextに関係なく、削除・処理を諦めるか最大延長時間を過ぎるまで延長する
+		handler := newHandler()
+		handler.startHeartbeat(newMessage("receipt1"))
+		handler.startHeartbeat(newMessage("receipt2"))
+
+		assert.Eventually(t, func() bool {
+			return heartbeats(handler) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+}
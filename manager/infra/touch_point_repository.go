@@ -2,6 +2,7 @@ package infra
 
 import (
 	"context"
+	"time"
 	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/domain/repository"
 )
@@ -52,3 +53,44 @@ func (t *TouchPointRepository) GetTouchPointByGroupID(ctx context.Context,
 	err = stmt.SelectContext(ctx, &touchPoints, _params...)
 	return touchPoints, err
 }
+
+func (t *TouchPointRepository) GetChangedGroupIDs(ctx context.Context, since time.Time) ([]int, error) {
+	// store_mapは更新日時を持たないため、店舗グループの更新日時で判定する
+	// CSVアップロードは組織単位のため、同じ組織の店舗グループを全て対象にする
+	query := `SELECT sg.id FROM store_group sg WHERE sg.updated_at > :since
+	UNION
+	SELECT sm.store_group_id FROM store_map sm
+	JOIN store s ON sm.store_id = s.id
+	WHERE s.updated_at > :since
+	UNION
+	SELECT sm.store_group_id FROM store_map sm
+	JOIN touch_point tp ON tp.store_id = sm.store_id
+	WHERE tp.updated_at > :since
+	UNION
+	SELECT sg.id FROM store_group sg
+	JOIN store_update_history h ON h.organization_code = sg.organization_code
+	WHERE h.created_at > :since
+	UNION
+	SELECT sg.id FROM store_group sg
+	JOIN touch_point_update_history h ON h.organization_code = sg.organization_code
+	WHERE h.created_at > :since`
+	params := map[string]interface{}{
+		"since": since,
+	}
+	_query, _params, err := t.sqlHandler.In(query, params)
+	if err != nil {
+		return nil, err
+	}
+	stmt, err := t.sqlHandler.PrepareContext(ctx, *_query)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err = stmt.Close(); err != nil {
+			t.logger.Error().Err(err).Msg("Failed to close statement")
+		}
+	}()
+	groupIDs := []int{}
+	err = stmt.SelectContext(ctx, &groupIDs, _params...)
+	return groupIDs, err
+}
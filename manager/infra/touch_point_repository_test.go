@@ -3,6 +3,7 @@ package infra
 import (
 	"context"
 	"testing"
+	"time"
 	"touchgift-job-manager/domain/repository"
 	mock_infra "touchgift-job-manager/mock/infra"
 
@@ -215,3 +216,53 @@ func TestTouchPointRepository_GetTouchPointByGroupID(t *testing.T) {
 
 	})
 }
+
+func TestTouchPointRepository_GetChangedGroupIDs(t *testing.T) {
+	logger := GetLogger()
+	sqlHandler := NewSQLHandler(logger)
+	defer sqlHandler.Close()
+
+	t.Run("指定した日時以降にタッチポイントが更新された店舗グループを返す", func(t *testing.T) {
+		//	mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		ctx := context.Background()
+		// トランザクションを開始する(トランザクション内でテストする)
+		tx, err := sqlHandler.Begin(ctx)
+		if !assert.NoError(t, err) {
+			return
+		}
+		//　ロールバックする(テストデータは不要なので)
+		defer func() {
+			err := tx.Rollback()
+			assert.NoError(t, err)
+		}()
+
+		//	テストデータを登録する
+		rdbUtil := NewTouchGiftRDBUtil(ctx, t, tx)
+		store_id := rdbUtil.InsertStore("ORG001", "S001", "東京本店", "100-0001", "13", "東京都千代田区丸の内1-1-1")
+		store_group_id := rdbUtil.InsertStoreGroup("グループA", "ORG001", 1)
+		rdbUtil.InsertTouchPoint("ORG001", "xxx", "yyy", store_id, "nfc", "ポイントA", 1)
+		_, err = rdbUtil.InsertStoreMap(store_group_id, store_id)
+		if !assert.NoError(t, err) {
+			return
+		}
+
+		_sqlHandler := mock_infra.NewMockSQLHandler(ctrl)
+		_sqlHandler.EXPECT().PrepareContext(gomock.Eq(ctx), gomock.Any()).DoAndReturn(func(ctx context.Context, query string) (*sqlx.Stmt, error) {
+			return tx.(*Transaction).Tx.PreparexContext(ctx, query)
+		}).Times(2)
+		_sqlHandler.EXPECT().In(gomock.Any(), gomock.Any()).DoAndReturn(func(query string, arg interface{}) (*string, []interface{}, error) {
+			return sqlHandler.In(query, arg)
+		}).Times(2)
+		touchPointRepository := NewTouchPointRepository(logger, _sqlHandler)
+		actuals, err := touchPointRepository.GetChangedGroupIDs(ctx, time.Now().Add(-24*time.Hour))
+		if assert.NoError(t, err) {
+			assert.Contains(t, actuals, store_group_id)
+		}
+		actuals, err = touchPointRepository.GetChangedGroupIDs(ctx, time.Now().Add(24*time.Hour))
+		if assert.NoError(t, err) {
+			assert.NotContains(t, actuals, store_group_id)
+		}
+	})
+}
@@ -0,0 +1,93 @@
+package infra
+
+import (
+	"context"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/pkg/errors"
+)
+
+// UploadHistoryRepository 店舗/タッチポイントのCSVアップロード履歴と反映状況
+type UploadHistoryRepository struct {
+	logger     *Logger
+	sqlHandler SQLHandler
+}
+
+func NewUploadHistoryRepository(logger *Logger, sqlHandler SQLHandler) repository.UploadHistoryRepository {
+	return &UploadHistoryRepository{
+		logger:     logger,
+		sqlHandler: sqlHandler,
+	}
+}
+
+// アップロード種別ごとの履歴テーブル
+var uploadHistoryTables = map[string]string{
+	codes.UploadKindStore:      "store_update_history",
+	codes.UploadKindTouchPoint: "touch_point_update_history",
+}
+
+// GetUnprocessed 未反映(反映に失敗したものを含む)のアップロード履歴を古い順に取得する
+// 処理中のまま残っている履歴(反映中にタスクが停止した場合)も再試行の対象にする
+func (u *UploadHistoryRepository) GetUnprocessed(ctx context.Context, args *repository.UnprocessedUploadCondition) ([]*models.UploadHistory, error) {
+	table, ok := uploadHistoryTables[args.Kind]
+	if !ok {
+		return nil, errors.Errorf("Unknown upload kind: %s", args.Kind)
+	}
+	query := `SELECT
+		:kind AS kind,
+		h.id AS id,
+		h.xid AS xid,
+		h.organization_code AS org_code,
+		h.file_name AS file_name,
+		h.created_at AS created_at,
+		IFNULL(p.attempts, 0) AS attempts
+	FROM ` + table + ` h
+	LEFT JOIN job_upload_process p ON p.kind = :kind AND p.history_id = h.id
+	WHERE
+		h.created_at > :since
+		AND (p.history_id IS NULL OR (p.status IN (:failed, :in_progress) AND p.attempts < :max_attempts))
+	ORDER BY h.id
+	LIMIT :limit`
+	stmt, err := u.sqlHandler.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer stmt.Close()
+	histories := []*models.UploadHistory{}
+	err = stmt.SelectContext(ctx, &histories, map[string]interface{}{
+		"kind":         args.Kind,
+		"since":        args.Since,
+		"failed":       codes.UploadStatusFailed,
+		"in_progress":  codes.UploadStatusInProgress,
+		"max_attempts": args.MaxAttempts,
+		"limit":        args.Limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return histories, nil
+}
+
+// SaveProcess 反映状況を登録/更新する
+func (u *UploadHistoryRepository) SaveProcess(ctx context.Context, process *models.UploadProcess) error {
+	query := `INSERT INTO job_upload_process
+		(kind, history_id, xid, organization_code, status, store_groups, put_count, deleted_count, attempts, last_error)
+	VALUES
+		(:kind, :history_id, :xid, :organization_code, :status, :store_groups, :put_count, :deleted_count, :attempts, :last_error)
+	ON DUPLICATE KEY UPDATE
+		status = VALUES(status),
+		store_groups = VALUES(store_groups),
+		put_count = VALUES(put_count),
+		deleted_count = VALUES(deleted_count),
+		attempts = VALUES(attempts),
+		last_error = VALUES(last_error)`
+	stmt, err := u.sqlHandler.PrepareNamedContext(ctx, query)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.ExecContext(ctx, process)
+	return err
+}
@@ -0,0 +1,175 @@
+package infra
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/notification"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/pkg/errors"
+)
+
+// Webhookのリクエストヘッダ
+const (
+	WebhookHeaderMessageID = "X-Touchgift-Message-Id"
+	WebhookHeaderTimestamp = "X-Touchgift-Timestamp"
+	// WebhookHeaderSignature "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))
+	WebhookHeaderSignature = "X-Touchgift-Signature"
+)
+
+var (
+	metricNotificationWebhookTotal       = "notification_webhook_total"
+	metricNotificationWebhookTotalDesc   = "webhook notification request count"
+	metricNotificationWebhookTotalLabels = []string{"host", "result"}
+
+	metricNotificationWebhookDuration        = "notification_webhook_duration_seconds"
+	metricNotificationWebhookDurationDesc    = "webhook notification processing time (seconds)"
+	metricNotificationWebhookDurationLabels  = []string{"host"}
+	metricNotificationWebhookDurationBuckets = []float64{0.01, 0.05, 0.1, 0.3, 0.5, 1, 3, 5, 10}
+)
+
+// notificationEnvelope SNS以外の通知先に送信するメッセージ
+// 受信側でSNSメッセージと同じように扱えるよう、SNSのHTTP(S)通知と同じ形式にする
+type notificationEnvelope struct {
+	Type              string                           `json:"Type"`
+	MessageID         string                           `json:"MessageId"`
+	TopicArn          string                           `json:"TopicArn"`
+	Message           string                           `json:"Message"`
+	Timestamp         string                           `json:"Timestamp"`
+	MessageAttributes map[string]notificationAttribute `json:"MessageAttributes,omitempty"`
+}
+
+type notificationAttribute struct {
+	Type  string `json:"Type"`
+	Value string `json:"Value"`
+}
+
+// MessageIdはWithMessageIDで指定されている場合はそれを使用し、ない場合はランダムに採番する
+func newNotificationEnvelope(ctx context.Context, message string, messageAttributes map[string]string, topicArn string) (*notificationEnvelope, error) {
+	messageID, ok := notification.MessageIDFromContext(ctx)
+	if !ok {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return nil, err
+		}
+		messageID = hex.EncodeToString(id)
+	}
+	envelope := &notificationEnvelope{
+		Type:      "Notification",
+		MessageID: messageID,
+		TopicArn:  topicArn,
+		Message:   message,
+		Timestamp: time.Now().UTC().Format(time.RFC3339Nano),
+	}
+	if len(messageAttributes) > 0 {
+		envelope.MessageAttributes = make(map[string]notificationAttribute, len(messageAttributes))
+		for k, v := range messageAttributes {
+			envelope.MessageAttributes[k] = notificationAttribute{Type: "String", Value: v}
+		}
+	}
+	return envelope, nil
+}
+
+type webhookNotificationHandler struct {
+	logger  *Logger
+	monitor *metrics.Monitor
+	config  *config.Notification
+	url     string
+	host    string
+	client  *http.Client
+}
+
+// NewWebhookNotificationHandler urlにメッセージをPOSTする
+// secretが設定されている場合は本文にHMAC-SHA256で署名する
+// 失敗した場合はエラーを返すだけで再送しない (outboxから同じMessageIdで再送される)
+func NewWebhookNotificationHandler(
+	logger *Logger,
+	monitor *metrics.Monitor,
+	config *config.Notification,
+	webhookURL string,
+) notification.NotificationHandler {
+	monitor.Metrics.AddCounter(metricNotificationWebhookTotal, metricNotificationWebhookTotalDesc, metricNotificationWebhookTotalLabels)
+	monitor.Metrics.AddHistogram(metricNotificationWebhookDuration, metricNotificationWebhookDurationDesc,
+		metricNotificationWebhookDurationLabels, metricNotificationWebhookDurationBuckets)
+	// URLのクエリなどに認証情報が含まれる可能性があるため、メトリクスにはホストのみ出力する
+	host := webhookURL
+	if u, err := url.Parse(webhookURL); err == nil {
+		host = u.Host
+	}
+	return &webhookNotificationHandler{
+		logger:  logger,
+		monitor: monitor,
+		config:  config,
+		url:     webhookURL,
+		host:    host,
+		client:  &http.Client{Timeout: config.WebhookTimeout},
+	}
+}
+
+func (w *webhookNotificationHandler) Publish(ctx context.Context, message string, messageAttributes map[string]string, topicArn string) (*string, error) {
+	startTime := time.Now()
+	defer func() {
+		w.monitor.Metrics.GetHistogram(metricNotificationWebhookDuration).WithLabelValues(w.host).Observe(time.Since(startTime).Seconds())
+	}()
+	envelope, err := newNotificationEnvelope(ctx, message, messageAttributes, topicArn)
+	if err != nil {
+		return nil, err
+	}
+	body, err := json.Marshal(envelope)
+	if err != nil {
+		return nil, err
+	}
+	if err := w.post(ctx, envelope.MessageID, body); err != nil {
+		w.monitor.Metrics.GetCounter(metricNotificationWebhookTotal).WithLabelValues(w.host, "error").Inc()
+		w.logger.Error().Err(err).Str("host", w.host).Str("message_id", envelope.MessageID).Str("message", message).Msg("Failed to publish message to webhook.")
+		return nil, err
+	}
+	w.monitor.Metrics.GetCounter(metricNotificationWebhookTotal).WithLabelValues(w.host, "success").Inc()
+	w.logger.Debug().Str("message_id", envelope.MessageID).Str("host", w.host).Msg("Publish message to webhook.")
+	return &envelope.MessageID, nil
+}
+
+func (w *webhookNotificationHandler) post(ctx context.Context, messageID string, body []byte) error {
+	request, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return errors.Wrap(err, "Failed to create webhook request")
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(WebhookHeaderMessageID, messageID)
+	request.Header.Set(WebhookHeaderTimestamp, timestamp)
+	if w.config.WebhookSecret != "" {
+		request.Header.Set(WebhookHeaderSignature, "sha256="+SignWebhook(w.config.WebhookSecret, timestamp, body))
+	}
+	response, err := w.client.Do(request)
+	if err != nil {
+		return errors.Wrap(err, "Failed to send webhook request")
+	}
+	defer response.Body.Close()
+	// コネクションを再利用できるように読み捨てる
+	_, _ = io.Copy(io.Discard, response.Body)
+	if response.StatusCode >= 200 && response.StatusCode < 300 {
+		return nil
+	}
+	return errors.Errorf("Webhook responded with status %d", response.StatusCode)
+}
+
+// SignWebhook Webhookの署名 (受信側での検証にも使用する)
+func SignWebhook(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -1,6 +1,8 @@
 package injector
 
 import (
+	"context"
+	"touchgift-job-manager/codes"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/domain/notification"
 	"touchgift-job-manager/domain/repository"
@@ -32,11 +34,59 @@ func InjectTimer(logger *infra.Logger) usecase.Timer {
 		timer = usecase.NewTimer(
 			logger,
 		)
+		if config.Env.Timer.PersistentReservation {
+			// 予約をRDBに保存し、リーダーになった時に読み込み直す
+			timer = usecase.NewReservationTimer(
+				logger,
+				metrics.GetMonitor(),
+				timer,
+				InjectReservationRepository(logger),
+			)
+		}
+		// リーダーでない場合は予約(保存を含む)をせず、リーダーでなくなった場合は予約をキャンセルする
+		timer = usecase.NewLeaderTimer(
+			timer,
+			InjectLeaderElection(logger),
+		)
+		restoreTimer := timer
+		InjectLeaderElection(logger).OnElected(func(ctx context.Context) {
+			if err := restoreTimer.Restore(ctx); err != nil {
+				logger.Error().Err(err).Msg("Failed to restore reservations")
+			}
+		})
 	}
 	return timer
 }
 
+func InjectReservationController(logger *infra.Logger) controllers.HTTPHandler {
+	return controllers.NewReservation(
+		logger,
+		InjectTimer(logger),
+	)
+}
+
+var leaderElection usecase.LeaderElection
+
+func InjectLeaderElection(logger *infra.Logger) usecase.LeaderElection {
+	subLogger := logger.With().Str("type", "leader_election").Logger()
+	if leaderElection == nil {
+		leaderElection = usecase.NewLeaderElection(
+			infra.NewLogger(&subLogger),
+			metrics.GetMonitor(),
+			&config.Env.LeaderElection,
+			InjectLeaseRepository(logger),
+		)
+	}
+	return leaderElection
+}
+
+var localQueueHandlers = map[string]infra.LocalQueueHandler{}
+
 func InjectSQSHandler(logger *infra.Logger, queueURL string) infra.SQSHandler {
+	switch config.Env.SQS.Backend {
+	case codes.QueueBackendMemory, codes.QueueBackendFile:
+		return InjectLocalQueueHandler(logger, queueURL)
+	}
 	return infra.NewSQSHandler(
 		logger,
 		InjectRegion(logger),
@@ -44,7 +94,44 @@ func InjectSQSHandler(logger *infra.Logger, queueURL string) infra.SQSHandler {
 		&config.Env.SQS.VisibilityTimeoutSeconds,
 		&config.Env.SQS.WaitTimeSeconds,
 		metrics.GetMonitor(),
+		InjectDeadLetterSink(logger),
+	)
+}
+
+// InjectLocalQueueHandler キューURLごとに同じインスタンスを返す (テストなどでメッセージを追加できるようにする)
+func InjectLocalQueueHandler(logger *infra.Logger, queueURL string) infra.LocalQueueHandler {
+	if handler, ok := localQueueHandlers[queueURL]; ok {
+		return handler
+	}
+	spoolDir := ""
+	if config.Env.SQS.Backend == codes.QueueBackendFile {
+		spoolDir = infra.LocalQueueSpoolDir(config.Env.SQS.SpoolDir, queueURL)
+	}
+	handler := infra.NewLocalQueueHandler(
+		logger,
+		&queueURL,
+		&config.Env.SQS.VisibilityTimeoutSeconds,
+		&config.Env.SQS.WaitTimeSeconds,
+		metrics.GetMonitor(),
+		InjectDeadLetterSink(logger),
+		spoolDir,
 	)
+	localQueueHandlers[queueURL] = handler
+	return handler
+}
+
+var deadLetterSink infra.DeadLetterSink
+
+func InjectDeadLetterSink(logger *infra.Logger) infra.DeadLetterSink {
+	if deadLetterSink == nil {
+		switch config.Env.DeadLetter.Sink {
+		case codes.DeadLetterSinkSQS:
+			deadLetterSink = infra.NewSQSDeadLetterSink(logger, InjectRegion(logger), &config.Env.DeadLetter.QueueURL)
+		default:
+			deadLetterSink = infra.NewFileDeadLetterSink(logger, config.Env.DeadLetter.FilePath)
+		}
+	}
+	return deadLetterSink
 }
 
 var sqlHandler infra.SQLHandler
@@ -69,6 +156,59 @@ func InjectSNSHandler(logger *infra.Logger) notification.NotificationHandler {
 	return notifactionHandler
 }
 
+var notificationHandler notification.NotificationHandler
+
+// InjectNotificationHandler キャッシュイベントの通知先 (config.Notificationのsinks/routesで組み合わせる)
+func InjectNotificationHandler(logger *infra.Logger) notification.NotificationHandler {
+	if notificationHandler == nil {
+		sinks := map[string]notification.NotificationHandler{}
+		build := func(names []string) notification.NotificationHandler {
+			handlers := make([]notification.NotificationHandler, 0, len(names))
+			for _, name := range names {
+				if _, ok := sinks[name]; !ok {
+					switch name {
+					case codes.NotificationSinkSNS:
+						sinks[name] = InjectSNSHandler(logger)
+					case codes.NotificationSinkWebhook:
+						urls := infra.ParseNotificationSinks(config.Env.Notification.WebhookURLs)
+						if len(urls) == 0 {
+							logger.Fatal().Msg("NOTIFICATION_WEBHOOK_URLS is required for webhook sink")
+						}
+						webhooks := make([]notification.NotificationHandler, 0, len(urls))
+						for _, url := range urls {
+							webhooks = append(webhooks, infra.NewWebhookNotificationHandler(
+								logger, metrics.GetMonitor(), &config.Env.Notification, url))
+						}
+						sinks[name] = infra.NewFanoutNotificationHandler(webhooks...)
+					case codes.NotificationSinkFile:
+						sinks[name] = infra.NewFileNotificationHandler(logger, config.Env.Notification.FilePath)
+					default:
+						logger.Fatal().Str("sink", name).Msg("Unknown notification sink")
+					}
+				}
+				handlers = append(handlers, sinks[name])
+			}
+			if len(handlers) == 1 {
+				return handlers[0]
+			}
+			return infra.NewFanoutNotificationHandler(handlers...)
+		}
+		routes, err := infra.ParseNotificationRoutes(config.Env.Notification.Routes)
+		if err != nil {
+			logger.Fatal().Err(err).Msg("Failed to parse NOTIFICATION_ROUTES")
+		}
+		routeHandlers := make(map[string]notification.NotificationHandler, len(routes))
+		for topic, names := range routes {
+			routeHandlers[topic] = build(names)
+		}
+		notificationHandler = infra.NewNotificationRouter(
+			routeHandlers,
+			build(infra.ParseNotificationSinks(config.Env.Notification.Sinks)),
+		)
+	}
+	return notificationHandler
+}
+
 var creativeUsecase usecase.Creative
 
 func InjectCreativeUsecase(logger *infra.Logger) usecase.Creative {
@@ -102,6 +242,7 @@ func InjectDeliveryStartUsecase(logger *infra.Logger) usecase.DeliveryStart {
 			InjectContentDataRepository(logger),
 			InjectCreativeDataRepository(logger),
 			InjectTouchPointDataRepository(logger),
+			InjectPrewarmRepository(logger),
 		)
 	}
 	return deliveryStartUsecase
@@ -144,23 +285,200 @@ func InjectDeliveryOperationUsecase(logger *infra.Logger) usecase.DeliveryOperat
 			InjectDeliveryStartUsecase(logger),
 			InjectDeliveryEndUsecase(logger),
 			InjectDeliveryControlEventUsecase(logger),
+			InjectPrewarmRepository(logger),
+			InjectTimer(logger),
 		)
 	}
 	return deliveryOperationUsecase
 }
 
+var adminCampaignUsecase usecase.AdminCampaign
+
+func InjectAdminCampaignUsecase(logger *infra.Logger) usecase.AdminCampaign {
+	if adminCampaignUsecase == nil {
+		adminCampaignUsecase = usecase.NewAdminCampaign(
+			logger,
+			InjectSQLHandler(logger),
+			InjectCampaignRepository(logger),
+			InjectTouchPointRepository(logger),
+			InjectCampaignDataRepository(logger),
+			InjectContentDataRepository(logger),
+			InjectCreativeDataRepository(logger),
+			InjectTouchPointDataRepository(logger),
+			InjectDeliveryStartUsecase(logger),
+			InjectDeliveryEndUsecase(logger),
+			InjectDeliveryOperationUsecase(logger),
+		)
+	}
+	return adminCampaignUsecase
+}
+
+var maintenanceUsecase usecase.Maintenance
+
+func InjectMaintenanceUsecase(logger *infra.Logger) usecase.Maintenance {
+	if maintenanceUsecase == nil {
+		maintenanceUsecase = usecase.NewMaintenance(
+			logger,
+			InjectSQLHandler(logger),
+			InjectCreativeRepository(logger),
+			InjectCreativeDataRepository(logger),
+			InjectCreativeUsecase(logger),
+		)
+	}
+	return maintenanceUsecase
+}
+
+var deliveryControlUsecase usecase.DeliveryControl
+
+func InjectDeliveryControlUsecase(logger *infra.Logger) usecase.DeliveryControl {
+	if deliveryControlUsecase == nil {
+		deliveryControlUsecase = usecase.NewDeliveryControl(
+			logger,
+			metrics.GetMonitor(),
+			InjectSQLHandler(logger),
+			InjectCampaignRepository(logger),
+			InjectDeliveryControlEventUsecase(logger),
+			InjectDeliveryEndUsecase(logger),
+		)
+	}
+	return deliveryControlUsecase
+}
+
+var messageDedupUsecase usecase.MessageDedup
+
+func InjectMessageDedupUsecase(logger *infra.Logger) usecase.MessageDedup {
+	if messageDedupUsecase == nil {
+		messageDedupUsecase = usecase.NewMessageDedup(
+			logger,
+			metrics.GetMonitor(),
+			&config.Env.MessageDedup,
+			InjectMessageDedupRepository(logger),
+		)
+	}
+	return messageDedupUsecase
+}
+
+var reconcileUsecase usecase.Reconcile
+
+func InjectReconcileUsecase(logger *infra.Logger) usecase.Reconcile {
+	if reconcileUsecase == nil {
+		reconcileUsecase = usecase.NewReconcile(
+			logger,
+			metrics.GetMonitor(),
+			InjectSQLHandler(logger),
+			InjectCampaignRepository(logger),
+			InjectCreativeRepository(logger),
+			InjectContentRepository(logger),
+			InjectTouchPointRepository(logger),
+			InjectCampaignDataRepository(logger),
+			InjectContentDataRepository(logger),
+			InjectCreativeDataRepository(logger),
+			InjectTouchPointDataRepository(logger),
+			InjectDeliveryControlEventUsecase(logger),
+			InjectDeliveryStartUsecase(logger),
+			InjectDeliveryEndUsecase(logger),
+			InjectMaintenanceUsecase(logger),
+			InjectCreativeUsecase(logger),
+		)
+	}
+	return reconcileUsecase
+}
+
+var touchPointSyncUsecase usecase.TouchPointSync
+
+func InjectTouchPointSyncUsecase(logger *infra.Logger) usecase.TouchPointSync {
+	if touchPointSyncUsecase == nil {
+		touchPointSyncUsecase = usecase.NewTouchPointSync(
+			logger,
+			metrics.GetMonitor(),
+			&config.Env.TouchPointSync,
+			InjectSQLHandler(logger),
+			InjectCampaignRepository(logger),
+			InjectTouchPointRepository(logger),
+			InjectTouchPointDataRepository(logger),
+			InjectDeliveryControlEventUsecase(logger),
+		)
+	}
+	return touchPointSyncUsecase
+}
+
+var timezone usecase.Timezone
+
+func InjectTimezone() usecase.Timezone {
+	if timezone == nil {
+		var err error
+		timezone, err = usecase.NewTimezone(&config.Env.Timezone)
+		if err != nil {
+			panic(err)
+		}
+	}
+	return timezone
+}
+
+var daypartUsecase usecase.Daypart
+
+func InjectDaypartUsecase(logger *infra.Logger) usecase.Daypart {
+	if daypartUsecase == nil {
+		daypartUsecase = usecase.NewDaypart(
+			logger,
+			metrics.GetMonitor(),
+			InjectSQLHandler(logger),
+			InjectCampaignRepository(logger),
+			InjectDaypartRepository(logger),
+			InjectDeliveryStartUsecase(logger),
+			InjectDeliveryEndUsecase(logger),
+			InjectDeliveryControlEventUsecase(logger),
+			InjectTimezone(),
+		)
+	}
+	return daypartUsecase
+}
+
+var uploadHistoryUsecase usecase.UploadHistory
+
+func InjectUploadHistoryUsecase(logger *infra.Logger) usecase.UploadHistory {
+	if uploadHistoryUsecase == nil {
+		uploadHistoryUsecase = usecase.NewUploadHistory(
+			logger,
+			metrics.GetMonitor(),
+			&config.Env.UploadHistory,
+			InjectUploadHistoryRepository(logger),
+			InjectTouchPointSyncUsecase(logger),
+		)
+	}
+	return uploadHistoryUsecase
+}
+
 var deliveryControlEventUsecase usecase.DeliveryControlEvent
 
 func InjectDeliveryControlEventUsecase(logger *infra.Logger) usecase.DeliveryControlEvent {
 	if deliveryControlEventUsecase == nil {
 		deliveryControlEventUsecase = usecase.NewDeliveryControlEvent(
 			logger,
-			InjectSNSHandler(logger),
+			&config.Env.DeliveryEventBatch,
+			InjectOutboxRepository(logger),
+			InjectTimezone(),
 		)
 	}
 	return deliveryControlEventUsecase
 }
 
+var outboxRelayUsecase usecase.OutboxRelay
+
+func InjectOutboxRelayUsecase(logger *infra.Logger) usecase.OutboxRelay {
+	if outboxRelayUsecase == nil {
+		outboxRelayUsecase = usecase.NewOutboxRelay(
+			logger,
+			metrics.GetMonitor(),
+			&config.Env.Outbox,
+			InjectSQLHandler(logger),
+			InjectOutboxRepository(logger),
+			InjectNotificationHandler(logger),
+		)
+	}
+	return outboxRelayUsecase
+}
+
 var appTicker controllers.AppTicker
 
 func InjectAppTicker() controllers.AppTicker {
@@ -180,11 +498,41 @@ func InjectDeliveryOperationSyncController(logger *infra.Logger) controllers.Del
 			metrics.GetMonitor(),
 			InjectSQSHandler(logger, config.Env.SQS.DeliveryOperationQueueURL),
 			InjectDeliveryOperationUsecase(logger),
+			InjectMessageDedupUsecase(logger),
 		)
 	}
 	return deliveryOperationSyncController
 }
 
+var replayController controllers.Replay
+
+func InjectReplayController(logger *infra.Logger) controllers.Replay {
+	subLogger := logger.With().Str("type", "replay").Logger()
+	if replayController == nil {
+		replayController = controllers.NewReplay(
+			infra.NewLogger(&subLogger),
+			config.Env.SQS.DeliveryOperationQueueURL,
+			InjectDeliveryOperationUsecase(logger),
+		)
+	}
+	return replayController
+}
+
+var adminCampaignController controllers.AdminCampaign
+
+func InjectAdminCampaignController(logger *infra.Logger) controllers.AdminCampaign {
+	subLogger := logger.With().Str("type", "admin").Logger()
+	auditLogger := logger.With().Str("type", "audit").Logger()
+	if adminCampaignController == nil {
+		adminCampaignController = controllers.NewAdminCampaign(
+			infra.NewLogger(&subLogger),
+			infra.NewLogger(&auditLogger),
+			InjectAdminCampaignUsecase(logger),
+		)
+	}
+	return adminCampaignController
+}
+
 var deliveryStartController controllers.DeliveryStart
 
 func InjectDeliveryStartController(logger *infra.Logger) controllers.DeliveryStart {
@@ -198,6 +546,7 @@ func InjectDeliveryStartController(logger *infra.Logger) controllers.DeliverySta
 			InjectSQLHandler(logger),
 			InjectDeliveryStartUsecase(logger),
 			InjectDeliveryControlEventUsecase(logger),
+			InjectLeaderElection(logger),
 		)
 	}
 	return deliveryStartController
@@ -215,19 +564,170 @@ func InjectDeliveryEndController(logger *infra.Logger) controllers.DeliveryEnd {
 			InjectAppTicker(),
 			InjectSQLHandler(logger),
 			InjectDeliveryEndUsecase(logger),
+			InjectLeaderElection(logger),
 		)
 	}
 	return deliveryEndController
 }
 
-// func InjectDeliveryControlSyncController(logger *infra.Logger) controllers.DeliveryControlSync {
-// 	subLogger := logger.With().Str("type", "delivery_control_event").Logger()
-// 	return controllers.NewDeliveryControlSync(
-// 		infra.NewLogger(&subLogger),
-// 		InjectSQSHandler(logger, config.Env.SQS.DeliveryControlQueueURL),
-// 		InjectDeliveryControlEventUsecase(logger),
-// 	)
-// }
+var reconcileController controllers.Reconcile
+
+func InjectReconcileController(logger *infra.Logger) controllers.Reconcile {
+	subLogger := logger.With().Str("type", "reconcile").Logger()
+	if reconcileController == nil {
+		reconcileController = controllers.NewReconcile(
+			infra.NewLogger(&subLogger),
+			&config.Env.Reconcile,
+			InjectAppTicker(),
+			InjectReconcileUsecase(logger),
+			InjectLeaderElection(logger),
+		)
+	}
+	return reconcileController
+}
+
+var touchPointSyncController controllers.TouchPointSync
+
+func InjectTouchPointSyncController(logger *infra.Logger) controllers.TouchPointSync {
+	subLogger := logger.With().Str("type", "touch_point_sync").Logger()
+	if touchPointSyncController == nil {
+		touchPointSyncController = controllers.NewTouchPointSync(
+			infra.NewLogger(&subLogger),
+			&config.Env.TouchPointSync,
+			InjectAppTicker(),
+			InjectTouchPointSyncUsecase(logger),
+			InjectLeaderElection(logger),
+		)
+	}
+	return touchPointSyncController
+}
+
+var daypartController controllers.Daypart
+
+func InjectDaypartController(logger *infra.Logger) controllers.Daypart {
+	subLogger := logger.With().Str("type", "daypart").Logger()
+	if daypartController == nil {
+		daypartController = controllers.NewDaypart(
+			infra.NewLogger(&subLogger),
+			&config.Env.Daypart,
+			InjectAppTicker(),
+			InjectDaypartUsecase(logger),
+			InjectLeaderElection(logger),
+		)
+	}
+	return daypartController
+}
+
+var uploadHistoryController controllers.UploadHistory
+
+func InjectUploadHistoryController(logger *infra.Logger) controllers.UploadHistory {
+	subLogger := logger.With().Str("type", "upload_history").Logger()
+	if uploadHistoryController == nil {
+		uploadHistoryController = controllers.NewUploadHistory(
+			infra.NewLogger(&subLogger),
+			&config.Env.UploadHistory,
+			InjectAppTicker(),
+			InjectUploadHistoryUsecase(logger),
+			InjectLeaderElection(logger),
+		)
+	}
+	return uploadHistoryController
+}
+
+var outboxRelayController controllers.OutboxRelay
+
+func InjectOutboxRelayController(logger *infra.Logger) controllers.OutboxRelay {
+	subLogger := logger.With().Str("type", "outbox_relay").Logger()
+	if outboxRelayController == nil {
+		outboxRelayController = controllers.NewOutboxRelay(
+			infra.NewLogger(&subLogger),
+			&config.Env.Outbox,
+			InjectAppTicker(),
+			InjectOutboxRelayUsecase(logger),
+			InjectLeaderElection(logger),
+		)
+	}
+	return outboxRelayController
+}
+
+var deliveryControlSyncController controllers.DeliveryControlSync
+
+func InjectDeliveryControlSyncController(logger *infra.Logger) controllers.DeliveryControlSync {
+	subLogger := logger.With().Str("type", "delivery_control").Logger()
+	if deliveryControlSyncController == nil {
+		deliveryControlSyncController = controllers.NewDeliveryControlSync(
+			infra.NewLogger(&subLogger),
+			metrics.GetMonitor(),
+			InjectSQSHandler(logger, config.Env.SQS.DeliveryControlQueueURL),
+			InjectDeliveryControlUsecase(logger),
+		)
+	}
+	return deliveryControlSyncController
+}
+
+var messageDedupRepository repository.MessageDedupRepository
+
+func InjectMessageDedupRepository(logger *infra.Logger) repository.MessageDedupRepository {
+	if messageDedupRepository == nil {
+		switch config.Env.MessageDedup.Backend {
+		case codes.MessageDedupBackendDynamoDB:
+			messageDedupRepository = infra.NewDynamoDBMessageDedupRepository(
+				infra.NewDynamoDBHandler(logger, InjectRegion(logger)),
+				logger,
+			)
+		default:
+			messageDedupRepository = infra.NewRDBMessageDedupRepository(
+				logger,
+				InjectSQLHandler(logger),
+			)
+		}
+	}
+	return messageDedupRepository
+}
+
+var leaseRepository repository.LeaseRepository
+
+func InjectLeaseRepository(logger *infra.Logger) repository.LeaseRepository {
+	if leaseRepository == nil {
+		switch config.Env.LeaderElection.Backend {
+		case codes.LeaseBackendDynamoDB:
+			leaseRepository = infra.NewDynamoDBLeaseRepository(
+				infra.NewDynamoDBHandler(logger, InjectRegion(logger)),
+				logger,
+			)
+		default:
+			leaseRepository = infra.NewRDBLeaseRepository(
+				logger,
+				InjectSQLHandler(logger),
+			)
+		}
+	}
+	return leaseRepository
+}
+
+var reservationRepository repository.ReservationRepository
+
+func InjectReservationRepository(logger *infra.Logger) repository.ReservationRepository {
+	if reservationRepository == nil {
+		reservationRepository = infra.NewReservationRepository(
+			logger,
+			InjectSQLHandler(logger),
+		)
+	}
+	return reservationRepository
+}
+
+var outboxRepository repository.OutboxRepository
+
+func InjectOutboxRepository(logger *infra.Logger) repository.OutboxRepository {
+	if outboxRepository == nil {
+		outboxRepository = infra.NewOutboxRepository(
+			logger,
+			InjectSQLHandler(logger),
+		)
+	}
+	return outboxRepository
+}
 
 var campaignRepository repository.CampaignRepository
 
@@ -265,6 +765,42 @@ func InjectContentRepository(logger *infra.Logger) repository.ContentRepository
 	return contentRepository
 }
 
+var uploadHistoryRepository repository.UploadHistoryRepository
+
+func InjectUploadHistoryRepository(logger *infra.Logger) repository.UploadHistoryRepository {
+	if uploadHistoryRepository == nil {
+		uploadHistoryRepository = infra.NewUploadHistoryRepository(
+			logger,
+			InjectSQLHandler(logger),
+		)
+	}
+	return uploadHistoryRepository
+}
+
+var daypartRepository repository.DaypartRepository
+
+func InjectDaypartRepository(logger *infra.Logger) repository.DaypartRepository {
+	if daypartRepository == nil {
+		daypartRepository = infra.NewDaypartRepository(
+			logger,
+			InjectSQLHandler(logger),
+		)
+	}
+	return daypartRepository
+}
+
+var prewarmRepository repository.PrewarmRepository
+
+func InjectPrewarmRepository(logger *infra.Logger) repository.PrewarmRepository {
+	if prewarmRepository == nil {
+		prewarmRepository = infra.NewPrewarmRepository(
+			logger,
+			InjectSQLHandler(logger),
+		)
+	}
+	return prewarmRepository
+}
+
 var touchPointRepository repository.TouchPointRepository
 
 func InjectTouchPointRepository(logger *infra.Logger) repository.TouchPointRepository {
@@ -27,24 +27,75 @@ func AdminRoute(ctx context.Context, router *gin.Engine) (*gin.Engine, Initializ
 	monitor := metrics.GetMonitor()
 
 	monitor.AddRoute(router, config.Env.Server.MetricsPath)
+	// 未実行の開始/終了予約
+	router.GET("/reservations", func(c *gin.Context) {
+		InjectReservationController(logger).Handler(infra.NewContext(c))
+	})
+	// キャンペーン配信状態の確認・操作
+	adminCampaign := InjectAdminCampaignController(logger)
+	campaigns := router.Group("/campaigns")
+	campaigns.GET("/:id", func(c *gin.Context) {
+		adminCampaign.Get(infra.NewContext(c))
+	})
+	campaigns.POST("/:id/start", func(c *gin.Context) {
+		adminCampaign.Start(infra.NewContext(c))
+	})
+	campaigns.POST("/:id/end", func(c *gin.Context) {
+		adminCampaign.End(infra.NewContext(c))
+	})
+	campaigns.POST("/:id/sync", func(c *gin.Context) {
+		adminCampaign.Sync(infra.NewContext(c))
+	})
+	// 配信開始/終了処理で登録・削除する配信データの確認 (action: start, end)
+	campaigns.GET("/:id/plan/:action", func(c *gin.Context) {
+		adminCampaign.Plan(infra.NewContext(c))
+	})
 
+	leaderElection := InjectLeaderElection(logger)
 	deliveryOperationSync := InjectDeliveryOperationSyncController(logger)
 	deliveryStart := InjectDeliveryStartController(logger)
 	deliveryEnd := InjectDeliveryEndController(logger)
-	// deliveryControlSync := InjectDeliveryControlSyncController(logger)
+	reconcile := InjectReconcileController(logger)
+	touchPointSync := InjectTouchPointSyncController(logger)
+	daypart := InjectDaypartController(logger)
+	uploadHistory := InjectUploadHistoryController(logger)
+	outboxRelay := InjectOutboxRelayController(logger)
+	deliveryControlSync := InjectDeliveryControlSyncController(logger)
 
 	var wg sync.WaitGroup
 	initialize := func() error {
+		// SQSの処理は全タスクで行い、開始/終了の監視はリーダーのみが行う
+		go leaderElection.Run(ctx, &wg)
 		deliveryOperationSync.Start(ctx, &wg)
+		deliveryControlSync.Start(ctx, &wg)
 		go deliveryStart.StartMonitoring(ctx, &wg)
 		go deliveryEnd.StartMonitoring(ctx, &wg)
+		go outboxRelay.StartMonitoring(ctx, &wg)
+		if config.Env.Reconcile.Enabled {
+			go reconcile.StartMonitoring(ctx, &wg)
+		}
+		if config.Env.TouchPointSync.Enabled {
+			go touchPointSync.StartMonitoring(ctx, &wg)
+		}
+		if config.Env.Daypart.Enabled {
+			go daypart.StartMonitoring(ctx, &wg)
+		}
+		if config.Env.UploadHistory.Enabled {
+			go uploadHistory.StartMonitoring(ctx, &wg)
+		}
 		return nil
 	}
 	terminate := func() error {
 		wg.Wait()
 		deliveryOperationSync.Close()
+		deliveryControlSync.Close()
 		deliveryStart.Close()
 		deliveryEnd.Close()
+		reconcile.Close()
+		touchPointSync.Close()
+		daypart.Close()
+		uploadHistory.Close()
+		outboxRelay.Close()
 		return nil
 	}
 	return router, initialize, terminate
@@ -0,0 +1,194 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/usecase"
+
+	"github.com/pkg/errors"
+)
+
+// AdminCampaign 管理API(キャンペーン配信状態の確認・操作)
+// 操作は全て監査ログ(type=audit)に出力する
+type AdminCampaign interface {
+	// Get RDBのキャンペーンとDynamoDBの配信データを返す
+	Get(c Context)
+	// Start 配信開始処理を実行する
+	Start(c Context)
+	// End 配信終了処理を実行する
+	End(c Context)
+	// Sync 配信データを同期し直す
+	Sync(c Context)
+	// Plan 配信開始/終了処理で登録・削除する配信データと配信制御イベントを返す (実行はしない)
+	Plan(c Context)
+}
+
+type adminCampaign struct {
+	logger               usecase.Logger
+	auditLogger          usecase.Logger
+	adminCampaignUsecase usecase.AdminCampaign
+}
+
+// NewAdminCampaign is function
+func NewAdminCampaign(
+	logger usecase.Logger,
+	auditLogger usecase.Logger,
+	adminCampaignUsecase usecase.AdminCampaign,
+) AdminCampaign {
+	return &adminCampaign{
+		logger:               logger,
+		auditLogger:          auditLogger,
+		adminCampaignUsecase: adminCampaignUsecase,
+	}
+}
+
+func (a *adminCampaign) Get(c Context) {
+	campaignID, ok := a.campaignID(c, "get")
+	if !ok {
+		return
+	}
+	inspection, err := a.adminCampaignUsecase.Inspect(c, campaignID)
+	if err == codes.ErrNoData {
+		a.audit(c, "get", campaignID, http.StatusNotFound, err)
+		c.JSON(http.StatusNotFound, map[string]string{"message": "campaign not found"})
+		return
+	}
+	if err != nil {
+		a.audit(c, "get", campaignID, http.StatusInternalServerError, err)
+		c.InternalError(err)
+		return
+	}
+	a.audit(c, "get", campaignID, http.StatusOK, nil)
+	c.JSON(http.StatusOK, inspection)
+}
+
+func (a *adminCampaign) Start(c Context) {
+	campaignID, ok := a.campaignID(c, "start")
+	if !ok {
+		return
+	}
+	// 非同期で処理されるため受付のみ返す
+	if err := a.adminCampaignUsecase.Start(c, campaignID); err != nil {
+		a.operationError(c, "start", campaignID, err)
+		return
+	}
+	a.audit(c, "start", campaignID, http.StatusAccepted, nil)
+	c.JSON(http.StatusAccepted, map[string]interface{}{"campaign_id": campaignID, "action": "start"})
+}
+
+func (a *adminCampaign) End(c Context) {
+	campaignID, ok := a.campaignID(c, "end")
+	if !ok {
+		return
+	}
+	// 非同期で処理されるため受付のみ返す
+	if err := a.adminCampaignUsecase.End(c, campaignID); err != nil {
+		a.operationError(c, "end", campaignID, err)
+		return
+	}
+	a.audit(c, "end", campaignID, http.StatusAccepted, nil)
+	c.JSON(http.StatusAccepted, map[string]interface{}{"campaign_id": campaignID, "action": "end"})
+}
+
+func (a *adminCampaign) Sync(c Context) {
+	campaignID, ok := a.campaignID(c, "sync")
+	if !ok {
+		return
+	}
+	err := a.adminCampaignUsecase.Sync(c, campaignID)
+	if err == codes.ErrDoNothing {
+		// 同期対象外のステータス
+		a.audit(c, "sync", campaignID, http.StatusOK, err)
+		c.JSON(http.StatusOK, map[string]interface{}{"campaign_id": campaignID, "action": "sync", "result": "do_nothing"})
+		return
+	}
+	if err != nil {
+		a.audit(c, "sync", campaignID, http.StatusInternalServerError, err)
+		c.InternalError(err)
+		return
+	}
+	a.audit(c, "sync", campaignID, http.StatusOK, nil)
+	c.JSON(http.StatusOK, map[string]interface{}{"campaign_id": campaignID, "action": "sync", "result": "synced"})
+}
+
+func (a *adminCampaign) Plan(c Context) {
+	campaignID, ok := a.campaignID(c, "plan")
+	if !ok {
+		return
+	}
+	action := c.Param("action")
+	if action != codes.PlanActionStart && action != codes.PlanActionEnd {
+		err := errors.Errorf("Invalid plan action: %s", action)
+		a.audit(c, "plan", campaignID, http.StatusBadRequest, err)
+		c.BindError(err)
+		return
+	}
+	plan, err := a.adminCampaignUsecase.Plan(c, campaignID, action)
+	if err == codes.ErrNoData {
+		a.audit(c, "plan", campaignID, http.StatusNotFound, err)
+		c.JSON(http.StatusNotFound, map[string]string{"message": "campaign not found"})
+		return
+	}
+	var validationErr *models.CampaignValidationError
+	if errors.As(err, &validationErr) {
+		// 配信内容に不備があり開始できない
+		a.audit(c, "plan", campaignID, http.StatusUnprocessableEntity, err)
+		c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{"message": "campaign is not ready to start", "reasons": validationErr.Reasons})
+		return
+	}
+	if err != nil {
+		a.audit(c, "plan", campaignID, http.StatusInternalServerError, err)
+		c.InternalError(err)
+		return
+	}
+	a.audit(c, "plan", campaignID, http.StatusOK, nil)
+	c.JSON(http.StatusOK, plan)
+}
+
+// 開始・終了を受け付けられなかった場合のレスポンスを返す
+func (a *adminCampaign) operationError(c Context, action string, campaignID int, err error) {
+	switch {
+	case err == codes.ErrNoData:
+		a.audit(c, action, campaignID, http.StatusNotFound, err)
+		c.JSON(http.StatusNotFound, map[string]string{"message": "campaign not found"})
+	case errors.Is(err, codes.ErrInvalidStatus):
+		// 処理対象外のステータス
+		a.audit(c, action, campaignID, http.StatusConflict, err)
+		c.JSON(http.StatusConflict, map[string]string{"message": err.Error()})
+	default:
+		a.audit(c, action, campaignID, http.StatusInternalServerError, err)
+		c.InternalError(err)
+	}
+}
+
+func (a *adminCampaign) campaignID(c Context, action string) (int, bool) {
+	campaignID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		a.audit(c, action, 0, http.StatusBadRequest, err)
+		c.BindError(errors.Wrap(err, "Invalid campaign id"))
+		return 0, false
+	}
+	return campaignID, true
+}
+
+// 監査ログを出力する
+func (a *adminCampaign) audit(c Context, action string, campaignID int, status int, err error) {
+	event := a.auditLogger.Info()
+	if err != nil && status >= http.StatusInternalServerError {
+		event = a.auditLogger.Error().Err(err)
+	} else if err != nil {
+		event = event.Str("reason", err.Error())
+	}
+	request := c.Request()
+	event.Str("request_id", c.RequestID()).
+		Str("action", action).
+		Int("campaign_id", campaignID).
+		Str("method", request.Method).
+		Str("path", request.URL.Path).
+		Str("remote_addr", request.RemoteAddr).
+		Str("user_agent", request.UserAgent()).
+		Int("status", status).
+		Msg("Admin campaign operation")
+}
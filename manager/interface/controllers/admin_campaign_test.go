@@ -0,0 +1,192 @@
+package controllers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/infra"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+
+	mock_usecase "touchgift-job-manager/mock/usecase"
+)
+
+func TestAdminCampaign(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	gin.SetMode(gin.TestMode)
+
+	newContext := func(method string, id string, params ...gin.Param) (*httptest.ResponseRecorder, Context) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, "/campaigns/"+id, nil)
+		c.Params = append(gin.Params{{Key: "id", Value: id}}, params...)
+		return w, infra.NewContext(c)
+	}
+
+	t.Run("キャンペーンの配信状態を返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		w, c := newContext(http.MethodGet, "1")
+		expected := &models.DeliveryInspection{Campaign: &models.Campaign{ID: 1, Status: "started"}}
+		adminCampaignUsecase.EXPECT().Inspect(gomock.Eq(c), gomock.Eq(1)).Return(expected, nil).Times(1)
+		adminCampaign.Get(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		actual := models.DeliveryInspection{}
+		if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual)) {
+			assert.Equal(t, 1, actual.Campaign.ID)
+			assert.Equal(t, "started", actual.Campaign.Status)
+		}
+	})
+
+	t.Run("キャンペーンがない場合は404を返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		w, c := newContext(http.MethodGet, "1")
+		adminCampaignUsecase.EXPECT().Inspect(gomock.Eq(c), gomock.Eq(1)).Return(nil, codes.ErrNoData).Times(1)
+		adminCampaign.Get(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("キャンペーンIDが数値でない場合は処理しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		_, c := newContext(http.MethodPost, "abc")
+		adminCampaign.Start(c)
+		assert.Len(t, c.(*infra.AppContext).Errors, 1)
+	})
+
+	t.Run("開始・終了は受付を返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		w, c := newContext(http.MethodPost, "1")
+		adminCampaignUsecase.EXPECT().Start(gomock.Eq(c), gomock.Eq(1)).Return(nil).Times(1)
+		adminCampaign.Start(c)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+
+		w, c = newContext(http.MethodPost, "2")
+		adminCampaignUsecase.EXPECT().End(gomock.Eq(c), gomock.Eq(2)).Return(nil).Times(1)
+		adminCampaign.End(c)
+		assert.Equal(t, http.StatusAccepted, w.Code)
+	})
+
+	t.Run("キャンペーンがない場合は404、処理対象外のステータスの場合は409を返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		w, c := newContext(http.MethodPost, "1")
+		adminCampaignUsecase.EXPECT().Start(gomock.Eq(c), gomock.Eq(1)).Return(codes.ErrNoData).Times(1)
+		adminCampaign.Start(c)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+
+		w, c = newContext(http.MethodPost, "2")
+		adminCampaignUsecase.EXPECT().End(gomock.Eq(c), gomock.Eq(2)).
+			Return(fmt.Errorf("status: started: %w", codes.ErrInvalidStatus)).Times(1)
+		adminCampaign.End(c)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("同期に失敗した場合はエラーにする", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		_, c := newContext(http.MethodPost, "1")
+		adminCampaignUsecase.EXPECT().Sync(gomock.Eq(c), gomock.Eq(1)).Return(errors.New("error")).Times(1)
+		adminCampaign.Sync(c)
+		assert.Len(t, c.(*infra.AppContext).Errors, 1)
+	})
+
+	t.Run("処理内容を返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		w, c := newContext(http.MethodGet, "1", gin.Param{Key: "action", Value: codes.PlanActionStart})
+		expected := &models.DeliveryPlan{Action: codes.PlanActionStart, CampaignID: 1,
+			Items: []*models.DeliveryPlanItem{{Table: codes.PlanTableCampaign, Operation: codes.PlanOperationPut}}}
+		adminCampaignUsecase.EXPECT().Plan(gomock.Eq(c), gomock.Eq(1), gomock.Eq(codes.PlanActionStart)).Return(expected, nil).Times(1)
+		adminCampaign.Plan(c)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		actual := models.DeliveryPlan{}
+		if assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &actual)) {
+			assert.Equal(t, codes.PlanActionStart, actual.Action)
+			assert.Len(t, actual.Items, 1)
+		}
+	})
+
+	t.Run("配信内容に不備がある場合は422と理由を返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		w, c := newContext(http.MethodGet, "1", gin.Param{Key: "action", Value: codes.PlanActionStart})
+		adminCampaignUsecase.EXPECT().Plan(gomock.Eq(c), gomock.Eq(1), gomock.Eq(codes.PlanActionStart)).
+			Return(nil, &models.CampaignValidationError{CampaignID: 1, Reasons: []string{"no creative"}}).Times(1)
+		adminCampaign.Plan(c)
+
+		assert.Equal(t, http.StatusUnprocessableEntity, w.Code)
+		assert.Contains(t, w.Body.String(), "no creative")
+	})
+
+	t.Run("キャンペーンがない場合は404を返す (plan)", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		w, c := newContext(http.MethodGet, "1", gin.Param{Key: "action", Value: codes.PlanActionEnd})
+		adminCampaignUsecase.EXPECT().Plan(gomock.Eq(c), gomock.Eq(1), gomock.Eq(codes.PlanActionEnd)).Return(nil, codes.ErrNoData).Times(1)
+		adminCampaign.Plan(c)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("処理がstart,end以外の場合は処理しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaignUsecase := mock_usecase.NewMockAdminCampaign(ctrl)
+		adminCampaign := NewAdminCampaign(logger, logger, adminCampaignUsecase)
+
+		_, c := newContext(http.MethodGet, "1", gin.Param{Key: "action", Value: "pause"})
+		adminCampaign.Plan(c)
+		assert.Len(t, c.(*infra.AppContext).Errors, 1)
+	})
+}
@@ -13,7 +13,8 @@ func NewAppTicker() AppTicker {
 	return &appTicker{}
 }
 
+// 次のunitの境界(UTC基準)まで待ってからTickerを作成する
 func (a *appTicker) New(interval time.Duration, unit time.Duration) *time.Ticker {
-	time.Sleep(time.Until(time.Now().Add(1 * unit).Truncate(unit)))
+	time.Sleep(time.Until(time.Now().UTC().Add(1 * unit).Truncate(unit)))
 	return time.NewTicker(interval)
 }
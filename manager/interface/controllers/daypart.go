@@ -0,0 +1,102 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/usecase"
+
+	"github.com/pkg/errors"
+)
+
+// Daypart 配信時間帯(dayparting)の境界でキャンペーンを定期的に一時停止/再開する
+type Daypart interface {
+	StartMonitoring(ctx context.Context, wg *sync.WaitGroup)
+	Close()
+}
+
+type daypart struct {
+	logger         usecase.Logger
+	config         *config.Daypart
+	appTicker      AppTicker
+	daypartUsecase usecase.Daypart
+	leaderElection usecase.LeaderElection
+	wg             *sync.WaitGroup
+}
+
+func NewDaypart(
+	logger usecase.Logger,
+	config *config.Daypart,
+	appTicker AppTicker,
+	daypartUsecase usecase.Daypart,
+	leaderElection usecase.LeaderElection,
+) Daypart {
+	return &daypart{
+		logger:         logger,
+		config:         config,
+		appTicker:      appTicker,
+		daypartUsecase: daypartUsecase,
+		leaderElection: leaderElection,
+		wg:             &sync.WaitGroup{},
+	}
+}
+
+func (d *daypart) StartMonitoring(ctx context.Context, wg *sync.WaitGroup) {
+	d.logger.Info().Msg("Start monitoring daypart")
+	wg.Add(1)
+	// 配信時間帯は分単位で切り替えるため、分の境界から実行する
+	ticker := d.appTicker.New(d.config.TaskInterval, time.Minute)
+	defer ticker.Stop()
+	running := false
+	mu := sync.Mutex{}
+	for {
+		select {
+		case now := <-ticker.C:
+			if !d.leaderElection.IsLeader() {
+				// リーダー以外は切り替えを行わない
+				d.logger.Debug().Msg("Skip daypart (not leader)")
+				continue
+			}
+			mu.Lock()
+			if running {
+				// 前回の処理が終わっていない場合は実行しない
+				mu.Unlock()
+				d.logger.Warn().Msg("Skip daypart (previous process is running)")
+				continue
+			}
+			running = true
+			mu.Unlock()
+			d.wg.Add(1)
+			go func() {
+				defer func() {
+					mu.Lock()
+					running = false
+					mu.Unlock()
+					d.wg.Done()
+				}()
+				if err := d.process(ctx, now); err != nil {
+					d.logger.Error().Err(err).Msg("Failed to flip campaigns by daypart")
+				}
+			}()
+		case <-ctx.Done():
+			d.logger.Info().Msg("Close monitoring daypart")
+			wg.Done()
+			return
+		}
+	}
+}
+
+func (d *daypart) Close() {
+	d.wg.Wait()
+}
+
+func (d *daypart) process(ctx context.Context, current time.Time) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+	}()
+	_, err = d.daypartUsecase.Run(ctx, current)
+	return err
+}
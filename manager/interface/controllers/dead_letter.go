@@ -0,0 +1,36 @@
+package controllers
+
+import (
+	"context"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/interface/gateways"
+	"touchgift-job-manager/usecase"
+)
+
+// パースできないメッセージは再送しても処理できないため、DeadLetterに移してキューから削除する
+func discardUnparsableMessage(ctx context.Context, logger usecase.Logger, queueHandler gateways.QueueHandler,
+	queueMessage gateways.QueueMessage, cause error) {
+	queueHandler.UnprocessableMessage()
+	if err := queueHandler.DeadLetter(ctx, queueMessage, cause); err != nil {
+		// 本文はパースエラーのログに出力済みのため削除する
+		logger.Error().Err(err).Str("message_id", *queueMessage.MessageID()).Msg("Failed to put dead letter")
+		queueHandler.DeleteMessage(ctx, queueMessage)
+	}
+}
+
+// 処理に失敗したメッセージは再送で処理できるようにキューに残す
+// 受信回数が上限に達した場合はDeadLetterに移してキューから削除する
+func handleFailedMessage(ctx context.Context, logger usecase.Logger, queueHandler gateways.QueueHandler,
+	queueMessage gateways.QueueMessage, cause error) {
+	queueHandler.UnprocessableMessage()
+	if queueMessage.ReceiveCount() >= config.Env.DeadLetter.MaxReceiveCount {
+		err := queueHandler.DeadLetter(ctx, queueMessage, cause)
+		if err == nil {
+			return
+		}
+		logger.Error().Err(err).Str("message_id", *queueMessage.MessageID()).Msg("Failed to put dead letter")
+	}
+	// リランできるように SQS からは削除しない代わりに、ログ出力しておく
+	queueHandler.Abandon(queueMessage)
+	queueHandler.OutputDeleteCliLog(queueMessage)
+}
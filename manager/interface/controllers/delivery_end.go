@@ -28,6 +28,7 @@ type deliveryEnd struct {
 	worker             deliveryEndWorker
 	transaction        gateways.TransactionHandler
 	deliveryEndUsecase usecase.DeliveryEnd
+	leaderElection     usecase.LeaderElection
 }
 
 type deliveryEndWorker struct {
@@ -54,6 +55,7 @@ func NewDeliveryEnd(
 	appTicker AppTicker,
 	transaction gateways.TransactionHandler,
 	deliveryEndUsecase usecase.DeliveryEnd,
+	leaderElection usecase.LeaderElection,
 ) DeliveryEnd {
 	instance := deliveryEnd{
 		logger:    logger,
@@ -66,6 +68,7 @@ func NewDeliveryEnd(
 		},
 		transaction:        transaction,
 		deliveryEndUsecase: deliveryEndUsecase,
+		leaderElection:     leaderElection,
 	}
 	monitor.Metrics.AddCounter(metricDeliveryEndCampaignTotal, metricDeliveryEndCampaignTotalDesc, metricDeliveryEndCampaignTotalLabels)
 	monitor.Metrics.AddHistogram(metricDeliveryEndCampaignDuration, metricDeliveryEndCampaignDurationDesc,
@@ -85,7 +88,12 @@ func (d *deliveryEnd) StartMonitoring(ctx context.Context, wg *sync.WaitGroup) {
 	for {
 		select {
 		case now := <-ticker.C:
-			baseTime := now.Truncate(time.Minute)
+			if !d.leaderElection.IsLeader() {
+				// リーダー以外は終了処理を行わない
+				d.logger.Debug().Msg("Skip delivery end (not leader)")
+				continue
+			}
+			baseTime := now.UTC().Truncate(time.Minute)
 			// 配信終了処理
 			go d.call(ctx, &DeliveryEndCondition{
 				BaseTime: baseTime,
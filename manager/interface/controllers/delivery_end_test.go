@@ -41,6 +41,8 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		// mockの準備
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 
 		// テスト設定準備
@@ -58,6 +60,7 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 			appTicker,
 			transactionHandler,
 			deliveryEndUsecase,
+			leaderElection,
 		)
 
 		// mockの呼び出し定義(想定される呼び出し)
@@ -108,6 +111,8 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		tx := mock_repository.NewMockTransaction(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 
 		// テスト設定準備
@@ -118,7 +123,7 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		// テスト対象準備
 		pctx := context.Background()
 		ctx, cancel := context.WithCancel(pctx)
-		deliveryEnd := NewDeliveryEnd(logger, metrics.GetMonitor(), &configData, appTicker, transactionHandler, deliveryEndUsecase)
+		deliveryEnd := NewDeliveryEnd(logger, metrics.GetMonitor(), &configData, appTicker, transactionHandler, deliveryEndUsecase, leaderElection)
 
 		// mockの呼び出し定義(想定される呼び出し)
 		campaigns := []*models.Campaign{createCampaign(1, "started")}
@@ -178,6 +183,8 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		tx := mock_repository.NewMockTransaction(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 
 		// テスト設定準備
@@ -188,7 +195,7 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		// テスト対象準備
 		pctx := context.Background()
 		ctx, cancel := context.WithCancel(pctx)
-		deliveryEnd := NewDeliveryEnd(logger, metrics.GetMonitor(), &configData, appTicker, transactionHandler, deliveryEndUsecase)
+		deliveryEnd := NewDeliveryEnd(logger, metrics.GetMonitor(), &configData, appTicker, transactionHandler, deliveryEndUsecase, leaderElection)
 
 		// mockの呼び出し定義(想定される呼び出し)
 		campaigns := []*models.Campaign{createCampaign(1, "started")}
@@ -245,6 +252,8 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		// mockの準備
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 
 		// テスト設定準備
@@ -255,7 +264,7 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		// テスト対象準備
 		pctx := context.Background()
 		ctx, cancel := context.WithCancel(pctx)
-		deliveryEnd := NewDeliveryEnd(logger, metrics.GetMonitor(), &configData, appTicker, transactionHandler, deliveryEndUsecase)
+		deliveryEnd := NewDeliveryEnd(logger, metrics.GetMonitor(), &configData, appTicker, transactionHandler, deliveryEndUsecase, leaderElection)
 
 		// mockの呼び出し定義(想定される呼び出し)
 		campaignTerminates := []*models.Campaign{createCampaign(2, "terminate")}
@@ -309,6 +318,8 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		tx := mock_repository.NewMockTransaction(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 
 		// テスト設定準備
@@ -326,6 +337,7 @@ func TestDeliveryEnd_Execute(t *testing.T) {
 			appTicker,
 			transactionHandler,
 			deliveryEndUsecase,
+			leaderElection,
 		)
 
 		// mockの呼び出し定義(想定される呼び出し)
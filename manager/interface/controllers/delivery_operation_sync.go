@@ -25,7 +25,8 @@ type deliveryOperationSync struct {
 	monitor                  *metrics.Monitor
 	queueHandler             gateways.QueueHandler
 	deliveryOperationUsecase usecase.DeliveryOperation
-	wg                       *sync.WaitGroup
+	messageDedup             usecase.MessageDedup
+	workerPool               *queueWorkerPool
 }
 
 // 　TODO: メトリクスちゃんとやる
@@ -40,18 +41,32 @@ var (
 	metricDeliveryOperationSyncDurationBuckets = []float64{0.01, 0.025, 0.050, 0.075, 0.100, 0.300, 0.500}
 )
 
+const (
+	// 入稿物に紐づくキャンペーンを取得するタイムアウト (メッセージの振り分けを止めないよう短くする)
+	assetCampaignIDsTimeout = 5 * time.Second
+	// 紐づくキャンペーンを取得できなかった入稿物のメッセージのキー (キャンペーンIDと重複しない値)
+	assetFallbackKey = -1
+)
+
 func NewDeliveryOperationSync(
 	logger usecase.Logger,
 	monitor *metrics.Monitor,
 	queueHandler gateways.QueueHandler,
-	deliveryOperationUsecase usecase.DeliveryOperation) DeliveryOperationSync {
+	deliveryOperationUsecase usecase.DeliveryOperation,
+	messageDedup usecase.MessageDedup) DeliveryOperationSync {
 	instance := deliveryOperationSync{
 		logger:                   logger,
 		monitor:                  monitor,
 		queueHandler:             queueHandler,
 		deliveryOperationUsecase: deliveryOperationUsecase,
-		wg:                       &sync.WaitGroup{},
+		messageDedup:             messageDedup,
 	}
+	instance.workerPool = newQueueWorkerPool(
+		logger, monitor, "delivery_operation", config.Env.SQS.WorkerPoolSize, config.Env.SQS.WorkerDrainTimeout,
+		queueHandler, instance.campaignIDs,
+		func(ctx context.Context, queueMessage gateways.QueueMessage) {
+			instance.process(ctx, time.Now(), queueMessage)
+		})
 	monitor.Metrics.AddCounter(
 		metricDeliveryOperationSyncTotal, metricDeliveryOperationSyncTotalDesc,
 		metricDeliveryOperationSyncTotalLabels)
@@ -65,34 +80,41 @@ func (d *deliveryOperationSync) Start(ctx context.Context, wg *sync.WaitGroup) {
 	maxMessages := config.Env.SQS.MaxMessages
 	ch := make(chan gateways.QueueMessage, maxMessages)
 	go d.queueHandler.Poll(ctx, wg, ch, maxMessages)
-	go func() {
-		defer func() {
-			close(ch)
-			if r := recover(); r != nil {
-				d.logger.Error().Msgf("goroutine unrecoverable detail: %#v", r)
-			}
-		}()
-		for {
-			select {
-			case <-ctx.Done():
-				d.wg.Done()
-				return
-			default:
-				for queueMessage := range ch {
-					startTime := time.Now()
-					d.wg.Add(1)
-					d.process(ctx, startTime, queueMessage)
-					d.wg.Done()
-				}
-			}
+	d.workerPool.Start(ctx, ch)
+}
+
+// 同じキャンペーンのメッセージを並列に処理しないよう、メッセージに含まれるキャンペーンIDを返す
+// 入稿物のログは紐づくキャンペーンをRDBから取得し、取得できない場合は入稿物のメッセージ同士で直列に処理する
+// パースできない場合は処理の中でDeadLetterに移すため空を返す
+func (d *deliveryOperationSync) campaignIDs(queueMessage gateways.QueueMessage) []int {
+	var deliveryOperationLog models.DeliveryOperationLog
+	if err := json.Unmarshal([]byte(*queueMessage.Message()), &deliveryOperationLog); err != nil {
+		return nil
+	}
+	campaignIDs := make([]int, 0, len(deliveryOperationLog.CampaignLogs))
+	for _, campaignLog := range deliveryOperationLog.CampaignLogs {
+		campaignIDs = append(campaignIDs, campaignLog.ID)
+	}
+	for i := range deliveryOperationLog.AssetLogs {
+		assetLog := deliveryOperationLog.AssetLogs[i]
+		ctx, cancel := context.WithTimeout(context.Background(), assetCampaignIDsTimeout)
+		ids, err := d.deliveryOperationUsecase.AssetCampaignIDs(ctx, &assetLog)
+		cancel()
+		if err != nil {
+			d.logger.Warn().Err(err).Str("kind", assetLog.Kind).Int("asset_id", assetLog.ID).Msg("Failed to get campaigns of asset")
+			campaignIDs = append(campaignIDs, assetFallbackKey)
+			continue
 		}
-	}()
+		campaignIDs = append(campaignIDs, ids...)
+	}
+	return campaignIDs
 }
 
 func (d *deliveryOperationSync) process(ctx context.Context, startTime time.Time, queueMessage infra.QueueMessage) {
 	defer func() {
 		if r := recover(); r != nil {
 			d.logger.Error().Msgf("Failed to process %#v", r)
+			d.queueHandler.Abandon(queueMessage)
 		}
 		endLatency := time.Since(startTime)
 		d.monitor.Metrics.GetHistogram(metricDeliveryOperationSyncDuration).
@@ -104,38 +126,73 @@ func (d *deliveryOperationSync) process(ctx context.Context, startTime time.Time
 	decoder := json.NewDecoder(strings.NewReader(message))
 	if err := decoder.Decode(&deliveryOperationLog); err != nil {
 		d.logger.Error().Err(err).Str("body", message).Msg("Failed to parse message")
-		d.queueHandler.UnprocessableMessage()
-		d.queueHandler.DeleteMessage(ctx, queueMessage)
+		discardUnparsableMessage(ctx, d.logger, d.queueHandler, queueMessage, err)
 	} else {
-		process := func() error {
-			for i := range deliveryOperationLog.CampaignLogs {
-				current := time.Now()
-				campaign := deliveryOperationLog.CampaignLogs[i]
-
-				// d.monitor.Metrics.GetCounter(metricDeliveryOperationSyncTotal).
-				// 	WithLabelValues(campaign.Event).Inc()
-				if err := d.deliveryOperationUsecase.Process(ctx, current, &campaign); err != nil {
-					if err == codes.ErrDoNothing {
-						return nil
-					}
-					return err
-				}
-			}
-			return nil
+		// 同じメッセージを重複して処理しないようにする
+		snsMessageID := *queueMessage.SnsMessageID()
+		status, err := d.messageDedup.Begin(ctx, startTime, snsMessageID, deliveryOperationLog.RequestID)
+		if err != nil {
+			// 重複判定ができない場合も配信への影響を避けるため処理は行う
+			d.logger.Error().Err(err).Str("message_id", *messageID).Str("sns_message_id", snsMessageID).Msg("Failed to check duplicate message")
+		}
+		switch status {
+		case codes.MessageDedupDone:
+			d.logger.Info().Str("message_id", *messageID).Str("sns_message_id", snsMessageID).Str("request_id", deliveryOperationLog.RequestID).Msg("Skip duplicate message")
+			d.queueHandler.DeleteMessage(ctx, queueMessage)
+			return
+		case codes.MessageDedupInProgress:
+			// 他のタスクが処理中のため削除せず、処理結果が確定してから再度受信する
+			d.logger.Info().Str("message_id", *messageID).Str("sns_message_id", snsMessageID).Str("request_id", deliveryOperationLog.RequestID).Msg("Skip message in progress")
+			d.queueHandler.Abandon(queueMessage)
+			return
 		}
-		err := process()
+		err = processDeliveryOperationLog(ctx, d.deliveryOperationUsecase, &deliveryOperationLog)
 		latency := time.Since(startTime)
 		if err != nil {
 			d.logger.Error().Dur("latency", latency).Err(err).Str("message_id", *messageID).Str("body", message).Msg("Failed to process")
-			d.queueHandler.UnprocessableMessage()
-			// リランできるように SQS からは削除しない代わりに、ログ出力しておく
-			d.queueHandler.OutputDeleteCliLog(queueMessage)
+			if status == codes.MessageDedupNew {
+				if err := d.messageDedup.Abort(ctx, snsMessageID, deliveryOperationLog.RequestID); err != nil {
+					d.logger.Error().Err(err).Str("message_id", *messageID).Str("sns_message_id", snsMessageID).Msg("Failed to abort message dedup")
+				}
+			}
+			handleFailedMessage(ctx, d.logger, d.queueHandler, queueMessage, err)
 		} else {
+			if status == codes.MessageDedupNew {
+				if err := d.messageDedup.Complete(ctx, time.Now(), snsMessageID, deliveryOperationLog.RequestID); err != nil {
+					d.logger.Error().Err(err).Str("message_id", *messageID).Str("sns_message_id", snsMessageID).Msg("Failed to complete message dedup")
+				}
+			}
 			d.queueHandler.DeleteMessage(ctx, queueMessage)
 		}
 	}
 }
 
+// DeliveryOperationLogに含まれるキャンペーンログ・入稿物のログを順に処理する
+func processDeliveryOperationLog(ctx context.Context, deliveryOperationUsecase usecase.DeliveryOperation,
+	deliveryOperationLog *models.DeliveryOperationLog) error {
+	for i := range deliveryOperationLog.CampaignLogs {
+		current := time.Now()
+		campaign := deliveryOperationLog.CampaignLogs[i]
+
+		// d.monitor.Metrics.GetCounter(metricDeliveryOperationSyncTotal).
+		// 	WithLabelValues(campaign.Event).Inc()
+		if err := deliveryOperationUsecase.Process(ctx, current, &campaign); err != nil {
+			if err == codes.ErrDoNothing {
+				break
+			}
+			return err
+		}
+	}
+	// 入稿物(クリエイティブ/クーポン/ギミック)の審査ステータスの変更
+	for i := range deliveryOperationLog.AssetLogs {
+		assetLog := deliveryOperationLog.AssetLogs[i]
+		if err := deliveryOperationUsecase.ProcessAsset(ctx, time.Now(), &assetLog); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func (d *deliveryOperationSync) Close() {
-	d.wg.Wait()
+	d.workerPool.Wait()
 }
@@ -9,6 +9,7 @@ import (
 	"sync"
 	"testing"
 	"time"
+	"touchgift-job-manager/codes"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/infra/metrics"
@@ -16,6 +17,8 @@ import (
 	mock_gateways "touchgift-job-manager/mock/gateways"
 	mock_infra "touchgift-job-manager/mock/infra"
 	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestDeliveryOperationSync_Start(t *testing.T) {
@@ -27,6 +30,7 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 
 		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
 		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		messageDedup := mock_usecase.NewMockMessageDedup(ctrl)
 		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
 
 		octx := context.Background()
@@ -45,16 +49,22 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 					"campaigns":[]
 				}`
 				return &json
-			}),
+			}).Times(2),
 			queueMessage.EXPECT().MessageID().DoAndReturn(func() *string {
 				messageID := "messageID1"
 				return &messageID
 			}),
-			queueHandler.EXPECT().DeleteMessage(gomock.Eq(ctx), gomock.Eq(queueMessage)),
+			queueMessage.EXPECT().SnsMessageID().DoAndReturn(func() *string {
+				snsMessageID := "snsMessageID1"
+				return &snsMessageID
+			}),
+			messageDedup.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("")).Return(codes.MessageDedupNew, nil),
+			messageDedup.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("")).Return(nil),
+			queueHandler.EXPECT().DeleteMessage(gomock.Any(), gomock.Eq(queueMessage)),
 		)
 
 		// テスト実行
-		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase)
+		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase, messageDedup)
 		deliveryOperationSync.Start(ctx, &wg)
 		time.Sleep(50 * time.Millisecond)
 
@@ -68,6 +78,7 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 
 		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
 		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		messageDedup := mock_usecase.NewMockMessageDedup(ctrl)
 		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
 
 		octx := context.Background()
@@ -84,17 +95,17 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 				}),
 			queueMessage.EXPECT().Message().DoAndReturn(func() *string {
 				return &jsonText
-			}),
+			}).Times(2),
 			queueMessage.EXPECT().MessageID().DoAndReturn(func() *string {
 				messageID := "messageID1"
 				return &messageID
 			}),
 			queueHandler.EXPECT().UnprocessableMessage(),
-			queueHandler.EXPECT().DeleteMessage(gomock.Eq(ctx), gomock.Eq(queueMessage)),
+			queueHandler.EXPECT().DeadLetter(gomock.Any(), gomock.Eq(queueMessage), gomock.Any()).Return(nil),
 		)
 
 		// テスト実行
-		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase)
+		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase, messageDedup)
 		deliveryOperationSync.Start(ctx, &wg)
 		time.Sleep(50 * time.Millisecond)
 
@@ -108,6 +119,7 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 
 		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
 		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		messageDedup := mock_usecase.NewMockMessageDedup(ctrl)
 		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
 
 		octx := context.Background()
@@ -132,18 +144,24 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 				}),
 			queueMessage.EXPECT().Message().DoAndReturn(func() *string {
 				return &jsonText
-			}),
+			}).Times(2),
 			queueMessage.EXPECT().MessageID().DoAndReturn(func() *string {
 				messageID := "messageID1"
 				return &messageID
 			}),
-			deliveryOperationUsecase.EXPECT().Process(gomock.Eq(ctx),
+			queueMessage.EXPECT().SnsMessageID().DoAndReturn(func() *string {
+				snsMessageID := "snsMessageID1"
+				return &snsMessageID
+			}),
+			messageDedup.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("")).Return(codes.MessageDedupNew, nil),
+			deliveryOperationUsecase.EXPECT().Process(gomock.Any(),
 				gomock.Any(), gomock.Eq(&deliveryOperationLog.CampaignLogs[0])).Return(nil),
-			queueHandler.EXPECT().DeleteMessage(gomock.Eq(ctx), gomock.Eq(queueMessage)),
+			messageDedup.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("")).Return(nil),
+			queueHandler.EXPECT().DeleteMessage(gomock.Any(), gomock.Eq(queueMessage)),
 		)
 
 		// テスト実行
-		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase)
+		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase, messageDedup)
 		deliveryOperationSync.Start(ctx, &wg)
 		time.Sleep(50 * time.Millisecond)
 
@@ -152,12 +170,73 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 		deliveryOperationSync.Close()
 
 	})
+	t.Run("assetsログがある場合、入稿物の審査ステータス変更を処理する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		messageDedup := mock_usecase.NewMockMessageDedup(ctrl)
+		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
+
+		octx := context.Background()
+		ctx, cancel := context.WithCancel(octx)
+		wg := sync.WaitGroup{}
+		jsonText := `{
+			"time": "2021-10-01T10:00:00.000Z",
+			"type": "delivery_operation",
+			"request_id": "request1",
+			"assets":[{
+				"kind": "coupon", "id": 5, "org_code": "org", "status": "4"
+			}]
+		}`
+		gomock.InOrder(
+			queueHandler.EXPECT().Poll(gomock.Eq(ctx), gomock.Eq(&wg), gomock.Any(), gomock.Eq(config.Env.SQS.MaxMessages)).Do(
+				func(ctx context.Context, wg *sync.WaitGroup, ch chan gateways.QueueMessage, maxMessages int64) {
+					ch <- queueMessage
+				}),
+			queueMessage.EXPECT().Message().DoAndReturn(func() *string {
+				return &jsonText
+			}),
+			// 紐づくキャンペーンと同じキーで直列に処理する
+			deliveryOperationUsecase.EXPECT().AssetCampaignIDs(gomock.Any(), gomock.Eq(&models.AssetLog{
+				Kind: codes.AssetKindCoupon, ID: 5, OrgCode: "org", Status: "4",
+			})).Return([]int{1, 2}, nil),
+			queueMessage.EXPECT().Message().DoAndReturn(func() *string {
+				return &jsonText
+			}),
+			queueMessage.EXPECT().MessageID().DoAndReturn(func() *string {
+				messageID := "messageID1"
+				return &messageID
+			}),
+			queueMessage.EXPECT().SnsMessageID().DoAndReturn(func() *string {
+				snsMessageID := "snsMessageID1"
+				return &snsMessageID
+			}),
+			messageDedup.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("request1")).Return(codes.MessageDedupNew, nil),
+			deliveryOperationUsecase.EXPECT().ProcessAsset(gomock.Any(), gomock.Any(), gomock.Eq(&models.AssetLog{
+				Kind: codes.AssetKindCoupon, ID: 5, OrgCode: "org", Status: "4",
+			})).Return(nil),
+			messageDedup.EXPECT().Complete(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("request1")).Return(nil),
+			queueHandler.EXPECT().DeleteMessage(gomock.Any(), gomock.Eq(queueMessage)),
+		)
+
+		// テスト実行
+		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase, messageDedup)
+		deliveryOperationSync.Start(ctx, &wg)
+		time.Sleep(50 * time.Millisecond)
+
+		// テスト完了待ち
+		cancel()
+		deliveryOperationSync.Close()
+	})
 	t.Run("campaignsログの処理でエラーが起きた場合、エラーを返して終了する", func(t *testing.T) {
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish()
 
 		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
 		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		messageDedup := mock_usecase.NewMockMessageDedup(ctrl)
 		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
 
 		octx := context.Background()
@@ -182,19 +261,27 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 				}),
 			queueMessage.EXPECT().Message().DoAndReturn(func() *string {
 				return &jsonText
-			}),
+			}).Times(2),
 			queueMessage.EXPECT().MessageID().DoAndReturn(func() *string {
 				messageID := "messageID1"
 				return &messageID
 			}),
-			deliveryOperationUsecase.EXPECT().Process(gomock.Eq(ctx),
+			queueMessage.EXPECT().SnsMessageID().DoAndReturn(func() *string {
+				snsMessageID := "snsMessageID1"
+				return &snsMessageID
+			}),
+			messageDedup.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("")).Return(codes.MessageDedupNew, nil),
+			deliveryOperationUsecase.EXPECT().Process(gomock.Any(),
 				gomock.Any(), gomock.Eq(&deliveryOperationLog.CampaignLogs[0])).Return(errors.New("Failed to process")),
+			messageDedup.EXPECT().Abort(gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("")).Return(nil),
 			queueHandler.EXPECT().UnprocessableMessage(),
+			queueMessage.EXPECT().ReceiveCount().Return(1),
+			queueHandler.EXPECT().Abandon(gomock.Eq(queueMessage)),
 			queueHandler.EXPECT().OutputDeleteCliLog(gomock.Eq(queueMessage)),
 		)
 
 		// テスト実行
-		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase)
+		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase, messageDedup)
 		deliveryOperationSync.Start(ctx, &wg)
 		time.Sleep(50 * time.Millisecond)
 
@@ -202,4 +289,140 @@ func TestDeliveryOperationSync_Start(t *testing.T) {
 		cancel()
 		deliveryOperationSync.Close()
 	})
+	t.Run("処理済みのメッセージの場合、処理せずに削除する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		messageDedup := mock_usecase.NewMockMessageDedup(ctrl)
+		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
+
+		octx := context.Background()
+		ctx, cancel := context.WithCancel(octx)
+		wg := sync.WaitGroup{}
+		jsonText := `{
+			"time": "2021-10-01T10:00:00.000Z",
+			"type": "delivery_operation",
+			"request_id": "request1",
+			"campaigns":[{
+				"id": 1, "event": "insert"
+			}]
+		}`
+		gomock.InOrder(
+			queueHandler.EXPECT().Poll(gomock.Eq(ctx), gomock.Eq(&wg), gomock.Any(), gomock.Eq(config.Env.SQS.MaxMessages)).Do(
+				func(ctx context.Context, wg *sync.WaitGroup, ch chan gateways.QueueMessage, maxMessages int64) {
+					ch <- queueMessage
+				}),
+			queueMessage.EXPECT().Message().DoAndReturn(func() *string {
+				return &jsonText
+			}).Times(2),
+			queueMessage.EXPECT().MessageID().DoAndReturn(func() *string {
+				messageID := "messageID1"
+				return &messageID
+			}),
+			queueMessage.EXPECT().SnsMessageID().DoAndReturn(func() *string {
+				snsMessageID := "snsMessageID1"
+				return &snsMessageID
+			}),
+			messageDedup.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("request1")).Return(codes.MessageDedupDone, nil),
+			queueHandler.EXPECT().DeleteMessage(gomock.Any(), gomock.Eq(queueMessage)),
+		)
+
+		// テスト実行
+		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase, messageDedup)
+		deliveryOperationSync.Start(ctx, &wg)
+		time.Sleep(50 * time.Millisecond)
+
+		// テスト完了待ち
+		cancel()
+		deliveryOperationSync.Close()
+	})
+	t.Run("他のタスクが処理中のメッセージの場合、処理せずに削除もしない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		messageDedup := mock_usecase.NewMockMessageDedup(ctrl)
+		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
+
+		octx := context.Background()
+		ctx, cancel := context.WithCancel(octx)
+		wg := sync.WaitGroup{}
+		jsonText := `{
+			"time": "2021-10-01T10:00:00.000Z",
+			"type": "delivery_operation",
+			"request_id": "request1",
+			"campaigns":[{
+				"id": 1, "event": "insert"
+			}]
+		}`
+		gomock.InOrder(
+			queueHandler.EXPECT().Poll(gomock.Eq(ctx), gomock.Eq(&wg), gomock.Any(), gomock.Eq(config.Env.SQS.MaxMessages)).Do(
+				func(ctx context.Context, wg *sync.WaitGroup, ch chan gateways.QueueMessage, maxMessages int64) {
+					ch <- queueMessage
+				}),
+			queueMessage.EXPECT().Message().DoAndReturn(func() *string {
+				return &jsonText
+			}).Times(2),
+			queueMessage.EXPECT().MessageID().DoAndReturn(func() *string {
+				messageID := "messageID1"
+				return &messageID
+			}),
+			queueMessage.EXPECT().SnsMessageID().DoAndReturn(func() *string {
+				snsMessageID := "snsMessageID1"
+				return &snsMessageID
+			}),
+			messageDedup.EXPECT().Begin(gomock.Any(), gomock.Any(), gomock.Eq("snsMessageID1"), gomock.Eq("request1")).Return(codes.MessageDedupInProgress, nil),
+			queueHandler.EXPECT().Abandon(gomock.Eq(queueMessage)),
+		)
+
+		// テスト実行
+		deliveryOperationSync := NewDeliveryOperationSync(logger, metrics.GetMonitor(), queueHandler, deliveryOperationUsecase, messageDedup)
+		deliveryOperationSync.Start(ctx, &wg)
+		time.Sleep(50 * time.Millisecond)
+
+		// テスト完了待ち
+		cancel()
+		deliveryOperationSync.Close()
+	})
+}
+
+func TestDeliveryOperationSync_campaignIDs(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	jsonText := `{
+		"type": "delivery_operation",
+		"campaigns":[{"id": 1, "event": "update"}],
+		"assets":[{"kind": "coupon", "id": 5, "org_code": "org", "status": "4"}]
+	}`
+
+	t.Run("入稿物のログは紐づくキャンペーンのIDをキーにする", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
+		queueMessage.EXPECT().Message().Return(&jsonText)
+		deliveryOperationUsecase.EXPECT().AssetCampaignIDs(gomock.Any(), gomock.Eq(&models.AssetLog{
+			Kind: codes.AssetKindCoupon, ID: 5, OrgCode: "org", Status: "4",
+		})).Return([]int{2, 3}, nil)
+
+		deliveryOperationSync := &deliveryOperationSync{logger: logger, deliveryOperationUsecase: deliveryOperationUsecase}
+		assert.Equal(t, []int{1, 2, 3}, deliveryOperationSync.campaignIDs(queueMessage))
+	})
+
+	t.Run("紐づくキャンペーンを取得できない場合は入稿物のメッセージ共通のキーにする", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		queueMessage := mock_infra.NewMockQueueMessage(ctrl)
+		queueMessage.EXPECT().Message().Return(&jsonText)
+		deliveryOperationUsecase.EXPECT().AssetCampaignIDs(gomock.Any(), gomock.Any()).Return(nil, errors.New("error"))
+
+		deliveryOperationSync := &deliveryOperationSync{logger: logger, deliveryOperationUsecase: deliveryOperationUsecase}
+		assert.Equal(t, []int{1, assetFallbackKey}, deliveryOperationSync.campaignIDs(queueMessage))
+	})
 }
@@ -28,6 +28,7 @@ type deliveryStart struct {
 	transaction          gateways.TransactionHandler
 	deliveryStartUsecase usecase.DeliveryStart
 	deliveryControlEvent usecase.DeliveryControlEvent
+	leaderElection       usecase.LeaderElection
 }
 
 type deliveryStartWorker struct {
@@ -54,6 +55,7 @@ func NewDeliveryStart(
 	transaction gateways.TransactionHandler,
 	deliveryStartUsecase usecase.DeliveryStart,
 	deliveryControlEvent usecase.DeliveryControlEvent,
+	leaderElection usecase.LeaderElection,
 ) DeliveryStart {
 	monitor.Metrics.AddCounter(metricDeliveryStartCampaignTotal, metricDeliveryStartCampaignTotalDesc, metricDeliveryStartCampaignTotalLabels)
 	monitor.Metrics.AddHistogram(metricDeliveryStartCampaignDuration, metricDeliveryStartCampaignDurationDesc, metricDeliveryStartCampaignDurationLabels, metricDeliveryStartCampaignDurationBuckets)
@@ -69,6 +71,7 @@ func NewDeliveryStart(
 		transaction:          transaction,
 		deliveryStartUsecase: deliveryStartUsecase,
 		deliveryControlEvent: deliveryControlEvent,
+		leaderElection:       leaderElection,
 	}
 }
 
@@ -82,12 +85,18 @@ func (d *deliveryStart) StartMonitoring(ctx context.Context, wg *sync.WaitGroup)
 	for {
 		select {
 		case now := <-ticker.C:
-			baseTime := now.Truncate(time.Minute)
+			if !d.leaderElection.IsLeader() {
+				// リーダー以外は開始処理を行わない
+				d.logger.Debug().Msg("Skip delivery start (not leader)")
+				continue
+			}
+			baseTime := now.UTC().Truncate(time.Minute)
 			// 配信開始処理
 			go d.call(ctx, &DeliveryStartCondition{
 				BaseTime: baseTime,
 				// 10sはぎりぎりで配信開始するのを防ぐために追加している
-				To:     baseTime.Add(d.config.TaskInterval).Add(10 * time.Second),
+				// 配信データを事前作成する場合は事前作成する時間分早くwarmupにする
+				To:     baseTime.Add(d.config.TaskInterval).Add(10 * time.Second).Add(d.config.PrewarmDuration),
 				Status: codes.StatusConfigured,
 				r:      make(chan int, d.config.NumberOfQueue),
 			})
@@ -232,11 +241,21 @@ func (d *deliveryStart) handleConfigured(ctx context.Context, baseTime time.Time
 	if err != nil {
 		return errors.Wrap(err, "Failed to update")
 	}
+	// 配信制御イベントを発行する
+	err = d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode, codes.StatusConfigured, codes.StatusWarmup, "")
+	if err != nil {
+		return errors.Wrap(err, "Failed to publish campaign event")
+	}
 	if err := tx.Commit(); err != nil {
 		return errors.Wrap(err, "Failed to commit")
 	}
-	// 配信制御イベントを発行する
-	d.deliveryControlEvent.PublishCampaignEvent(ctx, campaign.ID, campaign.GroupID, campaign.OrgCode, codes.StatusConfigured, codes.StatusWarmup, "")
+	if d.config.PrewarmDuration > 0 {
+		// 開始時の処理を減らすため、キャンペーン以外の配信データを事前に作成する
+		// 開始処理と並行しないように予約する前に作成する (失敗した場合は開始時に全ての配信データを作成する)
+		if err := d.deliveryStartUsecase.Prewarm(ctx, campaign); err != nil {
+			d.logger.Error().Err(err).Time("baseTime", baseTime).Int("campaign_id", campaign.ID).Msg("Failed to prewarm")
+		}
+	}
 	// 取得した配信対象の開始時間を指定時間として実行する
 	d.deliveryStartUsecase.Reserve(ctx, campaign.StartAt, campaign)
 	return nil
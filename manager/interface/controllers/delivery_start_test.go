@@ -43,6 +43,8 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		// mockの準備
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 
@@ -62,6 +64,7 @@ func TestDeliveryStart_Execute(t *testing.T) {
 			transactionHandler,
 			deliveryStartUsecase,
 			deliveryControlEvent,
+			leaderElection,
 		)
 
 		// mockの呼び出し定義(想定される呼び出し)
@@ -103,6 +106,60 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		deliveryStart.Close()
 	})
 
+	t.Run("リーダーでない場合は開始処理を行わない", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// mockの準備
+		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
+		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(false).MinTimes(1)
+		appTicker := mock_controllers.NewMockAppTicker(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		// テスト設定準備
+		configData := config.Env.DeliveryStart
+		configData.NumberOfConcurrent = 1
+		testExecuteInterval := 1 * time.Second
+
+		// テスト対象準備
+		pctx := context.Background()
+		ctx, cancel := context.WithCancel(pctx)
+		deliveryStart := NewDeliveryStart(
+			logger,
+			metrics.GetMonitor(),
+			&configData,
+			appTicker,
+			transactionHandler,
+			deliveryStartUsecase,
+			deliveryControlEvent,
+			leaderElection,
+		)
+
+		// mockの呼び出し定義(想定される呼び出し)
+		// GetCampaignToStartは呼ばれない
+		deliveryStartUsecase.EXPECT().CreateWorker(gomock.Eq(ctx)).Return().Times(1)
+		appTicker.EXPECT().New(gomock.Eq(configData.TaskInterval), time.Minute).DoAndReturn(func(interval time.Duration, unit time.Duration) *time.Ticker {
+			return NewAppTicker().New(testExecuteInterval, time.Second)
+		}).Times(1)
+		deliveryStartUsecase.EXPECT().Close().Return().Times(1)
+
+		// 実行時間の調整
+		time.Sleep(time.Until(time.Now().Add(testExecuteInterval).Truncate(time.Second).Add(-50 * time.Millisecond)))
+		// テストを実行する
+		var wg sync.WaitGroup
+		go deliveryStart.StartMonitoring(ctx, &wg)
+
+		// 非同期で処理が実行されるので待つ
+		time.Sleep(time.Until(time.Now().Add(testExecuteInterval).Add(100 * time.Millisecond)))
+		// 終了させる
+		cancel()
+		wg.Wait()
+		deliveryStart.Close()
+	})
+
 	t.Run("configured,warmup両方ともデータ1件ありの場合正常にする", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
@@ -112,6 +169,8 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		tx := mock_repository.NewMockTransaction(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 		// テスト設定準備
@@ -130,6 +189,7 @@ func TestDeliveryStart_Execute(t *testing.T) {
 			transactionHandler,
 			deliveryStartUsecase,
 			deliveryControlEvent,
+			leaderElection,
 		)
 
 		// mockの呼び出し定義(想定される呼び出し)
@@ -157,11 +217,11 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil).Times(1)
 		deliveryStartUsecase.EXPECT().UpdateStatus(
 			gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0]), codes.StatusWarmup).Return(1, nil).Times(1)
-		tx.EXPECT().Commit().Return(nil).Times(1)
 		deliveryControlEvent.EXPECT().PublishCampaignEvent(
-			gomock.Eq(ctx), gomock.Eq(campaigns[0].ID), gomock.Eq(campaigns[0].GroupID), gomock.Eq(campaigns[0].OrgCode),
+			gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0].ID), gomock.Eq(campaigns[0].GroupID), gomock.Eq(campaigns[0].OrgCode),
 			gomock.Eq("configured"), gomock.Eq("warmup"), gomock.Eq(""),
-		).Times(1)
+		).Return(nil).Times(1)
+		tx.EXPECT().Commit().Return(nil).Times(1)
 		deliveryStartUsecase.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(campaigns[0].StartAt), gomock.Eq(campaigns[0])).Return().Times(1)
 		// warmupデータの処理
 		deliveryStartUsecase.EXPECT().GetCampaignToStart(gomock.Eq(ctx), gomock.Any(), gomock.Eq("warmup"), gomock.Eq(configData.TaskLimit)).
@@ -187,6 +247,81 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		deliveryStart.Close()
 	})
 
+	t.Run("事前作成する場合は事前作成する時間分早くwarmupにして配信データを事前作成する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
+		appTicker := mock_controllers.NewMockAppTicker(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		configData := config.Env.DeliveryStart
+		configData.NumberOfConcurrent = 1
+		configData.PrewarmDuration = 10 * time.Minute
+		testExecuteInterval := 1 * time.Second
+
+		pctx := context.Background()
+		ctx, cancel := context.WithCancel(pctx)
+		deliveryStart := NewDeliveryStart(
+			logger,
+			metrics.GetMonitor(),
+			&configData,
+			appTicker,
+			transactionHandler,
+			deliveryStartUsecase,
+			deliveryControlEvent,
+			leaderElection,
+		)
+
+		campaigns := []*models.Campaign{
+			createCampaign(1, "configured"),
+		}
+		expectedTo := func() time.Time {
+			return time.Now().
+				Truncate(time.Minute).
+				Add(configData.TaskInterval).Add(10 * time.Second).Add(10 * time.Minute)
+		}
+		deliveryStartUsecase.EXPECT().CreateWorker(gomock.Eq(ctx)).Return().Times(1)
+		appTicker.EXPECT().New(gomock.Eq(configData.TaskInterval), time.Minute).DoAndReturn(func(interval time.Duration, unit time.Duration) *time.Ticker {
+			return NewAppTicker().New(testExecuteInterval, time.Second)
+		}).Times(1)
+		deliveryStartUsecase.EXPECT().GetCampaignToStart(gomock.Eq(ctx), gomock.Any(), gomock.Eq("configured"), gomock.Eq(configData.TaskLimit)).
+			DoAndReturn(func(ctx context.Context, to time.Time, status string, limit int) ([]*models.Campaign, error) {
+				assert.WithinDuration(t, expectedTo(), to, 1*time.Second)
+				return campaigns, nil
+			}).Times(1)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil).Times(1),
+			deliveryStartUsecase.EXPECT().UpdateStatus(
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0]), codes.StatusWarmup).Return(1, nil).Times(1),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0].ID), gomock.Eq(campaigns[0].GroupID), gomock.Eq(campaigns[0].OrgCode),
+				gomock.Eq("configured"), gomock.Eq("warmup"), gomock.Eq(""),
+			).Return(nil).Times(1),
+			tx.EXPECT().Commit().Return(nil).Times(1),
+			// 開始処理と並行しないように予約する前に事前作成する
+			deliveryStartUsecase.EXPECT().Prewarm(gomock.Eq(ctx), gomock.Eq(campaigns[0])).Return(nil).Times(1),
+			deliveryStartUsecase.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(campaigns[0].StartAt), gomock.Eq(campaigns[0])).Return().Times(1),
+		)
+		deliveryStartUsecase.EXPECT().GetCampaignToStart(gomock.Eq(ctx), gomock.Any(), gomock.Eq("warmup"), gomock.Eq(configData.TaskLimit)).
+			Return([]*models.Campaign{}, nil).Times(1)
+		deliveryStartUsecase.EXPECT().Close().Return().Times(1)
+
+		// 実行時間の調整
+		time.Sleep(time.Until(time.Now().Add(testExecuteInterval).Truncate(time.Second).Add(-50 * time.Millisecond)))
+		var wg sync.WaitGroup
+		go deliveryStart.StartMonitoring(ctx, &wg)
+
+		// 非同期で処理が実行されるので待つ
+		time.Sleep(time.Until(time.Now().Add(testExecuteInterval).Add(100 * time.Millisecond)))
+		cancel()
+		wg.Wait()
+		deliveryStart.Close()
+	})
+
 	t.Run("configuredのみデータ1件ありの場合正常に処理する", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
@@ -196,6 +331,8 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		tx := mock_repository.NewMockTransaction(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 
@@ -215,6 +352,7 @@ func TestDeliveryStart_Execute(t *testing.T) {
 			transactionHandler,
 			deliveryStartUsecase,
 			deliveryControlEvent,
+			leaderElection,
 		)
 
 		// mockの呼び出し定義(想定される呼び出し)
@@ -239,11 +377,11 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil).Times(1)
 		deliveryStartUsecase.EXPECT().UpdateStatus(
 			gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0]), gomock.Eq(codes.StatusWarmup)).Return(1, nil).Times(1)
-		tx.EXPECT().Commit().Return(nil).Times(1)
 		deliveryControlEvent.EXPECT().PublishCampaignEvent(
-			gomock.Eq(ctx), gomock.Eq(campaigns[0].ID), gomock.Eq(campaigns[0].GroupID), gomock.Eq(campaigns[0].OrgCode),
+			gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0].ID), gomock.Eq(campaigns[0].GroupID), gomock.Eq(campaigns[0].OrgCode),
 			gomock.Eq("configured"), gomock.Eq("warmup"), gomock.Eq(""),
-		).Times(1)
+		).Return(nil).Times(1)
+		tx.EXPECT().Commit().Return(nil).Times(1)
 		deliveryStartUsecase.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(campaigns[0].StartAt), campaigns[0]).Return().Times(1)
 		// warmupデータの処理
 		deliveryStartUsecase.EXPECT().GetCampaignToStart(gomock.Eq(ctx), gomock.Any(), gomock.Eq("warmup"), gomock.Eq(configData.TaskLimit)).
@@ -276,6 +414,8 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		// mockの準備
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 
@@ -295,6 +435,7 @@ func TestDeliveryStart_Execute(t *testing.T) {
 			transactionHandler,
 			deliveryStartUsecase,
 			deliveryControlEvent,
+			leaderElection,
 		)
 
 		// mockの呼び出し定義(想定される呼び出し)
@@ -349,6 +490,8 @@ func TestDeliveryStart_Execute(t *testing.T) {
 		transactionHandler := mock_gateways.NewMockTransactionHandler(ctrl)
 		tx := mock_repository.NewMockTransaction(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		leaderElection := mock_usecase.NewMockLeaderElection(ctrl)
+		leaderElection.EXPECT().IsLeader().Return(true).AnyTimes()
 		appTicker := mock_controllers.NewMockAppTicker(ctrl)
 		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 
@@ -368,6 +511,7 @@ func TestDeliveryStart_Execute(t *testing.T) {
 			transactionHandler,
 			deliveryStartUsecase,
 			deliveryControlEvent,
+			leaderElection,
 		)
 
 		// mockの呼び出し定義(想定される呼び出し)
@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/usecase"
+
+	"github.com/pkg/errors"
+)
+
+// OutboxRelay outboxの配信制御イベントを定期的にSNSへPublishする
+type OutboxRelay interface {
+	StartMonitoring(ctx context.Context, wg *sync.WaitGroup)
+	Close()
+}
+
+type outboxRelay struct {
+	logger             usecase.Logger
+	config             *config.Outbox
+	appTicker          AppTicker
+	outboxRelayUsecase usecase.OutboxRelay
+	leaderElection     usecase.LeaderElection
+	wg                 *sync.WaitGroup
+}
+
+func NewOutboxRelay(
+	logger usecase.Logger,
+	config *config.Outbox,
+	appTicker AppTicker,
+	outboxRelayUsecase usecase.OutboxRelay,
+	leaderElection usecase.LeaderElection,
+) OutboxRelay {
+	return &outboxRelay{
+		logger:             logger,
+		config:             config,
+		appTicker:          appTicker,
+		outboxRelayUsecase: outboxRelayUsecase,
+		leaderElection:     leaderElection,
+		wg:                 &sync.WaitGroup{},
+	}
+}
+
+func (o *outboxRelay) StartMonitoring(ctx context.Context, wg *sync.WaitGroup) {
+	o.logger.Info().Msg("Start monitoring outbox relay")
+	wg.Add(1)
+	ticker := o.appTicker.New(o.config.RelayInterval, time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case now := <-ticker.C:
+			if !o.leaderElection.IsLeader() {
+				// 送信順を保つためリーダーのみがPublishする
+				o.logger.Debug().Msg("Skip outbox relay (not leader)")
+				continue
+			}
+			// 次のtickまで処理が終わらない場合は、終わるまで次のtickを待つ
+			o.wg.Add(1)
+			if err := o.process(ctx, now); err != nil {
+				o.logger.Error().Err(err).Msg("Failed to relay outbox")
+			}
+			o.wg.Done()
+		case <-ctx.Done():
+			o.logger.Info().Msg("Close monitoring outbox relay")
+			wg.Done()
+			return
+		}
+	}
+}
+
+func (o *outboxRelay) Close() {
+	o.wg.Wait()
+}
+
+func (o *outboxRelay) process(ctx context.Context, now time.Time) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic. reason: %#v", r)
+		}
+	}()
+	for {
+		count, err := o.outboxRelayUsecase.Relay(ctx, now)
+		if err != nil {
+			return err
+		}
+		// バッチサイズ分取得できた場合は続けて処理する
+		if count < o.config.RelayBatchSize || ctx.Err() != nil {
+			break
+		}
+	}
+	if err := o.outboxRelayUsecase.ObserveBacklog(ctx, now); err != nil {
+		return err
+	}
+	deleted, err := o.outboxRelayUsecase.Cleanup(ctx, now)
+	if err != nil {
+		return err
+	}
+	if deleted > 0 {
+		o.logger.Debug().Int64("deleted", deleted).Msg("Cleanup delivered outbox events")
+	}
+	return nil
+}
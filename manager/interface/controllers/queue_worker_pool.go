@@ -0,0 +1,252 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/infra/metrics"
+	"touchgift-job-manager/interface/gateways"
+	"touchgift-job-manager/usecase"
+)
+
+var (
+	metricQueueWorkerInFlight       = "queue_worker_in_flight"
+	metricQueueWorkerInFlightDesc   = "message count processing by queue worker"
+	metricQueueWorkerInFlightLabels = []string{"name"}
+
+	metricQueueWorkerWaitDuration        = "queue_worker_wait_duration_seconds"
+	metricQueueWorkerWaitDurationDesc    = "waiting time for preceding messages of the same campaign (seconds)"
+	metricQueueWorkerWaitDurationLabels  = []string{"name"}
+	metricQueueWorkerWaitDurationBuckets = []float64{0.001, 0.01, 0.1, 0.5, 1, 5, 10, 30}
+
+	metricQueueWorkerAbandonedTotal       = "queue_worker_abandoned_total"
+	metricQueueWorkerAbandonedTotalDesc   = "message count left in queue without processing on shutdown"
+	metricQueueWorkerAbandonedTotalLabels = []string{"name"}
+)
+
+// queueWorkerPool キューのメッセージを複数のワーカーで並列に処理する
+// 同じキャンペーンのメッセージは受信した順番に1つずつ処理し、関係のないキャンペーンは並列に処理する
+type queueWorkerPool struct {
+	logger       usecase.Logger
+	monitor      *metrics.Monitor
+	name         string
+	size         int
+	drainTimeout time.Duration
+	queueHandler gateways.QueueHandler
+	// keys メッセージの処理対象のキャンペーンID
+	keys func(queueMessage gateways.QueueMessage) []int
+	// handle メッセージを処理する (削除/DeadLetterなどもhandleの中で行う)
+	handle    func(ctx context.Context, queueMessage gateways.QueueMessage)
+	sequencer *keySequencer
+	wg        sync.WaitGroup
+}
+
+type queueWorkerJob struct {
+	queueMessage gateways.QueueMessage
+	keys         []int
+	sequence     uint64
+}
+
+func newQueueWorkerPool(
+	logger usecase.Logger,
+	monitor *metrics.Monitor,
+	name string,
+	size int,
+	drainTimeout time.Duration,
+	queueHandler gateways.QueueHandler,
+	keys func(queueMessage gateways.QueueMessage) []int,
+	handle func(ctx context.Context, queueMessage gateways.QueueMessage),
+) *queueWorkerPool {
+	if size < 1 {
+		size = 1
+	}
+	monitor.Metrics.AddGauge(metricQueueWorkerInFlight, metricQueueWorkerInFlightDesc, metricQueueWorkerInFlightLabels)
+	monitor.Metrics.AddHistogram(metricQueueWorkerWaitDuration, metricQueueWorkerWaitDurationDesc,
+		metricQueueWorkerWaitDurationLabels, metricQueueWorkerWaitDurationBuckets)
+	monitor.Metrics.AddCounter(metricQueueWorkerAbandonedTotal, metricQueueWorkerAbandonedTotalDesc, metricQueueWorkerAbandonedTotalLabels)
+	return &queueWorkerPool{
+		logger:       logger,
+		monitor:      monitor,
+		name:         name,
+		size:         size,
+		drainTimeout: drainTimeout,
+		queueHandler: queueHandler,
+		keys:         keys,
+		handle:       handle,
+		sequencer:    newKeySequencer(),
+	}
+}
+
+// Start chのメッセージをワーカーに振り分けて処理する
+// ctxが終了した後は新しいメッセージの処理は開始せず(キューに残す)、処理中のメッセージはdrainTimeoutまで完了を待つ
+func (p *queueWorkerPool) Start(ctx context.Context, ch <-chan gateways.QueueMessage) {
+	// 終了時に処理中のDB更新などが中断されないよう、処理用のcontextはctxの終了からdrainTimeout後にキャンセルする
+	workCtx, cancel := context.WithCancel(context.Background())
+	jobs := make(chan *queueWorkerJob, p.size)
+	p.wg.Add(p.size)
+	for i := 0; i < p.size; i++ {
+		go p.work(ctx, workCtx, jobs)
+	}
+	go func() {
+		<-ctx.Done()
+		timer := time.NewTimer(p.drainTimeout)
+		defer timer.Stop()
+		drained := make(chan struct{})
+		go func() {
+			p.wg.Wait()
+			close(drained)
+		}()
+		select {
+		case <-drained:
+		case <-timer.C:
+			p.logger.Warn().Str("name", p.name).Dur("drain_timeout", p.drainTimeout).Msg("Drain timeout. Cancel processing messages")
+		}
+		cancel()
+	}()
+	go p.dispatch(ctx, ch, jobs)
+}
+
+func (p *queueWorkerPool) dispatch(ctx context.Context, ch <-chan gateways.QueueMessage, jobs chan<- *queueWorkerJob) {
+	defer close(jobs)
+	for {
+		select {
+		case <-ctx.Done():
+			p.abandonBuffered(ch)
+			return
+		case queueMessage, ok := <-ch:
+			if !ok {
+				return
+			}
+			keys := p.keys(queueMessage)
+			job := &queueWorkerJob{
+				queueMessage: queueMessage,
+				keys:         keys,
+				sequence:     p.sequencer.Reserve(keys),
+			}
+			select {
+			case jobs <- job:
+			case <-ctx.Done():
+				p.abandon(job)
+				p.abandonBuffered(ch)
+				return
+			}
+		}
+	}
+}
+
+// Wait 処理中のメッセージが完了するまで待つ
+func (p *queueWorkerPool) Wait() {
+	p.wg.Wait()
+}
+
+func (p *queueWorkerPool) work(ctx context.Context, workCtx context.Context, jobs <-chan *queueWorkerJob) {
+	defer p.wg.Done()
+	for job := range jobs {
+		p.process(ctx, workCtx, job)
+	}
+}
+
+func (p *queueWorkerPool) process(ctx context.Context, workCtx context.Context, job *queueWorkerJob) {
+	startTime := time.Now()
+	p.sequencer.Wait(job.keys, job.sequence)
+	p.monitor.Metrics.GetHistogram(metricQueueWorkerWaitDuration).WithLabelValues(p.name).Observe(time.Since(startTime).Seconds())
+	if ctx.Err() != nil {
+		// 終了中のため処理を開始せずキューに残す
+		p.abandon(job)
+		return
+	}
+	defer p.sequencer.Done(job.keys, job.sequence)
+
+	inFlight := p.monitor.Metrics.GetGauge(metricQueueWorkerInFlight).WithLabelValues(p.name)
+	inFlight.Inc()
+	defer inFlight.Dec()
+	p.handle(workCtx, job.queueMessage)
+}
+
+func (p *queueWorkerPool) abandon(job *queueWorkerJob) {
+	p.sequencer.Done(job.keys, job.sequence)
+	p.queueHandler.Abandon(job.queueMessage)
+	p.monitor.Metrics.GetCounter(metricQueueWorkerAbandonedTotal).WithLabelValues(p.name).Inc()
+}
+
+// 受信済みで処理待ちのメッセージをキューに残す
+func (p *queueWorkerPool) abandonBuffered(ch <-chan gateways.QueueMessage) {
+	for {
+		select {
+		case queueMessage, ok := <-ch:
+			if !ok {
+				return
+			}
+			p.queueHandler.Abandon(queueMessage)
+			p.monitor.Metrics.GetCounter(metricQueueWorkerAbandonedTotal).WithLabelValues(p.name).Inc()
+		default:
+			return
+		}
+	}
+}
+
+// keySequencer 同じキーを持つ処理を受け付けた順番に1つずつ実行させる
+// 複数のキーを持つ場合は全てのキーで先頭になるまで待つ (受付順に待つためデッドロックしない)
+type keySequencer struct {
+	mutex  sync.Mutex
+	cond   *sync.Cond
+	queues map[int][]uint64
+	next   uint64
+}
+
+func newKeySequencer() *keySequencer {
+	s := &keySequencer{queues: map[int][]uint64{}}
+	s.cond = sync.NewCond(&s.mutex)
+	return s
+}
+
+// Reserve 受付順の番号を払い出す
+func (s *keySequencer) Reserve(keys []int) uint64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	sequence := s.next
+	s.next++
+	for _, key := range keys {
+		s.queues[key] = append(s.queues[key], sequence)
+	}
+	return sequence
+}
+
+// Wait 同じキーの先行する処理が全て完了するまで待つ
+func (s *keySequencer) Wait(keys []int, sequence uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for !s.isHead(keys, sequence) {
+		s.cond.Wait()
+	}
+}
+
+// Done 処理の完了(または中止)を通知する
+func (s *keySequencer) Done(keys []int, sequence uint64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	for _, key := range keys {
+		queue := s.queues[key]
+		remains := queue[:0]
+		for _, reserved := range queue {
+			if reserved != sequence {
+				remains = append(remains, reserved)
+			}
+		}
+		if len(remains) == 0 {
+			delete(s.queues, key)
+			continue
+		}
+		s.queues[key] = remains
+	}
+	s.cond.Broadcast()
+}
+
+func (s *keySequencer) isHead(keys []int, sequence uint64) bool {
+	for _, key := range keys {
+		if queue := s.queues[key]; len(queue) > 0 && queue[0] != sequence {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,163 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+	"touchgift-job-manager/infra/metrics"
+	"touchgift-job-manager/interface/gateways"
+	mock_gateways "touchgift-job-manager/mock/gateways"
+	mock_infra "touchgift-job-manager/mock/infra"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestQueueWorkerPool_Start(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("同じキャンペーンのメッセージは受信した順番に1つずつ処理する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
+		messages := []gateways.QueueMessage{
+			mock_infra.NewMockQueueMessage(ctrl),
+			mock_infra.NewMockQueueMessage(ctrl),
+			mock_infra.NewMockQueueMessage(ctrl),
+		}
+		keys := map[gateways.QueueMessage][]int{
+			messages[0]: {1},
+			messages[1]: {1, 2},
+			messages[2]: {2},
+		}
+
+		var mutex sync.Mutex
+		var processed []int
+		running := map[int]bool{}
+		pool := newQueueWorkerPool(logger, metrics.GetMonitor(), "test", 3, time.Second, queueHandler,
+			func(queueMessage gateways.QueueMessage) []int {
+				return keys[queueMessage]
+			},
+			func(ctx context.Context, queueMessage gateways.QueueMessage) {
+				mutex.Lock()
+				for _, key := range keys[queueMessage] {
+					assert.False(t, running[key], "same campaign is processing")
+					running[key] = true
+				}
+				mutex.Unlock()
+				time.Sleep(20 * time.Millisecond)
+				mutex.Lock()
+				for _, key := range keys[queueMessage] {
+					running[key] = false
+				}
+				for i, message := range messages {
+					if message == queueMessage {
+						processed = append(processed, i)
+					}
+				}
+				mutex.Unlock()
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan gateways.QueueMessage, len(messages))
+		for _, message := range messages {
+			ch <- message
+		}
+		pool.Start(ctx, ch)
+		assert.Eventually(t, func() bool {
+			mutex.Lock()
+			defer mutex.Unlock()
+			return len(processed) == len(messages)
+		}, time.Second, 10*time.Millisecond)
+		cancel()
+		pool.Wait()
+		assert.Equal(t, []int{0, 1, 2}, processed)
+	})
+
+	t.Run("関係のないキャンペーンのメッセージは並列に処理する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
+		messages := []gateways.QueueMessage{
+			mock_infra.NewMockQueueMessage(ctrl),
+			mock_infra.NewMockQueueMessage(ctrl),
+		}
+		keys := map[gateways.QueueMessage][]int{
+			messages[0]: {1},
+			messages[1]: {2},
+		}
+
+		// 両方のメッセージが同時に処理中にならないと完了しない
+		started := sync.WaitGroup{}
+		started.Add(len(messages))
+		done := make(chan struct{}, len(messages))
+		pool := newQueueWorkerPool(logger, metrics.GetMonitor(), "test", 2, time.Second, queueHandler,
+			func(queueMessage gateways.QueueMessage) []int {
+				return keys[queueMessage]
+			},
+			func(ctx context.Context, queueMessage gateways.QueueMessage) {
+				started.Done()
+				started.Wait()
+				done <- struct{}{}
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan gateways.QueueMessage, len(messages))
+		for _, message := range messages {
+			ch <- message
+		}
+		pool.Start(ctx, ch)
+		for range messages {
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("messages are not processed in parallel")
+			}
+		}
+		cancel()
+		pool.Wait()
+	})
+
+	t.Run("終了時は処理中のメッセージの完了を待ち、処理待ちのメッセージはキューに残す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		queueHandler := mock_gateways.NewMockQueueHandler(ctrl)
+		messages := []gateways.QueueMessage{
+			mock_infra.NewMockQueueMessage(ctrl),
+			mock_infra.NewMockQueueMessage(ctrl),
+		}
+		queueHandler.EXPECT().Abandon(gomock.Eq(messages[1]))
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var processed []gateways.QueueMessage
+		pool := newQueueWorkerPool(logger, metrics.GetMonitor(), "test", 1, time.Second, queueHandler,
+			func(queueMessage gateways.QueueMessage) []int {
+				return []int{1}
+			},
+			func(ctx context.Context, queueMessage gateways.QueueMessage) {
+				close(started)
+				<-release
+				// 終了中でも処理用のcontextはキャンセルされない
+				assert.NoError(t, ctx.Err())
+				processed = append(processed, queueMessage)
+			})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		ch := make(chan gateways.QueueMessage, len(messages))
+		ch <- messages[0]
+		pool.Start(ctx, ch)
+		<-started
+		ch <- messages[1]
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		close(release)
+		pool.Wait()
+		assert.Equal(t, []gateways.QueueMessage{messages[0]}, processed)
+	})
+}
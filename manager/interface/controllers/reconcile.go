@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/usecase"
+
+	"github.com/pkg/errors"
+)
+
+// Reconcile RDBとDynamoDBの配信データの差分を定期的に検出する
+type Reconcile interface {
+	StartMonitoring(ctx context.Context, wg *sync.WaitGroup)
+	Close()
+}
+
+type reconcile struct {
+	logger           usecase.Logger
+	config           *config.Reconcile
+	appTicker        AppTicker
+	reconcileUsecase usecase.Reconcile
+	leaderElection   usecase.LeaderElection
+	wg               *sync.WaitGroup
+}
+
+func NewReconcile(
+	logger usecase.Logger,
+	config *config.Reconcile,
+	appTicker AppTicker,
+	reconcileUsecase usecase.Reconcile,
+	leaderElection usecase.LeaderElection,
+) Reconcile {
+	return &reconcile{
+		logger:           logger,
+		config:           config,
+		appTicker:        appTicker,
+		reconcileUsecase: reconcileUsecase,
+		leaderElection:   leaderElection,
+		wg:               &sync.WaitGroup{},
+	}
+}
+
+func (r *reconcile) StartMonitoring(ctx context.Context, wg *sync.WaitGroup) {
+	r.logger.Info().Bool("repair", r.config.Repair).Msg("Start monitoring reconcile")
+	wg.Add(1)
+	ticker := r.appTicker.New(r.config.TaskInterval, time.Minute)
+	defer ticker.Stop()
+	running := false
+	mu := sync.Mutex{}
+	for {
+		select {
+		case <-ticker.C:
+			if !r.leaderElection.IsLeader() {
+				// リーダー以外は差分検出を行わない
+				r.logger.Debug().Msg("Skip reconcile (not leader)")
+				continue
+			}
+			mu.Lock()
+			if running {
+				// 前回の処理が終わっていない場合は実行しない
+				mu.Unlock()
+				r.logger.Warn().Msg("Skip reconcile (previous reconcile is running)")
+				continue
+			}
+			running = true
+			mu.Unlock()
+			r.wg.Add(1)
+			go func() {
+				defer func() {
+					mu.Lock()
+					running = false
+					mu.Unlock()
+					r.wg.Done()
+				}()
+				if err := r.process(ctx); err != nil {
+					r.logger.Error().Err(err).Msg("Failed to reconcile")
+				}
+			}()
+		case <-ctx.Done():
+			r.logger.Info().Msg("Close monitoring reconcile")
+			wg.Done()
+			return
+		}
+	}
+}
+
+func (r *reconcile) Close() {
+	r.wg.Wait()
+}
+
+func (r *reconcile) process(ctx context.Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+	}()
+	_, err = r.reconcileUsecase.Run(ctx, r.config.Repair)
+	return err
+}
@@ -0,0 +1,174 @@
+package controllers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"strings"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/infra"
+	"touchgift-job-manager/usecase"
+
+	"github.com/pkg/errors"
+)
+
+// DeadLetterの1行の最大サイズ (SQSメッセージの上限256KBに余裕を持たせる)
+const replayMaxLineSize = 1024 * 1024
+
+// Replay DeadLetterに保存したメッセージをDeliveryOperationで再処理する
+type Replay interface {
+	Run(ctx context.Context, reader io.Reader, dryRun bool) (*models.ReplayResult, error)
+	// RunLogs DeliveryOperationLogのJSONを再処理する
+	RunLogs(ctx context.Context, reader io.Reader, dryRun bool) (*models.ReplayResult, error)
+}
+
+type replay struct {
+	logger                   usecase.Logger
+	queueURL                 string
+	deliveryOperationUsecase usecase.DeliveryOperation
+}
+
+// NewReplay queueURLのキューから保存したメッセージのみを再処理する
+func NewReplay(
+	logger usecase.Logger,
+	queueURL string,
+	deliveryOperationUsecase usecase.DeliveryOperation,
+) Replay {
+	return &replay{
+		logger:                   logger,
+		queueURL:                 queueURL,
+		deliveryOperationUsecase: deliveryOperationUsecase,
+	}
+}
+
+// Run NDJSON形式のDeadLetterを1行ずつ再処理する
+// dryRunの場合は再処理対象をログに出力するだけで処理は行わない
+func (r *replay) Run(ctx context.Context, reader io.Reader, dryRun bool) (*models.ReplayResult, error) {
+	result := &models.ReplayResult{DryRun: dryRun}
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), replayMaxLineSize)
+	for scanner.Scan() {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		result.Total++
+		var deadLetter models.DeadLetter
+		if err := json.Unmarshal([]byte(line), &deadLetter); err != nil {
+			r.logger.Error().Err(err).Str("line", line).Msg("Failed to parse dead letter")
+			result.Failed++
+			continue
+		}
+		if deadLetter.QueueURL != "" && deadLetter.QueueURL != r.queueURL {
+			r.logger.Info().Str("message_id", deadLetter.MessageID).Str("queue_url", deadLetter.QueueURL).Msg("Skip dead letter of other queue")
+			result.Skipped++
+			continue
+		}
+		deliveryOperationLog, err := r.parse(&deadLetter)
+		if err != nil {
+			r.logger.Error().Err(err).Str("message_id", deadLetter.MessageID).Str("body", deadLetter.Body).Msg("Failed to parse message")
+			result.Failed++
+			continue
+		}
+		logger := r.logger.Info().
+			Str("message_id", deadLetter.MessageID).
+			Str("request_id", deliveryOperationLog.RequestID).
+			Int("attempts", deadLetter.Attempts).
+			Interface("campaigns", deliveryOperationLog.CampaignLogs)
+		if dryRun {
+			logger.Msg("Replay target (dry run)")
+			result.Processed++
+			continue
+		}
+		if err := processDeliveryOperationLog(ctx, r.deliveryOperationUsecase, deliveryOperationLog); err != nil {
+			r.logger.Error().Err(err).Str("message_id", deadLetter.MessageID).Str("request_id", deliveryOperationLog.RequestID).Msg("Failed to replay")
+			result.Failed++
+			continue
+		}
+		logger.Msg("Replayed")
+		result.Processed++
+	}
+	if err := scanner.Err(); err != nil {
+		return result, errors.Wrap(err, "Failed to read dead letters")
+	}
+	return result, nil
+}
+
+// RunLogs DeliveryOperationLogのJSON(1件、または複数件を改行区切り・配列で並べたもの)を再処理する
+// DeadLetterに保存されていないメッセージ(SNSの配信ログ等から取り出したもの)の再処理に使う
+func (r *replay) RunLogs(ctx context.Context, reader io.Reader, dryRun bool) (*models.ReplayResult, error) {
+	deliveryOperationLogs, err := parseDeliveryOperationLogs(reader)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to parse delivery operation logs")
+	}
+	result := &models.ReplayResult{DryRun: dryRun}
+	for _, deliveryOperationLog := range deliveryOperationLogs {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
+		}
+		result.Total++
+		logger := r.logger.Info().
+			Str("request_id", deliveryOperationLog.RequestID).
+			Interface("campaigns", deliveryOperationLog.CampaignLogs).
+			Interface("assets", deliveryOperationLog.AssetLogs)
+		if dryRun {
+			logger.Msg("Replay target (dry run)")
+			result.Processed++
+			continue
+		}
+		if err := processDeliveryOperationLog(ctx, r.deliveryOperationUsecase, deliveryOperationLog); err != nil {
+			r.logger.Error().Err(err).Str("request_id", deliveryOperationLog.RequestID).Msg("Failed to replay")
+			result.Failed++
+			continue
+		}
+		logger.Msg("Replayed")
+		result.Processed++
+	}
+	return result, nil
+}
+
+func parseDeliveryOperationLogs(reader io.Reader) ([]*models.DeliveryOperationLog, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	deliveryOperationLogs := []*models.DeliveryOperationLog{}
+	if bytes.HasPrefix(data, []byte("[")) {
+		if err := json.Unmarshal(data, &deliveryOperationLogs); err != nil {
+			return nil, err
+		}
+		return deliveryOperationLogs, nil
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	for decoder.More() {
+		var deliveryOperationLog models.DeliveryOperationLog
+		if err := decoder.Decode(&deliveryOperationLog); err != nil {
+			return nil, err
+		}
+		deliveryOperationLogs = append(deliveryOperationLogs, &deliveryOperationLog)
+	}
+	return deliveryOperationLogs, nil
+}
+
+// SNSメッセージのMessageがない場合(SNSメッセージとしてパースできなかった場合)は本文からパースし直す
+func (r *replay) parse(deadLetter *models.DeadLetter) (*models.DeliveryOperationLog, error) {
+	message := deadLetter.Message
+	if message == "" {
+		var snsMessage infra.SnsMessage
+		if err := json.Unmarshal([]byte(deadLetter.Body), &snsMessage); err != nil {
+			return nil, err
+		}
+		message = snsMessage.Message
+	}
+	var deliveryOperationLog models.DeliveryOperationLog
+	if err := json.Unmarshal([]byte(message), &deliveryOperationLog); err != nil {
+		return nil, err
+	}
+	return &deliveryOperationLog, nil
+}
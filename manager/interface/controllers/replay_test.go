@@ -0,0 +1,106 @@
+package controllers
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"touchgift-job-manager/domain/models"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReplay_Run(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	queueURL := "http://localhost:4566/000000000000/touchgift-delivery-operation"
+	deadLetters := strings.Join([]string{
+		// SNSメッセージとしてパースできたもの
+		`{"queue_url":"` + queueURL + `","message_id":"m1","body":"","message":"{\"type\":\"delivery_operation\",\"request_id\":\"r1\",\"campaigns\":[{\"id\":1,\"org_code\":\"org\",\"event\":\"campaign_start\"}]}","attempts":5}`,
+		// 本文のみのもの
+		`{"queue_url":"` + queueURL + `","message_id":"m2","body":"{\"MessageId\":\"s2\",\"Message\":\"{\\\"request_id\\\":\\\"r2\\\",\\\"campaigns\\\":[{\\\"id\\\":2,\\\"org_code\\\":\\\"org\\\",\\\"event\\\":\\\"campaign_stop\\\"}]}\"}","attempts":5}`,
+		// 他のキューのもの
+		`{"queue_url":"http://localhost:4566/000000000000/other","message_id":"m3","body":"{}","attempts":5}`,
+		"",
+		// パースできないもの
+		`{"queue_url":"` + queueURL + `","message_id":"m4","body":"invalid","attempts":1}`,
+	}, "\n")
+
+	t.Run("同じキューのメッセージのみ再処理する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		ctx := context.Background()
+		gomock.InOrder(
+			deliveryOperationUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Any(), gomock.Eq(&models.CampaignLog{ID: 1, OrgCode: "org", Event: "campaign_start"})).Return(nil),
+			deliveryOperationUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Any(), gomock.Eq(&models.CampaignLog{ID: 2, OrgCode: "org", Event: "campaign_stop"})).Return(errors.New("error")),
+		)
+
+		replay := NewReplay(logger, queueURL, deliveryOperationUsecase)
+		result, err := replay.Run(ctx, strings.NewReader(deadLetters), false)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.ReplayResult{Total: 4, Processed: 1, Skipped: 1, Failed: 2}, result)
+	})
+
+	t.Run("dry runの場合は再処理しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+
+		replay := NewReplay(logger, queueURL, deliveryOperationUsecase)
+		result, err := replay.Run(context.Background(), strings.NewReader(deadLetters), true)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.ReplayResult{Total: 4, Processed: 2, Skipped: 1, Failed: 1, DryRun: true}, result)
+	})
+}
+
+func TestReplay_RunLogs(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	queueURL := "http://localhost:4566/000000000000/touchgift-delivery-operation"
+
+	t.Run("改行区切りのログを順に再処理する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+		ctx := context.Background()
+		gomock.InOrder(
+			deliveryOperationUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Any(), gomock.Eq(&models.CampaignLog{ID: 1, OrgCode: "org", Event: "campaign_start"})).Return(nil),
+			deliveryOperationUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Any(), gomock.Eq(&models.CampaignLog{ID: 2, OrgCode: "org", Event: "campaign_stop"})).Return(errors.New("error")),
+		)
+
+		logs := `{"request_id":"r1","campaigns":[{"id":1,"org_code":"org","event":"campaign_start"}]}
+{"request_id":"r2","campaigns":[{"id":2,"org_code":"org","event":"campaign_stop"}]}`
+		replay := NewReplay(logger, queueURL, deliveryOperationUsecase)
+		result, err := replay.RunLogs(ctx, strings.NewReader(logs), false)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.ReplayResult{Total: 2, Processed: 1, Failed: 1}, result)
+	})
+
+	t.Run("配列のログはdry runの場合は再処理しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deliveryOperationUsecase := mock_usecase.NewMockDeliveryOperation(ctrl)
+
+		logs := `[{"request_id":"r1","campaigns":[{"id":1,"org_code":"org","event":"campaign_start"}]},{"request_id":"r2"}]`
+		replay := NewReplay(logger, queueURL, deliveryOperationUsecase)
+		result, err := replay.RunLogs(context.Background(), strings.NewReader(logs), true)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.ReplayResult{Total: 2, Processed: 2, DryRun: true}, result)
+	})
+
+	t.Run("パースできない場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		replay := NewReplay(logger, queueURL, mock_usecase.NewMockDeliveryOperation(ctrl))
+		_, err := replay.RunLogs(context.Background(), strings.NewReader("invalid"), false)
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,30 @@
+package controllers
+
+import (
+	"net/http"
+	"touchgift-job-manager/usecase"
+)
+
+type reservation struct {
+	logger usecase.Logger
+	timer  usecase.Timer
+}
+
+// NewReservation 未実行の開始/終了予約を返す
+func NewReservation(logger usecase.Logger, timer usecase.Timer) HTTPHandler {
+	instance := reservation{
+		logger: logger,
+		timer:  timer,
+	}
+	return &instance
+}
+
+func (r *reservation) Handler(c Context) {
+	reservations, err := r.timer.Pending(c)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to get pending reservations")
+		c.InternalError(err)
+		return
+	}
+	c.JSON(http.StatusOK, reservations)
+}
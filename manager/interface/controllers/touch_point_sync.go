@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/usecase"
+
+	"github.com/pkg/errors"
+)
+
+// TouchPointSync 店舗グループ・タッチポイントの変更を定期的に配信データに反映する
+type TouchPointSync interface {
+	StartMonitoring(ctx context.Context, wg *sync.WaitGroup)
+	Close()
+}
+
+type touchPointSync struct {
+	logger                usecase.Logger
+	config                *config.TouchPointSync
+	appTicker             AppTicker
+	touchPointSyncUsecase usecase.TouchPointSync
+	leaderElection        usecase.LeaderElection
+	wg                    *sync.WaitGroup
+}
+
+func NewTouchPointSync(
+	logger usecase.Logger,
+	config *config.TouchPointSync,
+	appTicker AppTicker,
+	touchPointSyncUsecase usecase.TouchPointSync,
+	leaderElection usecase.LeaderElection,
+) TouchPointSync {
+	return &touchPointSync{
+		logger:                logger,
+		config:                config,
+		appTicker:             appTicker,
+		touchPointSyncUsecase: touchPointSyncUsecase,
+		leaderElection:        leaderElection,
+		wg:                    &sync.WaitGroup{},
+	}
+}
+
+func (t *touchPointSync) StartMonitoring(ctx context.Context, wg *sync.WaitGroup) {
+	t.logger.Info().Msg("Start monitoring touch point sync")
+	wg.Add(1)
+	ticker := t.appTicker.New(t.config.TaskInterval, time.Minute)
+	defer ticker.Stop()
+	running := false
+	mu := sync.Mutex{}
+	for {
+		select {
+		case <-ticker.C:
+			if !t.leaderElection.IsLeader() {
+				// リーダー以外は同期を行わない
+				t.logger.Debug().Msg("Skip touch point sync (not leader)")
+				continue
+			}
+			mu.Lock()
+			if running {
+				// 前回の処理が終わっていない場合は実行しない
+				mu.Unlock()
+				t.logger.Warn().Msg("Skip touch point sync (previous sync is running)")
+				continue
+			}
+			running = true
+			mu.Unlock()
+			t.wg.Add(1)
+			go func() {
+				defer func() {
+					mu.Lock()
+					running = false
+					mu.Unlock()
+					t.wg.Done()
+				}()
+				if err := t.process(ctx); err != nil {
+					t.logger.Error().Err(err).Msg("Failed to sync touch points")
+				}
+			}()
+		case <-ctx.Done():
+			t.logger.Info().Msg("Close monitoring touch point sync")
+			wg.Done()
+			return
+		}
+	}
+}
+
+func (t *touchPointSync) Close() {
+	t.wg.Wait()
+}
+
+func (t *touchPointSync) process(ctx context.Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+	}()
+	_, err = t.touchPointSyncUsecase.Run(ctx)
+	return err
+}
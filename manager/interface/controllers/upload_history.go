@@ -0,0 +1,101 @@
+package controllers
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/usecase"
+
+	"github.com/pkg/errors"
+)
+
+// UploadHistory 店舗/タッチポイントのCSVアップロード履歴を監視し、配信データに反映する
+type UploadHistory interface {
+	StartMonitoring(ctx context.Context, wg *sync.WaitGroup)
+	Close()
+}
+
+type uploadHistory struct {
+	logger               usecase.Logger
+	config               *config.UploadHistory
+	appTicker            AppTicker
+	uploadHistoryUsecase usecase.UploadHistory
+	leaderElection       usecase.LeaderElection
+	wg                   *sync.WaitGroup
+}
+
+func NewUploadHistory(
+	logger usecase.Logger,
+	config *config.UploadHistory,
+	appTicker AppTicker,
+	uploadHistoryUsecase usecase.UploadHistory,
+	leaderElection usecase.LeaderElection,
+) UploadHistory {
+	return &uploadHistory{
+		logger:               logger,
+		config:               config,
+		appTicker:            appTicker,
+		uploadHistoryUsecase: uploadHistoryUsecase,
+		leaderElection:       leaderElection,
+		wg:                   &sync.WaitGroup{},
+	}
+}
+
+func (u *uploadHistory) StartMonitoring(ctx context.Context, wg *sync.WaitGroup) {
+	u.logger.Info().Msg("Start monitoring upload history")
+	wg.Add(1)
+	ticker := u.appTicker.New(u.config.TaskInterval, time.Minute)
+	defer ticker.Stop()
+	running := false
+	mu := sync.Mutex{}
+	for {
+		select {
+		case <-ticker.C:
+			if !u.leaderElection.IsLeader() {
+				// リーダー以外は反映を行わない
+				u.logger.Debug().Msg("Skip upload history (not leader)")
+				continue
+			}
+			mu.Lock()
+			if running {
+				// 前回の処理が終わっていない場合は実行しない
+				mu.Unlock()
+				u.logger.Warn().Msg("Skip upload history (previous process is running)")
+				continue
+			}
+			running = true
+			mu.Unlock()
+			u.wg.Add(1)
+			go func() {
+				defer func() {
+					mu.Lock()
+					running = false
+					mu.Unlock()
+					u.wg.Done()
+				}()
+				if err := u.process(ctx); err != nil {
+					u.logger.Error().Err(err).Msg("Failed to process upload histories")
+				}
+			}()
+		case <-ctx.Done():
+			u.logger.Info().Msg("Close monitoring upload history")
+			wg.Done()
+			return
+		}
+	}
+}
+
+func (u *uploadHistory) Close() {
+	u.wg.Wait()
+}
+
+func (u *uploadHistory) process(ctx context.Context) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+	}()
+	_, err = u.uploadHistoryUsecase.ProcessPending(ctx)
+	return err
+}
@@ -14,4 +14,6 @@ type QueueHandler interface {
 	UnprocessableMessage()
 	OutputDeleteCliLog(message infra.QueueMessage)
 	DeleteMessage(ctx context.Context, message infra.QueueMessage)
+	DeadLetter(ctx context.Context, message infra.QueueMessage, cause error) error
+	Abandon(message infra.QueueMessage)
 }
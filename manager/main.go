@@ -2,17 +2,21 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"net/http"
 	"os"
 	"os/signal"
 	"sync"
 	"syscall"
 	"time"
+	// scratchイメージにはタイムゾーンのデータがないため埋め込む
+	_ "time/tzdata"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/infra"
 	"touchgift-job-manager/injector"
 
 	"github.com/gin-gonic/gin"
+	"github.com/urfave/cli"
 )
 
 func SignalContext(ctx context.Context, logger *infra.Logger) (context.Context, context.CancelFunc) {
@@ -37,6 +41,13 @@ func SignalContext(ctx context.Context, logger *infra.Logger) (context.Context,
 	return parent, cancelParent
 }
 
+// サブコマンドの結果を標準出力にJSONで出力する
+func printJSON(v interface{}) error {
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
 func listenAndServe(ctx context.Context, port string, router *gin.Engine, logger *infra.Logger) chan error {
 	srv := &http.Server{
 		Addr:              ":" + port,
@@ -117,6 +128,24 @@ func run(sctx context.Context, cancel context.CancelFunc, logger *infra.Logger)
 
 func main() {
 	logger := infra.GetLogger()
-	ctx, cancel := SignalContext(context.Background(), logger)
-	run(ctx, cancel, logger)
+	app := cli.NewApp()
+	app.Name = "touchgift-job-manager"
+	app.Usage = "start/end touchgift campaign delivery"
+	// サブコマンドを指定しない場合はサーバーとして起動する
+	app.Action = func(c *cli.Context) error {
+		ctx, cancel := SignalContext(context.Background(), logger)
+		run(ctx, cancel, logger)
+		return nil
+	}
+	app.Commands = []cli.Command{
+		replayCommand(logger),
+		replayLogCommand(logger),
+		planCommand(logger),
+		campaignCommand(logger),
+		dumpCommand(logger),
+		purgeCreativesCommand(logger),
+	}
+	if err := app.Run(os.Args); err != nil {
+		logger.Fatal().Err(err).Msg("Failed to run")
+	}
 }
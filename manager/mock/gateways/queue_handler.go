@@ -36,6 +36,32 @@ func (m *MockQueueHandler) EXPECT() *MockQueueHandlerMockRecorder {
 	return m.recorder
 }
 
+// Abandon mocks base method.
+func (m *MockQueueHandler) Abandon(message infra.QueueMessage) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Abandon", message)
+}
+
+// Abandon indicates an expected call of Abandon.
+func (mr *MockQueueHandlerMockRecorder) Abandon(message interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abandon", reflect.TypeOf((*MockQueueHandler)(nil).Abandon), message)
+}
+
+// DeadLetter mocks base method.
+func (m *MockQueueHandler) DeadLetter(ctx context.Context, message infra.QueueMessage, cause error) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeadLetter", ctx, message, cause)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeadLetter indicates an expected call of DeadLetter.
+func (mr *MockQueueHandlerMockRecorder) DeadLetter(ctx, message, cause interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeadLetter", reflect.TypeOf((*MockQueueHandler)(nil).DeadLetter), ctx, message, cause)
+}
+
 // DeleteMessage mocks base method.
 func (m *MockQueueHandler) DeleteMessage(ctx context.Context, message infra.QueueMessage) {
 	m.ctrl.T.Helper()
@@ -33,6 +33,20 @@ func (m *MockQueueMessage) EXPECT() *MockQueueMessageMockRecorder {
 	return m.recorder
 }
 
+// Body mocks base method.
+func (m *MockQueueMessage) Body() *string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Body")
+	ret0, _ := ret[0].(*string)
+	return ret0
+}
+
+// Body indicates an expected call of Body.
+func (mr *MockQueueMessageMockRecorder) Body() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Body", reflect.TypeOf((*MockQueueMessage)(nil).Body))
+}
+
 // Message mocks base method.
 func (m *MockQueueMessage) Message() *string {
 	m.ctrl.T.Helper()
@@ -74,3 +88,31 @@ func (mr *MockQueueMessageMockRecorder) ReceiptHandle() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiptHandle", reflect.TypeOf((*MockQueueMessage)(nil).ReceiptHandle))
 }
+
+// ReceiveCount mocks base method.
+func (m *MockQueueMessage) ReceiveCount() int {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReceiveCount")
+	ret0, _ := ret[0].(int)
+	return ret0
+}
+
+// ReceiveCount indicates an expected call of ReceiveCount.
+func (mr *MockQueueMessageMockRecorder) ReceiveCount() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReceiveCount", reflect.TypeOf((*MockQueueMessage)(nil).ReceiveCount))
+}
+
+// SnsMessageID mocks base method.
+func (m *MockQueueMessage) SnsMessageID() *string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SnsMessageID")
+	ret0, _ := ret[0].(*string)
+	return ret0
+}
+
+// SnsMessageID indicates an expected call of SnsMessageID.
+func (mr *MockQueueMessageMockRecorder) SnsMessageID() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SnsMessageID", reflect.TypeOf((*MockQueueMessage)(nil).SnsMessageID))
+}
@@ -63,6 +63,21 @@ func (mr *MockSQLHandlerMockRecorder) Close() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Close", reflect.TypeOf((*MockSQLHandler)(nil).Close))
 }
 
+// Conn mocks base method.
+func (m *MockSQLHandler) Conn(ctx context.Context) (*sqlx.Conn, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Conn", ctx)
+	ret0, _ := ret[0].(*sqlx.Conn)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Conn indicates an expected call of Conn.
+func (mr *MockSQLHandlerMockRecorder) Conn(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Conn", reflect.TypeOf((*MockSQLHandler)(nil).Conn), ctx)
+}
+
 // In mocks base method.
 func (m *MockSQLHandler) In(query string, arg interface{}) (*string, []interface{}, error) {
 	m.ctrl.T.Helper()
@@ -36,6 +36,36 @@ func (m *MockCampaignRepository) EXPECT() *MockCampaignRepositoryMockRecorder {
 	return m.recorder
 }
 
+// GetCampaignByAsset mocks base method.
+func (m *MockCampaignRepository) GetCampaignByAsset(ctx context.Context, args *repository.CampaignByAssetCondition) ([]*models.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignByAsset", ctx, args)
+	ret0, _ := ret[0].([]*models.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignByAsset indicates an expected call of GetCampaignByAsset.
+func (mr *MockCampaignRepositoryMockRecorder) GetCampaignByAsset(ctx, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignByAsset", reflect.TypeOf((*MockCampaignRepository)(nil).GetCampaignByAsset), ctx, args)
+}
+
+// GetCampaignByStatus mocks base method.
+func (m *MockCampaignRepository) GetCampaignByStatus(ctx context.Context, args *repository.CampaignByStatusCondition) ([]*models.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignByStatus", ctx, args)
+	ret0, _ := ret[0].([]*models.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignByStatus indicates an expected call of GetCampaignByStatus.
+func (mr *MockCampaignRepositoryMockRecorder) GetCampaignByStatus(ctx, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignByStatus", reflect.TypeOf((*MockCampaignRepository)(nil).GetCampaignByStatus), ctx, args)
+}
+
 // GetCampaignCreative mocks base method.
 func (m *MockCampaignRepository) GetCampaignCreative(ctx context.Context, tx repository.Transaction, args *repository.CampaignCondition) ([]*models.CampaignCreative, error) {
 	m.ctrl.T.Helper()
@@ -66,6 +96,21 @@ func (mr *MockCampaignRepositoryMockRecorder) GetCampaignToEnd(ctx, args interfa
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignToEnd", reflect.TypeOf((*MockCampaignRepository)(nil).GetCampaignToEnd), ctx, args)
 }
 
+// GetCampaignToExpendedOrShortage mocks base method.
+func (m *MockCampaignRepository) GetCampaignToExpendedOrShortage(ctx context.Context, tx repository.Transaction, campaignID int, budgetExpended bool) (*models.Campaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCampaignToExpendedOrShortage", ctx, tx, campaignID, budgetExpended)
+	ret0, _ := ret[0].(*models.Campaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCampaignToExpendedOrShortage indicates an expected call of GetCampaignToExpendedOrShortage.
+func (mr *MockCampaignRepositoryMockRecorder) GetCampaignToExpendedOrShortage(ctx, tx, campaignID, budgetExpended interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignToExpendedOrShortage", reflect.TypeOf((*MockCampaignRepository)(nil).GetCampaignToExpendedOrShortage), ctx, tx, campaignID, budgetExpended)
+}
+
 // GetCampaignToStart mocks base method.
 func (m *MockCampaignRepository) GetCampaignToStart(ctx context.Context, args *repository.CampaignToStartCondition) ([]*models.Campaign, error) {
 	m.ctrl.T.Helper()
@@ -111,6 +156,21 @@ func (mr *MockCampaignRepositoryMockRecorder) GetDeliveryToStart(ctx, tx, args i
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryToStart", reflect.TypeOf((*MockCampaignRepository)(nil).GetDeliveryToStart), ctx, tx, args)
 }
 
+// GetStatusForUpdate mocks base method.
+func (m *MockCampaignRepository) GetStatusForUpdate(ctx context.Context, tx repository.Transaction, campaignID int) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStatusForUpdate", ctx, tx, campaignID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStatusForUpdate indicates an expected call of GetStatusForUpdate.
+func (mr *MockCampaignRepositoryMockRecorder) GetStatusForUpdate(ctx, tx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStatusForUpdate", reflect.TypeOf((*MockCampaignRepository)(nil).GetStatusForUpdate), ctx, tx, campaignID)
+}
+
 // UpdateStatus mocks base method.
 func (m *MockCampaignRepository) UpdateStatus(ctx context.Context, tx repository.Transaction, campaign *repository.UpdateCondition) (int, error) {
 	m.ctrl.T.Helper()
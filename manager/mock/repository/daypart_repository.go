@@ -0,0 +1,96 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: daypart_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	models "touchgift-job-manager/domain/models"
+	repository "touchgift-job-manager/domain/repository"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDaypartRepository is a mock of DaypartRepository interface.
+type MockDaypartRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockDaypartRepositoryMockRecorder
+}
+
+// MockDaypartRepositoryMockRecorder is the mock recorder for MockDaypartRepository.
+type MockDaypartRepositoryMockRecorder struct {
+	mock *MockDaypartRepository
+}
+
+// NewMockDaypartRepository creates a new mock instance.
+func NewMockDaypartRepository(ctrl *gomock.Controller) *MockDaypartRepository {
+	mock := &MockDaypartRepository{ctrl: ctrl}
+	mock.recorder = &MockDaypartRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDaypartRepository) EXPECT() *MockDaypartRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeletePause mocks base method.
+func (m *MockDaypartRepository) DeletePause(ctx context.Context, tx repository.Transaction, campaignID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeletePause", ctx, tx, campaignID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeletePause indicates an expected call of DeletePause.
+func (mr *MockDaypartRepositoryMockRecorder) DeletePause(ctx, tx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeletePause", reflect.TypeOf((*MockDaypartRepository)(nil).DeletePause), ctx, tx, campaignID)
+}
+
+// GetByCampaignIDs mocks base method.
+func (m *MockDaypartRepository) GetByCampaignIDs(ctx context.Context, campaignIDs []int) ([]*models.Daypart, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetByCampaignIDs", ctx, campaignIDs)
+	ret0, _ := ret[0].([]*models.Daypart)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetByCampaignIDs indicates an expected call of GetByCampaignIDs.
+func (mr *MockDaypartRepositoryMockRecorder) GetByCampaignIDs(ctx, campaignIDs interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetByCampaignIDs", reflect.TypeOf((*MockDaypartRepository)(nil).GetByCampaignIDs), ctx, campaignIDs)
+}
+
+// GetPausedCampaignIDs mocks base method.
+func (m *MockDaypartRepository) GetPausedCampaignIDs(ctx context.Context) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPausedCampaignIDs", ctx)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPausedCampaignIDs indicates an expected call of GetPausedCampaignIDs.
+func (mr *MockDaypartRepositoryMockRecorder) GetPausedCampaignIDs(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPausedCampaignIDs", reflect.TypeOf((*MockDaypartRepository)(nil).GetPausedCampaignIDs), ctx)
+}
+
+// SavePause mocks base method.
+func (m *MockDaypartRepository) SavePause(ctx context.Context, tx repository.Transaction, campaignID int, pausedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SavePause", ctx, tx, campaignID, pausedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SavePause indicates an expected call of SavePause.
+func (mr *MockDaypartRepositoryMockRecorder) SavePause(ctx, tx, campaignID, pausedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SavePause", reflect.TypeOf((*MockDaypartRepository)(nil).SavePause), ctx, tx, campaignID, pausedAt)
+}
@@ -78,6 +78,21 @@ func (mr *MockDeliveryDataCampaignRepositoryMockRecorder) Get(ctx, id interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeliveryDataCampaignRepository)(nil).Get), ctx, id)
 }
 
+// GetAll mocks base method.
+func (m *MockDeliveryDataCampaignRepository) GetAll(ctx context.Context) ([]*models.DeliveryDataCampaign, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*models.DeliveryDataCampaign)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeliveryDataCampaignRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeliveryDataCampaignRepository)(nil).GetAll), ctx)
+}
+
 // Put mocks base method.
 func (m *MockDeliveryDataCampaignRepository) Put(ctx context.Context, updateData *models.DeliveryDataCampaign) error {
 	m.ctrl.T.Helper()
@@ -129,6 +144,21 @@ func (m *MockDeliveryDataTouchPointRepository) EXPECT() *MockDeliveryDataTouchPo
 	return m.recorder
 }
 
+// BatchGet mocks base method.
+func (m *MockDeliveryDataTouchPointRepository) BatchGet(ctx context.Context, keys *[]models.DeliveryTouchPoint) ([]*models.DeliveryTouchPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGet", ctx, keys)
+	ret0, _ := ret[0].([]*models.DeliveryTouchPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGet indicates an expected call of BatchGet.
+func (mr *MockDeliveryDataTouchPointRepositoryMockRecorder) BatchGet(ctx, keys interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGet", reflect.TypeOf((*MockDeliveryDataTouchPointRepository)(nil).BatchGet), ctx, keys)
+}
+
 // Delete mocks base method.
 func (m *MockDeliveryDataTouchPointRepository) Delete(ctx context.Context, id, groupID *string) error {
 	m.ctrl.T.Helper()
@@ -172,6 +202,21 @@ func (mr *MockDeliveryDataTouchPointRepositoryMockRecorder) Get(ctx, id, groupID
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeliveryDataTouchPointRepository)(nil).Get), ctx, id, groupID)
 }
 
+// GetAll mocks base method.
+func (m *MockDeliveryDataTouchPointRepository) GetAll(ctx context.Context) ([]*models.DeliveryTouchPoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*models.DeliveryTouchPoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeliveryDataTouchPointRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeliveryDataTouchPointRepository)(nil).GetAll), ctx)
+}
+
 // Put mocks base method.
 func (m *MockDeliveryDataTouchPointRepository) Put(ctx context.Context, updateData *models.DeliveryTouchPoint) error {
 	m.ctrl.T.Helper()
@@ -223,6 +268,21 @@ func (m *MockDeliveryDataCreativeRepository) EXPECT() *MockDeliveryDataCreativeR
 	return m.recorder
 }
 
+// BatchGet mocks base method.
+func (m *MockDeliveryDataCreativeRepository) BatchGet(ctx context.Context, ids []string) ([]*models.DeliveryDataCreative, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "BatchGet", ctx, ids)
+	ret0, _ := ret[0].([]*models.DeliveryDataCreative)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// BatchGet indicates an expected call of BatchGet.
+func (mr *MockDeliveryDataCreativeRepositoryMockRecorder) BatchGet(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "BatchGet", reflect.TypeOf((*MockDeliveryDataCreativeRepository)(nil).BatchGet), ctx, ids)
+}
+
 // Delete mocks base method.
 func (m *MockDeliveryDataCreativeRepository) Delete(ctx context.Context, campaignID *string) error {
 	m.ctrl.T.Helper()
@@ -266,6 +326,21 @@ func (mr *MockDeliveryDataCreativeRepositoryMockRecorder) Get(ctx, id interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Get", reflect.TypeOf((*MockDeliveryDataCreativeRepository)(nil).Get), ctx, id)
 }
 
+// GetAll mocks base method.
+func (m *MockDeliveryDataCreativeRepository) GetAll(ctx context.Context) ([]*models.DeliveryDataCreative, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*models.DeliveryDataCreative)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockDeliveryDataCreativeRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockDeliveryDataCreativeRepository)(nil).GetAll), ctx)
+}
+
 // Put mocks base method.
 func (m *MockDeliveryDataCreativeRepository) Put(ctx context.Context, updateData *models.DeliveryDataCreative) error {
 	m.ctrl.T.Helper()
@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: lease_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLeaseRepository is a mock of LeaseRepository interface.
+type MockLeaseRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaseRepositoryMockRecorder
+}
+
+// MockLeaseRepositoryMockRecorder is the mock recorder for MockLeaseRepository.
+type MockLeaseRepositoryMockRecorder struct {
+	mock *MockLeaseRepository
+}
+
+// NewMockLeaseRepository creates a new mock instance.
+func NewMockLeaseRepository(ctrl *gomock.Controller) *MockLeaseRepository {
+	mock := &MockLeaseRepository{ctrl: ctrl}
+	mock.recorder = &MockLeaseRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaseRepository) EXPECT() *MockLeaseRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Acquire mocks base method.
+func (m *MockLeaseRepository) Acquire(ctx context.Context, name, owner string, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Acquire", ctx, name, owner, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Acquire indicates an expected call of Acquire.
+func (mr *MockLeaseRepositoryMockRecorder) Acquire(ctx, name, owner, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Acquire", reflect.TypeOf((*MockLeaseRepository)(nil).Acquire), ctx, name, owner, duration)
+}
+
+// Release mocks base method.
+func (m *MockLeaseRepository) Release(ctx context.Context, name, owner string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, name, owner)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockLeaseRepositoryMockRecorder) Release(ctx, name, owner interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockLeaseRepository)(nil).Release), ctx, name, owner)
+}
+
+// Renew mocks base method.
+func (m *MockLeaseRepository) Renew(ctx context.Context, name, owner string, duration time.Duration) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Renew", ctx, name, owner, duration)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Renew indicates an expected call of Renew.
+func (mr *MockLeaseRepositoryMockRecorder) Renew(ctx, name, owner, duration interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Renew", reflect.TypeOf((*MockLeaseRepository)(nil).Renew), ctx, name, owner, duration)
+}
@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: message_dedup_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMessageDedupRepository is a mock of MessageDedupRepository interface.
+type MockMessageDedupRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageDedupRepositoryMockRecorder
+}
+
+// MockMessageDedupRepositoryMockRecorder is the mock recorder for MockMessageDedupRepository.
+type MockMessageDedupRepositoryMockRecorder struct {
+	mock *MockMessageDedupRepository
+}
+
+// NewMockMessageDedupRepository creates a new mock instance.
+func NewMockMessageDedupRepository(ctrl *gomock.Controller) *MockMessageDedupRepository {
+	mock := &MockMessageDedupRepository{ctrl: ctrl}
+	mock.recorder = &MockMessageDedupRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageDedupRepository) EXPECT() *MockMessageDedupRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Claim mocks base method.
+func (m *MockMessageDedupRepository) Claim(ctx context.Context, key string, now, expiresAt time.Time) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Claim", ctx, key, now, expiresAt)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Claim indicates an expected call of Claim.
+func (mr *MockMessageDedupRepositoryMockRecorder) Claim(ctx, key, now, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Claim", reflect.TypeOf((*MockMessageDedupRepository)(nil).Claim), ctx, key, now, expiresAt)
+}
+
+// Complete mocks base method.
+func (m *MockMessageDedupRepository) Complete(ctx context.Context, key string, expiresAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, key, expiresAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockMessageDedupRepositoryMockRecorder) Complete(ctx, key, expiresAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockMessageDedupRepository)(nil).Complete), ctx, key, expiresAt)
+}
+
+// DeleteExpired mocks base method.
+func (m *MockMessageDedupRepository) DeleteExpired(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteExpired", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteExpired indicates an expected call of DeleteExpired.
+func (mr *MockMessageDedupRepositoryMockRecorder) DeleteExpired(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteExpired", reflect.TypeOf((*MockMessageDedupRepository)(nil).DeleteExpired), ctx, before, limit)
+}
+
+// Release mocks base method.
+func (m *MockMessageDedupRepository) Release(ctx context.Context, key string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, key)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockMessageDedupRepositoryMockRecorder) Release(ctx, key interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockMessageDedupRepository)(nil).Release), ctx, key)
+}
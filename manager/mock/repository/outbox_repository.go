@@ -0,0 +1,212 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outbox_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	models "touchgift-job-manager/domain/models"
+	repository "touchgift-job-manager/domain/repository"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxRepository is a mock of OutboxRepository interface.
+type MockOutboxRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRepositoryMockRecorder
+}
+
+// MockOutboxRepositoryMockRecorder is the mock recorder for MockOutboxRepository.
+type MockOutboxRepositoryMockRecorder struct {
+	mock *MockOutboxRepository
+}
+
+// NewMockOutboxRepository creates a new mock instance.
+func NewMockOutboxRepository(ctrl *gomock.Controller) *MockOutboxRepository {
+	mock := &MockOutboxRepository{ctrl: ctrl}
+	mock.recorder = &MockOutboxRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRepository) EXPECT() *MockOutboxRepositoryMockRecorder {
+	return m.recorder
+}
+
+// DeleteDelivered mocks base method.
+func (m *MockOutboxRepository) DeleteDelivered(ctx context.Context, before time.Time, limit int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteDelivered", ctx, before, limit)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteDelivered indicates an expected call of DeleteDelivered.
+func (mr *MockOutboxRepositoryMockRecorder) DeleteDelivered(ctx, before, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteDelivered", reflect.TypeOf((*MockOutboxRepository)(nil).DeleteDelivered), ctx, before, limit)
+}
+
+// DeleteHeld mocks base method.
+func (m *MockOutboxRepository) DeleteHeld(ctx context.Context, tx repository.Transaction, campaignID int) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteHeld", ctx, tx, campaignID)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteHeld indicates an expected call of DeleteHeld.
+func (mr *MockOutboxRepositoryMockRecorder) DeleteHeld(ctx, tx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteHeld", reflect.TypeOf((*MockOutboxRepository)(nil).DeleteHeld), ctx, tx, campaignID)
+}
+
+// GetBacklog mocks base method.
+func (m *MockOutboxRepository) GetBacklog(ctx context.Context) (*models.OutboxBacklog, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetBacklog", ctx)
+	ret0, _ := ret[0].(*models.OutboxBacklog)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetBacklog indicates an expected call of GetBacklog.
+func (mr *MockOutboxRepositoryMockRecorder) GetBacklog(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetBacklog", reflect.TypeOf((*MockOutboxRepository)(nil).GetBacklog), ctx)
+}
+
+// GetOldestPendingID mocks base method.
+func (m *MockOutboxRepository) GetOldestPendingID(ctx context.Context, tx repository.Transaction) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetOldestPendingID", ctx, tx)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetOldestPendingID indicates an expected call of GetOldestPendingID.
+func (mr *MockOutboxRepositoryMockRecorder) GetOldestPendingID(ctx, tx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetOldestPendingID", reflect.TypeOf((*MockOutboxRepository)(nil).GetOldestPendingID), ctx, tx)
+}
+
+// GetPending mocks base method.
+func (m *MockOutboxRepository) GetPending(ctx context.Context, tx repository.Transaction, limit int) ([]*models.OutboxEvent, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPending", ctx, tx, limit)
+	ret0, _ := ret[0].([]*models.OutboxEvent)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPending indicates an expected call of GetPending.
+func (mr *MockOutboxRepositoryMockRecorder) GetPending(ctx, tx, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPending", reflect.TypeOf((*MockOutboxRepository)(nil).GetPending), ctx, tx, limit)
+}
+
+// Lease mocks base method.
+func (m *MockOutboxRepository) Lease(ctx context.Context, tx repository.Transaction, ids []int64, lockedUntil time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Lease", ctx, tx, ids, lockedUntil)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Lease indicates an expected call of Lease.
+func (mr *MockOutboxRepositoryMockRecorder) Lease(ctx, tx, ids, lockedUntil interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Lease", reflect.TypeOf((*MockOutboxRepository)(nil).Lease), ctx, tx, ids, lockedUntil)
+}
+
+// MarkDead mocks base method.
+func (m *MockOutboxRepository) MarkDead(ctx context.Context, id int64, failedAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDead", ctx, id, failedAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDead indicates an expected call of MarkDead.
+func (mr *MockOutboxRepositoryMockRecorder) MarkDead(ctx, id, failedAt, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDead", reflect.TypeOf((*MockOutboxRepository)(nil).MarkDead), ctx, id, failedAt, lastError)
+}
+
+// MarkDelivered mocks base method.
+func (m *MockOutboxRepository) MarkDelivered(ctx context.Context, id int64, deliveredAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkDelivered", ctx, id, deliveredAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkDelivered indicates an expected call of MarkDelivered.
+func (mr *MockOutboxRepositoryMockRecorder) MarkDelivered(ctx, id, deliveredAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkDelivered", reflect.TypeOf((*MockOutboxRepository)(nil).MarkDelivered), ctx, id, deliveredAt)
+}
+
+// MarkFailed mocks base method.
+func (m *MockOutboxRepository) MarkFailed(ctx context.Context, id int64, nextAttemptAt time.Time, lastError string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkFailed", ctx, id, nextAttemptAt, lastError)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// MarkFailed indicates an expected call of MarkFailed.
+func (mr *MockOutboxRepositoryMockRecorder) MarkFailed(ctx, id, nextAttemptAt, lastError interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkFailed", reflect.TypeOf((*MockOutboxRepository)(nil).MarkFailed), ctx, id, nextAttemptAt, lastError)
+}
+
+// Release mocks base method.
+func (m *MockOutboxRepository) Release(ctx context.Context, ids []int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Release", ctx, ids)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Release indicates an expected call of Release.
+func (mr *MockOutboxRepositoryMockRecorder) Release(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Release", reflect.TypeOf((*MockOutboxRepository)(nil).Release), ctx, ids)
+}
+
+// ReleaseHeld mocks base method.
+func (m *MockOutboxRepository) ReleaseHeld(ctx context.Context, tx repository.Transaction, campaignID int, nextAttemptAt time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHeld", ctx, tx, campaignID, nextAttemptAt)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReleaseHeld indicates an expected call of ReleaseHeld.
+func (mr *MockOutboxRepositoryMockRecorder) ReleaseHeld(ctx, tx, campaignID, nextAttemptAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHeld", reflect.TypeOf((*MockOutboxRepository)(nil).ReleaseHeld), ctx, tx, campaignID, nextAttemptAt)
+}
+
+// Save mocks base method.
+func (m *MockOutboxRepository) Save(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, tx, event)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockOutboxRepositoryMockRecorder) Save(ctx, tx, event interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockOutboxRepository)(nil).Save), ctx, tx, event)
+}
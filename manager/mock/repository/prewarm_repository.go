@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: prewarm_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	repository "touchgift-job-manager/domain/repository"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockPrewarmRepository is a mock of PrewarmRepository interface.
+type MockPrewarmRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockPrewarmRepositoryMockRecorder
+}
+
+// MockPrewarmRepositoryMockRecorder is the mock recorder for MockPrewarmRepository.
+type MockPrewarmRepositoryMockRecorder struct {
+	mock *MockPrewarmRepository
+}
+
+// NewMockPrewarmRepository creates a new mock instance.
+func NewMockPrewarmRepository(ctrl *gomock.Controller) *MockPrewarmRepository {
+	mock := &MockPrewarmRepository{ctrl: ctrl}
+	mock.recorder = &MockPrewarmRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockPrewarmRepository) EXPECT() *MockPrewarmRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockPrewarmRepository) Delete(ctx context.Context, tx repository.Transaction, campaignID int) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, tx, campaignID)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockPrewarmRepositoryMockRecorder) Delete(ctx, tx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockPrewarmRepository)(nil).Delete), ctx, tx, campaignID)
+}
+
+// Save mocks base method.
+func (m *MockPrewarmRepository) Save(ctx context.Context, tx repository.Transaction, campaignID int, prewarmedAt time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, tx, campaignID, prewarmedAt)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockPrewarmRepositoryMockRecorder) Save(ctx, tx, campaignID, prewarmedAt interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockPrewarmRepository)(nil).Save), ctx, tx, campaignID, prewarmedAt)
+}
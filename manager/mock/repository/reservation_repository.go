@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reservation_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	models "touchgift-job-manager/domain/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReservationRepository is a mock of ReservationRepository interface.
+type MockReservationRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockReservationRepositoryMockRecorder
+}
+
+// MockReservationRepositoryMockRecorder is the mock recorder for MockReservationRepository.
+type MockReservationRepositoryMockRecorder struct {
+	mock *MockReservationRepository
+}
+
+// NewMockReservationRepository creates a new mock instance.
+func NewMockReservationRepository(ctrl *gomock.Controller) *MockReservationRepository {
+	mock := &MockReservationRepository{ctrl: ctrl}
+	mock.recorder = &MockReservationRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReservationRepository) EXPECT() *MockReservationRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Delete mocks base method.
+func (m *MockReservationRepository) Delete(ctx context.Context, campaignID int, action string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Delete", ctx, campaignID, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Delete indicates an expected call of Delete.
+func (mr *MockReservationRepositoryMockRecorder) Delete(ctx, campaignID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockReservationRepository)(nil).Delete), ctx, campaignID, action)
+}
+
+// GetAll mocks base method.
+func (m *MockReservationRepository) GetAll(ctx context.Context) ([]*models.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetAll", ctx)
+	ret0, _ := ret[0].([]*models.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetAll indicates an expected call of GetAll.
+func (mr *MockReservationRepositoryMockRecorder) GetAll(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetAll", reflect.TypeOf((*MockReservationRepository)(nil).GetAll), ctx)
+}
+
+// Save mocks base method.
+func (m *MockReservationRepository) Save(ctx context.Context, reservation *models.Reservation) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Save", ctx, reservation)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Save indicates an expected call of Save.
+func (mr *MockReservationRepositoryMockRecorder) Save(ctx, reservation interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Save", reflect.TypeOf((*MockReservationRepository)(nil).Save), ctx, reservation)
+}
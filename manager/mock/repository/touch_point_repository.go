@@ -7,6 +7,7 @@ package mock_repository
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 	models "touchgift-job-manager/domain/models"
 	repository "touchgift-job-manager/domain/repository"
 
@@ -36,6 +37,21 @@ func (m *MockTouchPointRepository) EXPECT() *MockTouchPointRepositoryMockRecorde
 	return m.recorder
 }
 
+// GetChangedGroupIDs mocks base method.
+func (m *MockTouchPointRepository) GetChangedGroupIDs(ctx context.Context, since time.Time) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChangedGroupIDs", ctx, since)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChangedGroupIDs indicates an expected call of GetChangedGroupIDs.
+func (mr *MockTouchPointRepositoryMockRecorder) GetChangedGroupIDs(ctx, since interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChangedGroupIDs", reflect.TypeOf((*MockTouchPointRepository)(nil).GetChangedGroupIDs), ctx, since)
+}
+
 // GetTouchPointByGroupID mocks base method.
 func (m *MockTouchPointRepository) GetTouchPointByGroupID(ctx context.Context, args *repository.TouchPointByGroupIDCondition) ([]*models.TouchPoint, error) {
 	m.ctrl.T.Helper()
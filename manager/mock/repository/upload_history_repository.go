@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: upload_history_repository.go
+
+// Package mock_repository is a generated GoMock package.
+package mock_repository
+
+import (
+	context "context"
+	reflect "reflect"
+	models "touchgift-job-manager/domain/models"
+	repository "touchgift-job-manager/domain/repository"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUploadHistoryRepository is a mock of UploadHistoryRepository interface.
+type MockUploadHistoryRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadHistoryRepositoryMockRecorder
+}
+
+// MockUploadHistoryRepositoryMockRecorder is the mock recorder for MockUploadHistoryRepository.
+type MockUploadHistoryRepositoryMockRecorder struct {
+	mock *MockUploadHistoryRepository
+}
+
+// NewMockUploadHistoryRepository creates a new mock instance.
+func NewMockUploadHistoryRepository(ctrl *gomock.Controller) *MockUploadHistoryRepository {
+	mock := &MockUploadHistoryRepository{ctrl: ctrl}
+	mock.recorder = &MockUploadHistoryRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadHistoryRepository) EXPECT() *MockUploadHistoryRepositoryMockRecorder {
+	return m.recorder
+}
+
+// GetUnprocessed mocks base method.
+func (m *MockUploadHistoryRepository) GetUnprocessed(ctx context.Context, args *repository.UnprocessedUploadCondition) ([]*models.UploadHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUnprocessed", ctx, args)
+	ret0, _ := ret[0].([]*models.UploadHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUnprocessed indicates an expected call of GetUnprocessed.
+func (mr *MockUploadHistoryRepositoryMockRecorder) GetUnprocessed(ctx, args interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUnprocessed", reflect.TypeOf((*MockUploadHistoryRepository)(nil).GetUnprocessed), ctx, args)
+}
+
+// SaveProcess mocks base method.
+func (m *MockUploadHistoryRepository) SaveProcess(ctx context.Context, process *models.UploadProcess) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SaveProcess", ctx, process)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// SaveProcess indicates an expected call of SaveProcess.
+func (mr *MockUploadHistoryRepositoryMockRecorder) SaveProcess(ctx, process interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SaveProcess", reflect.TypeOf((*MockUploadHistoryRepository)(nil).SaveProcess), ctx, process)
+}
@@ -0,0 +1,123 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: admin_campaign.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	models "touchgift-job-manager/domain/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockAdminCampaign is a mock of AdminCampaign interface.
+type MockAdminCampaign struct {
+	ctrl     *gomock.Controller
+	recorder *MockAdminCampaignMockRecorder
+}
+
+// MockAdminCampaignMockRecorder is the mock recorder for MockAdminCampaign.
+type MockAdminCampaignMockRecorder struct {
+	mock *MockAdminCampaign
+}
+
+// NewMockAdminCampaign creates a new mock instance.
+func NewMockAdminCampaign(ctrl *gomock.Controller) *MockAdminCampaign {
+	mock := &MockAdminCampaign{ctrl: ctrl}
+	mock.recorder = &MockAdminCampaignMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockAdminCampaign) EXPECT() *MockAdminCampaignMockRecorder {
+	return m.recorder
+}
+
+// End mocks base method.
+func (m *MockAdminCampaign) End(ctx context.Context, campaignID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "End", ctx, campaignID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// End indicates an expected call of End.
+func (mr *MockAdminCampaignMockRecorder) End(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "End", reflect.TypeOf((*MockAdminCampaign)(nil).End), ctx, campaignID)
+}
+
+// Inspect mocks base method.
+func (m *MockAdminCampaign) Inspect(ctx context.Context, campaignID int) (*models.DeliveryInspection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Inspect", ctx, campaignID)
+	ret0, _ := ret[0].(*models.DeliveryInspection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Inspect indicates an expected call of Inspect.
+func (mr *MockAdminCampaignMockRecorder) Inspect(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Inspect", reflect.TypeOf((*MockAdminCampaign)(nil).Inspect), ctx, campaignID)
+}
+
+// InspectGroup mocks base method.
+func (m *MockAdminCampaign) InspectGroup(ctx context.Context, groupID int) (*models.GroupInspection, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "InspectGroup", ctx, groupID)
+	ret0, _ := ret[0].(*models.GroupInspection)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// InspectGroup indicates an expected call of InspectGroup.
+func (mr *MockAdminCampaignMockRecorder) InspectGroup(ctx, groupID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "InspectGroup", reflect.TypeOf((*MockAdminCampaign)(nil).InspectGroup), ctx, groupID)
+}
+
+// Plan mocks base method.
+func (m *MockAdminCampaign) Plan(ctx context.Context, campaignID int, action string) (*models.DeliveryPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", ctx, campaignID, action)
+	ret0, _ := ret[0].(*models.DeliveryPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockAdminCampaignMockRecorder) Plan(ctx, campaignID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockAdminCampaign)(nil).Plan), ctx, campaignID, action)
+}
+
+// Start mocks base method.
+func (m *MockAdminCampaign) Start(ctx context.Context, campaignID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Start", ctx, campaignID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Start indicates an expected call of Start.
+func (mr *MockAdminCampaignMockRecorder) Start(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockAdminCampaign)(nil).Start), ctx, campaignID)
+}
+
+// Sync mocks base method.
+func (m *MockAdminCampaign) Sync(ctx context.Context, campaignID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Sync", ctx, campaignID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Sync indicates an expected call of Sync.
+func (mr *MockAdminCampaignMockRecorder) Sync(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Sync", reflect.TypeOf((*MockAdminCampaign)(nil).Sync), ctx, campaignID)
+}
@@ -37,6 +37,20 @@ func (m *MockCreative) EXPECT() *MockCreativeMockRecorder {
 	return m.recorder
 }
 
+// Expire mocks base method.
+func (m *MockCreative) Expire(ctx context.Context, current time.Time, creativeID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Expire", ctx, current, creativeID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Expire indicates an expected call of Expire.
+func (mr *MockCreativeMockRecorder) Expire(ctx, current, creativeID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Expire", reflect.TypeOf((*MockCreative)(nil).Expire), ctx, current, creativeID)
+}
+
 // Process mocks base method.
 func (m *MockCreative) Process(ctx context.Context, tx repository.Transaction, current time.Time, creativeLogs *[]models.CreativeLog) error {
 	m.ctrl.T.Helper()
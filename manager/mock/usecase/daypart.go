@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: daypart.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	models "touchgift-job-manager/domain/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDaypart is a mock of Daypart interface.
+type MockDaypart struct {
+	ctrl     *gomock.Controller
+	recorder *MockDaypartMockRecorder
+}
+
+// MockDaypartMockRecorder is the mock recorder for MockDaypart.
+type MockDaypartMockRecorder struct {
+	mock *MockDaypart
+}
+
+// NewMockDaypart creates a new mock instance.
+func NewMockDaypart(ctrl *gomock.Controller) *MockDaypart {
+	mock := &MockDaypart{ctrl: ctrl}
+	mock.recorder = &MockDaypartMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDaypart) EXPECT() *MockDaypartMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockDaypart) Run(ctx context.Context, current time.Time) (*models.DaypartResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, current)
+	ret0, _ := ret[0].(*models.DaypartResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockDaypartMockRecorder) Run(ctx, current interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockDaypart)(nil).Run), ctx, current)
+}
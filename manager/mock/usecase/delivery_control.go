@@ -3,3 +3,49 @@
 
 // Package mock_usecase is a generated GoMock package.
 package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	models "touchgift-job-manager/domain/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockDeliveryControl is a mock of DeliveryControl interface.
+type MockDeliveryControl struct {
+	ctrl     *gomock.Controller
+	recorder *MockDeliveryControlMockRecorder
+}
+
+// MockDeliveryControlMockRecorder is the mock recorder for MockDeliveryControl.
+type MockDeliveryControlMockRecorder struct {
+	mock *MockDeliveryControl
+}
+
+// NewMockDeliveryControl creates a new mock instance.
+func NewMockDeliveryControl(ctrl *gomock.Controller) *MockDeliveryControl {
+	mock := &MockDeliveryControl{ctrl: ctrl}
+	mock.recorder = &MockDeliveryControlMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockDeliveryControl) EXPECT() *MockDeliveryControlMockRecorder {
+	return m.recorder
+}
+
+// Process mocks base method.
+func (m *MockDeliveryControl) Process(ctx context.Context, current time.Time, deliveryControlLog *models.DeliveryControlLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Process", ctx, current, deliveryControlLog)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Process indicates an expected call of Process.
+func (mr *MockDeliveryControlMockRecorder) Process(ctx, current, deliveryControlLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockDeliveryControl)(nil).Process), ctx, current, deliveryControlLog)
+}
@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 	models "touchgift-job-manager/domain/models"
+	repository "touchgift-job-manager/domain/repository"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,38 +36,86 @@ func (m *MockDeliveryControlEvent) EXPECT() *MockDeliveryControlEventMockRecorde
 	return m.recorder
 }
 
+// DiscardHeldEvents mocks base method.
+func (m *MockDeliveryControlEvent) DiscardHeldEvents(ctx context.Context, tx repository.Transaction, campaignID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DiscardHeldEvents", ctx, tx, campaignID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DiscardHeldEvents indicates an expected call of DiscardHeldEvents.
+func (mr *MockDeliveryControlEventMockRecorder) DiscardHeldEvents(ctx, tx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DiscardHeldEvents", reflect.TypeOf((*MockDeliveryControlEvent)(nil).DiscardHeldEvents), ctx, tx, campaignID)
+}
+
 // PublishCampaignEvent mocks base method.
-func (m *MockDeliveryControlEvent) PublishCampaignEvent(ctx context.Context, CampaignID, groupID int, organization, before, after, detail string) {
+func (m *MockDeliveryControlEvent) PublishCampaignEvent(ctx context.Context, tx repository.Transaction, CampaignID, groupID int, organization, before, after, detail string) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "PublishCampaignEvent", ctx, CampaignID, groupID, organization, before, after, detail)
+	ret := m.ctrl.Call(m, "PublishCampaignEvent", ctx, tx, CampaignID, groupID, organization, before, after, detail)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // PublishCampaignEvent indicates an expected call of PublishCampaignEvent.
-func (mr *MockDeliveryControlEventMockRecorder) PublishCampaignEvent(ctx, CampaignID, groupID, organization, before, after, detail interface{}) *gomock.Call {
+func (mr *MockDeliveryControlEventMockRecorder) PublishCampaignEvent(ctx, tx, CampaignID, groupID, organization, before, after, detail interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishCampaignEvent", reflect.TypeOf((*MockDeliveryControlEvent)(nil).PublishCampaignEvent), ctx, CampaignID, groupID, organization, before, after, detail)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishCampaignEvent", reflect.TypeOf((*MockDeliveryControlEvent)(nil).PublishCampaignEvent), ctx, tx, CampaignID, groupID, organization, before, after, detail)
 }
 
 // PublishCreativeEvent mocks base method.
-func (m *MockDeliveryControlEvent) PublishCreativeEvent(ctx context.Context, creative *models.DeliveryDataCreative, organization, action string) {
+func (m *MockDeliveryControlEvent) PublishCreativeEvent(ctx context.Context, tx repository.Transaction, creative *models.DeliveryDataCreative, organization, action string) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "PublishCreativeEvent", ctx, creative, organization, action)
+	ret := m.ctrl.Call(m, "PublishCreativeEvent", ctx, tx, creative, organization, action)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // PublishCreativeEvent indicates an expected call of PublishCreativeEvent.
-func (mr *MockDeliveryControlEventMockRecorder) PublishCreativeEvent(ctx, creative, organization, action interface{}) *gomock.Call {
+func (mr *MockDeliveryControlEventMockRecorder) PublishCreativeEvent(ctx, tx, creative, organization, action interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishCreativeEvent", reflect.TypeOf((*MockDeliveryControlEvent)(nil).PublishCreativeEvent), ctx, creative, organization, action)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishCreativeEvent", reflect.TypeOf((*MockDeliveryControlEvent)(nil).PublishCreativeEvent), ctx, tx, creative, organization, action)
 }
 
 // PublishDeliveryEvent mocks base method.
-func (m *MockDeliveryControlEvent) PublishDeliveryEvent(ctx context.Context, id string, groupID int, storeID string, campaignID int, organization, action string) {
+func (m *MockDeliveryControlEvent) PublishDeliveryEvent(ctx context.Context, tx repository.Transaction, id string, groupID int, storeID string, campaignID int, organization, action string) error {
 	m.ctrl.T.Helper()
-	m.ctrl.Call(m, "PublishDeliveryEvent", ctx, id, groupID, storeID, campaignID, organization, action)
+	ret := m.ctrl.Call(m, "PublishDeliveryEvent", ctx, tx, id, groupID, storeID, campaignID, organization, action)
+	ret0, _ := ret[0].(error)
+	return ret0
 }
 
 // PublishDeliveryEvent indicates an expected call of PublishDeliveryEvent.
-func (mr *MockDeliveryControlEventMockRecorder) PublishDeliveryEvent(ctx, id, groupID, storeID, campaignID, organization, action interface{}) *gomock.Call {
+func (mr *MockDeliveryControlEventMockRecorder) PublishDeliveryEvent(ctx, tx, id, groupID, storeID, campaignID, organization, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDeliveryEvent", reflect.TypeOf((*MockDeliveryControlEvent)(nil).PublishDeliveryEvent), ctx, tx, id, groupID, storeID, campaignID, organization, action)
+}
+
+// PublishDeliveryEvents mocks base method.
+func (m *MockDeliveryControlEvent) PublishDeliveryEvents(ctx context.Context, tx repository.Transaction, touchPoints []*models.DeliveryTouchPoint, campaignID int, organization, action string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PublishDeliveryEvents", ctx, tx, touchPoints, campaignID, organization, action)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PublishDeliveryEvents indicates an expected call of PublishDeliveryEvents.
+func (mr *MockDeliveryControlEventMockRecorder) PublishDeliveryEvents(ctx, tx, touchPoints, campaignID, organization, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDeliveryEvents", reflect.TypeOf((*MockDeliveryControlEvent)(nil).PublishDeliveryEvents), ctx, tx, touchPoints, campaignID, organization, action)
+}
+
+// ReleaseHeldEvents mocks base method.
+func (m *MockDeliveryControlEvent) ReleaseHeldEvents(ctx context.Context, tx repository.Transaction, campaignID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReleaseHeldEvents", ctx, tx, campaignID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ReleaseHeldEvents indicates an expected call of ReleaseHeldEvents.
+func (mr *MockDeliveryControlEventMockRecorder) ReleaseHeldEvents(ctx, tx, campaignID interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PublishDeliveryEvent", reflect.TypeOf((*MockDeliveryControlEvent)(nil).PublishDeliveryEvent), ctx, id, groupID, storeID, campaignID, organization, action)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReleaseHeldEvents", reflect.TypeOf((*MockDeliveryControlEvent)(nil).ReleaseHeldEvents), ctx, tx, campaignID)
 }
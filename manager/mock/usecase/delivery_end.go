@@ -62,17 +62,31 @@ func (mr *MockDeliveryEndMockRecorder) CreateWorker(ctx interface{}) *gomock.Cal
 }
 
 // Delete mocks base method.
-func (m *MockDeliveryEnd) Delete(ctx context.Context, campaign *models.Campaign) error {
+func (m *MockDeliveryEnd) Delete(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, campaign)
+	ret := m.ctrl.Call(m, "Delete", ctx, tx, campaign)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockDeliveryEndMockRecorder) Delete(ctx, campaign interface{}) *gomock.Call {
+func (mr *MockDeliveryEndMockRecorder) Delete(ctx, tx, campaign interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeliveryEnd)(nil).Delete), ctx, campaign)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockDeliveryEnd)(nil).Delete), ctx, tx, campaign)
+}
+
+// Execute mocks base method.
+func (m *MockDeliveryEnd) Execute(ctx context.Context, campaign *models.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockDeliveryEndMockRecorder) Execute(ctx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockDeliveryEnd)(nil).Execute), ctx, campaign)
 }
 
 // ExecuteNow mocks base method.
@@ -102,6 +116,21 @@ func (mr *MockDeliveryEndMockRecorder) GetDeliveryDataCampaigns(ctx, to, status,
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDataCampaigns", reflect.TypeOf((*MockDeliveryEnd)(nil).GetDeliveryDataCampaigns), ctx, to, status, limit)
 }
 
+// Plan mocks base method.
+func (m *MockDeliveryEnd) Plan(ctx context.Context, campaignID int) (*models.DeliveryPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", ctx, campaignID)
+	ret0, _ := ret[0].(*models.DeliveryPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockDeliveryEndMockRecorder) Plan(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockDeliveryEnd)(nil).Plan), ctx, campaignID)
+}
+
 // Reserve mocks base method.
 func (m *MockDeliveryEnd) Reserve(ctx context.Context, endAt time.Time, campaign *models.Campaign) {
 	m.ctrl.T.Helper()
@@ -128,6 +157,20 @@ func (mr *MockDeliveryEndMockRecorder) Stop(ctx, tx, campaign, status interface{
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockDeliveryEnd)(nil).Stop), ctx, tx, campaign, status)
 }
 
+// StopStarted mocks base method.
+func (m *MockDeliveryEnd) StopStarted(ctx context.Context, campaignID int) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopStarted", ctx, campaignID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopStarted indicates an expected call of StopStarted.
+func (mr *MockDeliveryEndMockRecorder) StopStarted(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopStarted", reflect.TypeOf((*MockDeliveryEnd)(nil).StopStarted), ctx, campaignID)
+}
+
 // Terminate mocks base method.
 func (m *MockDeliveryEnd) Terminate(ctx context.Context, tx repository.Transaction, campaignID int, updatedAt time.Time) (int, error) {
 	m.ctrl.T.Helper()
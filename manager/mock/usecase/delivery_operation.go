@@ -36,6 +36,21 @@ func (m *MockDeliveryOperation) EXPECT() *MockDeliveryOperationMockRecorder {
 	return m.recorder
 }
 
+// AssetCampaignIDs mocks base method.
+func (m *MockDeliveryOperation) AssetCampaignIDs(ctx context.Context, assetLog *models.AssetLog) ([]int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AssetCampaignIDs", ctx, assetLog)
+	ret0, _ := ret[0].([]int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// AssetCampaignIDs indicates an expected call of AssetCampaignIDs.
+func (mr *MockDeliveryOperationMockRecorder) AssetCampaignIDs(ctx, assetLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AssetCampaignIDs", reflect.TypeOf((*MockDeliveryOperation)(nil).AssetCampaignIDs), ctx, assetLog)
+}
+
 // Process mocks base method.
 func (m *MockDeliveryOperation) Process(ctx context.Context, current time.Time, campaignLog *models.CampaignLog) error {
 	m.ctrl.T.Helper()
@@ -49,3 +64,17 @@ func (mr *MockDeliveryOperationMockRecorder) Process(ctx, current, campaignLog i
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Process", reflect.TypeOf((*MockDeliveryOperation)(nil).Process), ctx, current, campaignLog)
 }
+
+// ProcessAsset mocks base method.
+func (m *MockDeliveryOperation) ProcessAsset(ctx context.Context, current time.Time, assetLog *models.AssetLog) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessAsset", ctx, current, assetLog)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ProcessAsset indicates an expected call of ProcessAsset.
+func (mr *MockDeliveryOperationMockRecorder) ProcessAsset(ctx, current, assetLog interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessAsset", reflect.TypeOf((*MockDeliveryOperation)(nil).ProcessAsset), ctx, current, assetLog)
+}
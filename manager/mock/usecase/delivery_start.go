@@ -75,6 +75,20 @@ func (mr *MockDeliveryStartMockRecorder) CreateWorker(ctx interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateWorker", reflect.TypeOf((*MockDeliveryStart)(nil).CreateWorker), ctx)
 }
 
+// Execute mocks base method.
+func (m *MockDeliveryStart) Execute(ctx context.Context, campaign *models.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Execute", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Execute indicates an expected call of Execute.
+func (mr *MockDeliveryStartMockRecorder) Execute(ctx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Execute", reflect.TypeOf((*MockDeliveryStart)(nil).Execute), ctx, campaign)
+}
+
 // ExecuteNow mocks base method.
 func (m *MockDeliveryStart) ExecuteNow(schduleData *models.Campaign) {
 	m.ctrl.T.Helper()
@@ -102,6 +116,50 @@ func (mr *MockDeliveryStartMockRecorder) GetCampaignToStart(ctx, to, status, lim
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCampaignToStart", reflect.TypeOf((*MockDeliveryStart)(nil).GetCampaignToStart), ctx, to, status, limit)
 }
 
+// GetDeliveryDatas mocks base method.
+func (m *MockDeliveryStart) GetDeliveryDatas(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) (*models.DeliveryDataSet, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetDeliveryDatas", ctx, tx, campaign)
+	ret0, _ := ret[0].(*models.DeliveryDataSet)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetDeliveryDatas indicates an expected call of GetDeliveryDatas.
+func (mr *MockDeliveryStartMockRecorder) GetDeliveryDatas(ctx, tx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetDeliveryDatas", reflect.TypeOf((*MockDeliveryStart)(nil).GetDeliveryDatas), ctx, tx, campaign)
+}
+
+// Plan mocks base method.
+func (m *MockDeliveryStart) Plan(ctx context.Context, campaignID int) (*models.DeliveryPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Plan", ctx, campaignID)
+	ret0, _ := ret[0].(*models.DeliveryPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Plan indicates an expected call of Plan.
+func (mr *MockDeliveryStartMockRecorder) Plan(ctx, campaignID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Plan", reflect.TypeOf((*MockDeliveryStart)(nil).Plan), ctx, campaignID)
+}
+
+// Prewarm mocks base method.
+func (m *MockDeliveryStart) Prewarm(ctx context.Context, campaign *models.Campaign) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Prewarm", ctx, campaign)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Prewarm indicates an expected call of Prewarm.
+func (mr *MockDeliveryStartMockRecorder) Prewarm(ctx, campaign interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Prewarm", reflect.TypeOf((*MockDeliveryStart)(nil).Prewarm), ctx, campaign)
+}
+
 // Reserve mocks base method.
 func (m *MockDeliveryStart) Reserve(ctx context.Context, startAt time.Time, Campaign *models.Campaign) {
 	m.ctrl.T.Helper()
@@ -0,0 +1,88 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: leader_election.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	sync "sync"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockLeaderElection is a mock of LeaderElection interface.
+type MockLeaderElection struct {
+	ctrl     *gomock.Controller
+	recorder *MockLeaderElectionMockRecorder
+}
+
+// MockLeaderElectionMockRecorder is the mock recorder for MockLeaderElection.
+type MockLeaderElectionMockRecorder struct {
+	mock *MockLeaderElection
+}
+
+// NewMockLeaderElection creates a new mock instance.
+func NewMockLeaderElection(ctrl *gomock.Controller) *MockLeaderElection {
+	mock := &MockLeaderElection{ctrl: ctrl}
+	mock.recorder = &MockLeaderElectionMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockLeaderElection) EXPECT() *MockLeaderElectionMockRecorder {
+	return m.recorder
+}
+
+// Context mocks base method.
+func (m *MockLeaderElection) Context() context.Context {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Context")
+	ret0, _ := ret[0].(context.Context)
+	return ret0
+}
+
+// Context indicates an expected call of Context.
+func (mr *MockLeaderElectionMockRecorder) Context() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Context", reflect.TypeOf((*MockLeaderElection)(nil).Context))
+}
+
+// IsLeader mocks base method.
+func (m *MockLeaderElection) IsLeader() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsLeader")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsLeader indicates an expected call of IsLeader.
+func (mr *MockLeaderElectionMockRecorder) IsLeader() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsLeader", reflect.TypeOf((*MockLeaderElection)(nil).IsLeader))
+}
+
+// OnElected mocks base method.
+func (m *MockLeaderElection) OnElected(callback func(context.Context)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnElected", callback)
+}
+
+// OnElected indicates an expected call of OnElected.
+func (mr *MockLeaderElectionMockRecorder) OnElected(callback interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnElected", reflect.TypeOf((*MockLeaderElection)(nil).OnElected), callback)
+}
+
+// Run mocks base method.
+func (m *MockLeaderElection) Run(ctx context.Context, wg *sync.WaitGroup) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Run", ctx, wg)
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockLeaderElectionMockRecorder) Run(ctx, wg interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockLeaderElection)(nil).Run), ctx, wg)
+}
@@ -0,0 +1,52 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: maintenance.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+	models "touchgift-job-manager/domain/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMaintenance is a mock of Maintenance interface.
+type MockMaintenance struct {
+	ctrl     *gomock.Controller
+	recorder *MockMaintenanceMockRecorder
+}
+
+// MockMaintenanceMockRecorder is the mock recorder for MockMaintenance.
+type MockMaintenanceMockRecorder struct {
+	mock *MockMaintenance
+}
+
+// NewMockMaintenance creates a new mock instance.
+func NewMockMaintenance(ctrl *gomock.Controller) *MockMaintenance {
+	mock := &MockMaintenance{ctrl: ctrl}
+	mock.recorder = &MockMaintenanceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMaintenance) EXPECT() *MockMaintenanceMockRecorder {
+	return m.recorder
+}
+
+// PurgeOrphanCreatives mocks base method.
+func (m *MockMaintenance) PurgeOrphanCreatives(ctx context.Context, current time.Time, dryRun bool) (*models.CreativePurgeResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeOrphanCreatives", ctx, current, dryRun)
+	ret0, _ := ret[0].(*models.CreativePurgeResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeOrphanCreatives indicates an expected call of PurgeOrphanCreatives.
+func (mr *MockMaintenanceMockRecorder) PurgeOrphanCreatives(ctx, current, dryRun interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeOrphanCreatives", reflect.TypeOf((*MockMaintenance)(nil).PurgeOrphanCreatives), ctx, current, dryRun)
+}
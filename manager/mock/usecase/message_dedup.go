@@ -0,0 +1,79 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: message_dedup.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockMessageDedup is a mock of MessageDedup interface.
+type MockMessageDedup struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageDedupMockRecorder
+}
+
+// MockMessageDedupMockRecorder is the mock recorder for MockMessageDedup.
+type MockMessageDedupMockRecorder struct {
+	mock *MockMessageDedup
+}
+
+// NewMockMessageDedup creates a new mock instance.
+func NewMockMessageDedup(ctrl *gomock.Controller) *MockMessageDedup {
+	mock := &MockMessageDedup{ctrl: ctrl}
+	mock.recorder = &MockMessageDedupMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageDedup) EXPECT() *MockMessageDedupMockRecorder {
+	return m.recorder
+}
+
+// Abort mocks base method.
+func (m *MockMessageDedup) Abort(ctx context.Context, messageID, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Abort", ctx, messageID, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Abort indicates an expected call of Abort.
+func (mr *MockMessageDedupMockRecorder) Abort(ctx, messageID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Abort", reflect.TypeOf((*MockMessageDedup)(nil).Abort), ctx, messageID, requestID)
+}
+
+// Begin mocks base method.
+func (m *MockMessageDedup) Begin(ctx context.Context, now time.Time, messageID, requestID string) (string, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Begin", ctx, now, messageID, requestID)
+	ret0, _ := ret[0].(string)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Begin indicates an expected call of Begin.
+func (mr *MockMessageDedupMockRecorder) Begin(ctx, now, messageID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Begin", reflect.TypeOf((*MockMessageDedup)(nil).Begin), ctx, now, messageID, requestID)
+}
+
+// Complete mocks base method.
+func (m *MockMessageDedup) Complete(ctx context.Context, now time.Time, messageID, requestID string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Complete", ctx, now, messageID, requestID)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Complete indicates an expected call of Complete.
+func (mr *MockMessageDedupMockRecorder) Complete(ctx, now, messageID, requestID interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Complete", reflect.TypeOf((*MockMessageDedup)(nil).Complete), ctx, now, messageID, requestID)
+}
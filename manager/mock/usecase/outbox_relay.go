@@ -0,0 +1,80 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: outbox_relay.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockOutboxRelay is a mock of OutboxRelay interface.
+type MockOutboxRelay struct {
+	ctrl     *gomock.Controller
+	recorder *MockOutboxRelayMockRecorder
+}
+
+// MockOutboxRelayMockRecorder is the mock recorder for MockOutboxRelay.
+type MockOutboxRelayMockRecorder struct {
+	mock *MockOutboxRelay
+}
+
+// NewMockOutboxRelay creates a new mock instance.
+func NewMockOutboxRelay(ctrl *gomock.Controller) *MockOutboxRelay {
+	mock := &MockOutboxRelay{ctrl: ctrl}
+	mock.recorder = &MockOutboxRelayMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockOutboxRelay) EXPECT() *MockOutboxRelayMockRecorder {
+	return m.recorder
+}
+
+// Cleanup mocks base method.
+func (m *MockOutboxRelay) Cleanup(ctx context.Context, now time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Cleanup", ctx, now)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Cleanup indicates an expected call of Cleanup.
+func (mr *MockOutboxRelayMockRecorder) Cleanup(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cleanup", reflect.TypeOf((*MockOutboxRelay)(nil).Cleanup), ctx, now)
+}
+
+// ObserveBacklog mocks base method.
+func (m *MockOutboxRelay) ObserveBacklog(ctx context.Context, now time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ObserveBacklog", ctx, now)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ObserveBacklog indicates an expected call of ObserveBacklog.
+func (mr *MockOutboxRelayMockRecorder) ObserveBacklog(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ObserveBacklog", reflect.TypeOf((*MockOutboxRelay)(nil).ObserveBacklog), ctx, now)
+}
+
+// Relay mocks base method.
+func (m *MockOutboxRelay) Relay(ctx context.Context, now time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Relay", ctx, now)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Relay indicates an expected call of Relay.
+func (mr *MockOutboxRelayMockRecorder) Relay(ctx, now interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Relay", reflect.TypeOf((*MockOutboxRelay)(nil).Relay), ctx, now)
+}
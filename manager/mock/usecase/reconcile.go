@@ -0,0 +1,51 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: reconcile.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	models "touchgift-job-manager/domain/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockReconcile is a mock of Reconcile interface.
+type MockReconcile struct {
+	ctrl     *gomock.Controller
+	recorder *MockReconcileMockRecorder
+}
+
+// MockReconcileMockRecorder is the mock recorder for MockReconcile.
+type MockReconcileMockRecorder struct {
+	mock *MockReconcile
+}
+
+// NewMockReconcile creates a new mock instance.
+func NewMockReconcile(ctrl *gomock.Controller) *MockReconcile {
+	mock := &MockReconcile{ctrl: ctrl}
+	mock.recorder = &MockReconcileMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockReconcile) EXPECT() *MockReconcileMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockReconcile) Run(ctx context.Context, repair bool) (*models.ReconcileResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx, repair)
+	ret0, _ := ret[0].(*models.ReconcileResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockReconcileMockRecorder) Run(ctx, repair interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockReconcile)(nil).Run), ctx, repair)
+}
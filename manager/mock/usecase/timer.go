@@ -8,6 +8,7 @@ import (
 	context "context"
 	reflect "reflect"
 	time "time"
+	models "touchgift-job-manager/domain/models"
 
 	gomock "github.com/golang/mock/gomock"
 )
@@ -35,6 +36,18 @@ func (m *MockTimer) EXPECT() *MockTimerMockRecorder {
 	return m.recorder
 }
 
+// Cancel mocks base method.
+func (m *MockTimer) Cancel(ctx context.Context, campaignID int, action string) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Cancel", ctx, campaignID, action)
+}
+
+// Cancel indicates an expected call of Cancel.
+func (mr *MockTimerMockRecorder) Cancel(ctx, campaignID, action interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Cancel", reflect.TypeOf((*MockTimer)(nil).Cancel), ctx, campaignID, action)
+}
+
 // ExecuteAtTime mocks base method.
 func (m *MockTimer) ExecuteAtTime(ctx context.Context, specifiedTime time.Time, process func()) {
 	m.ctrl.T.Helper()
@@ -46,3 +59,56 @@ func (mr *MockTimerMockRecorder) ExecuteAtTime(ctx, specifiedTime, process inter
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExecuteAtTime", reflect.TypeOf((*MockTimer)(nil).ExecuteAtTime), ctx, specifiedTime, process)
 }
+
+// Handle mocks base method.
+func (m *MockTimer) Handle(action string, handler func(*models.Reservation)) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Handle", action, handler)
+}
+
+// Handle indicates an expected call of Handle.
+func (mr *MockTimerMockRecorder) Handle(action, handler interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Handle", reflect.TypeOf((*MockTimer)(nil).Handle), action, handler)
+}
+
+// Pending mocks base method.
+func (m *MockTimer) Pending(ctx context.Context) ([]*models.Reservation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Pending", ctx)
+	ret0, _ := ret[0].([]*models.Reservation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Pending indicates an expected call of Pending.
+func (mr *MockTimerMockRecorder) Pending(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Pending", reflect.TypeOf((*MockTimer)(nil).Pending), ctx)
+}
+
+// Reserve mocks base method.
+func (m *MockTimer) Reserve(ctx context.Context, reservation *models.Reservation, process func()) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Reserve", ctx, reservation, process)
+}
+
+// Reserve indicates an expected call of Reserve.
+func (mr *MockTimerMockRecorder) Reserve(ctx, reservation, process interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reserve", reflect.TypeOf((*MockTimer)(nil).Reserve), ctx, reservation, process)
+}
+
+// Restore mocks base method.
+func (m *MockTimer) Restore(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockTimerMockRecorder) Restore(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockTimer)(nil).Restore), ctx)
+}
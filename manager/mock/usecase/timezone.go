@@ -0,0 +1,63 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: timezone.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	reflect "reflect"
+	time "time"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTimezone is a mock of Timezone interface.
+type MockTimezone struct {
+	ctrl     *gomock.Controller
+	recorder *MockTimezoneMockRecorder
+}
+
+// MockTimezoneMockRecorder is the mock recorder for MockTimezone.
+type MockTimezoneMockRecorder struct {
+	mock *MockTimezone
+}
+
+// NewMockTimezone creates a new mock instance.
+func NewMockTimezone(ctrl *gomock.Controller) *MockTimezone {
+	mock := &MockTimezone{ctrl: ctrl}
+	mock.recorder = &MockTimezoneMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTimezone) EXPECT() *MockTimezoneMockRecorder {
+	return m.recorder
+}
+
+// Format mocks base method.
+func (m *MockTimezone) Format(orgCode string, t time.Time) string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Format", orgCode, t)
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Format indicates an expected call of Format.
+func (mr *MockTimezoneMockRecorder) Format(orgCode, t interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Format", reflect.TypeOf((*MockTimezone)(nil).Format), orgCode, t)
+}
+
+// Location mocks base method.
+func (m *MockTimezone) Location(orgCode string) *time.Location {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Location", orgCode)
+	ret0, _ := ret[0].(*time.Location)
+	return ret0
+}
+
+// Location indicates an expected call of Location.
+func (mr *MockTimezoneMockRecorder) Location(orgCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Location", reflect.TypeOf((*MockTimezone)(nil).Location), orgCode)
+}
@@ -0,0 +1,66 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: touch_point_sync.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+	models "touchgift-job-manager/domain/models"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockTouchPointSync is a mock of TouchPointSync interface.
+type MockTouchPointSync struct {
+	ctrl     *gomock.Controller
+	recorder *MockTouchPointSyncMockRecorder
+}
+
+// MockTouchPointSyncMockRecorder is the mock recorder for MockTouchPointSync.
+type MockTouchPointSyncMockRecorder struct {
+	mock *MockTouchPointSync
+}
+
+// NewMockTouchPointSync creates a new mock instance.
+func NewMockTouchPointSync(ctrl *gomock.Controller) *MockTouchPointSync {
+	mock := &MockTouchPointSync{ctrl: ctrl}
+	mock.recorder = &MockTouchPointSyncMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockTouchPointSync) EXPECT() *MockTouchPointSyncMockRecorder {
+	return m.recorder
+}
+
+// Run mocks base method.
+func (m *MockTouchPointSync) Run(ctx context.Context) (*models.TouchPointSyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Run", ctx)
+	ret0, _ := ret[0].(*models.TouchPointSyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Run indicates an expected call of Run.
+func (mr *MockTouchPointSyncMockRecorder) Run(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Run", reflect.TypeOf((*MockTouchPointSync)(nil).Run), ctx)
+}
+
+// SyncOrganization mocks base method.
+func (m *MockTouchPointSync) SyncOrganization(ctx context.Context, orgCode string) (*models.TouchPointSyncResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SyncOrganization", ctx, orgCode)
+	ret0, _ := ret[0].(*models.TouchPointSyncResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SyncOrganization indicates an expected call of SyncOrganization.
+func (mr *MockTouchPointSyncMockRecorder) SyncOrganization(ctx, orgCode interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SyncOrganization", reflect.TypeOf((*MockTouchPointSync)(nil).SyncOrganization), ctx, orgCode)
+}
@@ -0,0 +1,50 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: upload_history.go
+
+// Package mock_usecase is a generated GoMock package.
+package mock_usecase
+
+import (
+	context "context"
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+)
+
+// MockUploadHistory is a mock of UploadHistory interface.
+type MockUploadHistory struct {
+	ctrl     *gomock.Controller
+	recorder *MockUploadHistoryMockRecorder
+}
+
+// MockUploadHistoryMockRecorder is the mock recorder for MockUploadHistory.
+type MockUploadHistoryMockRecorder struct {
+	mock *MockUploadHistory
+}
+
+// NewMockUploadHistory creates a new mock instance.
+func NewMockUploadHistory(ctrl *gomock.Controller) *MockUploadHistory {
+	mock := &MockUploadHistory{ctrl: ctrl}
+	mock.recorder = &MockUploadHistoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockUploadHistory) EXPECT() *MockUploadHistoryMockRecorder {
+	return m.recorder
+}
+
+// ProcessPending mocks base method.
+func (m *MockUploadHistory) ProcessPending(ctx context.Context) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ProcessPending", ctx)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ProcessPending indicates an expected call of ProcessPending.
+func (mr *MockUploadHistoryMockRecorder) ProcessPending(ctx interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ProcessPending", reflect.TypeOf((*MockUploadHistory)(nil).ProcessPending), ctx)
+}
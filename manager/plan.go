@@ -0,0 +1,51 @@
+package main
+
+import (
+	"context"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/infra"
+	"touchgift-job-manager/injector"
+
+	"github.com/urfave/cli"
+)
+
+// planCommand 配信開始/終了処理で登録・削除する配信データと配信制御イベントをJSONで出力する (実行はしない)
+// ex) ./manager plan --campaign 1 --action start
+func planCommand(logger *infra.Logger) cli.Command {
+	return cli.Command{
+		Name:  "plan",
+		Usage: "print DynamoDB items and SNS messages that delivery start/end would produce without applying them",
+		Flags: []cli.Flag{
+			cli.IntFlag{
+				Name:  "campaign, c",
+				Usage: "campaign id (required)",
+			},
+			cli.StringFlag{
+				Name:  "action, a",
+				Usage: "start or end",
+				Value: codes.PlanActionStart,
+			},
+		},
+		Action: func(c *cli.Context) error {
+			campaignID := c.Int("campaign")
+			if campaignID == 0 {
+				return cli.NewExitError("--campaign is required", 1)
+			}
+			action := c.String("action")
+			if action != codes.PlanActionStart && action != codes.PlanActionEnd {
+				return cli.NewExitError("--action must be start or end", 1)
+			}
+
+			ctx, cancel := SignalContext(context.Background(), logger)
+			defer cancel()
+			plan, err := injector.InjectAdminCampaignUsecase(logger).Plan(ctx, campaignID, action)
+			if err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			if err := printJSON(plan); err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			return nil
+		},
+	}
+}
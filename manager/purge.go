@@ -0,0 +1,40 @@
+package main
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/infra"
+	"touchgift-job-manager/injector"
+
+	"github.com/urfave/cli"
+)
+
+// purgeCreativesCommand どのキャンペーンにも紐付かないクリエイティブ配信データを有効期限(TTL)で削除する
+// ex) ./manager purge-creatives --dry-run
+func purgeCreativesCommand(logger *infra.Logger) cli.Command {
+	return cli.Command{
+		Name:  "purge-creatives",
+		Usage: "expire delivery creatives that no campaign refers to",
+		Flags: []cli.Flag{
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "only print creatives to purge",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			ctx, cancel := SignalContext(context.Background(), logger)
+			defer cancel()
+			result, err := injector.InjectMaintenanceUsecase(logger).PurgeOrphanCreatives(ctx, time.Now(), c.Bool("dry-run"))
+			if err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			if err := printJSON(result); err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			if result.Failed > 0 {
+				return cli.NewExitError("some creatives failed to purge", 1)
+			}
+			return nil
+		},
+	}
+}
@@ -54,6 +54,7 @@ CREATE TABLE `campaign` (
   `start_at` timestamp NOT NULL COMMENT '開始日時',
   `end_at` timestamp NULL DEFAULT NULL COMMENT '終了日時',
   `daily_coupon_limit_per_user` int DEFAULT NULL COMMENT '同一ユーザーへのクーポン配信上限数 / 日',
+  `daily_coupon_limit_per_store` int DEFAULT NULL COMMENT '同一店舗でのクーポン配信上限数 / 日',
   `store_group_id` int NOT NULL COMMENT '店舗グループID',
   `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'レコードが作成された日時',
   `updated_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT 'レコードが更新された日時',
@@ -76,6 +77,7 @@ CREATE TABLE `campaign_coupon` (
   `campaign_id` int NOT NULL,
   `coupon_id` int NOT NULL,
   `delivery_rate` int NOT NULL COMMENT '配信割合',
+  `total_limit` int DEFAULT NULL COMMENT 'クーポン配信上限数 (合計)',
   PRIMARY KEY (`id`),
   UNIQUE KEY `IDX_38f54ac1d553e6e8e6944220b3` (`campaign_id`,`coupon_id`),
   KEY `FK_6b22c97dbe4c270565c1bee6900` (`coupon_id`),
@@ -105,6 +107,25 @@ CREATE TABLE `campaign_creative` (
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
 /*!40101 SET character_set_client = @saved_cs_client */;
 
+--
+-- Table structure for table `campaign_daypart`
+--
+
+DROP TABLE IF EXISTS `campaign_daypart`;
+/*!40101 SET @saved_cs_client     = @@character_set_client */;
+/*!50503 SET character_set_client = utf8mb4 */;
+CREATE TABLE `campaign_daypart` (
+  `id` int NOT NULL AUTO_INCREMENT,
+  `campaign_id` int NOT NULL,
+  `day_of_week` tinyint DEFAULT NULL COMMENT '曜日 (0:日曜日 〜 6:土曜日)。NULLの場合は毎日',
+  `start_time` time NOT NULL COMMENT '配信開始時刻',
+  `end_time` time NOT NULL COMMENT '配信終了時刻。開始時刻以前の場合は翌日の時刻',
+  PRIMARY KEY (`id`),
+  KEY `IDX_campaign_daypart_campaign_id` (`campaign_id`),
+  CONSTRAINT `FK_campaign_daypart_campaign_id` FOREIGN KEY (`campaign_id`) REFERENCES `campaign` (`id`) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+/*!40101 SET character_set_client = @saved_cs_client */;
+
 --
 -- Table structure for table `campaign_gimmick`
 --
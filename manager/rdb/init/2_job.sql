@@ -0,0 +1,100 @@
+-- touchgift-job-manager が使用するテーブル
+
+--
+-- Table structure for table `job_reservation`
+--
+
+DROP TABLE IF EXISTS `job_reservation`;
+CREATE TABLE `job_reservation` (
+  `campaign_id` int NOT NULL COMMENT 'キャンペーンID',
+  `action` varchar(16) NOT NULL COMMENT '処理種別。start, end',
+  `fire_at` timestamp(3) NOT NULL COMMENT '実行予定日時',
+  `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'レコードが作成された日時',
+  PRIMARY KEY (`campaign_id`,`action`),
+  KEY `IDX_job_reservation_fire_at` (`fire_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+--
+-- Table structure for table `job_outbox`
+--
+
+DROP TABLE IF EXISTS `job_outbox`;
+CREATE TABLE `job_outbox` (
+  `id` bigint NOT NULL AUTO_INCREMENT,
+  `event_type` varchar(16) NOT NULL COMMENT 'イベント種別。campaign, creative, delivery',
+  `topic_arn` varchar(256) NOT NULL COMMENT '送信先のSNSトピック',
+  `message` mediumtext NOT NULL COMMENT 'メッセージ本文(JSON)。まとめたキャッシュイベントは64KBを超えるためmediumtextにする',
+  `attributes` text NOT NULL COMMENT 'MessageAttributes(JSON)',
+  `attempts` int NOT NULL DEFAULT '0' COMMENT '送信に失敗した回数',
+  `next_attempt_at` timestamp(3) NOT NULL DEFAULT CURRENT_TIMESTAMP(3) COMMENT '次回の送信日時',
+  `locked_until` timestamp(3) NULL DEFAULT NULL COMMENT '送信中のタスクが他のタスクに送信させない期限',
+  `last_error` text COMMENT '最後に送信に失敗した理由',
+  `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'レコードが作成された日時',
+  `delivered_at` timestamp(6) NULL DEFAULT NULL COMMENT '送信した日時',
+  `failed_at` timestamp(6) NULL DEFAULT NULL COMMENT '最大送信回数を超えて送信しないことにした日時',
+  `held_campaign_id` int DEFAULT NULL COMMENT '配信開始まで送信を保留するキャンペーンのID。配信開始前に作成した配信データのイベント',
+  PRIMARY KEY (`id`),
+  KEY `IDX_job_outbox_pending` (`delivered_at`,`failed_at`,`id`),
+  KEY `IDX_job_outbox_created_at` (`delivered_at`,`created_at`),
+  KEY `IDX_job_outbox_held_campaign_id` (`held_campaign_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+--
+-- Table structure for table `job_message_dedup`
+--
+
+DROP TABLE IF EXISTS `job_message_dedup`;
+CREATE TABLE `job_message_dedup` (
+  `dedup_key` varchar(255) NOT NULL COMMENT 'SNSのMessageIdとrequest_idから作成したキー',
+  `status` varchar(16) NOT NULL COMMENT '処理状態。in_progress, done',
+  `expires_at` timestamp(3) NOT NULL COMMENT 'この日時を過ぎると重複とみなさない',
+  `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'レコードが作成された日時',
+  PRIMARY KEY (`dedup_key`),
+  KEY `IDX_job_message_dedup_expires_at` (`expires_at`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+--
+-- Table structure for table `job_upload_process`
+--
+
+DROP TABLE IF EXISTS `job_upload_process`;
+CREATE TABLE `job_upload_process` (
+  `kind` varchar(16) NOT NULL COMMENT 'アップロード種別。store, touch_point',
+  `history_id` int NOT NULL COMMENT 'store_update_history/touch_point_update_historyのID',
+  `xid` varchar(32) NOT NULL COMMENT 'S3アップロード用識別子',
+  `organization_code` varchar(255) NOT NULL COMMENT '組織コード',
+  `status` varchar(16) NOT NULL COMMENT '反映状態。in_progress, done, failed',
+  `store_groups` int NOT NULL DEFAULT '0' COMMENT '反映した配信中の店舗グループ数',
+  `put_count` int NOT NULL DEFAULT '0' COMMENT '登録/更新したタッチポイント数',
+  `deleted_count` int NOT NULL DEFAULT '0' COMMENT '削除したタッチポイント数',
+  `attempts` int NOT NULL DEFAULT '0' COMMENT '反映を試みた回数',
+  `last_error` text COMMENT '最後に反映に失敗した理由',
+  `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'レコードが作成された日時',
+  `updated_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) ON UPDATE CURRENT_TIMESTAMP(6) COMMENT 'レコードが更新された日時',
+  PRIMARY KEY (`kind`,`history_id`),
+  KEY `IDX_job_upload_process_xid` (`xid`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+--
+-- Table structure for table `job_daypart_pause`
+--
+
+DROP TABLE IF EXISTS `job_daypart_pause`;
+CREATE TABLE `job_daypart_pause` (
+  `campaign_id` int NOT NULL COMMENT '配信時間帯外のため一時停止したキャンペーンID',
+  `paused_at` timestamp(3) NOT NULL COMMENT '一時停止した日時',
+  `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'レコードが作成された日時',
+  PRIMARY KEY (`campaign_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
+
+--
+-- Table structure for table `job_campaign_prewarm`
+--
+
+DROP TABLE IF EXISTS `job_campaign_prewarm`;
+CREATE TABLE `job_campaign_prewarm` (
+  `campaign_id` int NOT NULL COMMENT '配信開始前に配信データ(キャンペーン以外)を作成したキャンペーンID',
+  `prewarmed_at` timestamp(3) NOT NULL COMMENT '配信データを作成した日時',
+  `created_at` timestamp(6) NOT NULL DEFAULT CURRENT_TIMESTAMP(6) COMMENT 'レコードが作成された日時',
+  PRIMARY KEY (`campaign_id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"os"
+	"touchgift-job-manager/infra"
+	"touchgift-job-manager/injector"
+
+	"github.com/urfave/cli"
+)
+
+// replayCommand DeadLetterのファイルに保存したメッセージを再処理する
+// ex) ./manager replay --file dead_letter.ndjson --dry-run
+func replayCommand(logger *infra.Logger) cli.Command {
+	return cli.Command{
+		Name:  "replay",
+		Usage: "re-process delivery operation messages saved as dead letters",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file, f",
+				Usage: "dead letter file (NDJSON) to replay (required)",
+			},
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "only print messages to replay",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			path := c.String("file")
+			if path == "" {
+				return cli.NewExitError("--file is required", 1)
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			defer file.Close()
+
+			ctx, cancel := SignalContext(context.Background(), logger)
+			defer cancel()
+			result, err := injector.InjectReplayController(logger).Run(ctx, file, c.Bool("dry-run"))
+			if result != nil {
+				logger.Info().Interface("result", result).Msg("Replay finished")
+			}
+			if err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			if result.Failed > 0 {
+				return cli.NewExitError("some messages failed to replay", 1)
+			}
+			return nil
+		},
+	}
+}
+
+// replayLogCommand DeliveryOperationLogのJSONファイルを再処理する
+// ex) ./manager replay-log --file delivery_operation_log.json --dry-run
+func replayLogCommand(logger *infra.Logger) cli.Command {
+	return cli.Command{
+		Name:  "replay-log",
+		Usage: "re-process delivery operation logs saved as JSON",
+		Flags: []cli.Flag{
+			cli.StringFlag{
+				Name:  "file, f",
+				Usage: "delivery operation log file (JSON, NDJSON or JSON array) to replay (required)",
+			},
+			cli.BoolFlag{
+				Name:  "dry-run",
+				Usage: "only print logs to replay",
+			},
+		},
+		Action: func(c *cli.Context) error {
+			path := c.String("file")
+			if path == "" {
+				return cli.NewExitError("--file is required", 1)
+			}
+			file, err := os.Open(path)
+			if err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			defer file.Close()
+
+			ctx, cancel := SignalContext(context.Background(), logger)
+			defer cancel()
+			result, err := injector.InjectReplayController(logger).RunLogs(ctx, file, c.Bool("dry-run"))
+			if result != nil {
+				logger.Info().Interface("result", result).Msg("Replay finished")
+			}
+			if err != nil {
+				return cli.NewExitError(err.Error(), 1)
+			}
+			if result.Failed > 0 {
+				return cli.NewExitError("some logs failed to replay", 1)
+			}
+			return nil
+		},
+	}
+}
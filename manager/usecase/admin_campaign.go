@@ -0,0 +1,270 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/pkg/errors"
+)
+
+// AdminCampaign 管理APIからのキャンペーン配信状態の確認・操作
+type AdminCampaign interface {
+	// Inspect RDBのキャンペーンとDynamoDBの配信データを取得する (キャンペーンがない場合 codes.ErrNoData)
+	Inspect(ctx context.Context, campaignID int) (*models.DeliveryInspection, error)
+	// InspectGroup 店舗グループのタッチポイントとDynamoDBの配信データを取得する
+	InspectGroup(ctx context.Context, groupID int) (*models.GroupInspection, error)
+	// Start 配信開始処理を実行する(即時) (キャンペーンがない場合 codes.ErrNoData, warmup以外の場合 codes.ErrInvalidStatus)
+	Start(ctx context.Context, campaignID int) error
+	// End 配信終了処理を実行する(即時) (キャンペーンがない場合 codes.ErrNoData, terminate以外の場合 codes.ErrInvalidStatus)
+	End(ctx context.Context, campaignID int) error
+	// Sync RDBの状態で配信データを同期し直す
+	Sync(ctx context.Context, campaignID int) error
+	// Plan 配信開始/終了処理で登録・削除する配信データと発行する配信制御イベントを返す (キャンペーンがない場合 codes.ErrNoData)
+	Plan(ctx context.Context, campaignID int, action string) (*models.DeliveryPlan, error)
+}
+
+type adminCampaign struct {
+	logger                   Logger
+	transaction              repository.TransactionHandler
+	campaignRepository       repository.CampaignRepository
+	touchPointRepository     repository.TouchPointRepository
+	campaignDataRepository   repository.DeliveryDataCampaignRepository
+	contentDataRepository    repository.DeliveryDataContentRepository
+	creativeDataRepository   repository.DeliveryDataCreativeRepository
+	touchPointDataRepository repository.DeliveryDataTouchPointRepository
+	deliveryStart            DeliveryStart
+	deliveryEnd              DeliveryEnd
+	deliveryOperation        DeliveryOperation
+}
+
+// NewAdminCampaign is function
+func NewAdminCampaign(
+	logger Logger,
+	transaction repository.TransactionHandler,
+	campaignRepository repository.CampaignRepository,
+	touchPointRepository repository.TouchPointRepository,
+	campaignDataRepository repository.DeliveryDataCampaignRepository,
+	contentDataRepository repository.DeliveryDataContentRepository,
+	creativeDataRepository repository.DeliveryDataCreativeRepository,
+	touchPointDataRepository repository.DeliveryDataTouchPointRepository,
+	deliveryStart DeliveryStart,
+	deliveryEnd DeliveryEnd,
+	deliveryOperation DeliveryOperation,
+) AdminCampaign {
+	return &adminCampaign{
+		logger:                   logger,
+		transaction:              transaction,
+		campaignRepository:       campaignRepository,
+		touchPointRepository:     touchPointRepository,
+		campaignDataRepository:   campaignDataRepository,
+		contentDataRepository:    contentDataRepository,
+		creativeDataRepository:   creativeDataRepository,
+		touchPointDataRepository: touchPointDataRepository,
+		deliveryStart:            deliveryStart,
+		deliveryEnd:              deliveryEnd,
+		deliveryOperation:        deliveryOperation,
+	}
+}
+
+func (a *adminCampaign) Inspect(ctx context.Context, campaignID int) (*models.DeliveryInspection, error) {
+	campaign, cc, err := a.getCampaign(ctx, campaignID)
+	if err != nil {
+		return nil, err
+	}
+	touchPoints, err := a.touchPointRepository.GetTouchPointByGroupID(ctx, &repository.TouchPointByGroupIDCondition{
+		GroupID: campaign.GroupID,
+		Limit:   1000000,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get touch points")
+	}
+
+	id := strconv.Itoa(campaign.ID)
+	inspection := models.DeliveryInspection{
+		Campaign:           campaign,
+		DeliveryCreatives:  []*models.DeliveryDataCreative{},
+		MissingCreativeIDs: []int{},
+	}
+	inspection.DeliveryCampaign, err = a.campaignDataRepository.Get(ctx, &id)
+	if err != nil && err != codes.ErrNoData {
+		return nil, errors.Wrap(err, "Failed to get delivery campaign")
+	}
+	inspection.DeliveryContent, err = a.contentDataRepository.Get(ctx, &id)
+	if err != nil && err != codes.ErrNoData {
+		return nil, errors.Wrap(err, "Failed to get delivery content")
+	}
+	inspection.DeliveryTouchPoints, inspection.MissingTouchPointIDs, err = a.getDeliveryTouchPoints(ctx, touchPoints)
+	if err != nil {
+		return nil, err
+	}
+	// 審査OKでないクリエイティブは配信データに含めない
+	approved := models.ApprovedCampaignCreatives(cc)
+	creativeIDs := make([]string, 0, len(approved))
+	for _, creative := range approved {
+		creativeIDs = append(creativeIDs, strconv.Itoa(creative.ID))
+	}
+	deliveryCreatives, err := a.creativeDataRepository.BatchGet(ctx, creativeIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get delivery creatives")
+	}
+	deliveryCreativeMap := make(map[string]*models.DeliveryDataCreative, len(deliveryCreatives))
+	for _, deliveryCreative := range deliveryCreatives {
+		deliveryCreativeMap[deliveryCreative.ID] = deliveryCreative
+	}
+	for _, creative := range approved {
+		deliveryCreative, ok := deliveryCreativeMap[strconv.Itoa(creative.ID)]
+		if !ok {
+			inspection.MissingCreativeIDs = append(inspection.MissingCreativeIDs, creative.ID)
+			continue
+		}
+		inspection.DeliveryCreatives = append(inspection.DeliveryCreatives, deliveryCreative)
+	}
+	return &inspection, nil
+}
+
+// RDBからキャンペーンとクリエイティブを取得する
+// DynamoDBの取得中にトランザクションを保持しないよう、参照が終わったらすぐにロールバックする
+func (a *adminCampaign) getCampaign(ctx context.Context, campaignID int) (*models.Campaign, []*models.CampaignCreative, error) {
+	tx, err := a.transaction.Begin(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to begin transaction")
+	}
+	// 参照のみのためロールバックする
+	defer func() {
+		if terr := tx.Rollback(); terr != nil {
+			a.logger.Error().Err(terr).Int("campaign_id", campaignID).Msg("Failed to rollback")
+		}
+	}()
+	condition := repository.CampaignCondition{
+		CampaignID: campaignID,
+	}
+	campaign, err := a.campaignRepository.GetDeliveryToStart(ctx, tx, &condition)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get campaign")
+	}
+	if campaign == nil {
+		return nil, nil, codes.ErrNoData
+	}
+	cc, err := a.campaignRepository.GetCampaignCreative(ctx, tx, &condition)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get campaign creative")
+	}
+	return campaign, cc, nil
+}
+
+// タッチポイントの配信データをまとめて取得し、配信データがないタッチポイントIDを返す
+func (a *adminCampaign) getDeliveryTouchPoints(ctx context.Context, touchPoints []*models.TouchPoint) ([]*models.DeliveryTouchPoint, []string, error) {
+	// 同じタッチポイントが複数のキャンペーン経由で取得されることがあるため重複を除く
+	keys := make([]models.DeliveryTouchPoint, 0, len(touchPoints))
+	unique := make(map[string]bool, len(touchPoints))
+	for _, touchPoint := range touchPoints {
+		if unique[touchPoint.ID] {
+			continue
+		}
+		unique[touchPoint.ID] = true
+		keys = append(keys, models.DeliveryTouchPoint{ID: touchPoint.ID, GroupID: touchPoint.GroupID})
+	}
+	deliveryTouchPoints, err := a.touchPointDataRepository.BatchGet(ctx, &keys)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to get delivery touch points")
+	}
+	exists := make(map[string]bool, len(deliveryTouchPoints))
+	for _, deliveryTouchPoint := range deliveryTouchPoints {
+		exists[deliveryTouchPoint.ID] = true
+	}
+	missing := []string{}
+	for _, key := range keys {
+		if !exists[key.ID] {
+			missing = append(missing, key.ID)
+		}
+	}
+	return deliveryTouchPoints, missing, nil
+}
+
+func (a *adminCampaign) InspectGroup(ctx context.Context, groupID int) (*models.GroupInspection, error) {
+	touchPoints, err := a.touchPointRepository.GetTouchPointByGroupID(ctx, &repository.TouchPointByGroupIDCondition{
+		GroupID: groupID,
+		Limit:   1000000,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get touch points")
+	}
+	inspection := models.GroupInspection{
+		GroupID: groupID,
+	}
+	inspection.DeliveryTouchPoints, inspection.MissingTouchPointIDs, err = a.getDeliveryTouchPoints(ctx, touchPoints)
+	if err != nil {
+		return nil, err
+	}
+	return &inspection, nil
+}
+
+// 開始・終了処理はリーダー以外のインスタンスでも実行する
+// 処理の中でキャンペーンの行をロックしてステータスを確認し直すため、リーダーの開始・終了処理と重複しても片方のみ処理される
+func (a *adminCampaign) Start(ctx context.Context, campaignID int) error {
+	// 開始処理はwarmupのキャンペーンのみ処理されるため、受け付ける前に確認する
+	if err := a.checkStatus(ctx, campaignID, codes.StatusWarmup); err != nil {
+		return err
+	}
+	a.deliveryStart.ExecuteNow(&models.Campaign{ID: campaignID})
+	return nil
+}
+
+func (a *adminCampaign) End(ctx context.Context, campaignID int) error {
+	// 終了処理はterminateのキャンペーンのみ処理されるため、受け付ける前に確認する
+	if err := a.checkStatus(ctx, campaignID, codes.StatusTerminate); err != nil {
+		return err
+	}
+	a.deliveryEnd.ExecuteNow(&models.Campaign{ID: campaignID})
+	return nil
+}
+
+// キャンペーンのステータスが指定したステータスか確認する
+func (a *adminCampaign) checkStatus(ctx context.Context, campaignID int, status string) error {
+	tx, err := a.transaction.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed to begin transaction")
+	}
+	// 参照のみのためロールバックする
+	defer func() {
+		if terr := tx.Rollback(); terr != nil {
+			a.logger.Error().Err(terr).Int("campaign_id", campaignID).Msg("Failed to rollback")
+		}
+	}()
+	campaign, err := a.campaignRepository.GetDeliveryToStart(ctx, tx, &repository.CampaignCondition{CampaignID: campaignID})
+	if err != nil {
+		return errors.Wrap(err, "Failed to get campaign")
+	}
+	if campaign == nil {
+		return codes.ErrNoData
+	}
+	if campaign.Status != status {
+		return errors.Wrapf(codes.ErrInvalidStatus, "status: %s", campaign.Status)
+	}
+	return nil
+}
+
+func (a *adminCampaign) Sync(ctx context.Context, campaignID int) error {
+	campaignLog := models.CampaignLog{
+		ID:        campaignID,
+		Event:     "update",
+		Creatives: []models.CreativeLog{},
+	}
+	return a.deliveryOperation.Process(ctx, time.Now(), &campaignLog)
+}
+
+func (a *adminCampaign) Plan(ctx context.Context, campaignID int, action string) (*models.DeliveryPlan, error) {
+	switch action {
+	case codes.PlanActionStart:
+		return a.deliveryStart.Plan(ctx, campaignID)
+	case codes.PlanActionEnd:
+		return a.deliveryEnd.Plan(ctx, campaignID)
+	default:
+		return nil, errors.Errorf("Unknown plan action: %s", action)
+	}
+}
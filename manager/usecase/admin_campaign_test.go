@@ -0,0 +1,280 @@
+package usecase
+
+import (
+	"context"
+	"testing"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdminCampaign_Inspect(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("キャンペーンがない場合はErrNoDataを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryOperation := mock_usecase.NewMockDeliveryOperation(ctrl)
+
+		ctx := context.Background()
+		condition := repository.CampaignCondition{CampaignID: 1}
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(nil, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		adminCampaign := NewAdminCampaign(logger, transactionHandler, campaignRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryStart, deliveryEnd, deliveryOperation)
+		actual, err := adminCampaign.Inspect(ctx, 1)
+		assert.Nil(t, actual)
+		assert.Equal(t, codes.ErrNoData, err)
+	})
+
+	t.Run("RDBとDynamoDBのデータを返し、DynamoDBにないデータを列挙する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryOperation := mock_usecase.NewMockDeliveryOperation(ctrl)
+
+		ctx := context.Background()
+		condition := repository.CampaignCondition{CampaignID: 1}
+		campaign := &models.Campaign{ID: 1, GroupID: 10, OrgCode: "org", Status: "started"}
+		deliveryCampaign := &models.DeliveryDataCampaign{ID: "1", GroupID: "10", OrgCode: "org", Status: "started"}
+		touchPoints := []*models.TouchPoint{{GroupID: 10, StoreID: "s1", ID: "tp1"}, {GroupID: 10, StoreID: "s2", ID: "tp2"}}
+		deliveryTouchPoint := &models.DeliveryTouchPoint{GroupID: 10, StoreID: "s1", ID: "tp1"}
+		cc := []*models.CampaignCreative{{ID: 100, Rate: 50, Status: codes.ReviewStatusApproved}, {ID: 200, Rate: 50, Status: codes.ReviewStatusApproved}}
+		deliveryCreative := &models.DeliveryDataCreative{ID: "200", URL: "https://example.com/200.png"}
+		id := "1"
+		touchPointKeys := []models.DeliveryTouchPoint{{GroupID: 10, ID: "tp1"}, {GroupID: 10, ID: "tp2"}}
+
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(cc, nil),
+			// DynamoDBの取得前にトランザクションを終える
+			tx.EXPECT().Rollback().Return(nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return(touchPoints, nil),
+			campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryCampaign, nil),
+			contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil, codes.ErrNoData),
+			touchPointDataRepository.EXPECT().BatchGet(gomock.Eq(ctx), gomock.Eq(&touchPointKeys)).
+				Return([]*models.DeliveryTouchPoint{deliveryTouchPoint}, nil),
+			creativeDataRepository.EXPECT().BatchGet(gomock.Eq(ctx), gomock.Eq([]string{"100", "200"})).
+				Return([]*models.DeliveryDataCreative{deliveryCreative}, nil),
+		)
+
+		// テストを実行する
+		adminCampaign := NewAdminCampaign(logger, transactionHandler, campaignRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryStart, deliveryEnd, deliveryOperation)
+		actual, err := adminCampaign.Inspect(ctx, 1)
+		if assert.NoError(t, err) {
+			assert.Equal(t, campaign, actual.Campaign)
+			assert.Equal(t, deliveryCampaign, actual.DeliveryCampaign)
+			assert.Nil(t, actual.DeliveryContent)
+			assert.Equal(t, []*models.DeliveryTouchPoint{deliveryTouchPoint}, actual.DeliveryTouchPoints)
+			assert.Equal(t, []*models.DeliveryDataCreative{deliveryCreative}, actual.DeliveryCreatives)
+			assert.Equal(t, []string{"tp2"}, actual.MissingTouchPointIDs)
+			assert.Equal(t, []int{100}, actual.MissingCreativeIDs)
+		}
+	})
+
+	t.Run("店舗グループのタッチポイントの配信データを返し、DynamoDBにないタッチポイントを列挙する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryOperation := mock_usecase.NewMockDeliveryOperation(ctrl)
+
+		ctx := context.Background()
+		// 同じグループの複数キャンペーン経由で同じタッチポイントが重複して取得される
+		touchPoints := []*models.TouchPoint{
+			{GroupID: 10, StoreID: "s1", ID: "tp1"}, {GroupID: 10, StoreID: "s2", ID: "tp2"},
+			{GroupID: 10, StoreID: "s1", ID: "tp1"}, {GroupID: 10, StoreID: "s2", ID: "tp2"},
+		}
+		deliveryTouchPoint := &models.DeliveryTouchPoint{GroupID: 10, StoreID: "s1", ID: "tp1"}
+
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 10, Limit: 1000000})).
+			Return(touchPoints, nil)
+		touchPointKeys := []models.DeliveryTouchPoint{{GroupID: 10, ID: "tp1"}, {GroupID: 10, ID: "tp2"}}
+		touchPointDataRepository.EXPECT().BatchGet(gomock.Eq(ctx), gomock.Eq(&touchPointKeys)).Return([]*models.DeliveryTouchPoint{deliveryTouchPoint}, nil)
+
+		// テストを実行する
+		adminCampaign := NewAdminCampaign(logger, transactionHandler, campaignRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryStart, deliveryEnd, deliveryOperation)
+		actual, err := adminCampaign.InspectGroup(ctx, 10)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 10, actual.GroupID)
+			assert.Equal(t, []*models.DeliveryTouchPoint{deliveryTouchPoint}, actual.DeliveryTouchPoints)
+			assert.Equal(t, []string{"tp2"}, actual.MissingTouchPointIDs)
+		}
+	})
+}
+
+func TestAdminCampaign_Operation(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("開始はwarmup、終了はterminateのキャンペーンのみ即時実行する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).
+				Return(&models.Campaign{ID: 1, Status: codes.StatusWarmup}, nil),
+			tx.EXPECT().Rollback().Return(nil),
+			deliveryStart.EXPECT().ExecuteNow(gomock.Eq(&models.Campaign{ID: 1})).Times(1),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 2})).
+				Return(&models.Campaign{ID: 2, Status: codes.StatusTerminate}, nil),
+			tx.EXPECT().Rollback().Return(nil),
+			deliveryEnd.EXPECT().ExecuteNow(gomock.Eq(&models.Campaign{ID: 2})).Times(1),
+		)
+
+		// テストを実行する
+		adminCampaign := NewAdminCampaign(logger, transactionHandler, campaignRepository, nil, nil, nil, nil, nil, deliveryStart, deliveryEnd, nil)
+		assert.NoError(t, adminCampaign.Start(ctx, 1))
+		assert.NoError(t, adminCampaign.End(ctx, 2))
+	})
+
+	t.Run("キャンペーンがない場合はErrNoData、処理対象外のステータスの場合はErrInvalidStatusを返して実行しない", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(nil, nil),
+			tx.EXPECT().Rollback().Return(nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).
+				Return(&models.Campaign{ID: 2, Status: codes.StatusStarted}, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		adminCampaign := NewAdminCampaign(logger, transactionHandler, campaignRepository, nil, nil, nil, nil, nil, deliveryStart, deliveryEnd, nil)
+		assert.Equal(t, codes.ErrNoData, adminCampaign.Start(ctx, 1))
+		err := adminCampaign.End(ctx, 2)
+		assert.ErrorIs(t, err, codes.ErrInvalidStatus)
+	})
+
+	t.Run("同期はキャンペーンのupdateログとして処理する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		deliveryOperation := mock_usecase.NewMockDeliveryOperation(ctrl)
+		adminCampaign := NewAdminCampaign(logger, nil, nil, nil, nil, nil, nil, nil, nil, nil, deliveryOperation)
+
+		ctx := context.Background()
+		deliveryOperation.EXPECT().Process(gomock.Eq(ctx), gomock.Any(), gomock.Eq(&models.CampaignLog{ID: 1, Event: "update", Creatives: []models.CreativeLog{}})).
+			DoAndReturn(func(ctx context.Context, current time.Time, campaignLog *models.CampaignLog) error {
+				assert.WithinDuration(t, time.Now(), current, time.Second)
+				return codes.ErrDoNothing
+			}).Times(1)
+		err := adminCampaign.Sync(ctx, 1)
+		assert.Equal(t, codes.ErrDoNothing, err)
+	})
+}
+
+func TestAdminCampaign_Plan(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("開始・終了の処理内容を配信開始・終了処理から取得する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		adminCampaign := NewAdminCampaign(logger, nil, nil, nil, nil, nil, nil, nil, deliveryStart, deliveryEnd, nil)
+
+		ctx := context.Background()
+		startPlan := &models.DeliveryPlan{Action: codes.PlanActionStart, CampaignID: 1}
+		endPlan := &models.DeliveryPlan{Action: codes.PlanActionEnd, CampaignID: 2}
+		deliveryStart.EXPECT().Plan(gomock.Eq(ctx), gomock.Eq(1)).Return(startPlan, nil)
+		deliveryEnd.EXPECT().Plan(gomock.Eq(ctx), gomock.Eq(2)).Return(endPlan, nil)
+		plan, err := adminCampaign.Plan(ctx, 1, codes.PlanActionStart)
+		assert.NoError(t, err)
+		assert.Equal(t, startPlan, plan)
+		plan, err = adminCampaign.Plan(ctx, 2, codes.PlanActionEnd)
+		assert.NoError(t, err)
+		assert.Equal(t, endPlan, plan)
+	})
+
+	t.Run("不明な処理の場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		adminCampaign := NewAdminCampaign(logger, nil, nil, nil, nil, nil, nil, nil,
+			mock_usecase.NewMockDeliveryStart(ctrl), mock_usecase.NewMockDeliveryEnd(ctrl), nil)
+		_, err := adminCampaign.Plan(context.Background(), 1, "pause")
+		assert.Error(t, err)
+	})
+}
@@ -15,6 +15,8 @@ type Creative interface {
 	Process(ctx context.Context, tx repository.Transaction, current time.Time, creativeLogs *[]models.CreativeLog) error
 	// クリエイティブを登録/更新する
 	Put(ctx context.Context, creatives *[]models.DeliveryDataCreative) error
+	// 配信対象外になったクリエイティブの有効期限(TTL)を1日後に更新する
+	Expire(ctx context.Context, current time.Time, creativeID int) error
 }
 
 type creative struct {
@@ -54,8 +56,7 @@ func (c *creative) Process(ctx context.Context, tx repository.Transaction, curre
 			if len(creatives) == 0 {
 				c.logger.Info().Time("current", current).Str("org_code", creativeLog.OrgCode).Int("creative_id", creativeLog.ID).Msg("Delete (change ttl)")
 				// どのキャンペーンにも紐付かないデータの場合、有効期限(TTL)を1日後に更新
-				ttl := time.Now().Add(24 * time.Hour).Truncate(time.Millisecond)
-				if err := c.updateTTL(ctx, ttl, &creativeLog); err != nil {
+				if err := c.Expire(ctx, time.Now(), creativeLog.ID); err != nil {
 					return err
 				}
 			}
@@ -68,6 +69,11 @@ func (c *creative) Process(ctx context.Context, tx repository.Transaction, curre
 	return nil
 }
 
+func (c *creative) Expire(ctx context.Context, current time.Time, creativeID int) error {
+	ttl := current.Add(24 * time.Hour).Truncate(time.Millisecond)
+	return c.updateTTL(ctx, ttl, &models.CreativeLog{ID: creativeID})
+}
+
 // TTLを更新する
 func (c *creative) updateTTL(ctx context.Context, ttl time.Time, creativeLog *models.CreativeLog) error {
 	creativeID := strconv.Itoa(creativeLog.ID)
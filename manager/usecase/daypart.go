@@ -0,0 +1,208 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	metricDaypartFlipTotal     = "daypart_flip_total"
+	metricDaypartFlipTotalDesc = "campaign pause/resume count by daypart schedule"
+	// action: pause, resume
+	metricDaypartFlipTotalLabels = []string{"action", "result"}
+)
+
+// Daypart 配信時間帯(dayparting)に合わせてキャンペーンを一時停止/再開する
+type Daypart interface {
+	// Run 配信時間帯外になった配信中キャンペーンを一時停止し、配信時間帯になったキャンペーンを再開する
+	Run(ctx context.Context, current time.Time) (*models.DaypartResult, error)
+}
+
+type daypart struct {
+	logger               Logger
+	monitor              *metrics.Monitor
+	transaction          repository.TransactionHandler
+	campaignRepository   repository.CampaignRepository
+	daypartRepository    repository.DaypartRepository
+	deliveryStart        DeliveryStart
+	deliveryEnd          DeliveryEnd
+	deliveryControlEvent DeliveryControlEvent
+	timezone             Timezone
+}
+
+// NewDaypart is function
+func NewDaypart(
+	logger Logger,
+	monitor *metrics.Monitor,
+	transaction repository.TransactionHandler,
+	campaignRepository repository.CampaignRepository,
+	daypartRepository repository.DaypartRepository,
+	deliveryStart DeliveryStart,
+	deliveryEnd DeliveryEnd,
+	deliveryControlEvent DeliveryControlEvent,
+	timezone Timezone,
+) Daypart {
+	monitor.Metrics.AddCounter(metricDaypartFlipTotal, metricDaypartFlipTotalDesc, metricDaypartFlipTotalLabels)
+	return &daypart{
+		logger:               logger,
+		monitor:              monitor,
+		transaction:          transaction,
+		campaignRepository:   campaignRepository,
+		daypartRepository:    daypartRepository,
+		deliveryStart:        deliveryStart,
+		deliveryEnd:          deliveryEnd,
+		deliveryControlEvent: deliveryControlEvent,
+		timezone:             timezone,
+	}
+}
+
+func (d *daypart) Run(ctx context.Context, current time.Time) (*models.DaypartResult, error) {
+	campaigns, err := d.campaignRepository.GetCampaignByStatus(ctx, &repository.CampaignByStatusCondition{
+		Status: []string{codes.StatusStarted, codes.StatusPaused},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get campaigns")
+	}
+	// 手動で一時停止したキャンペーンは再開しないため、配信時間帯外で一時停止したキャンペーンを記録している
+	pausedIDs, err := d.daypartRepository.GetPausedCampaignIDs(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get paused campaigns")
+	}
+	pausedByDaypart := make(map[int]bool, len(pausedIDs))
+	for _, campaignID := range pausedIDs {
+		pausedByDaypart[campaignID] = true
+	}
+	campaignIDs := make([]int, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		campaignIDs = append(campaignIDs, campaign.ID)
+	}
+	dayparts, err := d.daypartRepository.GetByCampaignIDs(ctx, campaignIDs)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get dayparts")
+	}
+	daypartsByCampaign := map[int][]*models.Daypart{}
+	for _, daypart := range dayparts {
+		daypartsByCampaign[daypart.CampaignID] = append(daypartsByCampaign[daypart.CampaignID], daypart)
+	}
+
+	result := models.DaypartResult{}
+	targets := make(map[int]bool, len(campaigns))
+	for _, campaign := range campaigns {
+		targets[campaign.ID] = true
+		// 配信時間帯が削除された場合も再開するため、配信時間帯がないキャンペーンは常に配信時間帯内とする
+		// 配信時間帯は組織のタイムゾーンの時刻で判定する
+		inWindow, err := models.InDayparts(daypartsByCampaign[campaign.ID], current.In(d.timezone.Location(campaign.OrgCode)))
+		if err != nil {
+			d.logger.Error().Err(err).Int("campaign_id", campaign.ID).Msg("Invalid daypart")
+			result.Failed++
+			continue
+		}
+		switch {
+		case campaign.Status == codes.StatusStarted && !inWindow:
+			err = d.flip(ctx, current, campaign, codes.StatusPause, d.pause)
+			if err == nil {
+				result.Paused++
+			}
+		case campaign.Status == codes.StatusPaused && inWindow && pausedByDaypart[campaign.ID]:
+			err = d.flip(ctx, current, campaign, codes.StatusResume, d.resume)
+			if err == nil {
+				result.Resumed++
+			}
+		case campaign.Status == codes.StatusStarted && pausedByDaypart[campaign.ID]:
+			// 配信時間帯外に手動で再開された場合等の記録は不要
+			err = d.daypartRepository.DeletePause(ctx, nil, campaign.ID)
+		}
+		if err != nil {
+			d.logger.Error().Err(err).Int("campaign_id", campaign.ID).Str("status", campaign.Status).Msg("Failed to flip campaign by daypart")
+			result.Failed++
+		}
+	}
+	// 配信停止・終了したキャンペーンの記録を削除する
+	for _, campaignID := range pausedIDs {
+		if targets[campaignID] {
+			continue
+		}
+		if err := d.daypartRepository.DeletePause(ctx, nil, campaignID); err != nil {
+			d.logger.Error().Err(err).Int("campaign_id", campaignID).Msg("Failed to delete daypart pause")
+		}
+	}
+	if result.Paused > 0 || result.Resumed > 0 || result.Failed > 0 {
+		d.logger.Info().Time("current", current).Int("paused", result.Paused).Int("resumed", result.Resumed).
+			Int("failed", result.Failed).Msg("Flip campaigns by daypart")
+	}
+	return &result, nil
+}
+
+// キャンペーンごとにトランザクション内で一時停止/再開し、配信制御イベントを登録する
+func (d *daypart) flip(ctx context.Context, current time.Time, campaign *models.Campaign, action string,
+	fn func(ctx context.Context, tx repository.Transaction, current time.Time, campaign *models.Campaign) error) (err error) {
+	var tx repository.Transaction
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+		if err != nil && tx != nil {
+			if rerr := tx.Rollback(); rerr != nil {
+				d.logger.Error().Err(rerr).Int("campaign_id", campaign.ID).Msg("Failed to rollback")
+			}
+		}
+		result := "success"
+		if err != nil {
+			result = "error"
+		}
+		d.monitor.Metrics.GetCounter(metricDaypartFlipTotal).WithLabelValues(action, result).Inc()
+	}()
+	tx, err = d.transaction.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed to begin transaction")
+	}
+	if err = fn(ctx, tx, current, campaign); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "Failed to commit")
+	}
+	d.logger.Info().Time("current", current).Str("local_time", d.timezone.Format(campaign.OrgCode, current)).
+		Int("campaign_id", campaign.ID).Str("org_code", campaign.OrgCode).Str("action", action).Msg("Flip campaign by daypart")
+	return nil
+}
+
+// 一時停止(StatusPauseと同様に配信データを削除する)
+func (d *daypart) pause(ctx context.Context, tx repository.Transaction, current time.Time, campaign *models.Campaign) error {
+	if err := d.deliveryEnd.Stop(ctx, tx, campaign, codes.StatusPaused); err != nil {
+		return errors.Wrap(err, "Failed to stop campaign")
+	}
+	if err := d.deliveryEnd.Delete(ctx, tx, campaign); err != nil {
+		return errors.Wrap(err, "Failed to delete delivery data")
+	}
+	if err := d.daypartRepository.SavePause(ctx, tx, campaign.ID, current); err != nil {
+		return errors.Wrap(err, "Failed to save daypart pause")
+	}
+	return d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode,
+		codes.StatusStarted, codes.StatusPaused, codes.DetailDaypart)
+}
+
+// 再開(StatusResumeと同様に配信データを作り直す)
+func (d *daypart) resume(ctx context.Context, tx repository.Transaction, current time.Time, campaign *models.Campaign) error {
+	if _, err := d.deliveryStart.UpdateStatus(ctx, tx, campaign, codes.StatusStarted); err != nil {
+		return err
+	}
+	// 配信データには再開後のステータスを登録する
+	resumed := *campaign
+	resumed.Status = codes.StatusStarted
+	if err := d.deliveryStart.CreateDeliveryDatas(ctx, tx, &resumed); err != nil {
+		return errors.Wrap(err, "Failed to create delivery data")
+	}
+	if err := d.daypartRepository.DeletePause(ctx, tx, campaign.ID); err != nil {
+		return errors.Wrap(err, "Failed to delete daypart pause")
+	}
+	return d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode,
+		codes.StatusResume, codes.StatusStarted, codes.DetailDaypart)
+}
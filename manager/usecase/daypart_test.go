@@ -0,0 +1,295 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDaypart_Run(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	ctx := context.Background()
+
+	statusCondition := &repository.CampaignByStatusCondition{Status: []string{codes.StatusStarted, codes.StatusPaused}}
+	// 配信時間帯は組織のタイムゾーン(JST)で判定する
+	jst, _ := time.LoadLocation("Asia/Tokyo")
+	// 2024-06-03は月曜日
+	monday := func(hour int, min int) time.Time {
+		return time.Date(2024, 6, 3, hour, min, 0, 0, jst)
+	}
+	businessHours := func(campaignID int) *models.Daypart {
+		return &models.Daypart{CampaignID: campaignID, StartTime: "09:00:00", EndTime: "18:00:00"}
+	}
+
+	t.Run("配信時間帯外になった配信中キャンペーンを一時停止する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		daypartRepository := mock_repository.NewMockDaypartRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		campaigns := []*models.Campaign{
+			{ID: 1, GroupID: 10, OrgCode: "org", Status: codes.StatusStarted},
+			// 配信時間帯がないキャンペーンは常に配信する
+			{ID: 2, GroupID: 10, OrgCode: "org", Status: codes.StatusStarted},
+			// 手動で一時停止したキャンペーンは再開しない
+			{ID: 3, GroupID: 10, OrgCode: "org", Status: codes.StatusPaused},
+		}
+		current := monday(18, 0)
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns, nil),
+			daypartRepository.EXPECT().GetPausedCampaignIDs(gomock.Eq(ctx)).Return([]int{}, nil),
+			daypartRepository.EXPECT().GetByCampaignIDs(gomock.Eq(ctx), gomock.Eq([]int{1, 2, 3})).Return(
+				[]*models.Daypart{businessHours(1), businessHours(3)}, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryEnd.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0]), gomock.Eq(codes.StatusPaused)).Return(nil),
+			deliveryEnd.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0])).Return(nil),
+			daypartRepository.EXPECT().SavePause(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(current)).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusPaused), gomock.Eq(codes.DetailDaypart)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		daypart := NewDaypart(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			daypartRepository, deliveryStart, deliveryEnd, deliveryControlEvent, NewTestTimezone(t))
+		result, err := daypart.Run(ctx, current)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.DaypartResult{Paused: 1}, result)
+	})
+
+	t.Run("配信時間帯になった一時停止中のキャンペーンを再開する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		daypartRepository := mock_repository.NewMockDaypartRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		campaign := &models.Campaign{ID: 1, GroupID: 10, OrgCode: "org", Status: codes.StatusPaused}
+		resumed := *campaign
+		resumed.Status = codes.StatusStarted
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return([]*models.Campaign{campaign}, nil),
+			// 配信終了したキャンペーン(ID: 5)の記録が残っている
+			daypartRepository.EXPECT().GetPausedCampaignIDs(gomock.Eq(ctx)).Return([]int{1, 5}, nil),
+			daypartRepository.EXPECT().GetByCampaignIDs(gomock.Eq(ctx), gomock.Eq([]int{1})).Return(
+				[]*models.Daypart{{CampaignID: 1, DayOfWeek: sql.NullInt32{Int32: int32(time.Monday), Valid: true}, StartTime: "09:00:00", EndTime: "18:00:00"}}, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryStart.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&resumed)).Return(nil),
+			daypartRepository.EXPECT().DeletePause(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1)).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusResume), gomock.Eq(codes.StatusStarted), gomock.Eq(codes.DetailDaypart)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			daypartRepository.EXPECT().DeletePause(gomock.Eq(ctx), gomock.Nil(), gomock.Eq(5)).Return(nil),
+		)
+
+		// テストを実行する
+		daypart := NewDaypart(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			daypartRepository, deliveryStart, deliveryEnd, deliveryControlEvent, NewTestTimezone(t))
+		result, err := daypart.Run(ctx, monday(9, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, &models.DaypartResult{Resumed: 1}, result)
+	})
+
+	t.Run("日付が変わる前後はJSTの曜日・時刻で判定する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		daypartRepository := mock_repository.NewMockDaypartRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		campaign := &models.Campaign{ID: 1, GroupID: 10, OrgCode: "org", Status: codes.StatusStarted}
+		// 月曜日(JST)の終日
+		dayparts := []*models.Daypart{{CampaignID: 1, DayOfWeek: sql.NullInt32{Int32: int32(time.Monday), Valid: true}, StartTime: "00:00:00", EndTime: "23:59:59"}}
+		// UTCでは日曜日の15:00 (JSTでは月曜日の0:00)
+		sundayUTC := time.Date(2024, 6, 2, 15, 0, 0, 0, time.UTC)
+		// UTCでは月曜日の15:00 (JSTでは火曜日の0:00)
+		mondayUTC := time.Date(2024, 6, 3, 15, 0, 0, 0, time.UTC)
+		gomock.InOrder(
+			// JSTの月曜日になったため配信を続ける
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return([]*models.Campaign{campaign}, nil),
+			daypartRepository.EXPECT().GetPausedCampaignIDs(gomock.Eq(ctx)).Return([]int{}, nil),
+			daypartRepository.EXPECT().GetByCampaignIDs(gomock.Eq(ctx), gomock.Eq([]int{1})).Return(dayparts, nil),
+			// JSTの火曜日になったため一時停止する
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return([]*models.Campaign{campaign}, nil),
+			daypartRepository.EXPECT().GetPausedCampaignIDs(gomock.Eq(ctx)).Return([]int{}, nil),
+			daypartRepository.EXPECT().GetByCampaignIDs(gomock.Eq(ctx), gomock.Eq([]int{1})).Return(dayparts, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryEnd.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusPaused)).Return(nil),
+			deliveryEnd.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
+			daypartRepository.EXPECT().SavePause(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(mondayUTC)).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusPaused), gomock.Eq(codes.DetailDaypart)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		daypart := NewDaypart(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			daypartRepository, deliveryStart, deliveryEnd, deliveryControlEvent, NewTestTimezone(t))
+		result, err := daypart.Run(ctx, sundayUTC)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.DaypartResult{}, result)
+		result, err = daypart.Run(ctx, mondayUTC)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.DaypartResult{Paused: 1}, result)
+	})
+
+	t.Run("再開に失敗した場合はロールバックして次のキャンペーンを処理する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		daypartRepository := mock_repository.NewMockDaypartRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		campaigns := []*models.Campaign{
+			{ID: 1, GroupID: 10, OrgCode: "org", Status: codes.StatusPaused},
+			{ID: 2, GroupID: 20, OrgCode: "org", Status: codes.StatusStarted},
+		}
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns, nil),
+			daypartRepository.EXPECT().GetPausedCampaignIDs(gomock.Eq(ctx)).Return([]int{1}, nil),
+			daypartRepository.EXPECT().GetByCampaignIDs(gomock.Eq(ctx), gomock.Eq([]int{1, 2})).Return(
+				[]*models.Daypart{businessHours(1), businessHours(2)}, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryStart.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0]), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(errors.New("error")),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		daypart := NewDaypart(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			daypartRepository, deliveryStart, deliveryEnd, deliveryControlEvent, NewTestTimezone(t))
+		result, err := daypart.Run(ctx, monday(12, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, &models.DaypartResult{Failed: 1}, result)
+	})
+
+	t.Run("配信時間帯が不正な場合は切り替えない", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		daypartRepository := mock_repository.NewMockDaypartRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(
+				[]*models.Campaign{{ID: 1, GroupID: 10, OrgCode: "org", Status: codes.StatusStarted}}, nil),
+			daypartRepository.EXPECT().GetPausedCampaignIDs(gomock.Eq(ctx)).Return([]int{}, nil),
+			daypartRepository.EXPECT().GetByCampaignIDs(gomock.Eq(ctx), gomock.Eq([]int{1})).Return(
+				[]*models.Daypart{{CampaignID: 1, StartTime: "25:00:00", EndTime: "18:00:00"}}, nil),
+		)
+
+		// テストを実行する
+		daypart := NewDaypart(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			daypartRepository, deliveryStart, deliveryEnd, deliveryControlEvent, NewTestTimezone(t))
+		result, err := daypart.Run(ctx, monday(20, 0))
+		assert.NoError(t, err)
+		assert.Equal(t, &models.DaypartResult{Failed: 1}, result)
+	})
+
+	t.Run("キャンペーンの取得に失敗した場合はエラーを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		daypartRepository := mock_repository.NewMockDaypartRepository(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(nil, errors.New("error"))
+
+		// テストを実行する
+		daypart := NewDaypart(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			daypartRepository, deliveryStart, deliveryEnd, deliveryControlEvent, NewTestTimezone(t))
+		_, err := daypart.Run(ctx, monday(20, 0))
+		assert.Error(t, err)
+	})
+}
+
+func TestDaypart_InDayparts(t *testing.T) {
+	weekday := func(day time.Weekday) sql.NullInt32 {
+		return sql.NullInt32{Int32: int32(day), Valid: true}
+	}
+	// 月曜日の22:00から翌2:00まで
+	overnight := []*models.Daypart{{CampaignID: 1, DayOfWeek: weekday(time.Monday), StartTime: "22:00:00", EndTime: "02:00:00"}}
+	// 平日の9:00から18:00まで
+	weekdays := []*models.Daypart{}
+	for day := time.Monday; day <= time.Friday; day++ {
+		weekdays = append(weekdays, &models.Daypart{CampaignID: 1, DayOfWeek: weekday(day), StartTime: "09:00:00", EndTime: "18:00:00"})
+	}
+
+	tests := []struct {
+		name     string
+		dayparts []*models.Daypart
+		current  time.Time
+		expected bool
+	}{
+		{"配信時間帯がない場合は常に配信する", nil, time.Date(2024, 6, 2, 3, 0, 0, 0, time.Local), true},
+		{"開始時刻ちょうどは配信時間帯内", weekdays, time.Date(2024, 6, 3, 9, 0, 0, 0, time.Local), true},
+		{"終了時刻ちょうどは配信時間帯外", weekdays, time.Date(2024, 6, 3, 18, 0, 0, 0, time.Local), false},
+		{"対象外の曜日は配信時間帯外", weekdays, time.Date(2024, 6, 8, 12, 0, 0, 0, time.Local), false},
+		{"日付をまたぐ配信時間帯の当日", overnight, time.Date(2024, 6, 3, 23, 0, 0, 0, time.Local), true},
+		{"日付をまたぐ配信時間帯の翌日", overnight, time.Date(2024, 6, 4, 1, 59, 59, 0, time.Local), true},
+		{"日付をまたぐ配信時間帯の翌日の終了後", overnight, time.Date(2024, 6, 4, 2, 0, 0, 0, time.Local), false},
+		{"日付をまたぐ配信時間帯の前日は配信時間帯外", overnight, time.Date(2024, 6, 3, 1, 0, 0, 0, time.Local), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := models.InDayparts(tt.dayparts, tt.current)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, actual)
+		})
+	}
+}
@@ -1,123 +1,149 @@
 //go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
 package usecase
 
-// TODO: 予算消化追加時に実装する
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
 
-// var (
-// 	metricDeliveryControlProcess     = "delivery_control_usecase_process"
-// 	metricDeliveryControlProcessDesc = "delivery control usecase processing metrics"
+	"github.com/pkg/errors"
+)
 
-// 	// event: delivery_control_log.event
-// 	metricDeliveryControlProcessLabels = []string{"event"}
-// )
+var (
+	metricDeliveryControlProcess     = "delivery_control_usecase_process"
+	metricDeliveryControlProcessDesc = "delivery control usecase processing metrics"
 
-// // DeliveryControl is interface
-// type DeliveryControl interface {
-// 	// 配信制御ログを処理する
-// 	Process(ctx context.Context, current time.Time, deliveryControlLog *models.DeliveryControlLog) error
-// }
+	// event: delivery_control_log.event
+	metricDeliveryControlProcessLabels = []string{"event", "result"}
+)
 
-// type deliveryControl struct {
-// 	logger              Logger
-// 	monitor             *metrics.Monitor
-// 	transaction         repository.TransactionHandler
-// 	campaignRepository  repository.CampaignRepository
-// 	deliveryControlEvent DeliveryControlEvent
-// 	deliveryEnd         DeliveryEnd
-// }
+// DeliveryControl is interface
+type DeliveryControl interface {
+	// 配信制御ログを処理する
+	Process(ctx context.Context, current time.Time, deliveryControlLog *models.DeliveryControlLog) error
+}
 
-// // NewDeliveryControl is function
-// func NewDeliveryControl(
-// 	logger Logger,
-// 	monitor *metrics.Monitor,
-// 	transaction repository.TransactionHandler,
-// 	campaignRepository repository.CampaignRepository,
-// 	deliveryControlEvent DeliveryControlEvent,
-// 	deliveryEnd DeliveryEnd,
-// ) DeliveryControl {
-// 	instance := deliveryControl{
-// 		logger:              logger,
-// 		monitor:             monitor,
-// 		transaction:         transaction,
-// 		campaignRepository:  campaignRepository,
-// 		deliveryControlEvent: deliveryControlEvent,
-// 		deliveryEnd:         deliveryEnd,
-// 	}
-// 	monitor.Metrics.AddCounter(metricDeliveryControlProcess,
-// 		metricDeliveryControlProcessDesc,
-// 		metricDeliveryControlProcessLabels)
-// 	return &instance
-// }
+type deliveryControl struct {
+	logger               Logger
+	monitor              *metrics.Monitor
+	transaction          repository.TransactionHandler
+	campaignRepository   repository.CampaignRepository
+	deliveryControlEvent DeliveryControlEvent
+	deliveryEnd          DeliveryEnd
+}
 
-// //nolint:gocognit // [21]時間あるときに修正する
-// func (d *deliveryControl) Process(ctx context.Context, current time.Time, deliveryControlLog *models.DeliveryControlLog) (err error) {
-// 	var tx repository.Transaction
-// 	defer func() {
-// 		if err != nil && tx != nil {
-// 			if rerr := tx.Rollback(); rerr != nil {
-// 				d.logger.Error().Err(rerr).Time("current", current).Msg("Failed to rollback")
-// 			}
-// 		}
-// 	}()
-// 	d.monitor.Metrics.
-// 		GetCounter(metricDeliveryControlProcess).
-// 		WithLabelValues(deliveryControlLog.Event).
-// 		Inc()
-// 	switch deliveryControlLog.Event {
-// 	// 予算を消化した場合
-// 	case "expended":
-// 		tx, err = d.transaction.Begin(ctx)
-// 		if err != nil {
-// 			return err
-// 		}
-// 		// 配信制御ログを処理する
-// 		campaign, status, err := d.stopDelivery(ctx, tx, deliveryControlLog, "ended", true)
-// 		if err != nil {
-// 			return err
-// 		}
-// 		if err = tx.Commit(); err != nil {
-// 			return errors.Wrap(err, "Failed to commit")
-// 		}
-// 		// 配信制御イベントを発行する
-// 		d.deliveryControlEvent.Publish(ctx, campaign.ID, campaign.OrgCode, campaign.Status, *status, codes.DetailExpended)
-// 	// 予算が不足していた場合
-// 	case "shortage":
-// 		tx, err = d.transaction.Begin(ctx)
-// 		if err != nil {
-// 			return err
-// 		}
-// 		// 配信制御ログを処理する
-// 		campaign, status, err := d.stopDelivery(ctx, tx, deliveryControlLog, "paused", false)
-// 		if err != nil {
-// 			return err
-// 		}
-// 		if err = tx.Commit(); err != nil {
-// 			return errors.Wrap(err, "Failed to commit")
-// 		}
-// 		// 配信制御イベントを発行する
-// 		d.deliveryControlEvent.Publish(ctx, campaign.ID, campaign.OrgCode, campaign.Status, *status, codes.DetailShortage)
-// 	default:
-// 		d.logger.Info().Time("current", current).Interface("delivery_control_log", deliveryControlLog).Msg("Unknown event")
-// 	}
-// 	return nil
-// }
+// NewDeliveryControl is function
+func NewDeliveryControl(
+	logger Logger,
+	monitor *metrics.Monitor,
+	transaction repository.TransactionHandler,
+	campaignRepository repository.CampaignRepository,
+	deliveryControlEvent DeliveryControlEvent,
+	deliveryEnd DeliveryEnd,
+) DeliveryControl {
+	instance := deliveryControl{
+		logger:               logger,
+		monitor:              monitor,
+		transaction:          transaction,
+		campaignRepository:   campaignRepository,
+		deliveryControlEvent: deliveryControlEvent,
+		deliveryEnd:          deliveryEnd,
+	}
+	monitor.Metrics.AddCounter(metricDeliveryControlProcess,
+		metricDeliveryControlProcessDesc,
+		metricDeliveryControlProcessLabels)
+	return &instance
+}
 
-// // DeliveryControlLogを処理する
-// func (d *deliveryControl) stopDelivery(ctx context.Context,
-// 	tx repository.Transaction, deliveryControlLog *models.DeliveryControlLog,
-// 	afterStatus string, budgetExpended bool) (*models.Campaign, *string, error) {
-// 	// 該当する更新日時を取得
-// 	campaign, err := d.campaignRepository.GetCampaignToExpendedOrShortage(ctx, tx, deliveryControlLog.CampaignID, budgetExpended)
-// 	if err != nil && err != codes.ErrNoData {
-// 		return nil, nil, err
-// 	}
-// 	if err == codes.ErrNoData {
-// 		// 更新日時が取得できない場合は何もしない(できない)
-// 		return nil, nil, errors.Wrap(err, "Not found campaign")
-// 	}
-// 	err = d.deliveryEnd.Stop(ctx, tx, campaign, afterStatus)
-// 	if err != nil {
-// 		return nil, nil, err
-// 	}
-// 	return campaign, &afterStatus, nil
-// }
+func (d *deliveryControl) Process(ctx context.Context, current time.Time, deliveryControlLog *models.DeliveryControlLog) (err error) {
+	defer func() {
+		result := "success"
+		switch {
+		case err == codes.ErrDoNothing:
+			result = "do_nothing"
+		case err != nil:
+			result = "error"
+		}
+		d.monitor.Metrics.
+			GetCounter(metricDeliveryControlProcess).
+			WithLabelValues(deliveryControlLog.Event, result).
+			Inc()
+	}()
+	switch deliveryControlLog.Event {
+	// 予算を消化した場合
+	case codes.DetailExpended:
+		return d.stopDelivery(ctx, current, deliveryControlLog, codes.StatusEnded, true)
+	// 予算が不足していた場合
+	case codes.DetailShortage:
+		return d.stopDelivery(ctx, current, deliveryControlLog, codes.StatusPaused, false)
+	// 配信上限数に達した場合 (一時停止と同様に配信データを削除する)
+	case codes.DetailCapReached:
+		return d.stopDelivery(ctx, current, deliveryControlLog, codes.StatusPaused, false)
+	default:
+		d.logger.Info().Time("current", current).Interface("delivery_control_log", deliveryControlLog).Msg("Unknown event")
+		return codes.ErrDoNothing
+	}
+}
+
+// DeliveryControlLogを処理する
+// キャンペーンを停止して配信データを削除し、配信制御イベントを発行する
+func (d *deliveryControl) stopDelivery(ctx context.Context, current time.Time,
+	deliveryControlLog *models.DeliveryControlLog, afterStatus string, budgetExpended bool) (err error) {
+	var tx repository.Transaction
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic. reason: %#v", r)
+		}
+		if err != nil && tx != nil {
+			if rerr := tx.Rollback(); rerr != nil {
+				d.logger.Error().Err(rerr).Time("current", current).Msg("Failed to rollback")
+			}
+		}
+	}()
+	tx, err = d.transaction.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed to begin transaction")
+	}
+	// 停止対象のキャンペーンを取得
+	campaign, err := d.campaignRepository.GetCampaignToExpendedOrShortage(ctx, tx, deliveryControlLog.CampaignID, budgetExpended)
+	if err == codes.ErrNoData {
+		// 既に停止・終了している場合は何もしない
+		d.logger.Info().Time("current", current).
+			Str("trace_id", deliveryControlLog.TraceID).
+			Int("campaign_id", deliveryControlLog.CampaignID).
+			Str("event", deliveryControlLog.Event).
+			Msg("Not found campaign to stop")
+		return codes.ErrDoNothing
+	}
+	if err != nil {
+		return errors.Wrap(err, "Failed to get campaign")
+	}
+	if err = d.deliveryEnd.Stop(ctx, tx, campaign, afterStatus); err != nil {
+		return errors.Wrap(err, "Failed to stop campaign")
+	}
+	if err = d.deliveryEnd.Delete(ctx, tx, campaign); err != nil {
+		return errors.Wrap(err, "Failed to delete delivery data")
+	}
+	// 配信制御イベントを発行する
+	err = d.deliveryControlEvent.PublishCampaignEvent(
+		ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode, campaign.Status, afterStatus, deliveryControlLog.Event)
+	if err != nil {
+		return errors.Wrap(err, "Failed to publish campaign event")
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "Failed to commit")
+	}
+	d.logger.Info().Time("current", current).
+		Str("trace_id", deliveryControlLog.TraceID).
+		Int("campaign_id", campaign.ID).
+		Str("event", deliveryControlLog.Event).
+		Str("status_before_update", campaign.Status).
+		Str("status_after_update", afterStatus).
+		Str("cap", deliveryControlLog.Cap).
+		Int("coupon_id", deliveryControlLog.CouponID).
+		Msg("Stop delivery by delivery control")
+	return nil
+}
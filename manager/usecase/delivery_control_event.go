@@ -3,134 +3,221 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"strconv"
 	"time"
 	"touchgift-job-manager/codes"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/domain/models"
-	"touchgift-job-manager/domain/notification"
+	"touchgift-job-manager/domain/repository"
 
+	"github.com/pkg/errors"
 	"github.com/rs/xid"
 )
 
+// DeliveryControlEvent 配信制御イベント(サーバーのキャッシュ更新)を発行する
+// イベントはRDBの更新と同じトランザクションでoutboxに登録し、OutboxRelayがSNSへPublishする
 type DeliveryControlEvent interface {
-	PublishCampaignEvent(ctx context.Context, CampaignID int, groupID int, organization string, before string, after string, detail string)
-	PublishCreativeEvent(ctx context.Context, creative *models.DeliveryDataCreative, organization string, action string)
-	PublishDeliveryEvent(ctx context.Context, id string, groupID int, storeID string, campaignID int, organization string, action string)
+	PublishCampaignEvent(ctx context.Context, tx repository.Transaction, CampaignID int, groupID int, organization string, before string, after string, detail string) error
+	PublishCreativeEvent(ctx context.Context, tx repository.Transaction, creative *models.DeliveryDataCreative, organization string, action string) error
+	PublishDeliveryEvent(ctx context.Context, tx repository.Transaction, id string, groupID int, storeID string, campaignID int, organization string, action string) error
+	// PublishDeliveryEvents 複数のタッチポイントのキャッシュイベントをまとめて登録する (件数が多い場合に使用する)
+	PublishDeliveryEvents(ctx context.Context, tx repository.Transaction, touchPoints []*models.DeliveryTouchPoint, campaignID int, organization string, action string) error
+	// ReleaseHeldEvents 配信開始まで保留したキャンペーンのイベントを送信する
+	ReleaseHeldEvents(ctx context.Context, tx repository.Transaction, campaignID int) error
+	// DiscardHeldEvents 配信開始まで保留したキャンペーンのイベントを送信せずに破棄する
+	DiscardHeldEvents(ctx context.Context, tx repository.Transaction, campaignID int) error
+}
+
+type heldCampaignKey struct{}
+
+// holdEvents ctxで登録するイベントを、ReleaseHeldEventsするまで送信しないようにする
+// 配信開始前に作成した配信データのキャッシュを、配信開始前にサーバーへ反映させないために使用する
+func holdEvents(ctx context.Context, campaignID int) context.Context {
+	return context.WithValue(ctx, heldCampaignKey{}, campaignID)
+}
+
+func heldCampaignID(ctx context.Context) (int, bool) {
+	campaignID, ok := ctx.Value(heldCampaignKey{}).(int)
+	return campaignID, ok
 }
 
 type deliveryControlEvent struct {
-	logger              Logger
-	notificationHandler notification.NotificationHandler
+	logger           Logger
+	batchConfig      *config.DeliveryEventBatch
+	outboxRepository repository.OutboxRepository
+	timezone         Timezone
 }
 
 func NewDeliveryControlEvent(
 	logger Logger,
-	notificationHandler notification.NotificationHandler,
+	batchConfig *config.DeliveryEventBatch,
+	outboxRepository repository.OutboxRepository,
+	timezone Timezone,
 ) DeliveryControlEvent {
 	instance := deliveryControlEvent{
-		logger:              logger,
-		notificationHandler: notificationHandler,
+		logger:           logger,
+		batchConfig:      batchConfig,
+		outboxRepository: outboxRepository,
+		timezone:         timezone,
 	}
 	return &instance
 }
 
 // サーバーのCampaignキャッシュ更新のためSNSへPublishを行う
 // CampaignID, org_code, cacheOperation(サーバー上のキャッシュ操作), before(更新前のCampaign.status), after(更新後のCampaign.status)
-func (d *deliveryControlEvent) PublishCampaignEvent(ctx context.Context,
-	CampaignID int, groupID int, organization string, before string, after string, detail string) {
+func (d *deliveryControlEvent) PublishCampaignEvent(ctx context.Context, tx repository.Transaction,
+	CampaignID int, groupID int, organization string, before string, after string, detail string) error {
 	deliveryControl := d.createCampaignCacheLog(CampaignID, groupID, organization, before, after, detail)
 
-	message, err := json.Marshal(deliveryControl)
-	if err != nil {
-		d.failedToPublishLog(deliveryControl, err)
-	}
 	messageAttributes := map[string]string{
 		"event":  deliveryControl.Event,
 		"action": deliveryControl.Action,
 	}
-	messageID, err := d.notificationHandler.Publish(ctx, string(message), messageAttributes, config.Env.SNS.ControlLogTopicArn)
+	outboxID, err := d.enqueue(ctx, tx, codes.OutboxEventCampaign, deliveryControl, messageAttributes, config.Env.SNS.ControlLogTopicArn)
 	if err != nil {
 		d.failedToPublishLog(deliveryControl, err)
-	} else {
-		d.logger.Info().
-			Str("message_id", *messageID).
-			Str("trace_id", deliveryControl.TraceID).
-			Str("trace_time", deliveryControl.Time).
-			Int("version", deliveryControl.Version).
-			Str("event", deliveryControl.Event).
-			Str("event_detail", deliveryControl.EventDetail).
-			Str("action", deliveryControl.Action).
-			Str("source", deliveryControl.Source).
-			Str("org_code", deliveryControl.OrgCode).
-			Str("campaign_id", deliveryControl.ID).
-			Str("group_id", deliveryControl.GroupID).
-			Msg("Publish campaign cache event")
+		return err
 	}
+	d.logger.Info().
+		Int64("outbox_id", outboxID).
+		Str("trace_id", deliveryControl.TraceID).
+		Str("trace_time", deliveryControl.Time).
+		Int("version", deliveryControl.Version).
+		Str("event", deliveryControl.Event).
+		Str("event_detail", deliveryControl.EventDetail).
+		Str("action", deliveryControl.Action).
+		Str("source", deliveryControl.Source).
+		Str("org_code", deliveryControl.OrgCode).
+		Str("campaign_id", deliveryControl.ID).
+		Str("group_id", deliveryControl.GroupID).
+		Msg("Publish campaign cache event")
+	return nil
 }
 
 // サーバーのCreativeキャッシュ更新のためSNSへPublishを行う
-func (d *deliveryControlEvent) PublishCreativeEvent(ctx context.Context,
-	creative *models.DeliveryDataCreative, organization string, action string) {
+func (d *deliveryControlEvent) PublishCreativeEvent(ctx context.Context, tx repository.Transaction,
+	creative *models.DeliveryDataCreative, organization string, action string) error {
 	deliveryControl := d.createCreativeEventLog(creative, organization, action)
 
-	message, err := json.Marshal(deliveryControl)
-	if err != nil {
-		d.logger.Error().Err(err).
-			Str("creative_id", deliveryControl.ID).
-			Str("action", deliveryControl.Action).
-			Msg("Failed to marshal json")
-	}
 	messageAttributes := map[string]string{
 		"action": deliveryControl.Action,
 	}
-	messageID, err := d.notificationHandler.Publish(ctx, string(message), messageAttributes, config.Env.SNS.CreativeCacheTopicArn)
+	outboxID, err := d.enqueue(ctx, tx, codes.OutboxEventCreative, deliveryControl, messageAttributes, config.Env.SNS.CreativeCacheTopicArn)
 	if err != nil {
 		d.logger.Error().Err(err).
 			Str("creative_id", deliveryControl.ID).
 			Str("action", deliveryControl.Action).
 			Msg("Failed to creative cache sns publish")
-	} else {
-		d.logger.Info().
-			Str("message_id", *messageID).
-			Str("action", deliveryControl.Action).
-			Str("creative_id", deliveryControl.ID).
-			Msg("Publish creative cache event")
+		return err
 	}
+	d.logger.Info().
+		Int64("outbox_id", outboxID).
+		Str("action", deliveryControl.Action).
+		Str("creative_id", deliveryControl.ID).
+		Msg("Publish creative cache event")
+	return nil
 }
 
 // サーバーのTouchpointキャッシュ更新のためSNSへPublishを行う
-func (d *deliveryControlEvent) PublishDeliveryEvent(ctx context.Context,
-	id string, groupID int, storeID string, campaignID int, organization string, operation string) {
+func (d *deliveryControlEvent) PublishDeliveryEvent(ctx context.Context, tx repository.Transaction,
+	id string, groupID int, storeID string, campaignID int, organization string, operation string) error {
 	deliveryControl := d.createDeliveryEventLog(id, groupID, storeID, organization, campaignID, operation)
 
-	message, err := json.Marshal(deliveryControl)
-	if err != nil {
-		d.logger.Error().Err(err).
-			Str("touchpoint_id", deliveryControl.ID).
-			Str("action", deliveryControl.Action).
-			Msg("Failed to marshal json")
-	}
 	messageAttributes := map[string]string{
 		"action": deliveryControl.Action,
 	}
-	messageID, err := d.notificationHandler.Publish(ctx, string(message), messageAttributes, config.Env.SNS.DeliveryCacheTopicArn)
+	outboxID, err := d.enqueue(ctx, tx, codes.OutboxEventDelivery, deliveryControl, messageAttributes, config.Env.SNS.DeliveryCacheTopicArn)
 	if err != nil {
 		d.logger.Error().Err(err).
 			Str("touchpoint_id", deliveryControl.ID).
 			Str("action", deliveryControl.Action).
 			Msg("Failed to delivery cache sns publish")
-	} else {
-		d.logger.Info().
-			Str("message_id", *messageID).
-			Str("action", deliveryControl.Action).
-			Str("org_code", deliveryControl.OrgCode).
-			Int("campaign_id", deliveryControl.CampaignID).
-			Int("group_id", deliveryControl.GroupID).
-			Str("store_id", deliveryControl.StoreID).
-			Msg("Publish delivery control event")
+		return err
+	}
+	d.logger.Info().
+		Int64("outbox_id", outboxID).
+		Str("action", deliveryControl.Action).
+		Str("org_code", deliveryControl.OrgCode).
+		Int("campaign_id", deliveryControl.CampaignID).
+		Int("group_id", deliveryControl.GroupID).
+		Str("store_id", deliveryControl.StoreID).
+		Msg("Publish delivery control event")
+	return nil
+}
+
+// サーバーのTouchpointキャッシュ更新のため、複数件をまとめてSNSへPublishを行う
+// バッチを無効にしている場合は1件ずつ(version 1の形式で)Publishする
+func (d *deliveryControlEvent) PublishDeliveryEvents(ctx context.Context, tx repository.Transaction,
+	touchPoints []*models.DeliveryTouchPoint, campaignID int, organization string, action string) error {
+	if !d.batchConfig.Enabled {
+		for _, tp := range touchPoints {
+			if err := d.PublishDeliveryEvent(ctx, tx, tp.ID, tp.GroupID, tp.StoreID, campaignID, organization, action); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	batch := &deliveryEventBatch{
+		logger:               d.logger,
+		config:               d.batchConfig,
+		deliveryControlEvent: d,
+		tx:                   tx,
+	}
+	for _, tp := range touchPoints {
+		if err := batch.add(ctx, tp.ID, tp.GroupID, tp.StoreID, campaignID, organization, action); err != nil {
+			return err
+		}
+	}
+	return batch.flush(ctx)
+}
+
+// 配信開始まで保留したキャンペーンのイベントを送信待ちにする
+func (d *deliveryControlEvent) ReleaseHeldEvents(ctx context.Context, tx repository.Transaction, campaignID int) error {
+	count, err := d.outboxRepository.ReleaseHeld(ctx, tx, campaignID, time.Now())
+	if err != nil {
+		return errors.Wrap(err, "Failed to release held outbox")
 	}
+	d.logger.Info().Int("campaign_id", campaignID).Int64("count", count).Msg("Release held events")
+	return nil
+}
+
+// 配信開始まで保留したキャンペーンのイベントを破棄する
+func (d *deliveryControlEvent) DiscardHeldEvents(ctx context.Context, tx repository.Transaction, campaignID int) error {
+	count, err := d.outboxRepository.DeleteHeld(ctx, tx, campaignID)
+	if err != nil {
+		return errors.Wrap(err, "Failed to delete held outbox")
+	}
+	d.logger.Info().Int("campaign_id", campaignID).Int64("count", count).Msg("Discard held events")
+	return nil
+}
+
+// outboxにイベントを登録する (holdEventsしたctxの場合は送信を保留する)
+func (d *deliveryControlEvent) enqueue(ctx context.Context, tx repository.Transaction,
+	eventType string, message interface{}, messageAttributes map[string]string, topicArn string) (int64, error) {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to marshal json")
+	}
+	attributes, err := json.Marshal(messageAttributes)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to marshal json")
+	}
+	event := models.OutboxEvent{
+		EventType:     eventType,
+		TopicArn:      topicArn,
+		Message:       string(body),
+		Attributes:    string(attributes),
+		NextAttemptAt: time.Now(),
+	}
+	if campaignID, ok := heldCampaignID(ctx); ok {
+		event.HeldCampaignID = sql.NullInt64{Int64: int64(campaignID), Valid: true}
+	}
+	if err := d.outboxRepository.Save(ctx, tx, &event); err != nil {
+		return 0, errors.Wrap(err, "Failed to save outbox")
+	}
+	return event.ID, nil
 }
 
 func (d *deliveryControlEvent) failedToPublishLog(deliveryControl *models.CampaignCacheLog, err error) {
@@ -154,7 +241,8 @@ func (d *deliveryControlEvent) createCampaignCacheLog(campaignID int,
 	eventDetail string) *models.CampaignCacheLog {
 
 	event, operation := d.deliveryEvent(before, after)
-	current := time.Now().Format(time.RFC3339Nano)
+	// 組織のタイムゾーンで表示する (オフセットを含むため受信側はタイムゾーンに依存せず扱える)
+	current := d.timezone.Format(organization, time.Now())
 	return &models.CampaignCacheLog{
 		TraceID:     d.createTraceID(),
 		Time:        current,
@@ -212,6 +300,10 @@ func (d *deliveryControlEvent) deliveryEvent(before string, after string) (strin
 	case before == codes.StatusConfigured && after == codes.StatusWarmup:
 		event = codes.StatusWarmup
 		operation = "NONE"
+	case before == codes.StatusWarmup && after == codes.StatusWarmup:
+		// 配信開始前の検証で開始できなかった (サーバーのキャッシュは操作しない)
+		event = codes.EventWarmupFailed
+		operation = "NONE"
 	case before == codes.StatusWarmup && after == codes.StatusStarted:
 		event = codes.StatusStart
 		operation = "PUT"
@@ -221,7 +313,9 @@ func (d *deliveryControlEvent) deliveryEvent(before string, after string) (strin
 	case before == codes.StatusStarted && after == codes.StatusStarted:
 		event = "update"
 		operation = "PUT"
-	case before == codes.StatusStop && after == codes.StatusStopped:
+	case before == codes.StatusStop && after == codes.StatusStopped,
+		// CLIから配信中のキャンペーンを直接停止した
+		before == codes.StatusStarted && after == codes.StatusStopped:
 		event = codes.StatusStop
 		operation = "DELETE"
 	case after == codes.StatusPaused:
@@ -2,6 +2,7 @@ package usecase
 
 import (
 	"context"
+	"database/sql"
 	"encoding/json"
 	"errors"
 	"strconv"
@@ -10,7 +11,8 @@ import (
 	"touchgift-job-manager/codes"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/domain/models"
-	mock_notification "touchgift-job-manager/mock/notification"
+	"touchgift-job-manager/domain/repository"
+	mock_repository "touchgift-job-manager/mock/repository"
 
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
@@ -20,88 +22,188 @@ func TestDeliveryControlEvent_Publish(t *testing.T) {
 	// テスト用のLoggerを作成
 	logger := NewTestLogger(t)
 
-	t.Run("配信制御イベントの通知ができること", func(t *testing.T) {
+	t.Run("配信制御イベントをトランザクション内でoutboxに登録できること", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
 
 		// mockの処理を定義
 		// 引数に渡ると想定される値
 		ctx := context.Background()
-		current := time.Now()
-		cacheOperation := "NONE"
-		deliveryControlEvent := models.CampaignCacheLog{
-			TraceID: "traceid1",
-			Time:    current.Format(time.RFC3339Nano),
-			Version: 1,
-			Action:  cacheOperation,
-			Event:   "warmup",
-			Source:  "touchgift-job-manager",
-			OrgCode: "org1",
-			ID:      "1",
-			GroupID: "1",
-		}
-		_, err := json.Marshal(&deliveryControlEvent)
-		if err != nil {
-			t.Fatal("failed to marshal json")
-		}
-		messageAttributes := map[string]string{
-			"event":  deliveryControlEvent.Event,
-			"action": deliveryControlEvent.Action,
-		}
+		gomock.InOrder(
+			outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+					assert.Equal(t, codes.OutboxEventCampaign, event.EventType)
+					assert.Equal(t, config.Env.SNS.ControlLogTopicArn, event.TopicArn)
+					attributes, err := event.MessageAttributes()
+					if assert.NoError(t, err) {
+						assert.Equal(t, map[string]string{"event": "warmup", "action": "NONE"}, attributes)
+					}
+					message := models.CampaignCacheLog{}
+					if assert.NoError(t, json.Unmarshal([]byte(event.Message), &message)) {
+						assert.Equal(t, "1", message.ID)
+						assert.Equal(t, "org1", message.OrgCode)
+						assert.Equal(t, "warmup", message.Event)
+					}
+					assert.WithinDuration(t, time.Now(), event.NextAttemptAt, time.Second)
+					assert.False(t, event.HeldCampaignID.Valid)
+					event.ID = 1
+					return nil
+				}),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishCampaignEvent(ctx, tx, 1, 1, "org1", "configured", "warmup", "")
+		assert.NoError(t, err)
+	})
+
+	t.Run("タッチポイント・クリエイティブのイベントはそれぞれのトピックに登録する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
 
-		messageID := "message_id1"
+		ctx := context.Background()
 		gomock.InOrder(
-			notificationHandler.EXPECT().Publish(ctx, gomock.Any(), messageAttributes, config.Env.SNS.ControlLogTopicArn).Return(&messageID, nil),
+			outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+					assert.Equal(t, codes.OutboxEventDelivery, event.EventType)
+					assert.Equal(t, config.Env.SNS.DeliveryCacheTopicArn, event.TopicArn)
+					return nil
+				}),
+			outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+					assert.Equal(t, codes.OutboxEventCreative, event.EventType)
+					assert.Equal(t, config.Env.SNS.CreativeCacheTopicArn, event.TopicArn)
+					return nil
+				}),
 		)
 
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
-		deliveryControlEventUsecase.PublishCampaignEvent(ctx, 1, 1, "org1", "configured", "warmup", "")
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishDeliveryEvent(ctx, tx, "tp1", 1, "store1", 1, "org1", "PUT")
+		assert.NoError(t, err)
+		err = deliveryControlEventUsecase.PublishCreativeEvent(ctx, tx, &models.DeliveryDataCreative{ID: "1"}, "org1", "PUT")
+		assert.NoError(t, err)
 	})
 
-	t.Run("通知に失敗した場合は専用のエラーログを出力する", func(t *testing.T) {
+	t.Run("holdEventsしたctxのイベントはキャンペーンIDを指定して送信を保留する", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		// 配信開始前に作成した配信データのイベントはキャンペーン1の開始まで保留する
+		ctx := holdEvents(context.Background(), 1)
+		gomock.InOrder(
+			outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+					assert.Equal(t, codes.OutboxEventDelivery, event.EventType)
+					assert.Equal(t, sql.NullInt64{Int64: 1, Valid: true}, event.HeldCampaignID)
+					return nil
+				}),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishDeliveryEvent(ctx, tx, "tp1", 1, "store1", 1, "org1", "PUT")
+		assert.NoError(t, err)
+	})
+
+	t.Run("outboxへの登録に失敗した場合はエラーを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
 
-		// mockの処理を定義
-		// 引数に渡ると想定される値
 		ctx := context.Background()
-		current := time.Now()
-		cacheOperation := "PUT"
-		deliveryControlEvent := models.CampaignCacheLog{
-			TraceID: "traceid1",
-			Time:    current.Format(time.RFC3339Nano),
-			Version: 1,
-			Action:  cacheOperation,
-			Event:   "start",
-			Source:  "touchgift-job-manager",
-			OrgCode: "org1",
-			ID:      "1",
-			GroupID: "1",
-		}
-		_, err := json.Marshal(&deliveryControlEvent)
-		if err != nil {
-			t.Fatal("failed to marshal json")
-		}
-		messageAttributes := map[string]string{
-			"event":  deliveryControlEvent.Event,
-			"action": deliveryControlEvent.Action,
-		}
 		errUnexpected := errors.New("unexpected error")
 		gomock.InOrder(
-			notificationHandler.EXPECT().Publish(ctx, gomock.Any(), messageAttributes, config.Env.SNS.ControlLogTopicArn).Return(nil, errUnexpected),
+			outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(errUnexpected),
 		)
 
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
-		deliveryControlEventUsecase.PublishCampaignEvent(ctx, 1, 1, "org1", "warmup", "started", "")
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishCampaignEvent(ctx, tx, 1, 1, "org1", "warmup", "started", "")
+		assert.ErrorIs(t, err, errUnexpected)
+	})
+}
+
+func TestDeliveryControlEvent_HeldEvents(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("保留したイベントを送信待ちにする", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			outboxRepository.EXPECT().ReleaseHeld(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, tx repository.Transaction, campaignID int, nextAttemptAt time.Time) (int64, error) {
+					// すぐに送信する
+					assert.WithinDuration(t, time.Now(), nextAttemptAt, time.Second)
+					return 3, nil
+				}),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.ReleaseHeldEvents(ctx, tx, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("保留したイベントを破棄する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			outboxRepository.EXPECT().DeleteHeld(gomock.Eq(ctx), gomock.Nil(), gomock.Eq(1)).Return(int64(3), nil),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.DiscardHeldEvents(ctx, nil, 1)
+		assert.NoError(t, err)
+	})
+
+	t.Run("送信待ちにできなかった場合はエラーを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		errUnexpected := errors.New("unexpected error")
+		gomock.InOrder(
+			outboxRepository.EXPECT().ReleaseHeld(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Any()).Return(int64(0), errUnexpected),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.ReleaseHeldEvents(ctx, tx, 1)
+		assert.ErrorIs(t, err, errUnexpected)
 	})
 }
 
@@ -117,7 +219,7 @@ func TestDeliveryControlEvent_createCampaignCacheLog(t *testing.T) {
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		CampaignID := 1
 		groupID := 2
@@ -128,7 +230,7 @@ func TestDeliveryControlEvent_createCampaignCacheLog(t *testing.T) {
 		expectedEvent := "warmup"
 		expectedEventDetail := "shortage"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual := deliveryControlEventInteractor.createCampaignCacheLog(CampaignID, groupID, organization, before, after, codes.DetailShortage)
@@ -143,6 +245,27 @@ func TestDeliveryControlEvent_createCampaignCacheLog(t *testing.T) {
 		assert.NotEmpty(t, actual.Time)
 	})
 
+	t.Run("イベントの日時は組織のタイムゾーンで表示する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		timezone, err := NewTimezone(&config.Timezone{Default: "Asia/Tokyo", ByOrg: map[string]string{"org_utc": "UTC"}})
+		if !assert.NoError(t, err) {
+			return
+		}
+		deliveryControlEventInteractor := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, timezone).(*deliveryControlEvent)
+
+		for organization, expected := range map[string]int{"org": 9 * 60 * 60, "org_utc": 0} {
+			actual := deliveryControlEventInteractor.createCampaignCacheLog(1, 2, organization, "warmup", "started", "")
+			parsed, err := time.Parse(time.RFC3339Nano, actual.Time)
+			if assert.NoError(t, err) {
+				_, offset := parsed.Zone()
+				assert.Equal(t, expected, offset, organization)
+				assert.WithinDuration(t, time.Now(), parsed, time.Second)
+			}
+		}
+	})
 }
 
 // DeliveryControlEventのdeliveryEventのテスト
@@ -157,11 +280,11 @@ func TestDeliveryControlEvent_deliveryEvent(t *testing.T) {
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		expected := "warmup"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual, operation := deliveryControlEventInteractor.deliveryEvent("configured", "warmup")
@@ -175,11 +298,11 @@ func TestDeliveryControlEvent_deliveryEvent(t *testing.T) {
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		expected := "start"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual, operation := deliveryControlEventInteractor.deliveryEvent("warmup", "started")
@@ -187,17 +310,35 @@ func TestDeliveryControlEvent_deliveryEvent(t *testing.T) {
 		assert.Exactly(t, "PUT", operation)
 	})
 
+	t.Run("campaignのstatus遷移がwarmup->warmupの場合、配信制御イベントはwarmup_failedを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+
+		expected := "warmup_failed"
+		// テストを実行する
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		// private methodのテストを行うためにcastする
+		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
+		actual, operation := deliveryControlEventInteractor.deliveryEvent("warmup", "warmup")
+		assert.Exactly(t, expected, actual)
+		assert.Exactly(t, "NONE", operation)
+	})
+
 	t.Run("campaignのstatus遷移がresume->startedの場合、配信制御イベントはresumeを返す", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		expected := "resume"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual, operation := deliveryControlEventInteractor.deliveryEvent("resume", "started")
@@ -211,11 +352,11 @@ func TestDeliveryControlEvent_deliveryEvent(t *testing.T) {
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		expected := "update"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual, operation := deliveryControlEventInteractor.deliveryEvent("started", "started")
@@ -229,11 +370,11 @@ func TestDeliveryControlEvent_deliveryEvent(t *testing.T) {
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		expected := "pause"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual, operation := deliveryControlEventInteractor.deliveryEvent("pause", "paused")
@@ -247,11 +388,11 @@ func TestDeliveryControlEvent_deliveryEvent(t *testing.T) {
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		expected := "stop"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual, operation := deliveryControlEventInteractor.deliveryEvent("stop", "stopped")
@@ -259,17 +400,35 @@ func TestDeliveryControlEvent_deliveryEvent(t *testing.T) {
 		assert.Exactly(t, "DELETE", operation)
 	})
 
+	t.Run("campaignのstatus遷移がstarted->stoppedの場合、配信制御イベントはstopを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+
+		expected := "stop"
+		// テストを実行する
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		// private methodのテストを行うためにcastする
+		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
+		actual, operation := deliveryControlEventInteractor.deliveryEvent("started", "stopped")
+		assert.Exactly(t, expected, actual)
+		assert.Exactly(t, "DELETE", operation)
+	})
+
 	t.Run("campaignのstatus遷移がterminate->endedの場合、配信制御イベントはendを返す", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
 		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
 
 		// 必要なmockを作成
-		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
 
 		expected := "end"
 		// テストを実行する
-		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, notificationHandler)
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
 		// private methodのテストを行うためにcastする
 		deliveryControlEventInteractor := deliveryControlEventUsecase.(*deliveryControlEvent)
 		actual, operation := deliveryControlEventInteractor.deliveryEvent("terminate", "ended")
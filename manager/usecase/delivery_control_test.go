@@ -0,0 +1,178 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryControl_Process(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	createLog := func(event string) *models.DeliveryControlLog {
+		return &models.DeliveryControlLog{
+			TraceID:    "trace_id",
+			Time:       "2023-01-01T00:00:00+09:00",
+			Version:    1,
+			Event:      event,
+			Source:     "budget",
+			OrgCode:    "org",
+			CampaignID: 1,
+		}
+	}
+	createCampaign := func(status string) *models.Campaign {
+		return &models.Campaign{
+			ID:      1,
+			GroupID: 10,
+			OrgCode: "org",
+			Status:  status,
+		}
+	}
+
+	t.Run("予算消化の場合はキャンペーンを終了して配信データを削除する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+
+		ctx := context.Background()
+		campaign := createCampaign(codes.StatusPaused)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetCampaignToExpendedOrShortage(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(true)).Return(campaign, nil),
+			deliveryEnd.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusEnded)).Return(nil),
+			deliveryEnd.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusPaused), gomock.Eq(codes.StatusEnded), gomock.Eq(codes.DetailExpended)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		deliveryControl := NewDeliveryControl(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, deliveryControlEvent, deliveryEnd)
+		err := deliveryControl.Process(ctx, time.Now(), createLog(codes.DetailExpended))
+		assert.NoError(t, err)
+	})
+
+	t.Run("予算不足の場合はキャンペーンを一時停止して配信データを削除する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+
+		ctx := context.Background()
+		campaign := createCampaign(codes.StatusStarted)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetCampaignToExpendedOrShortage(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(false)).Return(campaign, nil),
+			deliveryEnd.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusPaused)).Return(nil),
+			deliveryEnd.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusPaused), gomock.Eq(codes.DetailShortage)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		deliveryControl := NewDeliveryControl(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, deliveryControlEvent, deliveryEnd)
+		err := deliveryControl.Process(ctx, time.Now(), createLog(codes.DetailShortage))
+		assert.NoError(t, err)
+	})
+
+	t.Run("配信上限に達した場合はキャンペーンを一時停止して配信データを削除する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+
+		ctx := context.Background()
+		campaign := createCampaign(codes.StatusStarted)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetCampaignToExpendedOrShortage(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(false)).Return(campaign, nil),
+			deliveryEnd.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusPaused)).Return(nil),
+			deliveryEnd.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusPaused), gomock.Eq(codes.DetailCapReached)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		deliveryControlLog := createLog(codes.DetailCapReached)
+		deliveryControlLog.Cap = "coupon_total"
+		deliveryControlLog.CouponID = 100
+		deliveryControl := NewDeliveryControl(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, deliveryControlEvent, deliveryEnd)
+		err := deliveryControl.Process(ctx, time.Now(), deliveryControlLog)
+		assert.NoError(t, err)
+	})
+
+	t.Run("停止対象のキャンペーンがない場合は何もしない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetCampaignToExpendedOrShortage(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(false)).Return(nil, codes.ErrNoData),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryControl := NewDeliveryControl(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, deliveryControlEvent, deliveryEnd)
+		err := deliveryControl.Process(ctx, time.Now(), createLog(codes.DetailShortage))
+		assert.EqualError(t, err, codes.ErrDoNothing.Error())
+	})
+
+	t.Run("ステータス更新に失敗した場合はロールバックする", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+
+		ctx := context.Background()
+		campaign := createCampaign(codes.StatusStarted)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetCampaignToExpendedOrShortage(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(true)).Return(campaign, nil),
+			deliveryEnd.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusEnded)).Return(errors.New("error")),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryControl := NewDeliveryControl(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, deliveryControlEvent, deliveryEnd)
+		err := deliveryControl.Process(ctx, time.Now(), createLog(codes.DetailExpended))
+		assert.Error(t, err)
+	})
+
+	t.Run("未知のイベントの場合は何もしない", func(t *testing.T) {
+		deliveryControl := NewDeliveryControl(logger, metrics.GetMonitor(), nil, nil, nil, nil)
+		err := deliveryControl.Process(context.Background(), time.Now(), createLog("unknown"))
+		assert.EqualError(t, err, codes.ErrDoNothing.Error())
+	})
+}
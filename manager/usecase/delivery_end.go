@@ -31,10 +31,16 @@ type DeliveryEnd interface {
 	Reserve(ctx context.Context, endAt time.Time, campaign *models.Campaign)
 	// 配信開始処理を実行する(即時)
 	ExecuteNow(campaign *models.Campaign)
+	// 配信終了処理を実行し、完了まで待つ (CLI用)
+	Execute(ctx context.Context, campaign *models.Campaign) error
+	// 配信中のキャンペーンを停止し、完了まで待つ (CLI用)
+	StopStarted(ctx context.Context, campaignID int) error
 	// 配信停止処理
 	Stop(ctx context.Context, tx repository.Transaction, campaign *models.Campaign, status string) error
 	// 配信データ削除
-	Delete(ctx context.Context, campaign *models.Campaign) error
+	Delete(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) error
+	// 配信終了処理で削除する配信データと発行する配信制御イベントを返す (DynamoDB・SNS・RDBへの登録や更新はしない)
+	Plan(ctx context.Context, campaignID int) (*models.DeliveryPlan, error)
 	// 終了する
 	Close()
 	// Workerを作成する
@@ -99,6 +105,10 @@ func NewDeliveryEnd(
 		metricDeliveryEndDurationDesc,
 		nil,
 		metricDeliveryEndDurationBuckets)
+	// 再起動後に読み込み直した予約の処理 (終了処理はキャンペーンIDのみ使用する)
+	timer.Handle(codes.ReservationActionEnd, func(reservation *models.Reservation) {
+		instance.ExecuteNow(&models.Campaign{ID: reservation.CampaignID})
+	})
 	return &instance
 }
 
@@ -118,7 +128,12 @@ func (d *deliveryEnd) Close() {
 
 // 配信終了処理を指定時間に実行するように予約する
 func (d *deliveryEnd) Reserve(ctx context.Context, endAt time.Time, campaign *models.Campaign) {
-	d.timer.ExecuteAtTime(ctx, endAt, func() {
+	reservation := models.Reservation{
+		CampaignID: campaign.ID,
+		Action:     codes.ReservationActionEnd,
+		FireAt:     endAt,
+	}
+	d.timer.Reserve(ctx, &reservation, func() {
 		d.ExecuteNow(campaign)
 	})
 }
@@ -128,6 +143,61 @@ func (d *deliveryEnd) ExecuteNow(campaign *models.Campaign) {
 	d.worker.q <- campaign // 実行する
 }
 
+// Execute 配信終了処理をWorkerを使わずに実行する
+func (d *deliveryEnd) Execute(ctx context.Context, campaign *models.Campaign) error {
+	return d.end(ctx, time.Now(), campaign)
+}
+
+// StopStarted 配信中(started)のキャンペーンをstoppedに更新して配信データを削除する
+// ステータス更新・配信データ削除・配信制御イベントの登録は同じトランザクションで行う
+func (d *deliveryEnd) StopStarted(ctx context.Context, campaignID int) (err error) {
+	var tx repository.Transaction
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic. reason: %#v", r)
+		}
+		if err != nil && tx != nil {
+			if terr := tx.Rollback(); terr != nil {
+				d.logger.Error().Err(terr).Int("campaign_id", campaignID).Msg("Failed to rollback")
+			}
+		}
+	}()
+	tx, err = d.transaction.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed to start transaction")
+	}
+	// 開始・終了処理と競合しないようにキャンペーンの行をロックしてからステータスを確認する
+	status, err := d.campaignRepository.GetStatusForUpdate(ctx, tx, campaignID)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get campaign status")
+	}
+	if status != codes.StatusStarted {
+		return errors.Errorf("campaign other than started. status: %s", status)
+	}
+	deliveryData, err := d.campaignRepository.GetDeliveryToStart(ctx, tx, &repository.CampaignCondition{CampaignID: campaignID})
+	if err != nil {
+		return errors.Wrap(err, "Failed to get deliveryData")
+	}
+	if deliveryData == nil {
+		return errors.Errorf("campaign not found. id: %d", campaignID)
+	}
+	if err := d.Stop(ctx, tx, deliveryData, codes.StatusStopped); err != nil {
+		return errors.Wrap(err, "Failed to update status")
+	}
+	if err := d.Delete(ctx, tx, deliveryData); err != nil {
+		return errors.Wrap(err, "Failed to delete deliveryData")
+	}
+	// 配信制御イベントを発行する
+	err = d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, deliveryData.ID, deliveryData.GroupID, deliveryData.OrgCode, status, codes.StatusStopped, "")
+	if err != nil {
+		return errors.Wrap(err, "Failed to publish campaign event")
+	}
+	if err := tx.Commit(); err != nil {
+		return errors.Wrap(err, "Failed to commit")
+	}
+	return nil
+}
+
 // 終了対象キャンペーンを取得する
 func (d *deliveryEnd) GetDeliveryDataCampaigns(ctx context.Context, to time.Time, status []string, limit int) ([]*models.Campaign, error) {
 	condition := repository.CampaignDataToEndCondition{
@@ -164,8 +234,8 @@ func (d *deliveryEnd) Stop(ctx context.Context, tx repository.Transaction, campa
 	return nil
 }
 
-// DynamoDBから配信データを削除する
-func (d *deliveryEnd) Delete(ctx context.Context, campaign *models.Campaign) error {
+// DynamoDBから配信データを削除する (配信制御イベントはtxでoutboxに登録する)
+func (d *deliveryEnd) Delete(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) error {
 	campaignID := strconv.Itoa(campaign.ID)
 	if err := d.campaignDataRepository.Delete(ctx, &campaignID); err != nil {
 		return err
@@ -186,17 +256,74 @@ func (d *deliveryEnd) Delete(ctx context.Context, campaign *models.Campaign) err
 		if err != nil {
 			return err
 		}
+		deleteDatas := make([]models.DeliveryTouchPoint, 0, len(touchPoints))
 		for _, touchPoint := range touchPoints {
-			groupIDStr := strconv.Itoa(touchPoint.GroupID)
-			if err := d.touchPointDataRepository.Delete(ctx, &touchPoint.ID, &groupIDStr); err != nil {
-				return err
-			}
-			d.deliveryControlEvent.PublishDeliveryEvent(ctx, touchPoint.ID, touchPoint.GroupID, touchPoint.StoreID, campaign.ID, campaign.OrgCode, "DELETE")
+			deleteDatas = append(deleteDatas, models.DeliveryTouchPoint{
+				ID:      touchPoint.ID,
+				GroupID: touchPoint.GroupID,
+				StoreID: touchPoint.StoreID,
+			})
+		}
+		// タッチポイントは件数が多いためBatchWriteItemでまとめて削除する
+		if err := d.touchPointDataRepository.DeleteAll(ctx, &deleteDatas); err != nil {
+			return err
+		}
+		events := make([]*models.DeliveryTouchPoint, 0, len(deleteDatas))
+		for i := range deleteDatas {
+			events = append(events, &deleteDatas[i])
+		}
+		if err := d.deliveryControlEvent.PublishDeliveryEvents(ctx, tx, events, campaign.ID, campaign.OrgCode, "DELETE"); err != nil {
+			return err
 		}
 	}
 	return nil
 }
 
+// Plan 配信終了処理(配信データ削除処理)をplan modeで実行する
+func (d *deliveryEnd) Plan(ctx context.Context, campaignID int) (*models.DeliveryPlan, error) {
+	tx, err := d.transaction.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to start transaction")
+	}
+	// 確認のみのため常にロールバックする
+	defer func() {
+		if terr := tx.Rollback(); terr != nil {
+			d.logger.Error().Err(terr).Int("campaign_id", campaignID).Msg("Failed to rollback")
+		}
+	}()
+	campaign, err := d.campaignRepository.GetDeliveryToStart(ctx, tx, &repository.CampaignCondition{
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get campaign")
+	}
+	if campaign == nil {
+		return nil, codes.ErrNoData
+	}
+	recorder := newDeliveryPlanRecorder(codes.PlanActionEnd, campaign, codes.StatusEnded)
+	planner := *d
+	planner.campaignRepository = &planCampaignRepository{CampaignRepository: d.campaignRepository, campaign: campaign}
+	planner.campaignDataRepository = &planCampaignDataRepository{d.campaignDataRepository, recorder}
+	planner.contentDataRepository = &planContentDataRepository{d.contentDataRepository, recorder}
+	planner.touchPointDataRepository = &planTouchPointDataRepository{d.touchPointDataRepository, recorder}
+	planner.deliveryControlEvent = recorder.deliveryControlEvent(d.deliveryControlEvent)
+
+	// 終了処理と同じ順で処理する (ステータスはRDBを更新せず、グループに紐づく配信中のキャンペーン数にのみ反映する)
+	if err := planner.Stop(ctx, tx, campaign, codes.StatusEnded); err != nil {
+		return nil, err
+	}
+	if err := planner.Delete(ctx, tx, campaign); err != nil {
+		return nil, err
+	}
+	// 終了処理はterminateのキャンペーンのみ処理される
+	err = planner.deliveryControlEvent.PublishCampaignEvent(
+		ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode, codes.StatusTerminate, codes.StatusEnded, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to publish campaign event")
+	}
+	return recorder.plan, nil
+}
+
 // 配信終了処理
 func (d *deliveryEnd) execute(ctx context.Context) {
 	defer d.worker.wg.Done()
@@ -243,6 +370,15 @@ func (d *deliveryEnd) end(ctx context.Context, startTime time.Time, reservedData
 	if err != nil {
 		return errors.Wrap(err, "Failed to start transaction")
 	}
+	// 管理APIからの即時実行はリーダー以外でも実行されるため、キャンペーンをロックしてから確認する
+	// (リーダーの終了処理と重複した場合は後から処理する方がterminate以外として終了する)
+	if _, err = d.campaignRepository.GetStatusForUpdate(ctx, tx, reservedData.ID); err != nil {
+		if err == codes.ErrNoData {
+			d.logger.Debug().Int("campaign_id", reservedData.ID).Msg("Campaign not found")
+			return tx.Rollback()
+		}
+		return errors.Wrap(err, "Failed to lock campaign")
+	}
 	d.logger.Debug().Int("campaign_id", reservedData.ID).Msg("Get campaign")
 
 	condition := repository.CampaignCondition{
@@ -266,11 +402,14 @@ func (d *deliveryEnd) end(ctx context.Context, startTime time.Time, reservedData
 	if err != nil {
 		return err
 	}
+	// 配信制御イベントを発行する
+	err = d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, deliveryData.ID, deliveryData.GroupID, deliveryData.OrgCode, deliveryData.Status, *afterStatus, "")
+	if err != nil {
+		return errors.Wrap(err, "Failed to publish campaign event")
+	}
 	if err := tx.Commit(); err != nil {
 		return errors.Wrap(err, "Failed to commit")
 	}
-	// 配信制御イベントを発行する
-	d.deliveryControlEvent.PublishCampaignEvent(ctx, deliveryData.ID, deliveryData.GroupID, deliveryData.OrgCode, deliveryData.Status, *afterStatus, "")
 	return nil
 }
 
@@ -286,7 +425,7 @@ func (d *deliveryEnd) handleTerminateStatus(
 	if err := d.Stop(ctx, tx, deliveryData, afterStatus); err != nil {
 		return nil, errors.Wrap(err, "Failed to delete process")
 	}
-	if err := d.Delete(ctx, deliveryData); err != nil {
+	if err := d.Delete(ctx, tx, deliveryData); err != nil {
 		return nil, errors.Wrap(err, "Failed to delete process")
 	}
 	return &afterStatus, nil
@@ -7,6 +7,7 @@ import (
 	"strconv"
 	"testing"
 	"time"
+	"touchgift-job-manager/codes"
 	"touchgift-job-manager/config"
 	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/domain/repository"
@@ -343,6 +344,7 @@ func TestDeliveryEnd_Execute_NotTERMINATE(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			tx.EXPECT().Rollback().Return(nil),
 		)
@@ -361,6 +363,31 @@ func TestDeliveryEnd_Execute_NotTERMINATE(t *testing.T) {
 		cancel()
 		deliveryEnd.Close()
 	})
+
+	t.Run("キャンペーンが存在しない場合はロールバックして終了", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1)).Return("", codes.ErrNoData),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryEnd := NewDeliveryEnd(
+			logger, metrics.GetMonitor(), &configE, &configUsecase, transactionHandler, NewTimer(logger),
+			nil, campaignRepository, nil, nil, nil, nil)
+		err := deliveryEnd.Execute(ctx, &models.Campaign{ID: 1})
+		assert.NoError(t, err)
+	})
 }
 
 // DeliveryEndのExecuteのテスト(配信終了)
@@ -413,7 +440,6 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 			GroupID: deliveryData.GroupID,
 			Limit:   100000,
 		}
-		groupIDStr := strconv.Itoa(deliveryData.GroupID)
 		touchPointID := "test"
 		storeID := "test_store"
 		touchPoints := []*models.TouchPoint{{ID: touchPointID, GroupID: deliveryData.GroupID, StoreID: storeID}}
@@ -421,18 +447,19 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
 			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
 			contentDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
 			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(deliveryData.GroupID)).Return(0, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&touchPointCondition)).Return(touchPoints, nil),
-			touchPointDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&touchPoints[0].ID), gomock.Eq(&groupIDStr)).Return(nil),
-			deliveryControlUsecase.EXPECT().PublishDeliveryEvent(gomock.Eq(ctx), gomock.Eq(touchPointID), gomock.Eq(deliveryData.GroupID), gomock.Eq(storeID), gomock.Eq(deliveryData.ID), gomock.Eq(deliveryData.OrgCode), gomock.Eq("DELETE")),
-			tx.EXPECT().Commit().Return(nil),
+			touchPointDataRepository.EXPECT().DeleteAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{{ID: touchPoints[0].ID, GroupID: touchPoints[0].GroupID, StoreID: touchPoints[0].StoreID}})).Return(nil),
+			deliveryControlUsecase.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]*models.DeliveryTouchPoint{{ID: touchPoints[0].ID, GroupID: touchPoints[0].GroupID, StoreID: touchPoints[0].StoreID}}), gomock.Eq(deliveryData.ID), gomock.Eq(deliveryData.OrgCode), gomock.Eq("DELETE")).Return(nil),
 			deliveryControlUsecase.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(deliveryData.ID), gomock.Eq(deliveryData.GroupID), gomock.Eq(deliveryData.OrgCode), gomock.Eq(deliveryData.Status), gomock.Eq(status), gomock.Eq(""),
-			),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID), gomock.Eq(deliveryData.GroupID), gomock.Eq(deliveryData.OrgCode), gomock.Eq(deliveryData.Status), gomock.Eq(status), gomock.Eq(""),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
@@ -483,15 +510,16 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
 			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
 			contentDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
 			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(deliveryData.GroupID)).Return(1, nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlUsecase.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(deliveryData.ID), gomock.Eq(deliveryData.GroupID), gomock.Eq(deliveryData.OrgCode), gomock.Eq(deliveryData.Status), gomock.Eq(status), gomock.Eq(""),
-			),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID), gomock.Eq(deliveryData.GroupID), gomock.Eq(deliveryData.OrgCode), gomock.Eq(deliveryData.Status), gomock.Eq(status), gomock.Eq(""),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
@@ -542,6 +570,7 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(0, errors.New("Failed to update")),
 			tx.EXPECT().Rollback().Return(nil),
@@ -584,6 +613,7 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
 		timer := mock_usecase.NewMockTimer(ctrl)
+		timer.EXPECT().Handle(gomock.Eq("end"), gomock.Any()).Times(1)
 
 		// mockの処理を定義
 		// テスト対象のexecuteは、 campaignDataRepository.Delete を使っているのでその処理を定義する
@@ -618,10 +648,11 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		// 何回呼ばれるか (Times)
 		// を定義する
 		gomock.InOrder(
-			timer.EXPECT().ExecuteAtTime(gomock.Eq(ctx), gomock.Eq(current), gomock.Any()).Do(func(ctx context.Context, specifiedTime time.Time, process func()) {
+			timer.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(&models.Reservation{CampaignID: campaign.ID, Action: "end", FireAt: current}), gomock.Any()).Do(func(ctx context.Context, reservation *models.Reservation, process func()) {
 				process()
 			}),
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			// campaignRepository.EXPECT().GetCampaignToEnd(gomock.Eq(ctx), gomock.Eq(&condition)).Return([]*models.Campaign{deliveryData}, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
@@ -656,6 +687,7 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
 		timer := mock_usecase.NewMockTimer(ctrl)
+		timer.EXPECT().Handle(gomock.Eq("end"), gomock.Any()).Times(1)
 
 		// mockの処理を定義
 		// テスト対象のexecuteは、 campaignDataRepository.Delete を使っているのでその処理を定義する
@@ -690,10 +722,11 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		// 何回呼ばれるか (Times)
 		// を定義する
 		gomock.InOrder(
-			timer.EXPECT().ExecuteAtTime(gomock.Eq(ctx), gomock.Eq(current), gomock.Any()).Do(func(ctx context.Context, specifiedTime time.Time, process func()) {
+			timer.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(&models.Reservation{CampaignID: campaign.ID, Action: "end", FireAt: current}), gomock.Any()).Do(func(ctx context.Context, reservation *models.Reservation, process func()) {
 				process()
 			}),
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
 			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
@@ -728,6 +761,7 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
 		timer := mock_usecase.NewMockTimer(ctrl)
+		timer.EXPECT().Handle(gomock.Eq("end"), gomock.Any()).Times(1)
 
 		// mockの処理を定義
 		// テスト対象のexecuteは、 campaignDataRepository.Delete を使っているのでその処理を定義する
@@ -762,10 +796,11 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		// 何回呼ばれるか (Times)
 		// を定義する
 		gomock.InOrder(
-			timer.EXPECT().ExecuteAtTime(gomock.Eq(ctx), gomock.Eq(current), gomock.Any()).Do(func(ctx context.Context, specifiedTime time.Time, process func()) {
+			timer.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(&models.Reservation{CampaignID: campaign.ID, Action: "end", FireAt: current}), gomock.Any()).Do(func(ctx context.Context, reservation *models.Reservation, process func()) {
 				process()
 			}),
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
 			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
@@ -801,6 +836,7 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
 		timer := mock_usecase.NewMockTimer(ctrl)
+		timer.EXPECT().Handle(gomock.Eq("end"), gomock.Any()).Times(1)
 
 		// mockの処理を定義
 		// テスト対象のexecuteは、 campaignDataRepository.Delete を使っているのでその処理を定義する
@@ -839,10 +875,11 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		// 何回呼ばれるか (Times)
 		// を定義する
 		gomock.InOrder(
-			timer.EXPECT().ExecuteAtTime(gomock.Eq(ctx), gomock.Eq(current), gomock.Any()).Do(func(ctx context.Context, specifiedTime time.Time, process func()) {
+			timer.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(&models.Reservation{CampaignID: campaign.ID, Action: "end", FireAt: current}), gomock.Any()).Do(func(ctx context.Context, reservation *models.Reservation, process func()) {
 				process()
 			}),
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
 			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
@@ -879,6 +916,7 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
 		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
 		timer := mock_usecase.NewMockTimer(ctrl)
+		timer.EXPECT().Handle(gomock.Eq("end"), gomock.Any()).Times(1)
 
 		// mockの処理を定義
 		// テスト対象のexecuteは、 campaignDataRepository.Delete を使っているのでその処理を定義する
@@ -914,24 +952,24 @@ func TestDeliveryEnd_Execute_DeliveryEnd(t *testing.T) {
 			Limit:   100000,
 		}
 		id := strconv.Itoa(deliveryData.ID)
-		groupIDStr := strconv.Itoa(deliveryData.GroupID)
 		touchPointID := "test"
 		storeID := "test_store"
 		touchPoints := []*models.TouchPoint{{ID: touchPointID, GroupID: deliveryData.GroupID, StoreID: storeID}}
 		// 何回呼ばれるか (Times)
 		// を定義する
 		gomock.InOrder(
-			timer.EXPECT().ExecuteAtTime(gomock.Eq(ctx), gomock.Eq(current), gomock.Any()).Do(func(ctx context.Context, specifiedTime time.Time, process func()) {
+			timer.EXPECT().Reserve(gomock.Eq(ctx), gomock.Eq(&models.Reservation{CampaignID: campaign.ID, Action: "end", FireAt: current}), gomock.Any()).Do(func(ctx context.Context, reservation *models.Reservation, process func()) {
 				process()
 			}),
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID)).Return(deliveryData.Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
 			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
 			contentDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
 			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(deliveryData.GroupID)).Return(0, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&touchPointCondition)).Return(touchPoints, nil),
-			touchPointDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&touchPoints[0].ID), gomock.Eq(&groupIDStr)).Return(errors.New("Failed to delete")),
+			touchPointDataRepository.EXPECT().DeleteAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{{ID: touchPoints[0].ID, GroupID: touchPoints[0].GroupID, StoreID: touchPoints[0].StoreID}})).Return(errors.New("Failed to delete")),
 			tx.EXPECT().Rollback().Return(nil),
 		)
 
@@ -959,3 +997,268 @@ func createEndTestCampaign(campaign *models.Campaign, startAt time.Time, endAt s
 		OrgCode:   "org1",
 	}
 }
+
+// DeliveryEndのStopStartedのテスト
+func TestDeliveryEnd_StopStarted(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	// テスト用の設定
+	configE := config.Env.DeliveryEnd
+	configUsecase := config.Env.DeliveryEndUsecase
+
+	t.Run("startedのキャンペーンはstoppedに更新して配信データを削除し、配信制御イベントを登録する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		timer := NewTimer(logger)
+
+		// mockの処理を定義
+		// 引数に渡ると想定される値
+		ctx := context.Background()
+		campaign := models.Campaign{ID: 1, GroupID: 2, StartAt: time.Now(), EndAt: sql.NullTime{Time: time.Now().Add(10 * time.Minute), Valid: true}, UpdatedAt: time.Now()}
+		deliveryData := createEndTestCampaign(&campaign, campaign.StartAt, campaign.EndAt, "started", campaign.UpdatedAt)
+		condition := repository.CampaignCondition{
+			CampaignID: campaign.ID,
+		}
+		id := strconv.Itoa(deliveryData.ID)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return("started", nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
+			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+				func(_ context.Context, _ repository.Transaction, condition *repository.UpdateCondition) (int, error) {
+					assert.Equal(t, campaign.ID, condition.CampaignID)
+					assert.Equal(t, "stopped", condition.Status)
+					return 1, nil
+				}),
+			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
+			contentDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil),
+			// 同じグループに配信中のキャンペーンがあるのでタッチポイントは削除しない
+			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(deliveryData.GroupID)).Return(1, nil),
+			deliveryControlUsecase.EXPECT().PublishCampaignEvent(
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData.ID), gomock.Eq(deliveryData.GroupID), gomock.Eq(deliveryData.OrgCode), gomock.Eq("started"), gomock.Eq("stopped"), gomock.Eq(""),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryEnd := NewDeliveryEnd(
+			logger, metrics.GetMonitor(), &configE, &configUsecase, transactionHandler, timer,
+			deliveryControlUsecase, campaignRepository, campaignDataRepository, contentDataRepository, touchPointDataRepository, touchPointRepository)
+		err := deliveryEnd.StopStarted(ctx, campaign.ID)
+		assert.NoError(t, err)
+	})
+
+	t.Run("started以外のキャンペーンは何も更新せずにエラーを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		timer := NewTimer(logger)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1)).Return("terminate", nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryEnd := NewDeliveryEnd(
+			logger, metrics.GetMonitor(), &configE, &configUsecase, transactionHandler, timer,
+			deliveryControlUsecase, campaignRepository, campaignDataRepository, contentDataRepository, touchPointDataRepository, touchPointRepository)
+		err := deliveryEnd.StopStarted(ctx, 1)
+		assert.EqualError(t, err, "campaign other than started. status: terminate")
+	})
+
+	t.Run("配信データの削除に失敗した場合はロールバックしてエラーを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		deliveryControlUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		timer := NewTimer(logger)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		campaign := models.Campaign{ID: 1, GroupID: 2, StartAt: time.Now(), UpdatedAt: time.Now()}
+		deliveryData := createEndTestCampaign(&campaign, campaign.StartAt, campaign.EndAt, "started", campaign.UpdatedAt)
+		condition := repository.CampaignCondition{
+			CampaignID: campaign.ID,
+		}
+		id := strconv.Itoa(deliveryData.ID)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return("started", nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData, nil),
+			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(1, nil),
+			campaignDataRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(&id)).Return(errors.New("dynamodb error")),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryEnd := NewDeliveryEnd(
+			logger, metrics.GetMonitor(), &configE, &configUsecase, transactionHandler, timer,
+			deliveryControlUsecase, campaignRepository, campaignDataRepository, contentDataRepository, touchPointDataRepository, touchPointRepository)
+		err := deliveryEnd.StopStarted(ctx, campaign.ID)
+		assert.EqualError(t, err, "Failed to delete deliveryData: dynamodb error")
+	})
+}
+
+func TestDeliveryEnd_Plan(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	configE := config.Env.DeliveryEnd
+	configUsecase := config.Env.DeliveryEndUsecase
+	campaign := &models.Campaign{ID: 1, GroupID: 2, OrgCode: "org1", Status: codes.StatusTerminate, UpdatedAt: time.Now()}
+	touchPoints := []*models.TouchPoint{{ID: "test", GroupID: campaign.GroupID, StoreID: "test_store"}}
+
+	t.Run("削除する配信データと配信制御イベントを返し、ステータスは更新しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		// 配信データ・outboxからは削除しない
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		// ステータスの更新(UpdateStatus)はしない
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx),
+				gomock.Eq(&repository.CampaignCondition{CampaignID: campaign.ID})).Return(campaign, nil),
+			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(campaign.GroupID)).Return(0, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return(touchPoints, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		deliveryEnd := NewDeliveryEnd(
+			logger, metrics.GetMonitor(), &configE, &configUsecase, transactionHandler, NewTimer(logger),
+			deliveryControlEventUsecase, campaignRepository, campaignDataRepository, contentDataRepository, touchPointDataRepository, touchPointRepository)
+		plan, err := deliveryEnd.Plan(ctx, campaign.ID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, codes.PlanActionEnd, plan.Action)
+		assert.Equal(t, codes.StatusEnded, plan.AfterStatus)
+		tables := []string{}
+		for _, item := range plan.Items {
+			assert.Equal(t, codes.PlanOperationDelete, item.Operation)
+			tables = append(tables, item.Table)
+		}
+		assert.Equal(t, []string{codes.PlanTableCampaign, codes.PlanTableContent, codes.PlanTableTouchPoint}, tables)
+		eventTypes := []string{}
+		for _, message := range plan.Messages {
+			eventTypes = append(eventTypes, message.EventType)
+		}
+		assert.Equal(t, []string{codes.OutboxEventDelivery, codes.OutboxEventCampaign}, eventTypes)
+	})
+
+	t.Run("配信中のキャンペーンの場合は自身を除いた配信中のキャンペーン数でタッチポイントを削除するか判定する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		started := &models.Campaign{ID: 1, GroupID: 2, OrgCode: "org1", Status: codes.StatusStarted, UpdatedAt: time.Now()}
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(started, nil),
+			// RDBは更新していないため自身も配信中として数えられる
+			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(started.GroupID)).Return(1, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return(touchPoints, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		deliveryEnd := NewDeliveryEnd(
+			logger, metrics.GetMonitor(), &configE, &configUsecase, transactionHandler, NewTimer(logger),
+			deliveryControlEventUsecase, campaignRepository, mock_repository.NewMockDeliveryDataCampaignRepository(ctrl),
+			mock_repository.NewMockDeliveryDataContentRepository(ctrl), mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl), touchPointRepository)
+		plan, err := deliveryEnd.Plan(ctx, started.ID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tables := []string{}
+		for _, item := range plan.Items {
+			tables = append(tables, item.Table)
+		}
+		assert.Equal(t, []string{codes.PlanTableCampaign, codes.PlanTableContent, codes.PlanTableTouchPoint}, tables)
+	})
+
+	t.Run("同じグループに他の配信中のキャンペーンがある場合はタッチポイントを削除しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		started := &models.Campaign{ID: 1, GroupID: 2, OrgCode: "org1", Status: codes.StatusStarted, UpdatedAt: time.Now()}
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(started, nil),
+			// 自身ともう1つのキャンペーン
+			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(started.GroupID)).Return(2, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		deliveryEnd := NewDeliveryEnd(
+			logger, metrics.GetMonitor(), &configE, &configUsecase, transactionHandler, NewTimer(logger),
+			deliveryControlEventUsecase, campaignRepository, mock_repository.NewMockDeliveryDataCampaignRepository(ctrl),
+			mock_repository.NewMockDeliveryDataContentRepository(ctrl), mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl),
+			mock_repository.NewMockTouchPointRepository(ctrl))
+		plan, err := deliveryEnd.Plan(ctx, started.ID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		tables := []string{}
+		for _, item := range plan.Items {
+			tables = append(tables, item.Table)
+		}
+		assert.Equal(t, []string{codes.PlanTableCampaign, codes.PlanTableContent}, tables)
+	})
+}
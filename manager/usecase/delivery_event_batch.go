@@ -0,0 +1,116 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/pkg/errors"
+)
+
+// deliveryEventBatch タッチポイントのキャッシュイベントをまとめて1つのメッセージとしてoutboxに登録する
+// サイズ/件数の上限を超える場合、actionが変わる場合、最初の追加からFlushIntervalが経過した場合はそれまでのイベントを登録する
+type deliveryEventBatch struct {
+	logger               Logger
+	config               *config.DeliveryEventBatch
+	deliveryControlEvent *deliveryControlEvent
+	tx                   repository.Transaction
+	action               string
+	records              []models.DeliveryCacheLog
+	size                 int
+	startedAt            time.Time
+}
+
+// {"version":2,"action":"DELETE","records":[]} の長さ (レコードの区切り文字を除く)
+var deliveryCacheBatchLogOverhead = func() int {
+	body, _ := json.Marshal(&models.DeliveryCacheBatchLog{
+		Version: codes.DeliveryCacheLogVersionBatch,
+		Action:  "DELETE",
+		Records: []models.DeliveryCacheLog{},
+	})
+	return len(body)
+}()
+
+// add イベントを追加する
+func (d *deliveryEventBatch) add(ctx context.Context,
+	id string, groupID int, storeID string, campaignID int, organization string, action string) error {
+	record := d.deliveryControlEvent.createDeliveryEventLog(id, groupID, storeID, organization, campaignID, action)
+	body, err := json.Marshal(record)
+	if err != nil {
+		return errors.Wrap(err, "Failed to marshal json")
+	}
+	// 1件で上限を超える場合はそのイベントのみ登録できない
+	if deliveryCacheBatchLogOverhead+len(body) > d.config.MaxBytes {
+		d.logger.Error().
+			Str("touchpoint_id", id).
+			Int("size", len(body)).
+			Int("max_bytes", d.config.MaxBytes).
+			Msg("Delivery cache event exceeds max bytes")
+		return errors.Errorf("Delivery cache event exceeds max bytes. touchpoint_id: %s", id)
+	}
+	if len(d.records) > 0 &&
+		(d.action != action ||
+			len(d.records) >= d.config.MaxRecords ||
+			d.size+len(body)+1 > d.config.MaxBytes) {
+		if err := d.flush(ctx); err != nil {
+			return err
+		}
+	}
+	if len(d.records) == 0 {
+		d.action = action
+		d.size = deliveryCacheBatchLogOverhead
+		d.startedAt = time.Now()
+	} else {
+		// レコードの区切り文字(,)
+		d.size++
+	}
+	d.records = append(d.records, *record)
+	d.size += len(body)
+	if time.Since(d.startedAt) >= d.config.FlushInterval {
+		return d.flush(ctx)
+	}
+	return nil
+}
+
+// flush 未登録のイベントを登録する
+func (d *deliveryEventBatch) flush(ctx context.Context) error {
+	if len(d.records) == 0 {
+		return nil
+	}
+	batchLog := &models.DeliveryCacheBatchLog{
+		Version: codes.DeliveryCacheLogVersionBatch,
+		Action:  d.action,
+		Records: d.records,
+	}
+	messageAttributes := map[string]string{
+		"action":  d.action,
+		"version": strconv.Itoa(codes.DeliveryCacheLogVersionBatch),
+	}
+	outboxID, err := d.deliveryControlEvent.enqueue(ctx, d.tx, codes.OutboxEventDelivery, batchLog, messageAttributes, config.Env.SNS.DeliveryCacheTopicArn)
+	if err != nil {
+		// 登録できなかったイベントを特定できるようにタッチポイントIDを出力する
+		ids := make([]string, 0, len(d.records))
+		for _, record := range d.records {
+			ids = append(ids, record.ID)
+		}
+		d.logger.Error().Err(err).
+			Strs("touchpoint_ids", ids).
+			Str("action", d.action).
+			Msg("Failed to delivery cache sns publish")
+		return err
+	}
+	d.logger.Info().
+		Int64("outbox_id", outboxID).
+		Str("action", d.action).
+		Int("records", len(d.records)).
+		Int("size", d.size).
+		Msg("Publish delivery control events")
+	d.records = nil
+	d.size = 0
+	return nil
+}
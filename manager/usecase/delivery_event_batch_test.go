@@ -0,0 +1,204 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	mock_repository "touchgift-job-manager/mock/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDeliveryControlEvent_PublishDeliveryEvents(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	ctx := context.Background()
+
+	touchPoints := func(n int) []*models.DeliveryTouchPoint {
+		tps := make([]*models.DeliveryTouchPoint, 0, n)
+		for i := 0; i < n; i++ {
+			tps = append(tps, &models.DeliveryTouchPoint{ID: "tp" + strconv.Itoa(i), GroupID: 1, StoreID: "store1"})
+		}
+		return tps
+	}
+	// outboxに登録されたメッセージのレコード数を記録する
+	saveBatch := func(t *testing.T, sizes *[]int) func(context.Context, repository.Transaction, *models.OutboxEvent) error {
+		return func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+			assert.Equal(t, codes.OutboxEventDelivery, event.EventType)
+			assert.Equal(t, config.Env.SNS.DeliveryCacheTopicArn, event.TopicArn)
+			attributes, err := event.MessageAttributes()
+			if assert.NoError(t, err) {
+				assert.Equal(t, map[string]string{"action": "PUT", "version": "2"}, attributes)
+			}
+			message := models.DeliveryCacheBatchLog{}
+			if assert.NoError(t, json.Unmarshal([]byte(event.Message), &message)) {
+				assert.Equal(t, codes.DeliveryCacheLogVersionBatch, message.Version)
+				assert.Equal(t, "PUT", message.Action)
+			}
+			*sizes = append(*sizes, len(message.Records))
+			return nil
+		}
+	}
+
+	t.Run("件数の上限ごとに1つのメッセージにまとめて登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		var sizes []int
+		outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(saveBatch(t, &sizes)).Times(3)
+
+		batchConfig := &config.DeliveryEventBatch{Enabled: true, MaxBytes: 240000, MaxRecords: 2, FlushInterval: time.Minute}
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, batchConfig, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishDeliveryEvents(ctx, tx, touchPoints(5), 1, "org1", "PUT")
+		assert.NoError(t, err)
+		assert.Equal(t, []int{2, 2, 1}, sizes)
+	})
+
+	t.Run("サイズの上限を超える場合は分割して登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		var sizes []int
+		var maxSize int
+		outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+				if len(event.Message) > maxSize {
+					maxSize = len(event.Message)
+				}
+				return saveBatch(t, &sizes)(ctx, tx, event)
+			}).AnyTimes()
+
+		// 1レコードは200byte程度のため、3件までしか入らない
+		batchConfig := &config.DeliveryEventBatch{Enabled: true, MaxBytes: 700, MaxRecords: 1000, FlushInterval: time.Minute}
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, batchConfig, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishDeliveryEvents(ctx, tx, touchPoints(10), 1, "org1", "PUT")
+		assert.NoError(t, err)
+		assert.Greater(t, len(sizes), 1)
+		assert.LessOrEqual(t, maxSize, 700)
+		total := 0
+		for _, size := range sizes {
+			total += size
+		}
+		assert.Equal(t, 10, total)
+	})
+
+	t.Run("1件で上限を超える場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		batchConfig := &config.DeliveryEventBatch{Enabled: true, MaxBytes: 100, MaxRecords: 1000, FlushInterval: time.Minute}
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, batchConfig, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishDeliveryEvents(ctx, tx, touchPoints(1), 1, "org1", "PUT")
+		assert.Error(t, err)
+	})
+
+	t.Run("outboxへの登録に失敗した場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(errors.New("error"))
+
+		batchConfig := &config.DeliveryEventBatch{Enabled: true, MaxBytes: 240000, MaxRecords: 1000, FlushInterval: time.Minute}
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, batchConfig, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishDeliveryEvents(ctx, tx, touchPoints(3), 1, "org1", "PUT")
+		assert.Error(t, err)
+	})
+
+	t.Run("バッチが無効の場合は1件ずつ従来の形式で登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+				attributes, err := event.MessageAttributes()
+				if assert.NoError(t, err) {
+					assert.Equal(t, map[string]string{"action": "PUT"}, attributes)
+				}
+				message := models.DeliveryCacheLog{}
+				if assert.NoError(t, json.Unmarshal([]byte(event.Message), &message)) {
+					assert.Equal(t, "store1", message.StoreID)
+				}
+				return nil
+			}).Times(3)
+
+		batchConfig := &config.DeliveryEventBatch{Enabled: false}
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, batchConfig, outboxRepository, NewTestTimezone(t))
+		err := deliveryControlEventUsecase.PublishDeliveryEvents(ctx, tx, touchPoints(3), 1, "org1", "PUT")
+		assert.NoError(t, err)
+	})
+}
+
+func TestDeliveryEventBatch_add(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	ctx := context.Background()
+
+	t.Run("actionが変わる場合はそれまでのイベントを登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		var actions []string
+		outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+				message := models.DeliveryCacheBatchLog{}
+				assert.NoError(t, json.Unmarshal([]byte(event.Message), &message))
+				actions = append(actions, message.Action)
+				return nil
+			}).Times(2)
+
+		batchConfig := &config.DeliveryEventBatch{Enabled: true, MaxBytes: 240000, MaxRecords: 1000, FlushInterval: time.Minute}
+		batch := &deliveryEventBatch{
+			logger:               logger,
+			config:               batchConfig,
+			deliveryControlEvent: NewDeliveryControlEvent(logger, batchConfig, outboxRepository, NewTestTimezone(t)).(*deliveryControlEvent),
+			tx:                   tx,
+		}
+		assert.NoError(t, batch.add(ctx, "tp1", 1, "store1", 1, "org1", "PUT"))
+		assert.NoError(t, batch.add(ctx, "tp2", 1, "store1", 1, "org1", "PUT"))
+		assert.NoError(t, batch.add(ctx, "tp1", 1, "store1", 1, "org1", "DELETE"))
+		assert.NoError(t, batch.flush(ctx))
+		assert.Equal(t, []string{"PUT", "DELETE"}, actions)
+	})
+
+	t.Run("最初の追加からFlushIntervalが経過した場合は登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(nil).Times(1)
+
+		batchConfig := &config.DeliveryEventBatch{Enabled: true, MaxBytes: 240000, MaxRecords: 1000, FlushInterval: 0}
+		batch := &deliveryEventBatch{
+			logger:               logger,
+			config:               batchConfig,
+			deliveryControlEvent: NewDeliveryControlEvent(logger, batchConfig, outboxRepository, NewTestTimezone(t)).(*deliveryControlEvent),
+			tx:                   tx,
+		}
+		assert.NoError(t, batch.add(ctx, "tp1", 1, "store1", 1, "org1", "PUT"))
+		assert.Empty(t, batch.records)
+		// 登録済みのため何もしない
+		assert.NoError(t, batch.flush(ctx))
+	})
+}
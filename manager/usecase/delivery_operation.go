@@ -3,16 +3,23 @@ package usecase
 
 import (
 	"context"
+	"strings"
 	"time"
 	"touchgift-job-manager/codes"
 	"touchgift-job-manager/domain/models"
 	"touchgift-job-manager/domain/repository"
 	"touchgift-job-manager/infra/metrics"
+
+	"github.com/pkg/errors"
 )
 
 type DeliveryOperation interface {
 	// キャンペーンのログを処理する
 	Process(ctx context.Context, current time.Time, campaignLog *models.CampaignLog) error
+	// 入稿物の審査ステータス変更ログを処理する (紐づく配信中キャンペーンの配信データを作り直す)
+	ProcessAsset(ctx context.Context, current time.Time, assetLog *models.AssetLog) error
+	// 入稿物が紐づくキャンペーンのIDを返す (ステータスに関わらない)
+	AssetCampaignIDs(ctx context.Context, assetLog *models.AssetLog) ([]int, error)
 }
 
 type deliveryOperation struct {
@@ -25,6 +32,8 @@ type deliveryOperation struct {
 	deliveryStart          DeliveryStart
 	deliveryEnd            DeliveryEnd
 	deliveryControlEvent   DeliveryControlEvent
+	prewarmRepository      repository.PrewarmRepository
+	timer                  Timer
 }
 
 func NewDeliveryOperation(
@@ -37,6 +46,8 @@ func NewDeliveryOperation(
 	deliveryStart DeliveryStart,
 	deliveryEnd DeliveryEnd,
 	deliveryControlEvent DeliveryControlEvent,
+	prewarmRepository repository.PrewarmRepository,
+	timer Timer,
 ) DeliveryOperation {
 	instance := deliveryOperation{
 		logger:                 logger,
@@ -48,6 +59,8 @@ func NewDeliveryOperation(
 		deliveryStart:          deliveryStart,
 		deliveryEnd:            deliveryEnd,
 		deliveryControlEvent:   deliveryControlEvent,
+		prewarmRepository:      prewarmRepository,
+		timer:                  timer,
 	}
 	// TODO:メトリクスの追加: どれだけデータが処理されたか
 	// monitor.Metrics.AddCounter(metricDynamodbPutTotal, metricDynamodbPutTotalDesc, metricDynamodbPutTotalLabels)
@@ -82,12 +95,14 @@ func (d *deliveryOperation) Process(ctx context.Context, current time.Time, camp
 			if err := d.creative.Process(ctx, tx, current, &campaignLog.Creatives); err != nil {
 				return err
 			}
+			// 配信制御イベントを発行する
+			if err := d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode, *beforeStatus, *afterStatus, ""); err != nil {
+				return err
+			}
 			if err := tx.Commit(); err != nil {
 				d.logger.Error().Err(err).Time("current", current).Msg("Failed to commit")
 				return err
 			}
-			// 配信制御イベントを発行する
-			d.deliveryControlEvent.PublishCampaignEvent(ctx, campaign.ID, campaign.GroupID, campaign.OrgCode, *beforeStatus, *afterStatus, "")
 		}
 	case "delete":
 		// キャンペーンの物理削除は配信後には起きないためdelivery_data削除はしない
@@ -113,6 +128,105 @@ func (d *deliveryOperation) Process(ctx context.Context, current time.Time, camp
 	return nil
 }
 
+func (d *deliveryOperation) ProcessAsset(ctx context.Context, current time.Time, assetLog *models.AssetLog) (err error) {
+	var tx repository.Transaction
+	defer func() {
+		if err != nil && tx != nil {
+			if result := tx.Rollback(); result != nil {
+				d.logger.Error().Err(result).Time("current", current).Msg("Failed to rollback")
+			}
+		}
+	}()
+	campaigns, err := d.campaignRepository.GetCampaignByAsset(ctx, &repository.CampaignByAssetCondition{
+		Kind:    assetLog.Kind,
+		AssetID: assetLog.ID,
+		Status:  []string{codes.StatusStarted, codes.StatusWarmup},
+	})
+	if err != nil {
+		return err
+	}
+	tx, err = d.transaction.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	// 配信データは審査OKの入稿物のみで作成するため、作り直すことで追加/削除される
+	for _, campaign := range campaigns {
+		if campaign.Status == codes.StatusWarmup {
+			// 事前作成した配信データは変更前の入稿物のため破棄し、開始時に全ての配信データを作り直す
+			if err := d.discardPrewarm(ctx, tx, campaign.ID); err != nil {
+				return err
+			}
+			d.logger.Info().
+				Time("current", current).
+				Str("kind", assetLog.Kind).
+				Int("asset_id", assetLog.ID).
+				Str("asset_status", assetLog.Status).
+				Int("campaign_id", campaign.ID).
+				Msg("Discard prewarmed delivery data by asset status")
+			continue
+		}
+		if err := d.refreshContents(ctx, tx, campaign); err != nil {
+			return err
+		}
+		d.logger.Info().
+			Time("current", current).
+			Str("kind", assetLog.Kind).
+			Int("asset_id", assetLog.ID).
+			Str("asset_status", assetLog.Status).
+			Int("campaign_id", campaign.ID).
+			Msg("Refresh delivery data by asset status")
+	}
+	// 審査OKでなくなったクリエイティブはどのキャンペーンの配信データからも外れるため有効期限を設定する
+	if assetLog.Kind == codes.AssetKindCreative && assetLog.Status != codes.ReviewStatusApproved {
+		if err := d.creative.Expire(ctx, current, assetLog.ID); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		d.logger.Error().Err(err).Time("current", current).Msg("Failed to commit")
+		return err
+	}
+	return nil
+}
+
+func (d *deliveryOperation) AssetCampaignIDs(ctx context.Context, assetLog *models.AssetLog) ([]int, error) {
+	campaigns, err := d.campaignRepository.GetCampaignByAsset(ctx, &repository.CampaignByAssetCondition{
+		Kind:    assetLog.Kind,
+		AssetID: assetLog.ID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	campaignIDs := make([]int, 0, len(campaigns))
+	for _, campaign := range campaigns {
+		campaignIDs = append(campaignIDs, campaign.ID)
+	}
+	return campaignIDs, nil
+}
+
+// 配信中キャンペーンの配信データを作り直す
+// 入稿物が外れたことで配信内容が不正になった場合(審査OKのクーポンがなくなった等)は一時停止する
+func (d *deliveryOperation) refreshContents(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) error {
+	err := d.deliveryStart.CreateDeliveryDatas(ctx, tx, campaign)
+	var validationErr *models.CampaignValidationError
+	if errors.As(err, &validationErr) {
+		if err := d.deliveryEnd.Stop(ctx, tx, campaign, codes.StatusPaused); err != nil {
+			return err
+		}
+		if err := d.deliveryEnd.Delete(ctx, tx, campaign); err != nil {
+			return err
+		}
+		d.logger.Warn().Int("campaign_id", campaign.ID).Strs("reasons", validationErr.Reasons).Msg("Pause invalid campaign")
+		return d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode,
+			codes.StatusStarted, codes.StatusPaused, strings.Join(validationErr.Reasons, ","))
+	}
+	if err != nil {
+		return err
+	}
+	return d.deliveryControlEvent.PublishCampaignEvent(ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode,
+		codes.StatusStarted, codes.StatusStarted, "")
+}
+
 // キャンペーンログの処理
 func (d *deliveryOperation) processCampaignLog(ctx context.Context,
 	tx repository.Transaction, current time.Time, campaign *models.CampaignLog,
@@ -147,11 +261,15 @@ func (d *deliveryOperation) sync(
 	switch campaign.Status {
 	// 配信中 または 配信再開
 	case codes.StatusStarted, codes.StatusResume:
+		d.cancelReservations(ctx, campaign.ID, codes.ReservationActionStart)
 		// 配信データを登録(更新)する
 		_, err := d.deliveryStart.UpdateStatus(ctx, tx, campaign, codes.StatusStarted)
 		if err != nil {
 			return campaign.Status, "", err
 		}
+		if err := d.discardPrewarm(ctx, tx, campaign.ID); err != nil {
+			return campaign.Status, "", err
+		}
 		err = d.deliveryStart.CreateDeliveryDatas(ctx, tx, campaign)
 		if err != nil {
 			return campaign.Status, "", err
@@ -159,27 +277,67 @@ func (d *deliveryOperation) sync(
 		return campaign.Status, codes.StatusStarted, nil
 		// 配信一時停止
 	case codes.StatusPause:
+		d.cancelReservations(ctx, campaign.ID, codes.ReservationActionStart, codes.ReservationActionEnd)
 		err := d.deliveryEnd.Stop(ctx, tx, campaign, codes.StatusPaused)
 		if err != nil {
 			return campaign.Status, "", err
 		}
-		return campaign.Status, codes.StatusPaused, d.deliveryEnd.Delete(ctx, campaign)
+		if err := d.discardPrewarm(ctx, tx, campaign.ID); err != nil {
+			return campaign.Status, "", err
+		}
+		return campaign.Status, codes.StatusPaused, d.deliveryEnd.Delete(ctx, tx, campaign)
 	// 配信停止
 	case codes.StatusStop:
+		d.cancelReservations(ctx, campaign.ID, codes.ReservationActionStart, codes.ReservationActionEnd)
 		err := d.deliveryEnd.Stop(ctx, tx, campaign, codes.StatusStopped)
 		if err != nil {
 			return campaign.Status, "", err
 		}
-		return campaign.Status, codes.StatusStopped, d.deliveryEnd.Delete(ctx, campaign)
+		if err := d.discardPrewarm(ctx, tx, campaign.ID); err != nil {
+			return campaign.Status, "", err
+		}
+		return campaign.Status, codes.StatusStopped, d.deliveryEnd.Delete(ctx, tx, campaign)
 		// 配信終了済
 	case codes.StatusEnded:
+		d.cancelReservations(ctx, campaign.ID, codes.ReservationActionStart, codes.ReservationActionEnd)
 		// DynamoDBから削除(campaign.statusの更新はしない)
-		return campaign.Status, codes.StatusEnded, d.deliveryEnd.Delete(ctx, campaign)
+		if err := d.discardPrewarm(ctx, tx, campaign.ID); err != nil {
+			return campaign.Status, "", err
+		}
+		return campaign.Status, codes.StatusEnded, d.deliveryEnd.Delete(ctx, tx, campaign)
 	case codes.StatusSuspend, codes.StatusConfigured:
 		// 未配信のため何もしない
 		return campaign.Status, "", codes.ErrDoNothing
+	// 配信開始前
+	case codes.StatusWarmup:
+		// 開始時間が変更されている場合があるため、開始処理の予約は取り消して開始の監視で予約し直す
+		d.cancelReservations(ctx, campaign.ID, codes.ReservationActionStart)
+		// 事前作成した配信データは変更前の内容のため、開始時に全ての配信データを作り直す
+		// 事前作成時に保留したイベントも変更前の内容のため破棄する
+		// (ロールバックするためトランザクション外で削除する)
+		if err := d.discardPrewarm(ctx, nil, campaign.ID); err != nil {
+			return campaign.Status, "", err
+		}
+		return campaign.Status, "", codes.ErrDoNothing
 	default:
 		d.logger.Error().Interface("delivery_data", *campaign).Msg("Unknown campaign status")
 		return campaign.Status, "", codes.ErrDoNothing
 	}
 }
+
+// 配信開始前(warmup)に事前作成した記録と保留したイベントを破棄する
+// 記録が残っていると開始時に変更前の配信データのまま配信されてしまうため、warmupから外れる全ての経路で呼び出す
+func (d *deliveryOperation) discardPrewarm(ctx context.Context, tx repository.Transaction, campaignID int) error {
+	if _, err := d.prewarmRepository.Delete(ctx, tx, campaignID); err != nil {
+		return err
+	}
+	return d.deliveryControlEvent.DiscardHeldEvents(ctx, tx, campaignID)
+}
+
+// 変更前の内容で予約した開始/終了処理を取り消す
+// (ロールバックされた場合も、開始/終了の対象であれば監視で予約し直される)
+func (d *deliveryOperation) cancelReservations(ctx context.Context, campaignID int, actions ...string) {
+	for _, action := range actions {
+		d.timer.Cancel(ctx, campaignID, action)
+	}
+}
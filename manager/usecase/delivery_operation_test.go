@@ -34,6 +34,8 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
@@ -70,7 +72,55 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
+		err := deliveryOperationUsecase.Process(ctx, current, campaignLog)
+		assert.EqualError(t, err, codes.ErrDoNothing.Error())
+	})
+
+	t.Run("ステータスがwarmupの場合、事前作成の記録を削除して終了", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
+		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
+		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		tx := mock_repository.NewMockTransaction(ctrl)
+		current := time.Now()
+
+		ctx := context.Background()
+		campaignLog := createTestDeliveryOperationLog("update")
+		campaignData := models.DeliveryDataCampaign{
+			ID: "1",
+		}
+		campaign := createSyncTestDeliveryOperation(
+			&campaignData,
+			time.Now().Add(5*time.Minute),
+			sql.NullTime{Time: time.Now().Add(10 * time.Minute), Valid: true},
+			time.Now().Add(1*time.Second),
+			codes.StatusWarmup)
+		condition := repository.CampaignCondition{
+			CampaignID: campaign.ID,
+		}
+
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
+			// ロールバックされないようにトランザクション外で削除する
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Nil(), gomock.Eq(campaign.ID)).Return(1, nil),
+			// 事前作成時に保留したイベントも破棄する
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Nil(), gomock.Eq(campaign.ID)).Return(nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, campaignLog)
 		assert.EqualError(t, err, codes.ErrDoNothing.Error())
 	})
@@ -82,6 +132,8 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 
 		// 必要なmockを作成
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
@@ -117,18 +169,21 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
 			deliveryStartUsecase.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
 			deliveryStartUsecase.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&campaignLog.Creatives)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEvent.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
 				gomock.Eq(after), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, campaignLog)
 		assert.NoError(t, err)
 	})
@@ -140,6 +195,8 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 
 		// 必要なmockを作成
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
@@ -175,18 +232,21 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
 			deliveryStartUsecase.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
 			deliveryStartUsecase.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&campaignLog.Creatives)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEvent.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
 				gomock.Eq(codes.StatusStarted), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, campaignLog)
 		assert.NoError(t, err)
 	})
@@ -199,6 +259,8 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
@@ -229,18 +291,21 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
 			deliveryStartUsecase.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
 			deliveryStartUsecase.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&campaignLog.Creatives)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEvent.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
 				gomock.Eq(codes.StatusStarted), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, campaignLog)
 		assert.NoError(t, err)
 	})
@@ -253,6 +318,8 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
@@ -284,18 +351,21 @@ func TestDeliveryOperation_Process_Sync_StoreDelivery_DuringDelivery(t *testing.
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
 			deliveryStartUsecase.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
 			deliveryStartUsecase.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&CampaignLog.Creatives)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEvent.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
 				gomock.Eq(codes.StatusStarted), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.NoError(t, err)
 	})
@@ -315,6 +385,8 @@ func TestDeliveryOperation_Process_Sync_DeleteDelivery(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
@@ -350,18 +422,22 @@ func TestDeliveryOperation_Process_Sync_DeleteDelivery(t *testing.T) {
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionEnd)),
 			deliveryEndUsecase.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(after)).Return(nil),
-			deliveryEndUsecase.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(campaign)).Return(nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
+			deliveryEndUsecase.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&CampaignLog.Creatives)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEvent.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
 				gomock.Eq(after), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.NoError(t, err)
 	})
@@ -374,6 +450,8 @@ func TestDeliveryOperation_Process_Sync_DeleteDelivery(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
@@ -409,18 +487,22 @@ func TestDeliveryOperation_Process_Sync_DeleteDelivery(t *testing.T) {
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionEnd)),
 			deliveryEndUsecase.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(after)).Return(nil),
-			deliveryEndUsecase.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(campaign)).Return(nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
+			deliveryEndUsecase.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&CampaignLog.Creatives)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEvent.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
 				gomock.Eq(after), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.NoError(t, err)
 	})
@@ -440,6 +522,8 @@ func TestDeliveryOperation_Process_Sync_EndedDelivery(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
@@ -473,17 +557,21 @@ func TestDeliveryOperation_Process_Sync_EndedDelivery(t *testing.T) {
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
-			deliveryEndUsecase.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(campaign)).Return(nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionEnd)),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
+			deliveryEndUsecase.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&CampaignLog.Creatives)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEvent.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
 				gomock.Eq(status), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.NoError(t, err)
 	})
@@ -503,6 +591,8 @@ func TestDeliveryOperation_Process_Delete(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
@@ -523,7 +613,7 @@ func TestDeliveryOperation_Process_Delete(t *testing.T) {
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.EqualError(t, err, codes.ErrDoNothing.Error())
 	})
@@ -543,6 +633,8 @@ func TestDeliveryOperation_Process_Error(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
@@ -564,7 +656,7 @@ func TestDeliveryOperation_Process_Error(t *testing.T) {
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.EqualError(t, err, expectedErr.Error())
 	})
@@ -577,6 +669,8 @@ func TestDeliveryOperation_Process_Error(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
@@ -608,14 +702,17 @@ func TestDeliveryOperation_Process_Error(t *testing.T) {
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
 			deliveryStartUsecase.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
 			deliveryStartUsecase.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&CampaignLog.Creatives)).Return(expectedErr),
 			tx.EXPECT().Rollback().Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.EqualError(t, err, expectedErr.Error())
 	})
@@ -628,6 +725,8 @@ func TestDeliveryOperation_Process_Error(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
 		deliveryEndUsecase := mock_usecase.NewMockDeliveryEnd(ctrl)
@@ -661,14 +760,21 @@ func TestDeliveryOperation_Process_Error(t *testing.T) {
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
 			deliveryStartUsecase.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
 			deliveryStartUsecase.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 			creativeUsecase.EXPECT().Process(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(current), gomock.Eq(&CampaignLog.Creatives)).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Eq(campaign.GroupID), gomock.Eq(campaign.OrgCode), gomock.Eq(campaign.Status),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(""),
+			).Return(nil),
 			tx.EXPECT().Commit().Return(expectedErr),
 			tx.EXPECT().Rollback().Return(nil),
 		)
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 		err := deliveryOperationUsecase.Process(ctx, current, CampaignLog)
 		assert.EqualError(t, err, expectedErr.Error())
 	})
@@ -689,6 +795,8 @@ func TestDeliveryOperation_processCampaignLog(t *testing.T) {
 		// 必要なmockを作成
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
 		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
@@ -720,12 +828,15 @@ func TestDeliveryOperation_processCampaignLog(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(campaign, nil),
+			timer.EXPECT().Cancel(gomock.Eq(ctx), gomock.Eq(campaign.ID), gomock.Eq(codes.ReservationActionStart)),
 			deliveryStartUsecase.EXPECT().UpdateStatus(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign), gomock.Eq(codes.StatusStarted)).Return(1, nil),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(0, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(nil),
 			deliveryStartUsecase.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil),
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 
 		// private methodのテストを行うためにcastする
 		deliveryOperationInteractor := deliveryOperationUsecase.(*deliveryOperation)
@@ -741,6 +852,8 @@ func TestDeliveryOperation_processCampaignLog(t *testing.T) {
 
 		// 必要なmockを作成
 		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
 		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
 		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
 		deliveryStartUsecase := mock_usecase.NewMockDeliveryStart(ctrl)
@@ -766,7 +879,7 @@ func TestDeliveryOperation_processCampaignLog(t *testing.T) {
 		)
 
 		// テストを実行する
-		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository, campaignDataRepository, creativeUsecase, deliveryStartUsecase, deliveryEndUsecase, deliveryControlEvent, prewarmRepository, timer)
 
 		// private methodのテストを行うためにcastする
 		deliveryOperationInteractor := deliveryOperationUsecase.(*deliveryOperation)
@@ -800,3 +913,223 @@ func createTestDeliveryOperationLog(event string) *models.CampaignLog {
 		},
 	}
 }
+
+// 入稿物の審査ステータス変更ログの処理のテスト
+func TestDeliveryOperation_ProcessAsset(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	t.Parallel()
+
+	ctx := context.Background()
+	current := time.Now()
+	campaigns := []*models.Campaign{
+		{ID: 1, GroupID: 10, OrgCode: "org", Status: codes.StatusStarted},
+		{ID: 2, GroupID: 20, OrgCode: "org", Status: codes.StatusStarted},
+	}
+
+	t.Run("入稿物が紐づく配信中のキャンペーンの配信データを作り直し、updateイベントを登録する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		assetLog := &models.AssetLog{Kind: codes.AssetKindCoupon, ID: 5, OrgCode: "org", Status: codes.ReviewStatusApproved}
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByAsset(gomock.Eq(ctx), gomock.Eq(&repository.CampaignByAssetCondition{
+				Kind: codes.AssetKindCoupon, AssetID: 5, Status: []string{codes.StatusStarted, codes.StatusWarmup},
+			})).Return(campaigns, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0])).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusStarted), gomock.Eq("")).Return(nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[1])).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(2), gomock.Eq(20), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusStarted), gomock.Eq("")).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			campaignDataRepository, creative, deliveryStart, deliveryEnd, deliveryControlEvent, prewarmRepository, timer)
+		err := deliveryOperationUsecase.ProcessAsset(ctx, current, assetLog)
+		assert.NoError(t, err)
+	})
+
+	t.Run("入稿物が紐づく配信開始前(warmup)のキャンペーンは事前作成の記録と保留したイベントを破棄する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		warmup := &models.Campaign{ID: 3, GroupID: 30, OrgCode: "org", Status: codes.StatusWarmup}
+		assetLog := &models.AssetLog{Kind: codes.AssetKindCoupon, ID: 5, OrgCode: "org", Status: codes.ReviewStatusApproved}
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByAsset(gomock.Eq(ctx), gomock.Any()).Return([]*models.Campaign{campaigns[0], warmup}, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0])).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusStarted), gomock.Eq("")).Return(nil),
+			// 配信開始時に全ての配信データを作り直すため、配信データは作成しない
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(3)).Return(1, nil),
+			deliveryControlEvent.EXPECT().DiscardHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(3)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			campaignDataRepository, creative, deliveryStart, deliveryEnd, deliveryControlEvent, prewarmRepository, timer)
+		err := deliveryOperationUsecase.ProcessAsset(ctx, current, assetLog)
+		assert.NoError(t, err)
+	})
+
+	t.Run("審査OKでなくなったクリエイティブの場合、配信データを作り直して有効期限を更新する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		assetLog := &models.AssetLog{Kind: codes.AssetKindCreative, ID: 100, OrgCode: "org", Status: "4"}
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByAsset(gomock.Eq(ctx), gomock.Any()).Return(campaigns[:1], nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0])).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusStarted), gomock.Eq("")).Return(nil),
+			creative.EXPECT().Expire(gomock.Eq(ctx), gomock.Eq(current), gomock.Eq(100)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			campaignDataRepository, creative, deliveryStart, deliveryEnd, deliveryControlEvent, prewarmRepository, timer)
+		err := deliveryOperationUsecase.ProcessAsset(ctx, current, assetLog)
+		assert.NoError(t, err)
+	})
+
+	t.Run("配信内容が不正になった場合、キャンペーンを一時停止して配信データを削除する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		assetLog := &models.AssetLog{Kind: codes.AssetKindGimmick, ID: 7, OrgCode: "org", Status: "3"}
+		validationErr := &models.CampaignValidationError{CampaignID: 1, Reasons: []string{"no gimmick"}}
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByAsset(gomock.Eq(ctx), gomock.Any()).Return(campaigns[:1], nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0])).Return(validationErr),
+			deliveryEnd.EXPECT().Stop(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0]), gomock.Eq(codes.StatusPaused)).Return(nil),
+			deliveryEnd.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0])).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCampaignEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1), gomock.Eq(10), gomock.Eq("org"),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(codes.StatusPaused), gomock.Eq("no gimmick")).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			campaignDataRepository, creative, deliveryStart, deliveryEnd, deliveryControlEvent, prewarmRepository, timer)
+		err := deliveryOperationUsecase.ProcessAsset(ctx, current, assetLog)
+		assert.NoError(t, err)
+	})
+
+	t.Run("配信データの作成でエラーが発生した場合、エラーを返してRollbackする", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := mock_usecase.NewMockTimer(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		assetLog := &models.AssetLog{Kind: codes.AssetKindCoupon, ID: 5, OrgCode: "org", Status: codes.ReviewStatusApproved}
+		dbErr := errors.New("db error")
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByAsset(gomock.Eq(ctx), gomock.Any()).Return(campaigns, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaigns[0])).Return(dbErr),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			campaignDataRepository, creative, deliveryStart, deliveryEnd, deliveryControlEvent, prewarmRepository, timer)
+		err := deliveryOperationUsecase.ProcessAsset(ctx, current, assetLog)
+		assert.ErrorIs(t, err, dbErr)
+	})
+}
+
+func TestDeliveryOperation_AssetCampaignIDs(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("ステータスに関わらず入稿物が紐づくキャンペーンのIDを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		deliveryOperationUsecase := NewDeliveryOperation(logger, metrics.GetMonitor(), nil, campaignRepository,
+			nil, nil, nil, nil, nil, nil, nil)
+
+		ctx := context.Background()
+		// ステータスで絞り込まない
+		campaignRepository.EXPECT().GetCampaignByAsset(gomock.Eq(ctx), gomock.Eq(&repository.CampaignByAssetCondition{
+			Kind: codes.AssetKindCreative, AssetID: 5,
+		})).Return([]*models.Campaign{{ID: 1, Status: codes.StatusStarted}, {ID: 2, Status: codes.StatusPaused}}, nil)
+
+		campaignIDs, err := deliveryOperationUsecase.AssetCampaignIDs(ctx, &models.AssetLog{Kind: codes.AssetKindCreative, ID: 5, Status: "4"})
+		assert.NoError(t, err)
+		assert.Equal(t, []int{1, 2}, campaignIDs)
+	})
+}
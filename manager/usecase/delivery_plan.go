@@ -0,0 +1,229 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+)
+
+// deliveryPlanRecorder 配信データの登録・削除と配信制御イベントを実行せずに記録する (plan mode)
+// 参照は実際のリポジトリで行い、登録・削除のみを置き換える
+type deliveryPlanRecorder struct {
+	mu   sync.Mutex
+	plan *models.DeliveryPlan
+}
+
+func newDeliveryPlanRecorder(action string, campaign *models.Campaign, afterStatus string) *deliveryPlanRecorder {
+	return &deliveryPlanRecorder{
+		plan: &models.DeliveryPlan{
+			Action:      action,
+			CampaignID:  campaign.ID,
+			Status:      campaign.Status,
+			AfterStatus: afterStatus,
+			Items:       []*models.DeliveryPlanItem{},
+			Messages:    []*models.DeliveryPlanMessage{},
+		},
+	}
+}
+
+func (r *deliveryPlanRecorder) item(table string, operation string, item interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plan.Items = append(r.plan.Items, &models.DeliveryPlanItem{Table: table, Operation: operation, Item: item})
+}
+
+func (r *deliveryPlanRecorder) message(event *models.OutboxEvent) error {
+	attributes, err := event.MessageAttributes()
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.plan.Messages = append(r.plan.Messages, &models.DeliveryPlanMessage{
+		EventType:  event.EventType,
+		TopicArn:   event.TopicArn,
+		Attributes: attributes,
+		Message:    json.RawMessage(event.Message),
+	})
+	return nil
+}
+
+// 配信制御イベントをoutboxに登録せずに記録するDeliveryControlEventを返す
+func (r *deliveryPlanRecorder) deliveryControlEvent(event DeliveryControlEvent) DeliveryControlEvent {
+	instance, ok := event.(*deliveryControlEvent)
+	if !ok {
+		// テスト用のmockはそのまま使う
+		return event
+	}
+	planned := *instance
+	planned.outboxRepository = &planOutboxRepository{recorder: r}
+	return &planned
+}
+
+// planCampaignRepository キャンペーンのステータスを更新せずに、更新後のステータスとして参照する
+// 実際に更新すると確認だけでも行ロックを取得し、開始/終了処理を待たせてしまう
+type planCampaignRepository struct {
+	repository.CampaignRepository
+	campaign *models.Campaign
+	status   string // 更新後のステータス (更新していない場合は空)
+}
+
+func (p *planCampaignRepository) UpdateStatus(ctx context.Context, tx repository.Transaction, target *repository.UpdateCondition) (int, error) {
+	if target.CampaignID == p.campaign.ID {
+		p.status = target.Status
+	}
+	return target.CampaignID, nil
+}
+
+// グループに紐づく配信中のキャンペーン数に、対象のキャンペーンの更新後のステータスを反映する
+func (p *planCampaignRepository) GetDeliveryCampaignCountByGroupID(ctx context.Context, groupID int) (int, error) {
+	count, err := p.CampaignRepository.GetDeliveryCampaignCountByGroupID(ctx, groupID)
+	if err != nil {
+		return 0, err
+	}
+	if groupID != p.campaign.GroupID || p.status == "" {
+		return count, nil
+	}
+	started := p.campaign.Status == codes.StatusStarted
+	switch {
+	case started && p.status != codes.StatusStarted:
+		count--
+	case !started && p.status == codes.StatusStarted:
+		count++
+	}
+	return count, nil
+}
+
+type planCampaignDataRepository struct {
+	repository.DeliveryDataCampaignRepository
+	recorder *deliveryPlanRecorder
+}
+
+func (p *planCampaignDataRepository) Put(ctx context.Context, updateData *models.DeliveryDataCampaign) error {
+	p.recorder.item(codes.PlanTableCampaign, codes.PlanOperationPut, updateData)
+	return nil
+}
+
+func (p *planCampaignDataRepository) PutAll(ctx context.Context, updateData *[]models.DeliveryDataCampaign) error {
+	for i := range *updateData {
+		p.recorder.item(codes.PlanTableCampaign, codes.PlanOperationPut, &(*updateData)[i])
+	}
+	return nil
+}
+
+func (p *planCampaignDataRepository) Delete(ctx context.Context, campaignID *string) error {
+	p.recorder.item(codes.PlanTableCampaign, codes.PlanOperationDelete, map[string]string{"id": *campaignID})
+	return nil
+}
+
+func (p *planCampaignDataRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryDataCampaign) error {
+	for _, deleteData := range *deleteDatas {
+		p.recorder.item(codes.PlanTableCampaign, codes.PlanOperationDelete, map[string]string{"id": deleteData.ID})
+	}
+	return nil
+}
+
+type planContentDataRepository struct {
+	repository.DeliveryDataContentRepository
+	recorder *deliveryPlanRecorder
+}
+
+func (p *planContentDataRepository) Put(ctx context.Context, updateData *models.DeliveryDataContent) error {
+	p.recorder.item(codes.PlanTableContent, codes.PlanOperationPut, updateData)
+	return nil
+}
+
+func (p *planContentDataRepository) PutAll(ctx context.Context, updateData *[]models.DeliveryDataContent) error {
+	for i := range *updateData {
+		p.recorder.item(codes.PlanTableContent, codes.PlanOperationPut, &(*updateData)[i])
+	}
+	return nil
+}
+
+func (p *planContentDataRepository) Delete(ctx context.Context, campaignID *string) error {
+	p.recorder.item(codes.PlanTableContent, codes.PlanOperationDelete, map[string]string{"campaign_id": *campaignID})
+	return nil
+}
+
+func (p *planContentDataRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryDataContent) error {
+	for _, deleteData := range *deleteDatas {
+		p.recorder.item(codes.PlanTableContent, codes.PlanOperationDelete, map[string]string{"campaign_id": deleteData.CampaignID})
+	}
+	return nil
+}
+
+type planCreativeDataRepository struct {
+	repository.DeliveryDataCreativeRepository
+	recorder *deliveryPlanRecorder
+}
+
+func (p *planCreativeDataRepository) Put(ctx context.Context, updateData *models.DeliveryDataCreative) error {
+	p.recorder.item(codes.PlanTableCreative, codes.PlanOperationPut, updateData)
+	return nil
+}
+
+func (p *planCreativeDataRepository) PutAll(ctx context.Context, updateData *[]models.DeliveryDataCreative) error {
+	for i := range *updateData {
+		p.recorder.item(codes.PlanTableCreative, codes.PlanOperationPut, &(*updateData)[i])
+	}
+	return nil
+}
+
+func (p *planCreativeDataRepository) Delete(ctx context.Context, campaignID *string) error {
+	p.recorder.item(codes.PlanTableCreative, codes.PlanOperationDelete, map[string]string{"id": *campaignID})
+	return nil
+}
+
+func (p *planCreativeDataRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryDataCreative) error {
+	for _, deleteData := range *deleteDatas {
+		p.recorder.item(codes.PlanTableCreative, codes.PlanOperationDelete, map[string]string{"id": deleteData.ID})
+	}
+	return nil
+}
+
+func (p *planCreativeDataRepository) UpdateTTL(ctx context.Context, id string, ttl int64) error {
+	p.recorder.item(codes.PlanTableCreative, codes.PlanOperationUpdateTTL, map[string]interface{}{"id": id, "ttl": ttl})
+	return nil
+}
+
+type planTouchPointDataRepository struct {
+	repository.DeliveryDataTouchPointRepository
+	recorder *deliveryPlanRecorder
+}
+
+func (p *planTouchPointDataRepository) Put(ctx context.Context, updateData *models.DeliveryTouchPoint) error {
+	p.recorder.item(codes.PlanTableTouchPoint, codes.PlanOperationPut, updateData)
+	return nil
+}
+
+func (p *planTouchPointDataRepository) PutAll(ctx context.Context, updateData *[]models.DeliveryTouchPoint) error {
+	for i := range *updateData {
+		p.recorder.item(codes.PlanTableTouchPoint, codes.PlanOperationPut, &(*updateData)[i])
+	}
+	return nil
+}
+
+func (p *planTouchPointDataRepository) Delete(ctx context.Context, id *string, groupID *string) error {
+	p.recorder.item(codes.PlanTableTouchPoint, codes.PlanOperationDelete, map[string]string{"id": *id, "group_id": *groupID})
+	return nil
+}
+
+func (p *planTouchPointDataRepository) DeleteAll(ctx context.Context, deleteDatas *[]models.DeliveryTouchPoint) error {
+	for i := range *deleteDatas {
+		p.recorder.item(codes.PlanTableTouchPoint, codes.PlanOperationDelete, &(*deleteDatas)[i])
+	}
+	return nil
+}
+
+// planOutboxRepository 配信制御イベントをoutboxに登録せずに記録する
+type planOutboxRepository struct {
+	repository.OutboxRepository
+	recorder *deliveryPlanRecorder
+}
+
+func (p *planOutboxRepository) Save(ctx context.Context, tx repository.Transaction, event *models.OutboxEvent) error {
+	return p.recorder.message(event)
+}
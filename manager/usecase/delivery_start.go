@@ -5,6 +5,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"sync"
 	"time"
 	"touchgift-job-manager/codes"
@@ -20,6 +21,10 @@ var (
 	metricDeliveryStartDuration        = "touchgift_delivery_start_duration_seconds"
 	metricDeliveryStartDurationDesc    = "touchgift delivery start processing time (seconds)"
 	metricDeliveryStartDurationBuckets = []float64{0.025, 0.050, 0.100, 0.300, 0.500}
+
+	metricDeliveryStartPrewarmTotal       = "touchgift_delivery_start_prewarm_total"
+	metricDeliveryStartPrewarmTotalDesc   = "touchgift delivery data prewarm count"
+	metricDeliveryStartPrewarmTotalLabels = []string{"result"}
 )
 
 // DeliveryStart is interface
@@ -32,12 +37,20 @@ type DeliveryStart interface {
 	Reserve(ctx context.Context, startAt time.Time, Campaign *models.Campaign)
 	// 配信開始処理を実行する(即時)
 	ExecuteNow(schduleData *models.Campaign)
+	// 配信開始処理を実行し、完了まで待つ (CLI用)
+	Execute(ctx context.Context, campaign *models.Campaign) error
 	// 終了する
 	Close()
 	// Workerを作成する
 	CreateWorker(ctx context.Context)
 	// 配信データを作成する
 	CreateDeliveryDatas(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) error
+	// 作成する配信データをRDBから組み立てる (DynamoDBへの書き込みはしない)
+	GetDeliveryDatas(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) (*models.DeliveryDataSet, error)
+	// 配信開始前にキャンペーン以外の配信データを作成する (開始時はキャンペーンの配信データの登録のみ行う)
+	Prewarm(ctx context.Context, campaign *models.Campaign) error
+	// 配信開始処理で登録する配信データと発行する配信制御イベントを返す (DynamoDB・SNS・RDBへの登録や更新はしない)
+	Plan(ctx context.Context, campaignID int) (*models.DeliveryPlan, error)
 }
 
 type deliveryStart struct {
@@ -57,6 +70,7 @@ type deliveryStart struct {
 	contentDataRepository    repository.DeliveryDataContentRepository
 	creativeDataRepository   repository.DeliveryDataCreativeRepository
 	touchPointDataRepository repository.DeliveryDataTouchPointRepository
+	prewarmRepository        repository.PrewarmRepository
 }
 
 type deliveryStartWorker struct {
@@ -81,6 +95,7 @@ func NewDeliveryStart(
 	contentDataRepository repository.DeliveryDataContentRepository,
 	creativeDataRepository repository.DeliveryDataCreativeRepository,
 	touchPointDataRepository repository.DeliveryDataTouchPointRepository,
+	prewarmRepository repository.PrewarmRepository,
 ) DeliveryStart {
 	instance := deliveryStart{
 		logger:        logger,
@@ -102,8 +117,14 @@ func NewDeliveryStart(
 		contentDataRepository:    contentDataRepository,
 		creativeDataRepository:   creativeDataRepository,
 		touchPointDataRepository: touchPointDataRepository,
+		prewarmRepository:        prewarmRepository,
 	}
 	monitor.Metrics.AddHistogram(metricDeliveryStartDuration, metricDeliveryStartDurationDesc, nil, metricDeliveryStartDurationBuckets)
+	monitor.Metrics.AddCounter(metricDeliveryStartPrewarmTotal, metricDeliveryStartPrewarmTotalDesc, metricDeliveryStartPrewarmTotalLabels)
+	// 再起動後に読み込み直した予約の処理 (開始処理はキャンペーンIDのみ使用する)
+	timer.Handle(codes.ReservationActionStart, func(reservation *models.Reservation) {
+		instance.ExecuteNow(&models.Campaign{ID: reservation.CampaignID})
+	})
 	return &instance
 }
 
@@ -124,7 +145,12 @@ func (d *deliveryStart) Close() {
 // 配信開始処理を指定時間に実行するように予約する
 func (d *deliveryStart) Reserve(ctx context.Context, startAt time.Time, Campaign *models.Campaign) {
 	// サーバキャッシュ用に150ms早く動かす
-	d.timer.ExecuteAtTime(ctx, startAt.Add(-150*time.Millisecond), func() {
+	reservation := models.Reservation{
+		CampaignID: Campaign.ID,
+		Action:     codes.ReservationActionStart,
+		FireAt:     startAt.Add(-150 * time.Millisecond),
+	}
+	d.timer.Reserve(ctx, &reservation, func() {
 		d.ExecuteNow(Campaign)
 	})
 }
@@ -134,6 +160,11 @@ func (d *deliveryStart) ExecuteNow(campaign *models.Campaign) {
 	d.worker.q <- campaign // 実行する
 }
 
+// Execute 配信開始処理をWorkerを使わずに実行する
+func (d *deliveryStart) Execute(ctx context.Context, campaign *models.Campaign) error {
+	return d.start(ctx, time.Now(), campaign)
+}
+
 // 開始対象キャンペーンを取得する
 func (d *deliveryStart) GetCampaignToStart(ctx context.Context, to time.Time, status string, limit int) ([]*models.Campaign, error) {
 	condition := repository.CampaignToStartCondition{
@@ -207,6 +238,15 @@ func (d *deliveryStart) start(
 		return errors.Wrap(err, "Failed to start transaction")
 	}
 
+	// 管理APIからの即時実行はリーダー以外でも実行されるため、キャンペーンをロックしてから確認する
+	// (リーダーの開始処理と重複した場合は後から処理する方がwarmup以外として終了する)
+	if _, err = d.campaignRepository.GetStatusForUpdate(ctx, tx, reservedData.ID); err != nil {
+		if err == codes.ErrNoData {
+			d.logger.Debug().Int("id", reservedData.ID).Msg("No delivery data")
+			return tx.Rollback()
+		}
+		return errors.Wrap(err, "Failed to lock campaign")
+	}
 	condition := repository.CampaignCondition{
 		CampaignID: reservedData.ID,
 		Status:     codes.StatusWarmup,
@@ -231,31 +271,188 @@ func (d *deliveryStart) start(
 	if err != nil {
 		return err
 	}
-	err = d.CreateDeliveryDatas(ctx, tx, startCampaign)
+	// 配信データを事前作成済みの場合はキャンペーンの配信データのみ登録する
+	prewarmed, err := d.prewarmRepository.Delete(ctx, tx, startCampaign.ID)
 	if err != nil {
+		return errors.Wrap(err, "Failed to delete prewarm")
+	}
+	if prewarmed > 0 {
+		err = d.activate(ctx, tx, startCampaign)
+	} else {
+		err = d.CreateDeliveryDatas(ctx, tx, startCampaign)
+	}
+	if err != nil {
+		var validationErr *models.CampaignValidationError
+		if errors.As(err, &validationErr) {
+			// 配信内容に不備がある場合は開始せず(warmupのまま)、開始できなかったことを通知する
+			// ステータスの更新はロールバックするため、イベントはトランザクション外で登録する
+			if perr := d.deliveryControlEvent.PublishCampaignEvent(
+				ctx, nil, startCampaign.ID, startCampaign.GroupID, startCampaign.OrgCode, codes.StatusWarmup, codes.StatusWarmup,
+				strings.Join(validationErr.Reasons, ","),
+			); perr != nil {
+				d.logger.Error().Err(perr).Int("id", startCampaign.ID).Msg("Failed to publish warmup failed event")
+			}
+		}
 		return err
 	}
+	// 配信制御イベントを発行する
+	err = d.deliveryControlEvent.PublishCampaignEvent(
+		ctx, tx, startCampaign.ID, startCampaign.GroupID, startCampaign.OrgCode, startCampaign.Status, codes.StatusStarted, "")
+	if err != nil {
+		return errors.Wrap(err, "Failed to publish campaign event")
+	}
 	if err := tx.Commit(); err != nil {
 		return errors.Wrap(err, "Failed to commit")
 	}
-	// 配信制御イベントを発行する
-	d.deliveryControlEvent.PublishCampaignEvent(
-		ctx, startCampaign.ID, startCampaign.GroupID, startCampaign.OrgCode, startCampaign.Status, codes.StatusStarted, "")
 	return nil
 }
 
+// Prewarm 配信開始前(warmup)にタッチポイント・クリエイティブ・コンテンツの配信データを作成する
+// キャンペーンの配信データがない間は配信されないため、開始時はキャンペーンの配信データの登録と配信制御イベントのみになる
+// キャッシュ用のイベントは開始前にサーバーへ反映させないため、開始時(activate)まで送信を保留する
+func (d *deliveryStart) Prewarm(ctx context.Context, campaign *models.Campaign) (err error) {
+	var tx repository.Transaction
+	skipped := false
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic. reason: %#v", r)
+		}
+		if (err != nil || skipped) && tx != nil {
+			if terr := tx.Rollback(); terr != nil {
+				d.logger.Error().Err(terr).Int("id", campaign.ID).Msg("Failed to rollback")
+			}
+		}
+		result := "success"
+		if err != nil {
+			result = "error"
+		} else if skipped {
+			result = "skipped"
+		}
+		d.monitor.Metrics.GetCounter(metricDeliveryStartPrewarmTotal).WithLabelValues(result).Inc()
+	}()
+	tx, err = d.transaction.Begin(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed to start transaction")
+	}
+	// 開始処理と並行して処理しないようにキャンペーンをロックして、warmupのままか確認する
+	// 開始済み等の場合に事前作成の記録を残すと、再度warmupになった時に古い配信データで開始してしまう
+	status, err := d.campaignRepository.GetStatusForUpdate(ctx, tx, campaign.ID)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get campaign status")
+	}
+	if status != codes.StatusWarmup {
+		d.logger.Info().Int("id", campaign.ID).Str("status", status).Msg("Skip prewarm (not warmup)")
+		skipped = true
+		return nil
+	}
+	deliveryDatas, err := d.GetDeliveryDatas(ctx, tx, campaign)
+	if err != nil {
+		return err
+	}
+	if err = d.putDeliveryDatas(holdEvents(ctx, campaign.ID), tx, campaign, deliveryDatas); err != nil {
+		return err
+	}
+	if err = d.prewarmRepository.Save(ctx, tx, campaign.ID, time.Now().UTC()); err != nil {
+		return errors.Wrap(err, "Failed to save prewarm")
+	}
+	if err = tx.Commit(); err != nil {
+		return errors.Wrap(err, "Failed to commit")
+	}
+	d.logger.Info().Int("id", campaign.ID).Int("touch_points", len(deliveryDatas.TouchPoints)).
+		Int("creatives", len(deliveryDatas.Creatives)).Msg("Prewarmed delivery data")
+	return nil
+}
+
+// 事前作成した配信データを有効にする (キャンペーンの配信データを登録すると配信される)
+// 事前作成時に保留したキャッシュ用のイベントは開始処理と同じトランザクションで送信待ちにする
+func (d *deliveryStart) activate(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) error {
+	cc, err := d.campaignRepository.GetCampaignCreative(ctx, tx, &repository.CampaignCondition{
+		CampaignID: campaign.ID,
+	})
+	if err != nil {
+		return err
+	}
+	if err := d.campaignDataRepository.Put(ctx, campaign.CreateDeliveryDataCampaign(models.ApprovedCampaignCreatives(cc))); err != nil {
+		return err
+	}
+	return d.deliveryControlEvent.ReleaseHeldEvents(ctx, tx, campaign.ID)
+}
+
+// Plan 配信開始処理(配信データ作成処理)をplan modeで実行する
+// 事前作成の有無に関わらず、全ての配信データを作成する場合の内容を返す
+func (d *deliveryStart) Plan(ctx context.Context, campaignID int) (*models.DeliveryPlan, error) {
+	tx, err := d.transaction.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to start transaction")
+	}
+	// 確認のみのため常にロールバックする
+	defer func() {
+		if terr := tx.Rollback(); terr != nil {
+			d.logger.Error().Err(terr).Int("id", campaignID).Msg("Failed to rollback")
+		}
+	}()
+	campaign, err := d.campaignRepository.GetDeliveryToStart(ctx, tx, &repository.CampaignCondition{
+		CampaignID: campaignID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get campaign")
+	}
+	if campaign == nil {
+		return nil, codes.ErrNoData
+	}
+	recorder := newDeliveryPlanRecorder(codes.PlanActionStart, campaign, codes.StatusStarted)
+	planner := *d
+	planner.campaignRepository = &planCampaignRepository{CampaignRepository: d.campaignRepository, campaign: campaign}
+	planner.campaignDataRepository = &planCampaignDataRepository{d.campaignDataRepository, recorder}
+	planner.contentDataRepository = &planContentDataRepository{d.contentDataRepository, recorder}
+	planner.creativeDataRepository = &planCreativeDataRepository{d.creativeDataRepository, recorder}
+	planner.touchPointDataRepository = &planTouchPointDataRepository{d.touchPointDataRepository, recorder}
+	planner.deliveryControlEvent = recorder.deliveryControlEvent(d.deliveryControlEvent)
+
+	if _, err := planner.UpdateStatus(ctx, tx, campaign, codes.StatusStarted); err != nil {
+		return nil, err
+	}
+	if err := planner.CreateDeliveryDatas(ctx, tx, campaign); err != nil {
+		return nil, err
+	}
+	// 開始処理はwarmupのキャンペーンのみ処理される
+	err = planner.deliveryControlEvent.PublishCampaignEvent(
+		ctx, tx, campaign.ID, campaign.GroupID, campaign.OrgCode, codes.StatusWarmup, codes.StatusStarted, "")
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to publish campaign event")
+	}
+	return recorder.plan, nil
+}
+
 func (d *deliveryStart) CreateDeliveryDatas(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) error {
-	cc, creatives, content, touchPointDatas, err := d.getDataFromRDB(ctx, tx, campaign)
+	deliveryDatas, err := d.GetDeliveryDatas(ctx, tx, campaign)
 	if err != nil {
 		return err
 	}
-	err = d.createDeliveryDatas(ctx, campaign, cc, creatives, content, touchPointDatas)
+	err = d.createDeliveryDatas(ctx, tx, campaign, deliveryDatas)
 	if err != nil {
 		return err
 	}
 	return nil
 }
 
+func (d *deliveryStart) GetDeliveryDatas(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) (*models.DeliveryDataSet, error) {
+	cc, creatives, content, touchPointDatas, err := d.getDataFromRDB(ctx, tx, campaign)
+	if err != nil {
+		return nil, err
+	}
+	deliveryCreatives := make([]*models.DeliveryDataCreative, 0, len(creatives))
+	for _, creative := range creatives {
+		deliveryCreatives = append(deliveryCreatives, creative.CreateDeliveryDataCreative())
+	}
+	return &models.DeliveryDataSet{
+		Campaign:    campaign.CreateDeliveryDataCampaign(cc),
+		Content:     content,
+		Creatives:   deliveryCreatives,
+		TouchPoints: touchPointDatas,
+	}, nil
+}
+
 // 配信開始時にRDBからデータを取得する処理
 func (d *deliveryStart) getDataFromRDB(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) (
 	[]*models.CampaignCreative, []*models.Creative, *models.DeliveryDataContent, []*models.DeliveryTouchPoint, error,
@@ -282,8 +479,8 @@ func (d *deliveryStart) getDataFromRDB(ctx context.Context, tx repository.Transa
 	}
 
 	// TODO: コンテンツをそれぞれキャンペーンから取得してメモリに展開
-	// ギミックURLの取得
-	gimmickURL, gimmickCode, err := d.contentRepository.GetGimmicksByCampaignID(ctx, tx, &condition)
+	// ギミック一覧の取得
+	gimmicks, err := d.contentRepository.GetGimmicksByCampaignID(ctx, tx, &condition)
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
@@ -292,10 +489,17 @@ func (d *deliveryStart) getDataFromRDB(ctx context.Context, tx repository.Transa
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
-	deliveryCouponDatas := make([]models.DeliveryCouponData, 0, len(coupons))
-	for _, coupon := range coupons {
-		deliveryCouponData := coupon.CreateDeliveryCouponData()
-		deliveryCouponDatas = append(deliveryCouponDatas, *deliveryCouponData)
+	// 配信内容に不備があるキャンペーンは配信を開始しない
+	if err := d.validateContents(campaign, cc, coupons, gimmicks); err != nil {
+		return nil, nil, nil, nil, err
+	}
+	// 審査OKでないクーポン・クリエイティブは配信しない
+	cc = models.ApprovedCampaignCreatives(cc)
+	coupons = models.ApprovedCoupons(coupons)
+	// content作成
+	content, err := models.NewDeliveryDataContent(campaign.ID, coupons, gimmicks)
+	if err != nil {
+		return nil, nil, nil, nil, err
 	}
 	// タッチポイントの取得
 	touchPointCondition := &repository.TouchPointByGroupIDCondition{
@@ -306,15 +510,6 @@ func (d *deliveryStart) getDataFromRDB(ctx context.Context, tx repository.Transa
 	if err != nil {
 		return nil, nil, nil, nil, err
 	}
-	// content作成
-	content := &models.DeliveryDataContent{
-		CampaignID: strconv.Itoa(campaign.ID),
-		Coupons:    deliveryCouponDatas,
-		Gimmicks: models.Gimmick{
-			URL:  gimmickURL,
-			Code: gimmickCode,
-		},
-	}
 	// touchPoint作成
 	touchPointDatas := make([]*models.DeliveryTouchPoint, 0, len(touchPoints))
 	for _, touchPoint := range touchPoints {
@@ -328,34 +523,101 @@ func (d *deliveryStart) getDataFromRDB(ctx context.Context, tx repository.Transa
 	return cc, creatives, content, touchPointDatas, nil
 }
 
-func (d *deliveryStart) createDeliveryDatas(ctx context.Context,
-	campaign *models.Campaign, cc []*models.CampaignCreative, creatives []*models.Creative, content *models.DeliveryDataContent, touchPoints []*models.DeliveryTouchPoint,
+// 配信内容を検証する (不備がある場合は全ての理由をまとめた*models.CampaignValidationErrorを返す)
+// クーポン: 審査OKのものが1件以上、審査OKのものは画像URLあり、配信割合が正の整数で合計が設定値と一致すること
+// クリエイティブ: 設定されている場合は配信割合が正の整数で合計が設定値と一致すること
+// ギミック: 1件以上(審査OKのもののみ取得する)、それぞれURLまたはコードが設定されていること
+// 配信割合はキャンペーンの設定を検証するため、審査OKでないクーポン・クリエイティブも含めて合計する
+// (審査OKでないものは配信データに含めないため、配信データの配信割合の合計は設定値より小さくなる場合がある)
+func (d *deliveryStart) validateContents(campaign *models.Campaign, cc []*models.CampaignCreative,
+	coupons []*models.Coupon, gimmicks []*models.Gimmick,
+) error {
+	var reasons []string
+	if len(coupons) == 0 {
+		reasons = append(reasons, "no coupon")
+	} else if len(models.ApprovedCoupons(coupons)) == 0 {
+		reasons = append(reasons, "no approved coupon")
+	}
+	couponRateTotal := 0
+	for _, coupon := range coupons {
+		if coupon.Status == codes.ReviewStatusApproved && coupon.ImageURL == "" {
+			reasons = append(reasons, fmt.Sprintf("coupon %d has no image url", coupon.ID))
+		}
+		rate, err := strconv.Atoi(coupon.Rate)
+		if err != nil || rate <= 0 {
+			reasons = append(reasons, fmt.Sprintf("coupon %d has invalid rate (%s)", coupon.ID, coupon.Rate))
+			continue
+		}
+		couponRateTotal += rate
+	}
+	if len(coupons) > 0 && couponRateTotal != d.configUsecase.RateTotal {
+		reasons = append(reasons, fmt.Sprintf("coupon rate total is %d (expected: %d)", couponRateTotal, d.configUsecase.RateTotal))
+	}
+	creativeRateTotal := 0
+	for _, creative := range cc {
+		if creative.Rate <= 0 {
+			reasons = append(reasons, fmt.Sprintf("creative %d has invalid rate (%d)", creative.ID, creative.Rate))
+			continue
+		}
+		creativeRateTotal += creative.Rate
+	}
+	if len(cc) > 0 && creativeRateTotal != d.configUsecase.RateTotal {
+		reasons = append(reasons, fmt.Sprintf("creative rate total is %d (expected: %d)", creativeRateTotal, d.configUsecase.RateTotal))
+	}
+	if len(gimmicks) == 0 {
+		reasons = append(reasons, "no gimmick")
+	}
+	for _, gimmick := range gimmicks {
+		if gimmick.URL == nil && gimmick.Code == nil {
+			reasons = append(reasons, fmt.Sprintf("gimmick %d has no url or code", gimmick.ID))
+		}
+	}
+	if len(reasons) > 0 {
+		return &models.CampaignValidationError{CampaignID: campaign.ID, Reasons: reasons}
+	}
+	return nil
+}
+
+func (d *deliveryStart) createDeliveryDatas(ctx context.Context, tx repository.Transaction,
+	campaign *models.Campaign, deliveryDatas *models.DeliveryDataSet,
 ) error {
-	err := d.campaignDataRepository.Put(ctx, campaign.CreateDeliveryDataCampaign(cc))
+	err := d.campaignDataRepository.Put(ctx, deliveryDatas.Campaign)
 	if err != nil {
 		return err
 	}
+	return d.putDeliveryDatas(ctx, tx, campaign, deliveryDatas)
+}
 
-	for _, tp := range touchPoints {
-		err := d.touchPointDataRepository.Put(ctx, tp)
-		if err != nil {
-			return err
-		}
-		d.deliveryControlEvent.PublishDeliveryEvent(ctx, tp.ID, tp.GroupID, tp.StoreID, campaign.ID, campaign.OrgCode, "PUT")
+// キャンペーン以外の配信データを登録し、キャッシュ用のイベントを発行する
+func (d *deliveryStart) putDeliveryDatas(ctx context.Context, tx repository.Transaction,
+	campaign *models.Campaign, deliveryDatas *models.DeliveryDataSet,
+) error {
+	// タッチポイント・クリエイティブは件数が多いためBatchWriteItemでまとめて登録する
+	touchPoints := make([]models.DeliveryTouchPoint, 0, len(deliveryDatas.TouchPoints))
+	for _, tp := range deliveryDatas.TouchPoints {
+		touchPoints = append(touchPoints, *tp)
+	}
+	if err := d.touchPointDataRepository.PutAll(ctx, &touchPoints); err != nil {
+		return err
+	}
+	creatives := make([]models.DeliveryDataCreative, 0, len(deliveryDatas.Creatives))
+	for _, deliveryCreative := range deliveryDatas.Creatives {
+		creatives = append(creatives, *deliveryCreative)
+	}
+	if err := d.creativeDataRepository.PutAll(ctx, &creatives); err != nil {
+		return err
 	}
 
-	for _, creative := range creatives {
-		deliveryCreative := creative.CreateDeliveryDataCreative()
-		err := d.creativeDataRepository.Put(ctx, deliveryCreative)
+	// タッチポイントは件数が多いため複数件をまとめて1メッセージで通知する
+	if err := d.deliveryControlEvent.PublishDeliveryEvents(ctx, tx, deliveryDatas.TouchPoints, campaign.ID, campaign.OrgCode, "PUT"); err != nil {
+		return err
+	}
+	for _, deliveryCreative := range deliveryDatas.Creatives {
+		err := d.deliveryControlEvent.PublishCreativeEvent(ctx, tx, deliveryCreative, campaign.OrgCode, "PUT")
 		if err != nil {
 			return err
 		}
-		d.deliveryControlEvent.PublishCreativeEvent(ctx, deliveryCreative, campaign.OrgCode, "PUT")
 	}
 
-	err = d.contentDataRepository.Put(ctx, content)
-	if err != nil {
-		return err
-	}
-	return nil
+	return d.contentDataRepository.Put(ctx, deliveryDatas.Content)
 }
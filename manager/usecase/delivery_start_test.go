@@ -44,6 +44,7 @@ func TestDeliveryStart_GetCampaignData(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 
 		// mockの処理を定義
@@ -66,7 +67,7 @@ func TestDeliveryStart_GetCampaignData(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		actual, err := deliveryStart.GetCampaignToStart(ctx, to, status, limit)
 		if assert.NoError(t, err) {
 			assert.Equal(t, len(expected), len(actual))
@@ -90,6 +91,7 @@ func TestDeliveryStart_GetCampaignData(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 
 		// mockの処理を定義
@@ -116,7 +118,7 @@ func TestDeliveryStart_GetCampaignData(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		actual, err := deliveryStart.GetCampaignToStart(ctx, to, status, limit)
 		if assert.NoError(t, err) {
 			assert.Equal(t, len(expected), len(actual))
@@ -140,6 +142,7 @@ func TestDeliveryStart_GetCampaignData(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 
 		// mockの処理を定義
@@ -162,7 +165,7 @@ func TestDeliveryStart_GetCampaignData(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		actual, err := deliveryStart.GetCampaignToStart(ctx, to, status, limit)
 		if assert.Error(t, err) {
 			assert.Nil(t, actual)
@@ -195,6 +198,7 @@ func TestDeliveryStart_UpdateStatus(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -227,7 +231,7 @@ func TestDeliveryStart_UpdateStatus(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		count, err := deliveryStart.UpdateStatus(ctx, tx, campaignData, codes.StatusWarmup)
 		assert.NoError(t, err)
 		assert.Equal(t, expected, count)
@@ -249,6 +253,7 @@ func TestDeliveryStart_UpdateStatus(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -283,7 +288,7 @@ func TestDeliveryStart_UpdateStatus(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 
 		count, err := deliveryStart.UpdateStatus(ctx, tx, campaignData, codes.StatusWarmup)
 		assert.NoError(t, err)
@@ -306,6 +311,7 @@ func TestDeliveryStart_UpdateStatus(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -339,7 +345,7 @@ func TestDeliveryStart_UpdateStatus(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		count, err := deliveryStart.UpdateStatus(ctx, tx, campaignData, codes.StatusWarmup)
 		assert.EqualError(t, err, "Failed to update status. status: warmup: Failed")
 		assert.Equal(t, 0, count)
@@ -368,6 +374,7 @@ func TestDeliveryStart_Execute_NotWARMUP(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -390,6 +397,7 @@ func TestDeliveryStart_Execute_NotWARMUP(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			tx.EXPECT().Rollback().Return(nil),
 		)
@@ -404,7 +412,7 @@ func TestDeliveryStart_Execute_NotWARMUP(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -415,6 +423,33 @@ func TestDeliveryStart_Execute_NotWARMUP(t *testing.T) {
 		cancel()
 		deliveryStart.Close()
 	})
+
+	t.Run("キャンペーンが存在しない場合はロールバックして終了", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1)).Return("", codes.ErrNoData),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+		configS := config.Env.DeliveryStart
+		configUsecase := config.Env.DeliveryStartUsecase
+
+		// テストを実行する
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, NewTimer(logger),
+			nil, campaignRepository, nil, nil, nil, nil, nil, nil, nil, nil)
+		err := deliveryStart.Execute(ctx, &models.Campaign{ID: 1})
+		assert.NoError(t, err)
+	})
 }
 
 // DeliveryStartのExecuteのテスト (配信開始)
@@ -427,15 +462,19 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		&campaignData, campaignData.StartAt, sql.NullTime{}, codes.StatusWarmup, campaignData.UpdatedAt.Add(1*time.Second),
 	)
 	creatives := []*models.Creative{{ID: 1}}
-	cc := []*models.CampaignCreative{{ID: creatives[0].ID}}
-	coupons := []*models.Coupon{{ID: 1}}
+	cc := []*models.CampaignCreative{{ID: creatives[0].ID, Rate: 100, Status: codes.ReviewStatusApproved}}
+	couponImageURL := "https://example.com/coupon.png"
+	coupons := []*models.Coupon{{ID: 1, ImageURL: couponImageURL, Rate: "100", Status: codes.ReviewStatusApproved}}
 	gimmickURL := "https://example.com"
 	gimmickCode := "gimmick_code"
+	gimmicks := []*models.Gimmick{{ID: 1, URL: &gimmickURL}, {ID: 2, Code: &gimmickCode}}
 	touchPoints := []*models.TouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}}
 	contentData := &models.DeliveryDataContent{
-		CampaignID: strconv.Itoa(campaignData.ID),
-		Coupons:    []models.DeliveryCouponData{{ID: 1}},
-		Gimmicks:   models.Gimmick{URL: &gimmickURL, Code: &gimmickCode},
+		CampaignID:  strconv.Itoa(campaignData.ID),
+		Version:     codes.DeliveryContentVersionGimmickList,
+		Coupons:     []models.DeliveryCouponData{{ID: 1, ImageURL: couponImageURL, Rate: 100}},
+		Gimmicks:    models.Gimmick{URL: &gimmickURL, Code: &gimmickCode},
+		GimmickList: []models.Gimmick{{ID: 1, URL: &gimmickURL}, {ID: 2, Code: &gimmickCode}},
 	}
 	// DBから取得するデータの条件
 	contentCondition := repository.ContentByCampaignIDCondition{CampaignID: campaignData.ID}
@@ -471,6 +510,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -481,32 +521,34 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&gimmickURL, &gimmickCode, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
 			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 1, Limit: 1000000})).Return(touchPoints, nil),
 			campaignDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(deliveryData[0].CreateDeliveryDataCampaign(cc))).Return(nil),
-			touchPointDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(&models.DeliveryTouchPoint{ID: "test", GroupID: 1, StoreID: "store1"})).Return(nil),
-			deliveryControlEventUsecase.EXPECT().PublishDeliveryEvent(gomock.Eq(ctx), gomock.Eq("test"), gomock.Eq(1), gomock.Eq("store1"), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")),
-			creativeDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(creatives[0].CreateDeliveryDataCreative())).Return(nil),
-			deliveryControlEventUsecase.EXPECT().PublishCreativeEvent(gomock.Eq(ctx), gomock.Eq(creatives[0].CreateDeliveryDataCreative()), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}})).Return(nil),
+			creativeDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryDataCreative{*creatives[0].CreateDeliveryDataCreative()})).Return(nil),
+			deliveryControlEventUsecase.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]*models.DeliveryTouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}}), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")).Return(nil),
+			deliveryControlEventUsecase.EXPECT().PublishCreativeEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creatives[0].CreateDeliveryDataCreative()), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")).Return(nil),
 			contentDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(contentData)).Return(nil),
-			tx.EXPECT().Commit().Return(nil),
 			deliveryControlEventUsecase.EXPECT().PublishCampaignEvent(
-				gomock.Eq(ctx), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].GroupID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq(deliveryData[0].Status),
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].GroupID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq(deliveryData[0].Status),
 				gomock.Eq(codes.StatusStarted), gomock.Eq(""),
-			),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
 		)
 
 		// テストを実行する
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -518,6 +560,58 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart.Close()
 	})
 
+	t.Run("配信データを事前作成済みの場合、キャンペーンの配信データのみ登録して開始する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryControlEventUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := NewTimer(logger)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		octx := context.Background()
+		ctx, cancel := context.WithCancel(octx)
+		// タッチポイント・クリエイティブ・コンテンツは登録しない
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
+			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
+				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(1, nil),
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
+			campaignDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(deliveryData[0].CreateDeliveryDataCampaign(cc))).Return(nil),
+			// 事前作成時に保留したイベントを開始と同じトランザクションで送信待ちにする
+			deliveryControlEventUsecase.EXPECT().ReleaseHeldEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(nil),
+			deliveryControlEventUsecase.EXPECT().PublishCampaignEvent(
+				gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].GroupID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq(deliveryData[0].Status),
+				gomock.Eq(codes.StatusStarted), gomock.Eq(""),
+			).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
+			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
+		deliveryStart.CreateWorker(ctx)
+
+		deliveryStart.Reserve(ctx, time.Now(), &campaignData) // 即時実行させる
+
+		time.Sleep(100 * time.Millisecond) // 非同期で処理が実行されるので待つ
+		cancel()
+		deliveryStart.Close()
+	})
+
 	t.Run("配信開始時間のキャンペーンのstatus変更でエラーが起きた場合、エラーを返してロールバックする", func(t *testing.T) {
 		// mockを使用する準備
 		ctrl := gomock.NewController(t)
@@ -535,6 +629,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -547,6 +642,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(0, dbErr).Times(1),
@@ -557,7 +653,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -585,6 +681,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -598,9 +695,11 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(nil, dbErr),
 			tx.EXPECT().Rollback().Return(nil),
@@ -610,7 +709,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -638,6 +737,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -647,17 +747,18 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		octx := context.Background()
 		ctx, cancel := context.WithCancel(octx)
 		dbErr := errors.New("db error")
-		errRes := ""
 		// どう呼ばれるか (呼び出し順も考慮)
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&errRes, &errRes, dbErr),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(nil, dbErr),
 			tx.EXPECT().Rollback().Return(nil),
 		)
 
@@ -665,7 +766,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -693,6 +794,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -706,12 +808,14 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&gimmickURL, &gimmickCode, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
 			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(nil, dbErr),
 			tx.EXPECT().Rollback().Return(nil),
 		)
@@ -720,7 +824,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -748,6 +852,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -761,12 +866,14 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&gimmickURL, &gimmickCode, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
 			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 1, Limit: 1000000})).Return(nil, dbErr),
 			tx.EXPECT().Rollback().Return(nil),
@@ -776,7 +883,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -804,6 +911,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -817,12 +925,14 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&gimmickURL, &gimmickCode, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
 			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 1, Limit: 1000000})).Return(touchPoints, nil),
 			campaignDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(deliveryData[0].CreateDeliveryDataCampaign(cc))).Return(dbErr),
@@ -833,7 +943,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -861,6 +971,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -874,16 +985,18 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&gimmickURL, &gimmickCode, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
 			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 1, Limit: 1000000})).Return(touchPoints, nil),
 			campaignDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(deliveryData[0].CreateDeliveryDataCampaign(cc))).Return(nil),
-			touchPointDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(&models.DeliveryTouchPoint{ID: "test", GroupID: 1, StoreID: "store1"})).Return(dbErr),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}})).Return(dbErr),
 			tx.EXPECT().Rollback().Return(nil),
 		)
 
@@ -891,7 +1004,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -919,6 +1032,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -932,18 +1046,19 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&gimmickURL, &gimmickCode, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
 			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 1, Limit: 1000000})).Return(touchPoints, nil),
 			campaignDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(deliveryData[0].CreateDeliveryDataCampaign(cc))).Return(nil),
-			touchPointDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(&models.DeliveryTouchPoint{ID: "test", GroupID: 1, StoreID: "store1"})).Return(nil),
-			deliveryControlEventUsecase.EXPECT().PublishDeliveryEvent(gomock.Eq(ctx), gomock.Eq("test"), gomock.Eq(1), gomock.Eq("store1"), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")),
-			creativeDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(creatives[0].CreateDeliveryDataCreative())).Return(dbErr),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}})).Return(nil),
+			creativeDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryDataCreative{*creatives[0].CreateDeliveryDataCreative()})).Return(dbErr),
 			tx.EXPECT().Rollback().Return(nil),
 		)
 
@@ -951,7 +1066,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -979,6 +1094,7 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
 		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
 		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
 		timer := NewTimer(logger)
 		tx := mock_repository.NewMockTransaction(ctrl)
 
@@ -992,19 +1108,21 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		// を定義する
 		gomock.InOrder(
 			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
 			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
 			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
 				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
 			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
 			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
-			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(&gimmickURL, &gimmickCode, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
 			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
 			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 1, Limit: 1000000})).Return(touchPoints, nil),
 			campaignDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(deliveryData[0].CreateDeliveryDataCampaign(cc))).Return(nil),
-			touchPointDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(&models.DeliveryTouchPoint{ID: "test", GroupID: 1, StoreID: "store1"})).Return(nil),
-			deliveryControlEventUsecase.EXPECT().PublishDeliveryEvent(gomock.Eq(ctx), gomock.Eq("test"), gomock.Eq(1), gomock.Eq("store1"), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")),
-			creativeDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(creatives[0].CreateDeliveryDataCreative())).Return(nil),
-			deliveryControlEventUsecase.EXPECT().PublishCreativeEvent(gomock.Eq(ctx), gomock.Eq(creatives[0].CreateDeliveryDataCreative()), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}})).Return(nil),
+			creativeDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryDataCreative{*creatives[0].CreateDeliveryDataCreative()})).Return(nil),
+			deliveryControlEventUsecase.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]*models.DeliveryTouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}}), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")).Return(nil),
+			deliveryControlEventUsecase.EXPECT().PublishCreativeEvent(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creatives[0].CreateDeliveryDataCreative()), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq("PUT")).Return(nil),
 			contentDataRepository.EXPECT().Put(gomock.Eq(ctx), gomock.Eq(contentData)).Return(dbErr),
 			tx.EXPECT().Rollback().Return(nil),
 		)
@@ -1013,7 +1131,65 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 		deliveryStart := NewDeliveryStart(
 			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
 			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
-			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository)
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
+		// Workerを使って実行するので作成
+		deliveryStart.CreateWorker(ctx)
+
+		deliveryStart.Reserve(ctx, time.Now(), &campaignData) // 即時実行させる
+
+		time.Sleep(100 * time.Millisecond) // 非同期で処理が実行されるので待つ
+		// Workerを終了させる
+		cancel()
+		deliveryStart.Close()
+	})
+	t.Run("配信開始時間のキャンペーンがwarmupで配信内容に不備がある場合、warmup_failedイベントを登録してロールバックする", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryControlEventUsecase := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		timer := NewTimer(logger)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		octx := context.Background()
+		ctx, cancel := context.WithCancel(octx)
+		// 審査中のクーポン
+		invalidCoupons := []*models.Coupon{{ID: 1, ImageURL: couponImageURL, Rate: "100", Status: "1"}}
+		// 配信データは登録せず、トランザクション外でイベントを登録する
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deliveryData[0].ID)).Return(deliveryData[0].Status, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&condition)).Return(deliveryData[0], nil),
+			campaignRepository.EXPECT().UpdateStatus(gomock.Eq(ctx),
+				gomock.Eq(tx), gomock.Eq(&updateCondition)).Return(1, nil).Times(1),
+			prewarmRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaignData.ID)).Return(0, nil),
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaignData.ID})).Return(cc, nil),
+			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
+			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(invalidCoupons, nil),
+			deliveryControlEventUsecase.EXPECT().PublishCampaignEvent(
+				gomock.Eq(ctx), gomock.Nil(), gomock.Eq(deliveryData[0].ID), gomock.Eq(deliveryData[0].GroupID), gomock.Eq(deliveryData[0].OrgCode), gomock.Eq(codes.StatusWarmup),
+				gomock.Eq(codes.StatusWarmup), gomock.Eq("no approved coupon"),
+			).Return(nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, timer,
+			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
 		// Workerを使って実行するので作成
 		deliveryStart.CreateWorker(ctx)
 
@@ -1026,6 +1202,364 @@ func TestDeliveryStart_Execute_DeliveryStart(t *testing.T) {
 	})
 }
 
+// DeliveryStartのPrewarmのテスト (配信データの事前作成)
+func TestDeliveryStart_Prewarm(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	ctx := context.Background()
+	// テスト用データ
+	campaign := &models.Campaign{ID: 1, GroupID: 1, OrgCode: "org", Status: codes.StatusConfigured}
+	creatives := []*models.Creative{{ID: 1}}
+	cc := []*models.CampaignCreative{{ID: creatives[0].ID, Rate: 100, Status: codes.ReviewStatusApproved}}
+	couponImageURL := "https://example.com/coupon.png"
+	coupons := []*models.Coupon{{ID: 1, ImageURL: couponImageURL, Rate: "100", Status: codes.ReviewStatusApproved}}
+	gimmickURL := "https://example.com"
+	gimmicks := []*models.Gimmick{{ID: 1, URL: &gimmickURL}}
+	touchPoints := []*models.TouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}}
+	contentCondition := repository.ContentByCampaignIDCondition{CampaignID: campaign.ID}
+	creativeCondition := repository.CreativeByCampaignIDCondition{CampaignID: campaign.ID, Limit: 100}
+
+	t.Run("キャンペーン以外の配信データを登録し、イベントを保留して事前作成したことを記録する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		deliveryTouchPoints := []*models.DeliveryTouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}}
+		content, err := models.NewDeliveryDataContent(campaign.ID, coupons, gimmicks)
+		assert.NoError(t, err)
+		// キャッシュ用のイベントは開始時まで送信を保留する
+		heldCtx := holdEvents(ctx, campaign.ID)
+		// キャンペーンの配信データは登録しない
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(codes.StatusWarmup, nil),
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaign.ID})).Return(cc, nil),
+			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
+			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 1, Limit: 1000000})).Return(touchPoints, nil),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Eq(heldCtx), gomock.Eq(&[]models.DeliveryTouchPoint{*deliveryTouchPoints[0]})).Return(nil),
+			creativeDataRepository.EXPECT().PutAll(gomock.Eq(heldCtx), gomock.Eq(&[]models.DeliveryDataCreative{*creatives[0].CreateDeliveryDataCreative()})).Return(nil),
+			deliveryControlEvent.EXPECT().PublishDeliveryEvents(gomock.Eq(heldCtx), gomock.Eq(tx), gomock.Eq(deliveryTouchPoints), gomock.Eq(campaign.ID), gomock.Eq(campaign.OrgCode), gomock.Eq("PUT")).Return(nil),
+			deliveryControlEvent.EXPECT().PublishCreativeEvent(gomock.Eq(heldCtx), gomock.Eq(tx), gomock.Eq(creatives[0].CreateDeliveryDataCreative()), gomock.Eq(campaign.OrgCode), gomock.Eq("PUT")).Return(nil),
+			contentDataRepository.EXPECT().Put(gomock.Eq(heldCtx), gomock.Eq(content)).Return(nil),
+			prewarmRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID), gomock.Any()).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, NewTimer(logger),
+			deliveryControlEvent, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
+		err = deliveryStart.Prewarm(ctx, campaign)
+		assert.NoError(t, err)
+	})
+
+	t.Run("開始処理が先に終わってwarmupでない場合は事前作成せずにロールバックする", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		// 配信データの登録・事前作成の記録はしない
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(codes.StatusStarted, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, NewTimer(logger),
+			mock_usecase.NewMockDeliveryControlEvent(ctrl), campaignRepository, mock_repository.NewMockCreativeRepository(ctrl),
+			mock_repository.NewMockContentRepository(ctrl), mock_repository.NewMockTouchPointRepository(ctrl),
+			mock_repository.NewMockDeliveryDataCampaignRepository(ctrl), mock_repository.NewMockDeliveryDataContentRepository(ctrl),
+			mock_repository.NewMockDeliveryDataCreativeRepository(ctrl), mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl), prewarmRepository)
+		err := deliveryStart.Prewarm(ctx, campaign)
+		assert.NoError(t, err)
+	})
+
+	t.Run("配信内容に不備がある場合は配信データを登録せずにエラーを返す", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		// ギミックがないため検証エラーになる
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(codes.StatusWarmup, nil),
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: campaign.ID})).Return(cc, nil),
+			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&creativeCondition)).Return(creatives, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return([]*models.Gimmick{}, nil),
+			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, NewTimer(logger),
+			mock_usecase.NewMockDeliveryControlEvent(ctrl), campaignRepository, creativeRepository, contentRepository,
+			mock_repository.NewMockTouchPointRepository(ctrl), mock_repository.NewMockDeliveryDataCampaignRepository(ctrl),
+			mock_repository.NewMockDeliveryDataContentRepository(ctrl), mock_repository.NewMockDeliveryDataCreativeRepository(ctrl),
+			mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl), mock_repository.NewMockPrewarmRepository(ctrl))
+		err := deliveryStart.Prewarm(ctx, campaign)
+		var validationErr *models.CampaignValidationError
+		assert.ErrorAs(t, err, &validationErr)
+	})
+
+	t.Run("配信データの登録に失敗した場合はロールバックして事前作成を記録しない", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		// 保留したイベントもロールバックされる
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(codes.StatusWarmup, nil),
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(cc, nil),
+			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(creatives, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(gimmicks, nil),
+			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(coupons, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return(touchPoints, nil),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Any(), gomock.Any()).Return(errors.New("dynamodb error")),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		// テストを実行する
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &config.Env.DeliveryStart, &config.Env.DeliveryStartUsecase, transactionHandler, NewTimer(logger),
+			mock_usecase.NewMockDeliveryControlEvent(ctrl), campaignRepository, creativeRepository, contentRepository, touchPointRepository,
+			mock_repository.NewMockDeliveryDataCampaignRepository(ctrl), mock_repository.NewMockDeliveryDataContentRepository(ctrl),
+			mock_repository.NewMockDeliveryDataCreativeRepository(ctrl), touchPointDataRepository, mock_repository.NewMockPrewarmRepository(ctrl))
+		err := deliveryStart.Prewarm(ctx, campaign)
+		assert.Error(t, err)
+	})
+}
+
+// 配信内容の検証のテスト
+func TestDeliveryStart_ValidateContents(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	campaign := &models.Campaign{ID: 1}
+	gimmickURL := "https://example.com"
+	gimmicks := []*models.Gimmick{{ID: 1, URL: &gimmickURL}}
+	validCoupons := func() []*models.Coupon {
+		return []*models.Coupon{
+			{ID: 1, ImageURL: "https://example.com/1.png", Rate: "60", Status: codes.ReviewStatusApproved},
+			{ID: 2, ImageURL: "https://example.com/2.png", Rate: "40", Status: codes.ReviewStatusApproved},
+		}
+	}
+	validCreatives := []*models.CampaignCreative{{ID: 1, Rate: 50}, {ID: 2, Rate: 50}}
+
+	tests := []struct {
+		name     string
+		cc       []*models.CampaignCreative
+		coupons  func() []*models.Coupon
+		gimmicks []*models.Gimmick
+		expected []string
+	}{
+		{
+			name:     "不備がない場合、エラーを返さない",
+			cc:       validCreatives,
+			coupons:  validCoupons,
+			gimmicks: gimmicks,
+		},
+		{
+			name:     "クリエイティブが設定されていない場合、クリエイティブの配信割合は検証しない",
+			coupons:  validCoupons,
+			gimmicks: gimmicks,
+		},
+		{
+			name:     "クーポンがない場合、エラーを返す",
+			cc:       validCreatives,
+			coupons:  func() []*models.Coupon { return nil },
+			gimmicks: gimmicks,
+			expected: []string{"no coupon"},
+		},
+		{
+			name: "クーポンの配信割合が数値でない、または合計が一致しない場合、エラーを返す",
+			cc:   validCreatives,
+			coupons: func() []*models.Coupon {
+				coupons := validCoupons()
+				coupons[1].Rate = "abc"
+				return coupons
+			},
+			gimmicks: gimmicks,
+			expected: []string{"coupon 2 has invalid rate (abc)", "coupon rate total is 60 (expected: 100)"},
+		},
+		{
+			name: "画像URLがないクーポンがある場合、エラーを返す",
+			cc:   validCreatives,
+			coupons: func() []*models.Coupon {
+				coupons := validCoupons()
+				coupons[1].ImageURL = ""
+				return coupons
+			},
+			gimmicks: gimmicks,
+			expected: []string{"coupon 2 has no image url"},
+		},
+		{
+			name: "審査OKでないクーポンは画像URLを検証せず、配信割合の合計には含める",
+			cc:   validCreatives,
+			coupons: func() []*models.Coupon {
+				coupons := validCoupons()
+				coupons[0].Status = "3"
+				coupons[0].ImageURL = ""
+				return coupons
+			},
+			gimmicks: gimmicks,
+		},
+		{
+			name: "審査OKのクーポンがない場合、エラーを返す",
+			cc:   validCreatives,
+			coupons: func() []*models.Coupon {
+				coupons := validCoupons()
+				coupons[0].Status = "1"
+				coupons[1].Status = "4"
+				return coupons
+			},
+			gimmicks: gimmicks,
+			expected: []string{"no approved coupon"},
+		},
+		{
+			name:     "審査OKでないクリエイティブも配信割合の合計に含める",
+			cc:       []*models.CampaignCreative{{ID: 1, Rate: 50, Status: codes.ReviewStatusApproved}, {ID: 2, Rate: 50, Status: "4"}},
+			coupons:  validCoupons,
+			gimmicks: gimmicks,
+		},
+		{
+			name:     "クリエイティブの配信割合の合計が一致しない場合、エラーを返す",
+			cc:       []*models.CampaignCreative{{ID: 1, Rate: 50}, {ID: 2, Rate: 0}},
+			coupons:  validCoupons,
+			gimmicks: gimmicks,
+			expected: []string{"creative 2 has invalid rate (0)", "creative rate total is 50 (expected: 100)"},
+		},
+		{
+			name:     "ギミックがない場合、エラーを返す",
+			cc:       validCreatives,
+			coupons:  validCoupons,
+			expected: []string{"no gimmick"},
+		},
+		{
+			name:     "URLもコードも設定されていないギミックがある場合、エラーを返す",
+			cc:       validCreatives,
+			coupons:  validCoupons,
+			gimmicks: []*models.Gimmick{{ID: 1, URL: &gimmickURL}, {ID: 2}},
+			expected: []string{"gimmick 2 has no url or code"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &deliveryStart{logger: logger, configUsecase: &config.DeliveryStartUsecase{RateTotal: 100}}
+			err := d.validateContents(campaign, tt.cc, tt.coupons(), tt.gimmicks)
+			if len(tt.expected) == 0 {
+				assert.NoError(t, err)
+				return
+			}
+			assert.ErrorIs(t, err, codes.ErrInvalidCampaign)
+			var validationErr *models.CampaignValidationError
+			if assert.ErrorAs(t, err, &validationErr) {
+				assert.Equal(t, campaign.ID, validationErr.CampaignID)
+				assert.Equal(t, tt.expected, validationErr.Reasons)
+			}
+		})
+	}
+}
+
+// 審査OKでない入稿物を含むキャンペーンの配信データ作成のテスト
+func TestDeliveryStart_GetDeliveryDatas(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	campaign := &models.Campaign{ID: 1, GroupID: 1, OrgCode: "org1", Status: codes.StatusWarmup}
+	gimmickURL := "https://example.com"
+	gimmicks := []*models.Gimmick{{ID: 1, URL: &gimmickURL}}
+	touchPoints := []*models.TouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}}
+	configS := config.Env.DeliveryStart
+	configUsecase := config.DeliveryStartUsecase{RateTotal: 100}
+
+	t.Run("2つのクリエイティブのうち1つが審査OKでない場合、審査OKのものだけで配信データを作成する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		// クリエイティブ2は停止中 (配信割合の合計は設定値と一致する)
+		cc := []*models.CampaignCreative{
+			{ID: 1, Rate: 60, Status: codes.ReviewStatusApproved},
+			{ID: 2, Rate: 40, Status: "4"},
+		}
+		// クリエイティブの詳細は審査OKのもののみ取得される
+		creatives := []*models.Creative{{ID: 1}}
+		// クーポン2は審査中
+		coupons := []*models.Coupon{
+			{ID: 1, ImageURL: "https://example.com/1.png", Rate: "70", Status: codes.ReviewStatusApproved},
+			{ID: 2, ImageURL: "https://example.com/2.png", Rate: "30", Status: "1"},
+		}
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(cc, nil),
+			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(creatives, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(gimmicks, nil),
+			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(coupons, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return(touchPoints, nil),
+		)
+
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &configS, &configUsecase, nil, NewTimer(logger),
+			nil, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
+			nil, nil, nil, nil, nil)
+		deliveryDatas, err := deliveryStart.GetDeliveryDatas(ctx, tx, campaign)
+		// キャンペーン全体を停止せずに配信データを作成する
+		assert.NoError(t, err)
+		assert.Equal(t, []*models.CampaignCreative{cc[0]}, deliveryDatas.Campaign.Creatives)
+		assert.Len(t, deliveryDatas.Creatives, 1)
+		assert.Equal(t, "1", deliveryDatas.Creatives[0].ID)
+		if assert.Len(t, deliveryDatas.Content.Coupons, 1) {
+			assert.Equal(t, 1, deliveryDatas.Content.Coupons[0].ID)
+		}
+	})
+}
+
 //nolint:unparam // `endAt` always receives `sql.NullTime{}` となっているが今後変わる可能性があるため
 func createStartTestCampaign(campaignData *models.Campaign, startAt time.Time, endAt sql.NullTime, status string, updatedAt time.Time) []*models.Campaign {
 	return []*models.Campaign{
@@ -1039,3 +1573,99 @@ func createStartTestCampaign(campaignData *models.Campaign, startAt time.Time, e
 		},
 	}
 }
+
+func TestDeliveryStart_Plan(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	// テスト用データ
+	campaign := &models.Campaign{ID: 1, GroupID: 1, OrgCode: "org1", Status: codes.StatusWarmup, UpdatedAt: time.Now()}
+	creatives := []*models.Creative{{ID: 1}}
+	cc := []*models.CampaignCreative{{ID: creatives[0].ID, Rate: 100, Status: codes.ReviewStatusApproved}}
+	coupons := []*models.Coupon{{ID: 1, ImageURL: "https://example.com/coupon.png", Rate: "100", Status: codes.ReviewStatusApproved}}
+	gimmickURL := "https://example.com"
+	gimmicks := []*models.Gimmick{{ID: 1, URL: &gimmickURL}}
+	touchPoints := []*models.TouchPoint{{ID: "test", GroupID: 1, StoreID: "store1"}}
+	configS := config.Env.DeliveryStart
+	configUsecase := config.Env.DeliveryStartUsecase
+
+	t.Run("登録する配信データと配信制御イベントを返し、ステータスは更新しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		// 配信データ・outboxには登録しない
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		prewarmRepository := mock_repository.NewMockPrewarmRepository(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		contentCondition := repository.ContentByCampaignIDCondition{CampaignID: campaign.ID}
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx),
+				gomock.Eq(&repository.CampaignCondition{CampaignID: campaign.ID})).Return(campaign, nil),
+			// ステータスは更新しない
+			campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(cc, nil),
+			creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(creatives, nil),
+			contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(gimmicks, nil),
+			contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&contentCondition)).Return(coupons, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return(touchPoints, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryControlEventUsecase := NewDeliveryControlEvent(logger, &config.Env.DeliveryEventBatch, outboxRepository, NewTestTimezone(t))
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, NewTimer(logger),
+			deliveryControlEventUsecase, campaignRepository, creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository, prewarmRepository)
+		plan, err := deliveryStart.Plan(ctx, campaign.ID)
+		if !assert.NoError(t, err) {
+			return
+		}
+		assert.Equal(t, codes.PlanActionStart, plan.Action)
+		assert.Equal(t, codes.StatusWarmup, plan.Status)
+		assert.Equal(t, codes.StatusStarted, plan.AfterStatus)
+		tables := []string{}
+		for _, item := range plan.Items {
+			assert.Equal(t, codes.PlanOperationPut, item.Operation)
+			tables = append(tables, item.Table)
+		}
+		assert.Equal(t, []string{codes.PlanTableCampaign, codes.PlanTableTouchPoint, codes.PlanTableCreative, codes.PlanTableContent}, tables)
+		eventTypes := []string{}
+		for _, message := range plan.Messages {
+			eventTypes = append(eventTypes, message.EventType)
+		}
+		assert.Equal(t, []string{codes.OutboxEventDelivery, codes.OutboxEventCreative, codes.OutboxEventCampaign}, eventTypes)
+		assert.Equal(t, map[string]string{"event": "start", "action": "PUT"}, plan.Messages[2].Attributes)
+	})
+
+	t.Run("キャンペーンが存在しない場合はErrNoDataを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(nil, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+
+		deliveryStart := NewDeliveryStart(
+			logger, metrics.GetMonitor(), &configS, &configUsecase, transactionHandler, NewTimer(logger),
+			mock_usecase.NewMockDeliveryControlEvent(ctrl), campaignRepository, nil, nil, nil, nil, nil, nil, nil, nil)
+		_, err := deliveryStart.Plan(ctx, campaign.ID)
+		assert.ErrorIs(t, err, codes.ErrNoData)
+	})
+}
@@ -0,0 +1,208 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/rs/xid"
+)
+
+// LeaderElection 複数タスク構成時に開始/終了処理を行うリーダーを1つに絞る
+type LeaderElection interface {
+	// Run リースの取得/延長を行う (ctxが終了するとリースを解放して返る)
+	Run(ctx context.Context, wg *sync.WaitGroup)
+	// IsLeader リーダーかどうか
+	IsLeader() bool
+	// Context リーダーである間だけ有効なcontextを返す (リーダーでない場合は終了済みのcontext)
+	Context() context.Context
+	// OnElected リーダーになった時に実行する処理を登録する (リーダーである間だけ有効なcontextで実行される)
+	OnElected(callback func(ctx context.Context))
+}
+
+type leaderElection struct {
+	logger          Logger
+	monitor         *metrics.Monitor
+	config          *config.LeaderElection
+	leaseRepository repository.LeaseRepository
+	owner           string
+	mutex           sync.RWMutex
+	leader          bool
+	termCtx         context.Context
+	termCancel      context.CancelFunc
+	renewedAt       time.Time
+	callbacks       []func(ctx context.Context)
+}
+
+var (
+	metricLeaderElectionIsLeader       = "leader_election_is_leader"
+	metricLeaderElectionIsLeaderDesc   = "1 if this instance is the leader"
+	metricLeaderElectionIsLeaderLabels = []string{"lease_name"}
+
+	metricLeaderElectionTransitionTotal       = "leader_election_transition_total"
+	metricLeaderElectionTransitionTotalDesc   = "leader election transition count"
+	metricLeaderElectionTransitionTotalLabels = []string{"lease_name", "event"}
+)
+
+// NewLeaderElection is function
+func NewLeaderElection(
+	logger Logger,
+	monitor *metrics.Monitor,
+	config *config.LeaderElection,
+	leaseRepository repository.LeaseRepository,
+) LeaderElection {
+	monitor.Metrics.AddGauge(metricLeaderElectionIsLeader, metricLeaderElectionIsLeaderDesc, metricLeaderElectionIsLeaderLabels)
+	monitor.Metrics.AddCounter(metricLeaderElectionTransitionTotal, metricLeaderElectionTransitionTotalDesc, metricLeaderElectionTransitionTotalLabels)
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	termCtx, termCancel := context.WithCancel(context.Background())
+	// 初期状態はリーダーではない
+	termCancel()
+	return &leaderElection{
+		logger:          logger,
+		monitor:         monitor,
+		config:          config,
+		leaseRepository: leaseRepository,
+		owner:           fmt.Sprintf("%s-%s", hostname, xid.New().String()),
+		termCtx:         termCtx,
+		termCancel:      termCancel,
+	}
+}
+
+func (l *leaderElection) Run(ctx context.Context, wg *sync.WaitGroup) {
+	wg.Add(1)
+	defer wg.Done()
+	if !l.config.Enabled {
+		// リーダー選出しない場合は常にリーダーとして動作する
+		l.elect(ctx)
+		<-ctx.Done()
+		l.resign("shutdown")
+		return
+	}
+	l.logger.Info().Str("lease_name", l.config.LeaseName).Str("owner", l.owner).Msg("Start leader election")
+	l.tryAcquireOrRenew(ctx)
+	ticker := time.NewTicker(l.config.RetryInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			l.tryAcquireOrRenew(ctx)
+		case <-ctx.Done():
+			l.release()
+			l.logger.Info().Str("lease_name", l.config.LeaseName).Msg("Close leader election")
+			return
+		}
+	}
+}
+
+func (l *leaderElection) IsLeader() bool {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.leader
+}
+
+func (l *leaderElection) Context() context.Context {
+	l.mutex.RLock()
+	defer l.mutex.RUnlock()
+	return l.termCtx
+}
+
+func (l *leaderElection) OnElected(callback func(ctx context.Context)) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	l.callbacks = append(l.callbacks, callback)
+}
+
+// tryAcquireOrRenew リーダーであればリースを延長し、そうでなければ取得を試みる
+func (l *leaderElection) tryAcquireOrRenew(ctx context.Context) {
+	if l.IsLeader() {
+		// 延長できないままリースの期限を過ぎると他のタスクがリーダーになるため、期限までに延長できなければ辞める
+		deadline := l.renewDeadline()
+		// 延長の応答が次の延長の時間までに返らない場合は失敗とする
+		renewCtx, cancel := context.WithDeadline(ctx, deadline.Add(l.config.RetryInterval))
+		defer cancel()
+		ok, err := l.leaseRepository.Renew(renewCtx, l.config.LeaseName, l.owner, l.config.LeaseDuration)
+		if err != nil {
+			l.logger.Warn().Err(err).Str("lease_name", l.config.LeaseName).Msg("Failed to renew lease")
+			// 一時的なエラーは次の延長で期限に間に合う間は許容する
+			if !time.Now().Before(deadline) {
+				l.resign("expired")
+			}
+			return
+		}
+		if !ok {
+			l.resign("lost")
+			return
+		}
+		l.renewedAt = time.Now()
+		return
+	}
+	ok, err := l.leaseRepository.Acquire(ctx, l.config.LeaseName, l.owner, l.config.LeaseDuration)
+	if err != nil {
+		l.logger.Warn().Err(err).Str("lease_name", l.config.LeaseName).Msg("Failed to acquire lease")
+		return
+	}
+	if ok {
+		l.renewedAt = time.Now()
+		l.elect(ctx)
+	}
+}
+
+// renewDeadline リーダーを辞める期限
+// 次の延長(RetryInterval後)が間に合わない時点で辞めないと、リースの期限切れ後も処理を続けてしまう
+func (l *leaderElection) renewDeadline() time.Time {
+	return l.renewedAt.Add(l.config.LeaseDuration - l.config.RetryInterval - l.config.RenewMargin)
+}
+
+// release リーダーを辞めてリースを解放する (SIGTERM時に速やかに引き継ぐため)
+func (l *leaderElection) release() {
+	if !l.IsLeader() {
+		return
+	}
+	l.resign("shutdown")
+	ctx, cancel := context.WithTimeout(context.Background(), l.config.ReleaseTimeout)
+	defer cancel()
+	if err := l.leaseRepository.Release(ctx, l.config.LeaseName, l.owner); err != nil {
+		l.logger.Error().Err(err).Str("lease_name", l.config.LeaseName).Msg("Failed to release lease")
+		return
+	}
+	l.logger.Info().Str("lease_name", l.config.LeaseName).Str("owner", l.owner).Msg("Released lease")
+}
+
+func (l *leaderElection) elect(ctx context.Context) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if l.leader {
+		return
+	}
+	l.leader = true
+	// リーダーを辞めた時(resign)に処理を止めるため、期間ごとにcontextを作成して登録した処理に渡す
+	l.termCtx, l.termCancel = context.WithCancel(ctx)
+	l.monitor.Metrics.GetGauge(metricLeaderElectionIsLeader).WithLabelValues(l.config.LeaseName).Set(1)
+	l.monitor.Metrics.GetCounter(metricLeaderElectionTransitionTotal).WithLabelValues(l.config.LeaseName, "elected").Inc()
+	l.logger.Info().Str("lease_name", l.config.LeaseName).Str("owner", l.owner).Msg("Became leader")
+	for i := range l.callbacks {
+		go l.callbacks[i](l.termCtx)
+	}
+}
+
+func (l *leaderElection) resign(reason string) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+	if !l.leader {
+		return
+	}
+	l.leader = false
+	l.termCancel()
+	l.monitor.Metrics.GetGauge(metricLeaderElectionIsLeader).WithLabelValues(l.config.LeaseName).Set(0)
+	l.monitor.Metrics.GetCounter(metricLeaderElectionTransitionTotal).WithLabelValues(l.config.LeaseName, reason).Inc()
+	l.logger.Info().Str("lease_name", l.config.LeaseName).Str("owner", l.owner).Str("reason", reason).Msg("Resigned leader")
+}
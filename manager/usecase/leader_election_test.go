@@ -0,0 +1,290 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLeaderElection_Run(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	createConfig := func(enabled bool) *config.LeaderElection {
+		return &config.LeaderElection{
+			Enabled:        enabled,
+			LeaseName:      "test",
+			LeaseDuration:  100 * time.Millisecond,
+			RetryInterval:  10 * time.Millisecond,
+			ReleaseTimeout: 100 * time.Millisecond,
+		}
+	}
+
+	t.Run("無効の場合は常にリーダーとして動作する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), createConfig(false), leaseRepository)
+		assert.False(t, leaderElection.IsLeader())
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		go leaderElection.Run(ctx, &wg)
+		assert.Eventually(t, leaderElection.IsLeader, time.Second, 10*time.Millisecond)
+		assert.NoError(t, leaderElection.Context().Err())
+
+		cancel()
+		assert.Eventually(t, func() bool { return !leaderElection.IsLeader() }, time.Second, 10*time.Millisecond)
+		wg.Wait()
+		assert.Error(t, leaderElection.Context().Err())
+	})
+
+	t.Run("リースを取得した場合リーダーになり、終了時にリースを解放する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		conf := createConfig(true)
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), conf, leaseRepository)
+
+		leaseRepository.EXPECT().Acquire(gomock.Any(), gomock.Eq("test"), gomock.Any(), gomock.Eq(conf.LeaseDuration)).Return(true, nil).Times(1)
+		leaseRepository.EXPECT().Renew(gomock.Any(), gomock.Eq("test"), gomock.Any(), gomock.Eq(conf.LeaseDuration)).Return(true, nil).AnyTimes()
+		released := make(chan struct{})
+		leaseRepository.EXPECT().Release(gomock.Any(), gomock.Eq("test"), gomock.Any()).DoAndReturn(func(ctx context.Context, name string, owner string) error {
+			close(released)
+			return nil
+		}).Times(1)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		go leaderElection.Run(ctx, &wg)
+		assert.Eventually(t, leaderElection.IsLeader, time.Second, 10*time.Millisecond)
+		termCtx := leaderElection.Context()
+		assert.NoError(t, termCtx.Err())
+
+		cancel()
+		<-released
+		wg.Wait()
+		assert.False(t, leaderElection.IsLeader())
+		assert.Error(t, termCtx.Err())
+	})
+
+	t.Run("リースの取得に失敗した場合はリーダーにならない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), createConfig(true), leaseRepository)
+
+		leaseRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).MinTimes(2)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		go leaderElection.Run(ctx, &wg)
+		time.Sleep(50 * time.Millisecond)
+		assert.False(t, leaderElection.IsLeader())
+		assert.Error(t, leaderElection.Context().Err())
+
+		// リーダーでないのでReleaseは呼ばれない
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		wg.Wait()
+	})
+
+	t.Run("リーダーになった時の処理にはリーダーを辞めると終了するcontextを渡す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), createConfig(true), leaseRepository)
+
+		gomock.InOrder(
+			leaseRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(1),
+			leaseRepository.EXPECT().Renew(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).Times(1),
+			leaseRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes(),
+		)
+		elected := make(chan context.Context, 1)
+		leaderElection.OnElected(func(ctx context.Context) {
+			elected <- ctx
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var wg sync.WaitGroup
+		go leaderElection.Run(ctx, &wg)
+		termCtx := <-elected
+		// Runのcontextが終了していなくても、リースを失った時点で終了する
+		assert.Eventually(t, func() bool { return termCtx.Err() != nil }, time.Second, time.Millisecond)
+		assert.NoError(t, ctx.Err())
+
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		wg.Wait()
+	})
+
+	t.Run("リースを失った場合はリーダーを辞める", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), createConfig(true), leaseRepository)
+
+		gomock.InOrder(
+			leaseRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(true, nil).Times(1),
+			leaseRepository.EXPECT().Renew(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).Times(1),
+			leaseRepository.EXPECT().Acquire(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(false, nil).AnyTimes(),
+		)
+
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		go leaderElection.Run(ctx, &wg)
+		assert.Eventually(t, leaderElection.IsLeader, time.Second, time.Millisecond)
+		termCtx := leaderElection.Context()
+		assert.Eventually(t, func() bool { return !leaderElection.IsLeader() }, time.Second, time.Millisecond)
+		assert.Error(t, termCtx.Err())
+
+		cancel()
+		time.Sleep(20 * time.Millisecond)
+		wg.Wait()
+	})
+}
+
+func TestLeaderElection_tryAcquireOrRenew(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	// リースの期限(1s)から次の延長の間隔(100ms)と余裕(100ms)を引いた800msを過ぎると辞める
+	conf := &config.LeaderElection{
+		Enabled:        true,
+		LeaseName:      "test",
+		LeaseDuration:  time.Second,
+		RetryInterval:  100 * time.Millisecond,
+		RenewMargin:    100 * time.Millisecond,
+		ReleaseTimeout: 100 * time.Millisecond,
+	}
+	errUnexpected := errors.New("unexpected error")
+
+	t.Run("延長に失敗しても辞める期限の前であればリーダーのまま", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaseRepository.EXPECT().Renew(gomock.Any(), gomock.Eq("test"), gomock.Any(), gomock.Eq(conf.LeaseDuration)).Return(false, errUnexpected).Times(1)
+
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), conf, leaseRepository).(*leaderElection)
+		leaderElection.elect(context.Background())
+		// 期限の100ms前に延長に失敗する
+		leaderElection.renewedAt = time.Now().Add(-700 * time.Millisecond)
+		leaderElection.tryAcquireOrRenew(context.Background())
+		assert.True(t, leaderElection.IsLeader())
+		assert.NoError(t, leaderElection.Context().Err())
+	})
+
+	t.Run("辞める期限を過ぎて延長に失敗した場合は、リースの期限前でもリーダーを辞める", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaseRepository.EXPECT().Renew(gomock.Any(), gomock.Eq("test"), gomock.Any(), gomock.Eq(conf.LeaseDuration)).Return(false, errUnexpected).Times(1)
+
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), conf, leaseRepository).(*leaderElection)
+		leaderElection.elect(context.Background())
+		// 最後の延長から800ms(辞める期限ちょうど)経過している
+		leaderElection.renewedAt = time.Now().Add(-800 * time.Millisecond)
+		leaderElection.tryAcquireOrRenew(context.Background())
+		assert.False(t, leaderElection.IsLeader())
+		assert.Error(t, leaderElection.Context().Err())
+	})
+
+	t.Run("延長の応答がリースの期限切れ前に返らない場合は失敗としてリーダーを辞める", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 最後の延長から750ms経過した時点で延長を開始する
+		renewedAt := time.Now().Add(-750 * time.Millisecond)
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaseRepository.EXPECT().Renew(gomock.Any(), gomock.Eq("test"), gomock.Any(), gomock.Eq(conf.LeaseDuration)).DoAndReturn(
+			func(ctx context.Context, name string, owner string, duration time.Duration) (bool, error) {
+				// 次の延長の時間(辞める期限+100ms)で打ち切られる
+				deadline, ok := ctx.Deadline()
+				if assert.True(t, ok) {
+					assert.Equal(t, renewedAt.Add(900*time.Millisecond), deadline)
+				}
+				<-ctx.Done()
+				return false, ctx.Err()
+			}).Times(1)
+
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), conf, leaseRepository).(*leaderElection)
+		leaderElection.elect(context.Background())
+		leaderElection.renewedAt = renewedAt
+		leaderElection.tryAcquireOrRenew(context.Background())
+		assert.False(t, leaderElection.IsLeader())
+	})
+}
+
+func TestLeaderTimer_ExecuteAtTime(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("リーダーの場合は指定時間に実行する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), &config.LeaderElection{Enabled: false, LeaseName: "test"}, leaseRepository)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var wg sync.WaitGroup
+		go leaderElection.Run(ctx, &wg)
+		assert.Eventually(t, leaderElection.IsLeader, time.Second, 10*time.Millisecond)
+
+		executed := make(chan struct{})
+		NewLeaderTimer(NewTimer(logger), leaderElection).ExecuteAtTime(ctx, time.Now().Add(10*time.Millisecond), func() {
+			close(executed)
+		})
+		select {
+		case <-executed:
+		case <-time.After(time.Second):
+			assert.Fail(t, "not executed")
+		}
+	})
+
+	t.Run("リーダーでなくなった場合は予約をキャンセルする", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), &config.LeaderElection{Enabled: false, LeaseName: "test"}, leaseRepository)
+		ctx, cancel := context.WithCancel(context.Background())
+		var wg sync.WaitGroup
+		go leaderElection.Run(ctx, &wg)
+		assert.Eventually(t, leaderElection.IsLeader, time.Second, 10*time.Millisecond)
+
+		executed := false
+		NewLeaderTimer(NewTimer(logger), leaderElection).ExecuteAtTime(context.Background(), time.Now().Add(100*time.Millisecond), func() {
+			executed = true
+		})
+		// 終了させてリーダーを辞める
+		cancel()
+		wg.Wait()
+		time.Sleep(200 * time.Millisecond)
+		assert.False(t, executed)
+
+		// リーダーでない場合は予約しない
+		NewLeaderTimer(NewTimer(logger), leaderElection).ExecuteAtTime(context.Background(), time.Now(), func() {
+			executed = true
+		})
+		time.Sleep(50 * time.Millisecond)
+		assert.False(t, executed)
+	})
+}
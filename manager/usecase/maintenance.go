@@ -0,0 +1,107 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"strconv"
+	"time"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	"github.com/pkg/errors"
+)
+
+// Maintenance 運用作業(CLI)用の配信データのメンテナンス
+type Maintenance interface {
+	// PurgeOrphanCreatives どのキャンペーンにも紐付かないクリエイティブ配信データの有効期限(TTL)を1日後に更新する
+	// dryRunの場合は対象を返すだけで更新しない
+	PurgeOrphanCreatives(ctx context.Context, current time.Time, dryRun bool) (*models.CreativePurgeResult, error)
+}
+
+type maintenance struct {
+	logger                 Logger
+	transaction            repository.TransactionHandler
+	creativeRepository     repository.CreativeRepository
+	creativeDataRepository repository.DeliveryDataCreativeRepository
+	creative               Creative
+}
+
+// NewMaintenance is function
+func NewMaintenance(
+	logger Logger,
+	transaction repository.TransactionHandler,
+	creativeRepository repository.CreativeRepository,
+	creativeDataRepository repository.DeliveryDataCreativeRepository,
+	creative Creative,
+) Maintenance {
+	return &maintenance{
+		logger:                 logger,
+		transaction:            transaction,
+		creativeRepository:     creativeRepository,
+		creativeDataRepository: creativeDataRepository,
+		creative:               creative,
+	}
+}
+
+func (m *maintenance) PurgeOrphanCreatives(ctx context.Context, current time.Time, dryRun bool) (*models.CreativePurgeResult, error) {
+	deliveryCreatives, err := m.creativeDataRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get delivery creatives")
+	}
+	orphans, err := m.getOrphanCreativeIDs(ctx, current, deliveryCreatives)
+	if err != nil {
+		return nil, err
+	}
+	result := models.CreativePurgeResult{DryRun: dryRun, Scanned: len(deliveryCreatives), Orphans: []string{}}
+	for _, creativeID := range orphans {
+		result.Orphans = append(result.Orphans, strconv.Itoa(creativeID))
+		if dryRun {
+			continue
+		}
+		if err := m.creative.Expire(ctx, current, creativeID); err != nil {
+			// 1件の失敗で全体を止めない
+			m.logger.Error().Err(err).Int("creative_id", creativeID).Msg("Failed to expire orphan creative")
+			result.Failed++
+			continue
+		}
+		result.Purged++
+	}
+	m.logger.Info().Bool("dry_run", dryRun).Int("scanned", result.Scanned).Int("orphans", len(result.Orphans)).
+		Int("purged", result.Purged).Int("failed", result.Failed).Msg("Purge orphan creatives")
+	return &result, nil
+}
+
+// RDBでどのキャンペーンにも紐付かないクリエイティブのIDを返す (有効期限が1日以内のものは削除済みとして除く)
+func (m *maintenance) getOrphanCreativeIDs(ctx context.Context, current time.Time,
+	deliveryCreatives []*models.DeliveryDataCreative) ([]int, error) {
+	tx, err := m.transaction.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to begin transaction")
+	}
+	// 参照のみのためロールバックする
+	defer func() {
+		if terr := tx.Rollback(); terr != nil {
+			m.logger.Error().Err(terr).Msg("Failed to rollback")
+		}
+	}()
+	expireAt := current.Add(24 * time.Hour).Unix()
+	orphans := []int{}
+	for _, deliveryCreative := range deliveryCreatives {
+		if deliveryCreative.TTL > 0 && deliveryCreative.TTL <= expireAt {
+			continue
+		}
+		creativeID, err := strconv.Atoi(deliveryCreative.ID)
+		if err != nil {
+			m.logger.Error().Err(err).Str("creative_id", deliveryCreative.ID).Msg("Invalid creative id")
+			continue
+		}
+		creatives, err := m.creativeRepository.GetCreative(ctx, tx, &repository.CreativeCondition{ID: creativeID})
+		if err != nil {
+			return nil, errors.Wrapf(err, "Failed to get creative. id: %d", creativeID)
+		}
+		if len(creatives) == 0 {
+			orphans = append(orphans, creativeID)
+		}
+	}
+	return orphans, nil
+}
@@ -0,0 +1,104 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMaintenance_PurgeOrphanCreatives(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	current := time.Now()
+	deliveryCreatives := []*models.DeliveryDataCreative{
+		// キャンペーンに紐づくもの
+		{ID: "1"},
+		// どのキャンペーンにも紐付かないもの
+		{ID: "2", TTL: current.Add(30 * 24 * time.Hour).Unix()},
+		// 有効期限を更新済みのもの
+		{ID: "3", TTL: current.Add(time.Hour).Unix()},
+		{ID: "4"},
+	}
+
+	t.Run("どのキャンペーンにも紐付かないクリエイティブの有効期限を更新する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return(deliveryCreatives, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			creativeRepository.EXPECT().GetCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CreativeCondition{ID: 1})).
+				Return([]models.Creative{{ID: 1}}, nil),
+			creativeRepository.EXPECT().GetCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CreativeCondition{ID: 2})).
+				Return([]models.Creative{}, nil),
+			creativeRepository.EXPECT().GetCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CreativeCondition{ID: 4})).
+				Return([]models.Creative{}, nil),
+			tx.EXPECT().Rollback().Return(nil),
+			creativeUsecase.EXPECT().Expire(gomock.Eq(ctx), gomock.Eq(current), gomock.Eq(2)).Return(nil),
+			creativeUsecase.EXPECT().Expire(gomock.Eq(ctx), gomock.Eq(current), gomock.Eq(4)).Return(errors.New("error")),
+		)
+
+		maintenance := NewMaintenance(logger, transactionHandler, creativeRepository, creativeDataRepository, creativeUsecase)
+		result, err := maintenance.PurgeOrphanCreatives(ctx, current, false)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.CreativePurgeResult{Scanned: 4, Orphans: []string{"2", "4"}, Purged: 1, Failed: 1}, result)
+	})
+
+	t.Run("dry runの場合は有効期限を更新しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		creativeUsecase := mock_usecase.NewMockCreative(ctrl)
+
+		ctx := context.Background()
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return(deliveryCreatives[1:2], nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		creativeRepository.EXPECT().GetCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return([]models.Creative{}, nil)
+		tx.EXPECT().Rollback().Return(nil)
+
+		maintenance := NewMaintenance(logger, transactionHandler, creativeRepository, creativeDataRepository, creativeUsecase)
+		result, err := maintenance.PurgeOrphanCreatives(ctx, current, true)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.CreativePurgeResult{DryRun: true, Scanned: 1, Orphans: []string{"2"}}, result)
+	})
+
+	t.Run("クリエイティブの取得に失敗した場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+
+		ctx := context.Background()
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return(deliveryCreatives[:1], nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		creativeRepository.EXPECT().GetCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any()).Return(nil, errors.New("error"))
+		tx.EXPECT().Rollback().Return(nil)
+
+		maintenance := NewMaintenance(logger, transactionHandler, creativeRepository, creativeDataRepository, mock_usecase.NewMockCreative(ctrl))
+		_, err := maintenance.PurgeOrphanCreatives(ctx, current, false)
+		assert.Error(t, err)
+	})
+}
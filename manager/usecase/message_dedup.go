@@ -0,0 +1,110 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"sync"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+)
+
+var (
+	metricMessageDedupTotal       = "message_dedup_total"
+	metricMessageDedupTotalDesc   = "sqs message deduplication result count"
+	metricMessageDedupTotalLabels = []string{"result"}
+)
+
+// MessageDedup SQSメッセージの重複処理を防ぐ
+// SNSのMessageIdとrequest_idの組み合わせで、処理中/処理済みのメッセージを判定する
+type MessageDedup interface {
+	// Begin 処理開始前に呼び出し、処理中として登録する
+	// 戻り値がcodes.MessageDedupNew以外の場合は重複のため処理しない
+	Begin(ctx context.Context, now time.Time, messageID string, requestID string) (string, error)
+	// Complete 処理済みにする (TTLの間は重複とみなす)
+	Complete(ctx context.Context, now time.Time, messageID string, requestID string) error
+	// Abort 処理に失敗した場合に登録を削除し、再送時に再処理できるようにする
+	Abort(ctx context.Context, messageID string, requestID string) error
+}
+
+type messageDedup struct {
+	logger                 Logger
+	monitor                *metrics.Monitor
+	config                 *config.MessageDedup
+	messageDedupRepository repository.MessageDedupRepository
+	mutex                  sync.Mutex
+	lastCleanup            time.Time
+}
+
+// NewMessageDedup is function
+func NewMessageDedup(
+	logger Logger,
+	monitor *metrics.Monitor,
+	config *config.MessageDedup,
+	messageDedupRepository repository.MessageDedupRepository,
+) MessageDedup {
+	monitor.Metrics.AddCounter(metricMessageDedupTotal, metricMessageDedupTotalDesc, metricMessageDedupTotalLabels)
+	return &messageDedup{
+		logger:                 logger,
+		monitor:                monitor,
+		config:                 config,
+		messageDedupRepository: messageDedupRepository,
+	}
+}
+
+func (m *messageDedup) Begin(ctx context.Context, now time.Time, messageID string, requestID string) (string, error) {
+	if !m.config.Enabled {
+		return codes.MessageDedupNew, nil
+	}
+	m.cleanup(ctx, now)
+	status, err := m.messageDedupRepository.Claim(ctx, m.key(messageID, requestID), now, now.Add(m.config.ProcessingTimeout))
+	if err != nil {
+		m.monitor.Metrics.GetCounter(metricMessageDedupTotal).WithLabelValues("error").Inc()
+		return "", err
+	}
+	m.monitor.Metrics.GetCounter(metricMessageDedupTotal).WithLabelValues(status).Inc()
+	return status, nil
+}
+
+func (m *messageDedup) Complete(ctx context.Context, now time.Time, messageID string, requestID string) error {
+	if !m.config.Enabled {
+		return nil
+	}
+	return m.messageDedupRepository.Complete(ctx, m.key(messageID, requestID), now.Add(m.config.TTL))
+}
+
+func (m *messageDedup) Abort(ctx context.Context, messageID string, requestID string) error {
+	if !m.config.Enabled {
+		return nil
+	}
+	return m.messageDedupRepository.Release(ctx, m.key(messageID, requestID))
+}
+
+// SNSの再送ではMessageIdが変わらないため、MessageIdとrequest_idを組み合わせてキーにする
+func (m *messageDedup) key(messageID string, requestID string) string {
+	if requestID == "" {
+		return messageID
+	}
+	return messageID + ":" + requestID
+}
+
+// 期限切れの登録を一定間隔で削除する (失敗しても処理は続ける)
+func (m *messageDedup) cleanup(ctx context.Context, now time.Time) {
+	m.mutex.Lock()
+	if now.Sub(m.lastCleanup) < m.config.CleanupInterval {
+		m.mutex.Unlock()
+		return
+	}
+	m.lastCleanup = now
+	m.mutex.Unlock()
+	deleted, err := m.messageDedupRepository.DeleteExpired(ctx, now, m.config.CleanupBatchSize)
+	if err != nil {
+		m.logger.Error().Err(err).Msg("Failed to delete expired message dedup")
+		return
+	}
+	if deleted > 0 {
+		m.logger.Debug().Int64("deleted", deleted).Msg("Delete expired message dedup")
+	}
+}
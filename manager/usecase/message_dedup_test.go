@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageDedup_Begin(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	configData := config.MessageDedup{
+		Enabled:           true,
+		TTL:               24 * time.Hour,
+		ProcessingTimeout: 5 * time.Minute,
+		CleanupInterval:   10 * time.Minute,
+		CleanupBatchSize:  100,
+	}
+
+	t.Run("MessageIdとrequest_idをキーに処理中として登録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		messageDedupRepository := mock_repository.NewMockMessageDedupRepository(ctrl)
+		messageDedup := NewMessageDedup(logger, metrics.GetMonitor(), &configData, messageDedupRepository)
+
+		ctx := context.Background()
+		now := time.Now()
+		gomock.InOrder(
+			messageDedupRepository.EXPECT().DeleteExpired(gomock.Eq(ctx), gomock.Eq(now), gomock.Eq(100)).Return(int64(0), nil),
+			messageDedupRepository.EXPECT().Claim(gomock.Eq(ctx), gomock.Eq("message1:request1"), gomock.Eq(now), gomock.Eq(now.Add(5*time.Minute))).Return(codes.MessageDedupNew, nil),
+			// 削除間隔内のため期限切れの削除は行わない
+			messageDedupRepository.EXPECT().Claim(gomock.Eq(ctx), gomock.Eq("message1:request1"), gomock.Eq(now.Add(time.Second)), gomock.Any()).Return(codes.MessageDedupDone, nil),
+		)
+		status, err := messageDedup.Begin(ctx, now, "message1", "request1")
+		assert.NoError(t, err)
+		assert.Equal(t, codes.MessageDedupNew, status)
+
+		status, err = messageDedup.Begin(ctx, now.Add(time.Second), "message1", "request1")
+		assert.NoError(t, err)
+		assert.Equal(t, codes.MessageDedupDone, status)
+	})
+
+	t.Run("登録に失敗した場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		messageDedupRepository := mock_repository.NewMockMessageDedupRepository(ctrl)
+		messageDedup := NewMessageDedup(logger, metrics.GetMonitor(), &configData, messageDedupRepository)
+
+		ctx := context.Background()
+		now := time.Now()
+		messageDedupRepository.EXPECT().DeleteExpired(gomock.Eq(ctx), gomock.Any(), gomock.Any()).Return(int64(0), errors.New("error"))
+		messageDedupRepository.EXPECT().Claim(gomock.Eq(ctx), gomock.Eq("message1"), gomock.Any(), gomock.Any()).Return("", errors.New("error"))
+		_, err := messageDedup.Begin(ctx, now, "message1", "")
+		assert.Error(t, err)
+	})
+
+	t.Run("無効の場合は常に未処理として扱う", func(t *testing.T) {
+		messageDedup := NewMessageDedup(logger, metrics.GetMonitor(), &config.MessageDedup{Enabled: false}, nil)
+		status, err := messageDedup.Begin(context.Background(), time.Now(), "message1", "request1")
+		assert.NoError(t, err)
+		assert.Equal(t, codes.MessageDedupNew, status)
+	})
+}
+
+func TestMessageDedup_Complete(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("TTLの間は処理済みとして保持する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		messageDedupRepository := mock_repository.NewMockMessageDedupRepository(ctrl)
+		messageDedup := NewMessageDedup(logger, metrics.GetMonitor(), &config.MessageDedup{Enabled: true, TTL: time.Hour}, messageDedupRepository)
+
+		ctx := context.Background()
+		now := time.Now()
+		gomock.InOrder(
+			messageDedupRepository.EXPECT().Complete(gomock.Eq(ctx), gomock.Eq("message1:request1"), gomock.Eq(now.Add(time.Hour))).Return(nil),
+			messageDedupRepository.EXPECT().Release(gomock.Eq(ctx), gomock.Eq("message2:request2")).Return(nil),
+		)
+		assert.NoError(t, messageDedup.Complete(ctx, now, "message1", "request1"))
+		assert.NoError(t, messageDedup.Abort(ctx, "message2", "request2"))
+	})
+}
@@ -0,0 +1,251 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/notification"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	metricOutboxPublishTotal       = "outbox_publish_total"
+	metricOutboxPublishTotalDesc   = "outbox event publish count"
+	metricOutboxPublishTotalLabels = []string{"event_type", "result"}
+
+	metricOutboxBacklogAge       = "outbox_backlog_age_seconds"
+	metricOutboxBacklogAgeDesc   = "age of the oldest undelivered outbox event (seconds)"
+	metricOutboxBacklogAgeLabels = []string{}
+
+	metricOutboxBacklogEvents       = "outbox_backlog_events"
+	metricOutboxBacklogEventsDesc   = "number of undelivered outbox events"
+	metricOutboxBacklogEventsLabels = []string{}
+
+	metricOutboxFailedEvents       = "outbox_failed_events"
+	metricOutboxFailedEventsDesc   = "number of outbox events given up after max attempts"
+	metricOutboxFailedEventsLabels = []string{}
+)
+
+// OutboxRelay outboxに登録された配信制御イベントをSNSへPublishする
+type OutboxRelay interface {
+	// Relay 送信待ちのイベントを1バッチ分Publishして、処理した件数を返す
+	// 最大送信回数を超えて失敗したイベントは送信失敗にして、後続のイベントの送信を続ける
+	Relay(ctx context.Context, now time.Time) (int, error)
+	// ObserveBacklog 送信待ちイベントの状況をメトリクスに反映する
+	ObserveBacklog(ctx context.Context, now time.Time) error
+	// Cleanup 保持期間を過ぎた送信済みイベントを削除する
+	Cleanup(ctx context.Context, now time.Time) (int64, error)
+}
+
+type outboxRelay struct {
+	logger              Logger
+	monitor             *metrics.Monitor
+	config              *config.Outbox
+	transaction         repository.TransactionHandler
+	outboxRepository    repository.OutboxRepository
+	notificationHandler notification.NotificationHandler
+}
+
+// NewOutboxRelay is function
+func NewOutboxRelay(
+	logger Logger,
+	monitor *metrics.Monitor,
+	config *config.Outbox,
+	transaction repository.TransactionHandler,
+	outboxRepository repository.OutboxRepository,
+	notificationHandler notification.NotificationHandler,
+) OutboxRelay {
+	monitor.Metrics.AddCounter(metricOutboxPublishTotal, metricOutboxPublishTotalDesc, metricOutboxPublishTotalLabels)
+	monitor.Metrics.AddGauge(metricOutboxBacklogAge, metricOutboxBacklogAgeDesc, metricOutboxBacklogAgeLabels)
+	monitor.Metrics.AddGauge(metricOutboxBacklogEvents, metricOutboxBacklogEventsDesc, metricOutboxBacklogEventsLabels)
+	monitor.Metrics.AddGauge(metricOutboxFailedEvents, metricOutboxFailedEventsDesc, metricOutboxFailedEventsLabels)
+	return &outboxRelay{
+		logger:              logger,
+		monitor:             monitor,
+		config:              config,
+		transaction:         transaction,
+		outboxRepository:    outboxRepository,
+		notificationHandler: notificationHandler,
+	}
+}
+
+func (o *outboxRelay) Relay(ctx context.Context, now time.Time) (int, error) {
+	events, err := o.claim(ctx, now)
+	if err != nil {
+		return 0, err
+	}
+	// Publishはロックを持たずに行い、結果を1件ずつ記録する
+	count := 0
+	for i, event := range events {
+		messageID, perr := o.publish(ctx, event)
+		if perr != nil {
+			count++
+			if event.Attempts+1 >= o.config.MaxAttempts {
+				// 最大送信回数を超えたイベントは送信失敗にして、後続のイベントを止めない
+				o.monitor.Metrics.GetCounter(metricOutboxPublishTotal).WithLabelValues(event.EventType, "dead").Inc()
+				o.logger.Error().Err(perr).
+					Int64("outbox_id", event.ID).
+					Str("event_type", event.EventType).
+					Int("attempts", event.Attempts+1).
+					Msg("Give up publishing outbox event")
+				if err := o.outboxRepository.MarkDead(ctx, event.ID, now, perr.Error()); err != nil {
+					return count, errors.Wrap(err, "Failed to mark dead")
+				}
+				continue
+			}
+			o.monitor.Metrics.GetCounter(metricOutboxPublishTotal).WithLabelValues(event.EventType, "error").Inc()
+			nextAttemptAt := now.Add(o.backoff(event.Attempts))
+			o.logger.Error().Err(perr).
+				Int64("outbox_id", event.ID).
+				Str("event_type", event.EventType).
+				Int("attempts", event.Attempts+1).
+				Time("next_attempt_at", nextAttemptAt).
+				Msg("Failed to publish outbox event")
+			if err := o.outboxRepository.MarkFailed(ctx, event.ID, nextAttemptAt, perr.Error()); err != nil {
+				return count, errors.Wrap(err, "Failed to mark failed")
+			}
+			// 後続のイベントが先に届くとキャッシュの状態が逆転するため、再送まで後続も送信しない
+			if err := o.release(ctx, events[i+1:]); err != nil {
+				return count, err
+			}
+			break
+		}
+		o.monitor.Metrics.GetCounter(metricOutboxPublishTotal).WithLabelValues(event.EventType, "success").Inc()
+		o.logger.Info().
+			Int64("outbox_id", event.ID).
+			Str("event_type", event.EventType).
+			Str("message_id", *messageID).
+			Dur("delay", now.Sub(event.CreatedAt)).
+			Msg("Relay outbox event")
+		if err := o.outboxRepository.MarkDelivered(ctx, event.ID, time.Now()); err != nil {
+			return count, errors.Wrap(err, "Failed to mark delivered")
+		}
+		count++
+	}
+	return count, nil
+}
+
+// 送信するイベントを取得してLeaseを設定する
+// ロックはLeaseを設定するまでの短いトランザクションでのみ持つ
+func (o *outboxRelay) claim(ctx context.Context, now time.Time) (events []*models.OutboxEvent, err error) {
+	var tx repository.Transaction
+	defer func() {
+		if r := recover(); r != nil {
+			err = errors.Errorf("panic. reason: %#v", r)
+		}
+		if err != nil && tx != nil {
+			if terr := tx.Rollback(); terr != nil {
+				o.logger.Error().Err(terr).Msg("Failed to rollback")
+			}
+		}
+	}()
+	tx, err = o.transaction.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to begin transaction")
+	}
+	pending, err := o.outboxRepository.GetPending(ctx, tx, o.config.RelayBatchSize)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get pending events")
+	}
+	events = []*models.OutboxEvent{}
+	for _, event := range pending {
+		// 他のタスクが送信中、または再送日時前のイベントより後は送信しない (送信順を保つ)
+		if event.LockedUntil.Valid && event.LockedUntil.Time.After(now) {
+			break
+		}
+		if event.NextAttemptAt.After(now) {
+			break
+		}
+		events = append(events, event)
+	}
+	if len(events) > 0 {
+		// 他のタスクが取得中のイベント(SKIP LOCKEDで読み飛ばした行)より後は送信しない
+		oldestID, err := o.outboxRepository.GetOldestPendingID(ctx, tx)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get oldest pending event")
+		}
+		if oldestID < events[0].ID {
+			events = []*models.OutboxEvent{}
+		}
+	}
+	if len(events) > 0 {
+		if err = o.outboxRepository.Lease(ctx, tx, eventIDs(events), now.Add(o.config.LeaseDuration)); err != nil {
+			return nil, errors.Wrap(err, "Failed to lease events")
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return nil, errors.Wrap(err, "Failed to commit")
+	}
+	return events, nil
+}
+
+func (o *outboxRelay) release(ctx context.Context, events []*models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	if err := o.outboxRepository.Release(ctx, eventIDs(events)); err != nil {
+		return errors.Wrap(err, "Failed to release events")
+	}
+	return nil
+}
+
+func eventIDs(events []*models.OutboxEvent) []int64 {
+	ids := make([]int64, 0, len(events))
+	for _, event := range events {
+		ids = append(ids, event.ID)
+	}
+	return ids
+}
+
+func (o *outboxRelay) ObserveBacklog(ctx context.Context, now time.Time) error {
+	backlog, err := o.outboxRepository.GetBacklog(ctx)
+	if err != nil {
+		return errors.Wrap(err, "Failed to get backlog")
+	}
+	age := 0.0
+	if backlog.OldestCreatedAt.Valid {
+		age = now.Sub(backlog.OldestCreatedAt.Time).Seconds()
+	}
+	o.monitor.Metrics.GetGauge(metricOutboxBacklogAge).WithLabelValues().Set(age)
+	o.monitor.Metrics.GetGauge(metricOutboxBacklogEvents).WithLabelValues().Set(float64(backlog.Count))
+	o.monitor.Metrics.GetGauge(metricOutboxFailedEvents).WithLabelValues().Set(float64(backlog.Failed))
+	return nil
+}
+
+func (o *outboxRelay) Cleanup(ctx context.Context, now time.Time) (int64, error) {
+	deleted, err := o.outboxRepository.DeleteDelivered(ctx, now.Add(-o.config.Retention), o.config.RelayBatchSize)
+	if err != nil {
+		return 0, errors.Wrap(err, "Failed to delete delivered events")
+	}
+	return deleted, nil
+}
+
+func (o *outboxRelay) publish(ctx context.Context, event *models.OutboxEvent) (*string, error) {
+	attributes, err := event.MessageAttributes()
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to unmarshal attributes")
+	}
+	// 再送時も同じMessageIdにして、送信先で重複を判定できるようにする
+	ctx = notification.WithMessageID(ctx, notification.OutboxMessageID(event.ID))
+	return o.notificationHandler.Publish(ctx, event.Message, attributes, event.TopicArn)
+}
+
+// 失敗回数に応じて再送間隔を倍にする
+func (o *outboxRelay) backoff(attempts int) time.Duration {
+	interval := o.config.RetryBaseInterval
+	for i := 0; i < attempts; i++ {
+		interval *= 2
+		if interval >= o.config.RetryMaxInterval {
+			return o.config.RetryMaxInterval
+		}
+	}
+	if interval > o.config.RetryMaxInterval {
+		return o.config.RetryMaxInterval
+	}
+	return interval
+}
@@ -0,0 +1,282 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/notification"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_notification "touchgift-job-manager/mock/notification"
+	mock_repository "touchgift-job-manager/mock/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOutboxRelay_Relay(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	configData := config.Outbox{
+		RelayBatchSize:    10,
+		RetryBaseInterval: time.Second,
+		RetryMaxInterval:  10 * time.Second,
+		MaxAttempts:       3,
+		LeaseDuration:     30 * time.Second,
+		Retention:         time.Hour,
+	}
+	now := time.Now()
+	createEvent := func(id int64, attempts int, nextAttemptAt time.Time) *models.OutboxEvent {
+		return &models.OutboxEvent{
+			ID:            id,
+			EventType:     "campaign",
+			TopicArn:      "arn",
+			Message:       `{"id":"1"}`,
+			Attributes:    `{"action":"PUT"}`,
+			Attempts:      attempts,
+			NextAttemptAt: nextAttemptAt,
+			CreatedAt:     now.Add(-time.Minute),
+		}
+	}
+
+	t.Run("送信待ちのイベントにLeaseを設定してコミットした後にPublishし、送信済みにする", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &configData, transactionHandler, outboxRepository, notificationHandler)
+
+		ctx := context.Background()
+		events := []*models.OutboxEvent{createEvent(1, 0, now), createEvent(2, 0, now)}
+		messageID := "message_id"
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return(events, nil),
+			outboxRepository.EXPECT().GetOldestPendingID(gomock.Eq(ctx), gomock.Eq(tx)).Return(int64(1), nil),
+			outboxRepository.EXPECT().Lease(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]int64{1, 2}), gomock.Eq(now.Add(30*time.Second))).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			// Publishはトランザクションの外で行う
+			notificationHandler.EXPECT().Publish(gomock.Any(), gomock.Eq(`{"id":"1"}`), gomock.Eq(map[string]string{"action": "PUT"}), gomock.Eq("arn")).
+				DoAndReturn(func(ctx context.Context, _ string, _ map[string]string, _ string) (*string, error) {
+					// 再送時も同じMessageIdになるようにoutboxのIDから作成する
+					id, ok := notification.MessageIDFromContext(ctx)
+					assert.True(t, ok)
+					assert.Equal(t, "outbox-1", id)
+					return &messageID, nil
+				}),
+			outboxRepository.EXPECT().MarkDelivered(gomock.Eq(ctx), gomock.Eq(int64(1)), gomock.Any()).Return(nil),
+			notificationHandler.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&messageID, nil),
+			outboxRepository.EXPECT().MarkDelivered(gomock.Eq(ctx), gomock.Eq(int64(2)), gomock.Any()).Return(nil),
+		)
+		count, err := outboxRelay.Relay(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("送信に失敗した場合は再送日時を設定し、後続のイベントはLeaseを解除して送信しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &configData, transactionHandler, outboxRepository, notificationHandler)
+
+		ctx := context.Background()
+		events := []*models.OutboxEvent{createEvent(1, 1, now), createEvent(2, 0, now), createEvent(3, 0, now)}
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return(events, nil),
+			outboxRepository.EXPECT().GetOldestPendingID(gomock.Eq(ctx), gomock.Eq(tx)).Return(int64(1), nil),
+			outboxRepository.EXPECT().Lease(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]int64{1, 2, 3}), gomock.Any()).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			notificationHandler.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")),
+			// 1回失敗しているので1s * 2^1後に再送する
+			outboxRepository.EXPECT().MarkFailed(gomock.Eq(ctx), gomock.Eq(int64(1)), gomock.Eq(now.Add(2*time.Second)), gomock.Eq("error")).Return(nil),
+			outboxRepository.EXPECT().Release(gomock.Eq(ctx), gomock.Eq([]int64{2, 3})).Return(nil),
+		)
+		count, err := outboxRelay.Relay(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, count)
+	})
+
+	t.Run("最大送信回数に達したイベントは送信失敗にして、後続のイベントを送信する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &configData, transactionHandler, outboxRepository, notificationHandler)
+
+		ctx := context.Background()
+		// 2回失敗しているので今回の失敗で最大送信回数(3回)に達する
+		events := []*models.OutboxEvent{createEvent(1, 2, now), createEvent(2, 0, now)}
+		messageID := "message_id"
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return(events, nil),
+			outboxRepository.EXPECT().GetOldestPendingID(gomock.Eq(ctx), gomock.Eq(tx)).Return(int64(1), nil),
+			outboxRepository.EXPECT().Lease(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]int64{1, 2}), gomock.Any()).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			notificationHandler.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(nil, errors.New("error")),
+			outboxRepository.EXPECT().MarkDead(gomock.Eq(ctx), gomock.Eq(int64(1)), gomock.Eq(now), gomock.Eq("error")).Return(nil),
+			notificationHandler.EXPECT().Publish(gomock.Any(), gomock.Any(), gomock.Any(), gomock.Any()).Return(&messageID, nil),
+			outboxRepository.EXPECT().MarkDelivered(gomock.Eq(ctx), gomock.Eq(int64(2)), gomock.Any()).Return(nil),
+		)
+		count, err := outboxRelay.Relay(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, count)
+	})
+
+	t.Run("先頭のイベントが再送日時前の場合は何も送信しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &configData, transactionHandler, outboxRepository, notificationHandler)
+
+		ctx := context.Background()
+		events := []*models.OutboxEvent{createEvent(1, 1, now.Add(time.Second)), createEvent(2, 0, now)}
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return(events, nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+		count, err := outboxRelay.Relay(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("他のタスクがLease中のイベント以降は送信しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		relay := NewOutboxRelay(logger, metrics.GetMonitor(), &configData, transactionHandler, outboxRepository, notificationHandler).(*outboxRelay)
+
+		ctx := context.Background()
+		leased := createEvent(1, 0, now)
+		leased.LockedUntil = sql.NullTime{Time: now.Add(time.Second), Valid: true}
+		// Lease期限を過ぎたイベントは停止したタスクのものとして送信する
+		expired := createEvent(1, 0, now)
+		expired.LockedUntil = sql.NullTime{Time: now.Add(-time.Second), Valid: true}
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return([]*models.OutboxEvent{leased, createEvent(2, 0, now)}, nil),
+			tx.EXPECT().Commit().Return(nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return([]*models.OutboxEvent{expired}, nil),
+			outboxRepository.EXPECT().GetOldestPendingID(gomock.Eq(ctx), gomock.Eq(tx)).Return(int64(1), nil),
+			outboxRepository.EXPECT().Lease(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]int64{1}), gomock.Any()).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+		events, err := relay.claim(ctx, now)
+		assert.NoError(t, err)
+		assert.Empty(t, events)
+		events, err = relay.claim(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, []*models.OutboxEvent{expired}, events)
+	})
+
+	t.Run("他のタスクが取得中の古いイベントがある場合は何も送信しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &configData, transactionHandler, outboxRepository, notificationHandler)
+
+		ctx := context.Background()
+		// ID:1はSKIP LOCKEDで読み飛ばされている
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return([]*models.OutboxEvent{createEvent(2, 0, now)}, nil),
+			outboxRepository.EXPECT().GetOldestPendingID(gomock.Eq(ctx), gomock.Eq(tx)).Return(int64(1), nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+		count, err := outboxRelay.Relay(ctx, now)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, count)
+	})
+
+	t.Run("Leaseの設定に失敗した場合はロールバックして送信しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		notificationHandler := mock_notification.NewMockNotificationHandler(ctrl)
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &configData, transactionHandler, outboxRepository, notificationHandler)
+
+		ctx := context.Background()
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			outboxRepository.EXPECT().GetPending(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(10)).Return([]*models.OutboxEvent{createEvent(1, 0, now)}, nil),
+			outboxRepository.EXPECT().GetOldestPendingID(gomock.Eq(ctx), gomock.Eq(tx)).Return(int64(1), nil),
+			outboxRepository.EXPECT().Lease(gomock.Eq(ctx), gomock.Eq(tx), gomock.Any(), gomock.Any()).Return(errors.New("error")),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+		_, err := outboxRelay.Relay(ctx, now)
+		assert.Error(t, err)
+	})
+}
+
+func TestOutboxRelay_ObserveBacklog(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("最も古い送信待ちイベントの経過時間をメトリクスに設定する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		outboxRepository := mock_repository.NewMockOutboxRepository(ctrl)
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &config.Outbox{}, nil, outboxRepository, nil)
+
+		ctx := context.Background()
+		now := time.Now()
+		outboxRepository.EXPECT().GetBacklog(gomock.Eq(ctx)).Return(&models.OutboxBacklog{
+			Count:           3,
+			OldestCreatedAt: sql.NullTime{Time: now.Add(-time.Minute), Valid: true},
+			Failed:          1,
+		}, nil)
+		err := outboxRelay.ObserveBacklog(ctx, now)
+		assert.NoError(t, err)
+	})
+}
+
+func TestOutboxRelay_backoff(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("失敗回数ごとに倍にして上限で止める", func(t *testing.T) {
+		outboxRelay := NewOutboxRelay(logger, metrics.GetMonitor(), &config.Outbox{
+			RetryBaseInterval: time.Second,
+			RetryMaxInterval:  10 * time.Second,
+		}, nil, nil, nil).(*outboxRelay)
+		assert.Equal(t, time.Second, outboxRelay.backoff(0))
+		assert.Equal(t, 2*time.Second, outboxRelay.backoff(1))
+		assert.Equal(t, 8*time.Second, outboxRelay.backoff(3))
+		assert.Equal(t, 10*time.Second, outboxRelay.backoff(4))
+		assert.Equal(t, 10*time.Second, outboxRelay.backoff(100))
+	})
+}
@@ -0,0 +1,598 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	metricReconcileDrift       = "reconcile_drift"
+	metricReconcileDriftDesc   = "number of drifts found by the last reconcile"
+	metricReconcileDriftLabels = []string{"kind"}
+
+	metricReconcileRepairTotal       = "reconcile_repair_total"
+	metricReconcileRepairTotalDesc   = "reconcile repair count"
+	metricReconcileRepairTotalLabels = []string{"kind", "result"}
+
+	reconcileDriftKinds = []string{
+		codes.DriftCampaignMissing,
+		codes.DriftCampaignMismatch,
+		codes.DriftContentMissing,
+		codes.DriftContentMismatch,
+		codes.DriftCreativeMissing,
+		codes.DriftTouchPointMissing,
+		codes.DriftOrphanCampaign,
+		codes.DriftOrphanTouchPoint,
+		codes.DriftOrphanCreative,
+	}
+)
+
+// Reconcile RDBの配信中キャンペーンとDynamoDBの配信データの差分を検出する
+type Reconcile interface {
+	// Run 差分を検出する (repairがtrueの場合は差分を修復する)
+	Run(ctx context.Context, repair bool) (*models.ReconcileResult, error)
+}
+
+type reconcile struct {
+	logger                   Logger
+	monitor                  *metrics.Monitor
+	transaction              repository.TransactionHandler
+	campaignRepository       repository.CampaignRepository
+	creativeRepository       repository.CreativeRepository
+	contentRepository        repository.ContentRepository
+	touchPointRepository     repository.TouchPointRepository
+	campaignDataRepository   repository.DeliveryDataCampaignRepository
+	contentDataRepository    repository.DeliveryDataContentRepository
+	creativeDataRepository   repository.DeliveryDataCreativeRepository
+	touchPointDataRepository repository.DeliveryDataTouchPointRepository
+	deliveryControlEvent     DeliveryControlEvent
+	deliveryStart            DeliveryStart
+	deliveryEnd              DeliveryEnd
+	maintenance              Maintenance
+	creative                 Creative
+}
+
+// NewReconcile is function
+func NewReconcile(
+	logger Logger,
+	monitor *metrics.Monitor,
+	transaction repository.TransactionHandler,
+	campaignRepository repository.CampaignRepository,
+	creativeRepository repository.CreativeRepository,
+	contentRepository repository.ContentRepository,
+	touchPointRepository repository.TouchPointRepository,
+	campaignDataRepository repository.DeliveryDataCampaignRepository,
+	contentDataRepository repository.DeliveryDataContentRepository,
+	creativeDataRepository repository.DeliveryDataCreativeRepository,
+	touchPointDataRepository repository.DeliveryDataTouchPointRepository,
+	deliveryControlEvent DeliveryControlEvent,
+	deliveryStart DeliveryStart,
+	deliveryEnd DeliveryEnd,
+	maintenance Maintenance,
+	creative Creative,
+) Reconcile {
+	monitor.Metrics.AddGauge(metricReconcileDrift, metricReconcileDriftDesc, metricReconcileDriftLabels)
+	monitor.Metrics.AddCounter(metricReconcileRepairTotal, metricReconcileRepairTotalDesc, metricReconcileRepairTotalLabels)
+	return &reconcile{
+		logger:                   logger,
+		monitor:                  monitor,
+		transaction:              transaction,
+		campaignRepository:       campaignRepository,
+		creativeRepository:       creativeRepository,
+		contentRepository:        contentRepository,
+		touchPointRepository:     touchPointRepository,
+		campaignDataRepository:   campaignDataRepository,
+		contentDataRepository:    contentDataRepository,
+		creativeDataRepository:   creativeDataRepository,
+		touchPointDataRepository: touchPointDataRepository,
+		deliveryControlEvent:     deliveryControlEvent,
+		deliveryStart:            deliveryStart,
+		deliveryEnd:              deliveryEnd,
+		maintenance:              maintenance,
+		creative:                 creative,
+	}
+}
+
+// まとめて取得したDynamoDBの配信データ
+type reconcileDeliveryData struct {
+	// 店舗グループIDごとのタッチポイントID
+	touchPoints map[int]map[string]struct{}
+	// クリエイティブID
+	creatives map[string]struct{}
+}
+
+func newReconcileDeliveryData(touchPoints []*models.DeliveryTouchPoint, creatives []*models.DeliveryDataCreative) *reconcileDeliveryData {
+	data := reconcileDeliveryData{
+		touchPoints: map[int]map[string]struct{}{},
+		creatives:   make(map[string]struct{}, len(creatives)),
+	}
+	for _, touchPoint := range touchPoints {
+		if _, ok := data.touchPoints[touchPoint.GroupID]; !ok {
+			data.touchPoints[touchPoint.GroupID] = map[string]struct{}{}
+		}
+		data.touchPoints[touchPoint.GroupID][touchPoint.ID] = struct{}{}
+	}
+	for _, creative := range creatives {
+		data.creatives[creative.ID] = struct{}{}
+	}
+	return &data
+}
+
+//nolint:gocognit // 差分の種類ごとに確認するため
+func (r *reconcile) Run(ctx context.Context, repair bool) (*models.ReconcileResult, error) {
+	// 開始処理中(warmup)のキャンペーンは配信データを先に登録しているため、削除漏れとして扱わないように取得する
+	campaigns, err := r.campaignRepository.GetCampaignByStatus(ctx, &repository.CampaignByStatusCondition{
+		Status: []string{codes.StatusStarted, codes.StatusWarmup},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get started campaigns")
+	}
+	// DynamoDBの配信データはキャンペーンごとに取得せず、まとめて取得したものと比較する
+	// 取得後に作成された配信データは欠けているものとして報告される場合がある (修復は作り直しのため影響はない)
+	deliveryTouchPoints, err := r.touchPointDataRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get delivery touch points")
+	}
+	deliveryCreatives, err := r.creativeDataRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get delivery creatives")
+	}
+	actual := newReconcileDeliveryData(deliveryTouchPoints, deliveryCreatives)
+	result := models.ReconcileResult{
+		Drifts: []*models.ReconcileDrift{},
+	}
+	active := make(map[string]struct{}, len(campaigns))
+	// 店舗グループごとの配信中のキャンペーンとRDBのタッチポイント
+	groupCampaigns := map[int]*models.Campaign{}
+	expectedTouchPoints := map[int]map[string]struct{}{}
+	// タッチポイントの削除漏れを確認しない店舗グループ (開始処理中・確認に失敗したもの)
+	skipGroups := map[int]struct{}{}
+	for _, campaign := range campaigns {
+		active[strconv.Itoa(campaign.ID)] = struct{}{}
+		if campaign.Status != codes.StatusStarted {
+			skipGroups[campaign.GroupID] = struct{}{}
+			continue
+		}
+		result.Checked++
+		drifts, touchPoints, err := r.reconcileStarted(ctx, campaign, actual, repair)
+		if err != nil {
+			// 1キャンペーンの失敗で全体を止めない
+			r.logger.Error().Err(err).Int("campaign_id", campaign.ID).Msg("Failed to reconcile campaign")
+			skipGroups[campaign.GroupID] = struct{}{}
+			continue
+		}
+		result.Drifts = append(result.Drifts, drifts...)
+		if _, ok := groupCampaigns[campaign.GroupID]; !ok {
+			groupCampaigns[campaign.GroupID] = campaign
+			expectedTouchPoints[campaign.GroupID] = make(map[string]struct{}, len(touchPoints))
+		}
+		for _, touchPoint := range touchPoints {
+			expectedTouchPoints[campaign.GroupID][touchPoint.ID] = struct{}{}
+		}
+	}
+
+	// 配信中でないキャンペーンの配信データ(削除漏れ)
+	deliveryCampaigns, err := r.campaignDataRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get delivery campaigns")
+	}
+	for _, deliveryCampaign := range deliveryCampaigns {
+		if _, ok := active[deliveryCampaign.ID]; ok {
+			continue
+		}
+		drift, err := r.reconcileOrphan(ctx, deliveryCampaign, repair)
+		if err != nil {
+			r.logger.Error().Err(err).Str("campaign_id", deliveryCampaign.ID).Msg("Failed to reconcile orphan campaign")
+			continue
+		}
+		if drift != nil {
+			result.Drifts = append(result.Drifts, drift)
+		}
+	}
+
+	// 配信中のキャンペーンの店舗グループにないタッチポイントの配信データ(削除漏れ)
+	orphanTouchPoints := map[int][]*models.DeliveryTouchPoint{}
+	for _, touchPoint := range deliveryTouchPoints {
+		if _, ok := skipGroups[touchPoint.GroupID]; ok {
+			continue
+		}
+		if _, ok := expectedTouchPoints[touchPoint.GroupID][touchPoint.ID]; ok {
+			continue
+		}
+		orphanTouchPoints[touchPoint.GroupID] = append(orphanTouchPoints[touchPoint.GroupID], touchPoint)
+	}
+	groupIDs := make([]int, 0, len(orphanTouchPoints))
+	for groupID := range orphanTouchPoints {
+		groupIDs = append(groupIDs, groupID)
+	}
+	sort.Ints(groupIDs)
+	for _, groupID := range groupIDs {
+		drift, err := r.reconcileOrphanTouchPoints(ctx, groupID, groupCampaigns[groupID], orphanTouchPoints[groupID], repair)
+		if err != nil {
+			r.logger.Error().Err(err).Int("group_id", groupID).Msg("Failed to reconcile orphan touch points")
+			continue
+		}
+		if drift != nil {
+			result.Drifts = append(result.Drifts, drift)
+		}
+	}
+
+	// どのキャンペーンにも紐付かないクリエイティブの配信データ(削除漏れ)
+	drifts, err := r.reconcileOrphanCreatives(ctx, repair)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to reconcile orphan creatives")
+	}
+	result.Drifts = append(result.Drifts, drifts...)
+
+	counts := make(map[string]int, len(reconcileDriftKinds))
+	for _, drift := range result.Drifts {
+		counts[drift.Kind]++
+		r.logger.Warn().Int("campaign_id", drift.CampaignID).Str("kind", drift.Kind).Str("detail", drift.Detail).
+			Bool("repaired", drift.Repaired).Msg("Delivery data drift")
+	}
+	for _, kind := range reconcileDriftKinds {
+		r.monitor.Metrics.GetGauge(metricReconcileDrift).WithLabelValues(kind).Set(float64(counts[kind]))
+	}
+	r.logger.Info().Int("checked", result.Checked).Int("drifts", len(result.Drifts)).Bool("repair", repair).Msg("Reconciled")
+	return &result, nil
+}
+
+// 配信中キャンペーンの配信データを比較する (比較したRDBのタッチポイントを返す)
+func (r *reconcile) reconcileStarted(ctx context.Context, campaign *models.Campaign, actual *reconcileDeliveryData, repair bool) (
+	drifts []*models.ReconcileDrift, touchPoints []*models.DeliveryTouchPoint, err error,
+) {
+	var tx repository.Transaction
+	committed := false
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+		if !committed && tx != nil {
+			if terr := tx.Rollback(); terr != nil {
+				r.logger.Error().Err(terr).Int("campaign_id", campaign.ID).Msg("Failed to rollback")
+			}
+		}
+	}()
+	tx, err = r.transaction.Begin(ctx)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to begin transaction")
+	}
+	expected, err := r.getExpected(ctx, tx, campaign)
+	if err != nil {
+		return nil, nil, err
+	}
+	drifts, err = r.diff(ctx, campaign.ID, expected, actual)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(drifts) > 0 && repair {
+		// 配信データを作り直す
+		// 配信内容に不備がある場合は作り直せないため、差分の報告のみ行う
+		repairErr := r.deliveryStart.CreateDeliveryDatas(ctx, tx, campaign)
+		r.countRepair(drifts, repairErr)
+		if repairErr != nil {
+			r.logger.Error().Err(repairErr).Int("campaign_id", campaign.ID).Msg("Failed to repair delivery datas")
+			return drifts, expected.TouchPoints, nil
+		}
+		for _, drift := range drifts {
+			drift.Repaired = true
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, nil, errors.Wrap(err, "Failed to commit")
+	}
+	committed = true
+	return drifts, expected.TouchPoints, nil
+}
+
+// RDBのレコードから配信データを組み立てる
+// 配信開始時の配信内容の検証は行わない (配信中に検証に通らない設定になっても配信データとの差分を確認する)
+func (r *reconcile) getExpected(ctx context.Context, tx repository.Transaction, campaign *models.Campaign) (*models.DeliveryDataSet, error) {
+	cc, err := r.campaignRepository.GetCampaignCreative(ctx, tx, &repository.CampaignCondition{
+		CampaignID: campaign.ID,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get campaign creatives")
+	}
+	creatives, err := r.creativeRepository.GetCreativeByCampaignID(ctx, tx, &repository.CreativeByCampaignIDCondition{
+		CampaignID: campaign.ID,
+		// 開始処理と同じく100件までとする
+		Limit: 100,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get creatives")
+	}
+	contentCondition := repository.ContentByCampaignIDCondition{
+		CampaignID: campaign.ID,
+	}
+	gimmicks, err := r.contentRepository.GetGimmicksByCampaignID(ctx, tx, &contentCondition)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get gimmicks")
+	}
+	coupons, err := r.contentRepository.GetCouponsByCampaignID(ctx, tx, &contentCondition)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get coupons")
+	}
+	touchPoints, err := r.touchPointRepository.GetTouchPointByGroupID(ctx, &repository.TouchPointByGroupIDCondition{
+		GroupID: campaign.GroupID,
+		Limit:   1000000,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get touch points")
+	}
+
+	expected := models.DeliveryDataSet{
+		// 審査OKでないクーポン・クリエイティブは配信データに含まれない
+		Campaign:    campaign.CreateDeliveryDataCampaign(models.ApprovedCampaignCreatives(cc)),
+		Creatives:   make([]*models.DeliveryDataCreative, 0, len(creatives)),
+		TouchPoints: make([]*models.DeliveryTouchPoint, 0, len(touchPoints)),
+	}
+	// 配信割合が数値でないクーポンがある場合は組み立てられないため、コンテンツはnilのまま差分として扱う
+	content, err := models.NewDeliveryDataContent(campaign.ID, models.ApprovedCoupons(coupons), gimmicks)
+	if err != nil {
+		r.logger.Warn().Err(err).Int("campaign_id", campaign.ID).Msg("Invalid content in RDB")
+	} else {
+		expected.Content = content
+	}
+	for _, creative := range creatives {
+		expected.Creatives = append(expected.Creatives, creative.CreateDeliveryDataCreative())
+	}
+	for _, touchPoint := range touchPoints {
+		expected.TouchPoints = append(expected.TouchPoints, &models.DeliveryTouchPoint{
+			ID:      touchPoint.ID,
+			GroupID: touchPoint.GroupID,
+			StoreID: touchPoint.StoreID,
+		})
+	}
+	return &expected, nil
+}
+
+func (r *reconcile) diff(ctx context.Context, campaignID int, expected *models.DeliveryDataSet, actual *reconcileDeliveryData) ([]*models.ReconcileDrift, error) {
+	drifts := []*models.ReconcileDrift{}
+	addDrift := func(kind string, detail string) {
+		drifts = append(drifts, &models.ReconcileDrift{CampaignID: campaignID, Kind: kind, Detail: detail})
+	}
+	id := strconv.Itoa(campaignID)
+
+	actualCampaign, err := r.campaignDataRepository.Get(ctx, &id)
+	switch {
+	case err == codes.ErrNoData:
+		addDrift(codes.DriftCampaignMissing, "")
+	case err != nil:
+		return nil, errors.Wrap(err, "Failed to get delivery campaign")
+	case !equalDeliveryCampaign(expected.Campaign, actualCampaign):
+		addDrift(codes.DriftCampaignMismatch, "")
+	}
+
+	actualContent, err := r.contentDataRepository.Get(ctx, &id)
+	switch {
+	case err == codes.ErrNoData:
+		addDrift(codes.DriftContentMissing, "")
+	case err != nil:
+		return nil, errors.Wrap(err, "Failed to get delivery content")
+	case expected.Content == nil:
+		addDrift(codes.DriftContentMismatch, "invalid content in RDB")
+	case !equalDeliveryContent(expected.Content, actualContent):
+		addDrift(codes.DriftContentMismatch, "")
+	}
+
+	for _, creative := range expected.Creatives {
+		if _, ok := actual.creatives[creative.ID]; !ok {
+			addDrift(codes.DriftCreativeMissing, fmt.Sprintf("creative_id: %s", creative.ID))
+		}
+	}
+
+	for _, touchPoint := range expected.TouchPoints {
+		if _, ok := actual.touchPoints[touchPoint.GroupID][touchPoint.ID]; !ok {
+			addDrift(codes.DriftTouchPointMissing, fmt.Sprintf("touch_point_id: %s", touchPoint.ID))
+		}
+	}
+	return drifts, nil
+}
+
+// 配信中でないキャンペーンの配信データを確認する
+func (r *reconcile) reconcileOrphan(ctx context.Context, deliveryCampaign *models.DeliveryDataCampaign, repair bool) (drift *models.ReconcileDrift, err error) {
+	campaign := deliveryCampaign.CreateCampaign()
+	var tx repository.Transaction
+	committed := false
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+		if !committed && tx != nil {
+			if terr := tx.Rollback(); terr != nil {
+				r.logger.Error().Err(terr).Int("campaign_id", campaign.ID).Msg("Failed to rollback")
+			}
+		}
+	}()
+	tx, err = r.transaction.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to begin transaction")
+	}
+	// 一覧取得後に開始された可能性があるため最新のステータスを確認する
+	current, err := r.campaignRepository.GetDeliveryToStart(ctx, tx, &repository.CampaignCondition{CampaignID: campaign.ID})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get campaign")
+	}
+	if current != nil {
+		switch current.Status {
+		case codes.StatusStarted, codes.StatusWarmup:
+			return nil, nil
+		}
+		campaign = current
+	}
+	drift = &models.ReconcileDrift{CampaignID: campaign.ID, Kind: codes.DriftOrphanCampaign, Detail: fmt.Sprintf("status: %s", campaign.Status)}
+	if current == nil {
+		drift.Detail = "campaign not found"
+	}
+	if repair {
+		err = r.deliveryEnd.Delete(ctx, tx, campaign)
+		if err == nil {
+			// 配信制御イベントを登録する
+			err = tx.Commit()
+			committed = err == nil
+		}
+		r.countRepair([]*models.ReconcileDrift{drift}, err)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to delete orphan delivery datas")
+		}
+		drift.Repaired = true
+	}
+	return drift, nil
+}
+
+// 配信中のキャンペーンの店舗グループにないタッチポイントの配信データを確認する
+// campaignは店舗グループの配信中のキャンペーン (配信中のキャンペーンがない店舗グループの場合はnil)
+func (r *reconcile) reconcileOrphanTouchPoints(ctx context.Context, groupID int, campaign *models.Campaign,
+	touchPoints []*models.DeliveryTouchPoint, repair bool) (drift *models.ReconcileDrift, err error) {
+	drift = &models.ReconcileDrift{
+		Kind:   codes.DriftOrphanTouchPoint,
+		Detail: fmt.Sprintf("group_id: %d, touch_points: %d", groupID, len(touchPoints)),
+	}
+	if campaign != nil {
+		drift.CampaignID = campaign.ID
+	}
+	if !repair {
+		return drift, nil
+	}
+	var tx repository.Transaction
+	committed := false
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+		if !committed && tx != nil {
+			if terr := tx.Rollback(); terr != nil {
+				r.logger.Error().Err(terr).Int("group_id", groupID).Msg("Failed to rollback")
+			}
+		}
+	}()
+	tx, err = r.transaction.Begin(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to begin transaction")
+	}
+	// キャンペーンに紐付かない店舗グループの配信制御イベントはキャンペーンIDを0で登録する
+	campaignID, orgCode := 0, ""
+	if campaign != nil {
+		// 配信終了処理と並行して処理しないようにキャンペーンをロックして、配信中のままか確認する
+		status, err := r.campaignRepository.GetStatusForUpdate(ctx, tx, campaign.ID)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get campaign status")
+		}
+		if status != codes.StatusStarted {
+			return nil, errors.Errorf("Campaign is no longer started. campaign_id: %d, status: %s", campaign.ID, status)
+		}
+		campaignID, orgCode = campaign.ID, campaign.OrgCode
+	} else {
+		// 一覧取得後に開始された可能性があるため、配信中のキャンペーンがないことを確認する
+		count, err := r.campaignRepository.GetDeliveryCampaignCountByGroupID(ctx, groupID)
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get started campaign count")
+		}
+		if count > 0 {
+			return nil, nil
+		}
+	}
+	deleteDatas := make([]models.DeliveryTouchPoint, 0, len(touchPoints))
+	for _, touchPoint := range touchPoints {
+		deleteDatas = append(deleteDatas, *touchPoint)
+	}
+	err = r.touchPointDataRepository.DeleteAll(ctx, &deleteDatas)
+	if err == nil {
+		err = r.deliveryControlEvent.PublishDeliveryEvents(ctx, tx, touchPoints, campaignID, orgCode, "DELETE")
+	}
+	if err == nil {
+		// 配信制御イベントを登録する
+		err = tx.Commit()
+		committed = err == nil
+	}
+	r.countRepair([]*models.ReconcileDrift{drift}, err)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to delete orphan touch points")
+	}
+	drift.Repaired = true
+	return drift, nil
+}
+
+// どのキャンペーンにも紐付かないクリエイティブの配信データを確認する (修復する場合は有効期限(TTL)を1日後に更新する)
+func (r *reconcile) reconcileOrphanCreatives(ctx context.Context, repair bool) ([]*models.ReconcileDrift, error) {
+	current := time.Now()
+	orphans, err := r.maintenance.PurgeOrphanCreatives(ctx, current, true)
+	if err != nil {
+		return nil, err
+	}
+	drifts := make([]*models.ReconcileDrift, 0, len(orphans.Orphans))
+	for _, orphan := range orphans.Orphans {
+		drift := &models.ReconcileDrift{Kind: codes.DriftOrphanCreative, Detail: fmt.Sprintf("creative_id: %s", orphan)}
+		drifts = append(drifts, drift)
+		if !repair {
+			continue
+		}
+		creativeID, err := strconv.Atoi(orphan)
+		if err == nil {
+			err = r.creative.Expire(ctx, current, creativeID)
+		}
+		r.countRepair([]*models.ReconcileDrift{drift}, err)
+		if err != nil {
+			r.logger.Error().Err(err).Str("creative_id", orphan).Msg("Failed to expire orphan creative")
+			continue
+		}
+		drift.Repaired = true
+	}
+	return drifts, nil
+}
+
+func (r *reconcile) countRepair(drifts []*models.ReconcileDrift, err error) {
+	result := "success"
+	if err != nil {
+		result = "error"
+	}
+	for _, drift := range drifts {
+		r.monitor.Metrics.GetCounter(metricReconcileRepairTotal).WithLabelValues(drift.Kind, result).Inc()
+	}
+}
+
+// ステータスは開始処理時点(warmup)の値で登録されるため比較しない
+func equalDeliveryCampaign(expected *models.DeliveryDataCampaign, actual *models.DeliveryDataCampaign) bool {
+	if expected.ID != actual.ID || expected.GroupID != actual.GroupID ||
+		expected.OrgCode != actual.OrgCode || expected.DailyLimit != actual.DailyLimit ||
+		expected.DailyStoreLimit != actual.DailyStoreLimit {
+		return false
+	}
+	if len(expected.Creatives) != len(actual.Creatives) {
+		return false
+	}
+	// クリエイティブの審査ステータスは配信データに含まれないため比較しない
+	for i := range expected.Creatives {
+		if expected.Creatives[i].ID != actual.Creatives[i].ID || expected.Creatives[i].Rate != actual.Creatives[i].Rate ||
+			expected.Creatives[i].SkipOffset != actual.Creatives[i].SkipOffset {
+			return false
+		}
+	}
+	return true
+}
+
+func equalDeliveryContent(expected *models.DeliveryDataContent, actual *models.DeliveryDataContent) bool {
+	if expected.CampaignID != actual.CampaignID || len(expected.Coupons) != len(actual.Coupons) {
+		return false
+	}
+	for i := range expected.Coupons {
+		if expected.Coupons[i] != actual.Coupons[i] {
+			return false
+		}
+	}
+	return expected.Version == actual.Version &&
+		reflect.DeepEqual(expected.Gimmicks, actual.Gimmicks) &&
+		reflect.DeepEqual(expected.GimmickList, actual.GimmickList)
+}
@@ -0,0 +1,596 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcile_Run(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	// RDBのデータ
+	campaign := &models.Campaign{ID: 1, GroupID: 10, OrgCode: "org", DailyCouponLimitPerUser: 1, Status: codes.StatusStarted}
+	cc := []*models.CampaignCreative{{ID: 100, Rate: 100, Status: codes.ReviewStatusApproved}}
+	creatives := []*models.Creative{{ID: 100}}
+	gimmickURL := "https://example.com/gimmick"
+	gimmicks := []*models.Gimmick{{ID: 1, URL: &gimmickURL}}
+	coupons := []*models.Coupon{{ID: 1, Rate: "100", Status: codes.ReviewStatusApproved}}
+	touchPoints := []*models.TouchPoint{{GroupID: 10, StoreID: "s1", ID: "tp1"}}
+	// DynamoDBの配信データ (ステータスは開始処理時点の値なので比較しない・審査ステータスは含まれない)
+	deliveryCampaign := &models.DeliveryDataCampaign{ID: "1", GroupID: "10", OrgCode: "org", DailyLimit: 1,
+		Creatives: []*models.CampaignCreative{{ID: 100, Rate: 100}}, Status: codes.StatusWarmup}
+	deliveryContent, err := models.NewDeliveryDataContent(1, coupons, gimmicks)
+	if err != nil {
+		t.Fatal(err)
+	}
+	deliveryCreative := &models.DeliveryDataCreative{ID: "100"}
+	deliveryTouchPoint := &models.DeliveryTouchPoint{GroupID: 10, StoreID: "s1", ID: "tp1"}
+	noOrphanCreatives := &models.CreativePurgeResult{DryRun: true, Orphans: []string{}}
+
+	id := "1"
+	condition := &repository.CampaignByStatusCondition{Status: []string{codes.StatusStarted, codes.StatusWarmup}}
+	creativeCondition := &repository.CreativeByCampaignIDCondition{CampaignID: 1, Limit: 100}
+	contentCondition := &repository.ContentByCampaignIDCondition{CampaignID: 1}
+	touchPointCondition := &repository.TouchPointByGroupIDCondition{GroupID: 10, Limit: 1000000}
+
+	t.Run("差分がない場合はDriftを返さない", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{campaign}, nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).Return(cc, nil)
+		creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creativeCondition)).Return(creatives, nil)
+		contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(gimmicks, nil)
+		contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(coupons, nil)
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(touchPointCondition)).Return(touchPoints, nil)
+		campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryCampaign, nil)
+		contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryContent, nil)
+		tx.EXPECT().Commit().Return(nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{deliveryCampaign}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{deliveryTouchPoint}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{deliveryCreative}, nil)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, actual.Checked)
+			assert.Empty(t, actual.Drifts)
+		}
+	})
+
+	t.Run("配信データが欠けている場合は再作成して修復する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		changed := &models.DeliveryDataContent{CampaignID: "1", Coupons: []models.DeliveryCouponData{{ID: 1, Rate: 50}}}
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{campaign}, nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).Return(cc, nil)
+		creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creativeCondition)).Return(creatives, nil)
+		contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(gimmicks, nil)
+		contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(coupons, nil)
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(touchPointCondition)).Return(touchPoints, nil)
+		campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil, codes.ErrNoData)
+		contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(changed, nil)
+		deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(nil)
+		tx.EXPECT().Commit().Return(nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{}, nil)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			kinds := []string{}
+			for _, drift := range actual.Drifts {
+				assert.True(t, drift.Repaired)
+				kinds = append(kinds, drift.Kind)
+			}
+			assert.Equal(t, []string{
+				codes.DriftCampaignMissing,
+				codes.DriftContentMismatch,
+				codes.DriftCreativeMissing,
+				codes.DriftTouchPointMissing,
+			}, kinds)
+		}
+	})
+
+	t.Run("修復しない場合は差分の報告のみ行う", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{campaign}, nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).Return(cc, nil)
+		creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creativeCondition)).Return(creatives, nil)
+		contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(gimmicks, nil)
+		contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(coupons, nil)
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(touchPointCondition)).Return(touchPoints, nil)
+		campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(nil, codes.ErrNoData)
+		contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryContent, nil)
+		tx.EXPECT().Commit().Return(nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{deliveryTouchPoint}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{deliveryCreative}, nil)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, false)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*models.ReconcileDrift{{CampaignID: 1, Kind: codes.DriftCampaignMissing}}, actual.Drifts)
+		}
+	})
+
+	t.Run("配信上限数が異なる場合はキャンペーンの差分として報告する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		changed := *deliveryCampaign
+		changed.DailyStoreLimit = 10
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{campaign}, nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).Return(cc, nil)
+		creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creativeCondition)).Return(creatives, nil)
+		contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(gimmicks, nil)
+		contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(coupons, nil)
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(touchPointCondition)).Return(touchPoints, nil)
+		campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(&changed, nil)
+		contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryContent, nil)
+		tx.EXPECT().Commit().Return(nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{deliveryTouchPoint}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{deliveryCreative}, nil)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, false)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*models.ReconcileDrift{{CampaignID: 1, Kind: codes.DriftCampaignMismatch}}, actual.Drifts)
+		}
+	})
+
+	t.Run("審査OKでないクリエイティブ・クーポンは配信データにないものとして比較する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		// 審査中("1")のものを含めると配信割合の合計が設定値と一致せず開始処理の検証には通らないが、差分の確認は行う
+		withPending := append([]*models.CampaignCreative{{ID: 101, Rate: 50, Status: "1"}}, cc...)
+		couponsWithPending := append([]*models.Coupon{{ID: 2, Rate: "50", Status: "1"}}, coupons...)
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{campaign}, nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).Return(withPending, nil)
+		creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creativeCondition)).Return(creatives, nil)
+		contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(gimmicks, nil)
+		contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(couponsWithPending, nil)
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(touchPointCondition)).Return(touchPoints, nil)
+		campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryCampaign, nil)
+		contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryContent, nil)
+		tx.EXPECT().Commit().Return(nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{deliveryCampaign}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{deliveryTouchPoint}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{deliveryCreative}, nil)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 1, actual.Checked)
+			assert.Empty(t, actual.Drifts)
+		}
+	})
+
+	t.Run("配信内容に不備があり修復できない場合は差分を修復せずに報告する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		invalidCoupons := []*models.Coupon{{ID: 1, Rate: "abc", Status: codes.ReviewStatusApproved}}
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{campaign}, nil)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryContent, nil),
+			deliveryStart.EXPECT().CreateDeliveryDatas(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign)).Return(errors.New("invalid campaign")),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+		campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).Return(cc, nil)
+		creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creativeCondition)).Return(creatives, nil)
+		contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(gimmicks, nil)
+		contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(invalidCoupons, nil)
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(touchPointCondition)).Return(touchPoints, nil)
+		campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryCampaign, nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{deliveryCampaign}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{deliveryTouchPoint}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{deliveryCreative}, nil)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*models.ReconcileDrift{{CampaignID: 1, Kind: codes.DriftContentMismatch, Detail: "invalid content in RDB"}}, actual.Drifts)
+		}
+	})
+
+	t.Run("配信中でないキャンペーンの配信データは削除する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		ended := &models.Campaign{ID: 2, GroupID: 10, OrgCode: "org", Status: codes.StatusEnded}
+		warmup := &models.Campaign{ID: 3, GroupID: 10, OrgCode: "org", Status: codes.StatusWarmup}
+		deliveryCampaigns := []*models.DeliveryDataCampaign{
+			{ID: "2", GroupID: "10", OrgCode: "org", Status: codes.StatusStarted},
+			{ID: "3", GroupID: "10", OrgCode: "org", Status: codes.StatusWarmup},
+		}
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{}, nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return(deliveryCampaigns, nil)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 2})).Return(ended, nil),
+			deliveryEnd.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(ended)).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			// 開始処理中のキャンペーンは削除しない
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryToStart(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 3})).Return(warmup, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{}, nil)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, 0, actual.Checked)
+			assert.Equal(t, []*models.ReconcileDrift{{CampaignID: 2, Kind: codes.DriftOrphanCampaign, Detail: "status: ended", Repaired: true}}, actual.Drifts)
+		}
+	})
+
+	t.Run("配信中のキャンペーンの店舗グループにないタッチポイントは削除する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		warmup := &models.Campaign{ID: 3, GroupID: 30, OrgCode: "org", Status: codes.StatusWarmup}
+		// RDBから削除されたタッチポイント
+		deleted := &models.DeliveryTouchPoint{GroupID: 10, StoreID: "s1", ID: "tp2"}
+		// 配信中のキャンペーンがない店舗グループのタッチポイント
+		noCampaign := &models.DeliveryTouchPoint{GroupID: 20, StoreID: "s2", ID: "tp3"}
+		// 開始処理中のキャンペーンの店舗グループのタッチポイントは削除しない
+		prewarmed := &models.DeliveryTouchPoint{GroupID: 30, StoreID: "s3", ID: "tp4"}
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{campaign, warmup}, nil)
+		transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil)
+		campaignRepository.EXPECT().GetCampaignCreative(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(&repository.CampaignCondition{CampaignID: 1})).Return(cc, nil)
+		creativeRepository.EXPECT().GetCreativeByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(creativeCondition)).Return(creatives, nil)
+		contentRepository.EXPECT().GetGimmicksByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(gimmicks, nil)
+		contentRepository.EXPECT().GetCouponsByCampaignID(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(contentCondition)).Return(coupons, nil)
+		touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(touchPointCondition)).Return(touchPoints, nil)
+		campaignDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryCampaign, nil)
+		contentDataRepository.EXPECT().Get(gomock.Eq(ctx), gomock.Eq(&id)).Return(deliveryContent, nil)
+		tx.EXPECT().Commit().Return(nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{deliveryCampaign}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{deliveryTouchPoint, deleted, noCampaign, prewarmed}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{deliveryCreative}, nil)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(campaign.ID)).Return(codes.StatusStarted, nil),
+			touchPointDataRepository.EXPECT().DeleteAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{*deleted})).Return(nil),
+			deliveryControlEvent.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]*models.DeliveryTouchPoint{deleted}), gomock.Eq(campaign.ID), gomock.Eq(campaign.OrgCode), gomock.Eq("DELETE")).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(20)).Return(0, nil),
+			touchPointDataRepository.EXPECT().DeleteAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{*noCampaign})).Return(nil),
+			deliveryControlEvent.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq([]*models.DeliveryTouchPoint{noCampaign}), gomock.Eq(0), gomock.Eq(""), gomock.Eq("DELETE")).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+		)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*models.ReconcileDrift{
+				{CampaignID: 1, Kind: codes.DriftOrphanTouchPoint, Detail: "group_id: 10, touch_points: 1", Repaired: true},
+				{CampaignID: 0, Kind: codes.DriftOrphanTouchPoint, Detail: "group_id: 20, touch_points: 1", Repaired: true},
+			}, actual.Drifts)
+		}
+	})
+
+	t.Run("削除までに配信が開始された店舗グループのタッチポイントは削除しない", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		noCampaign := &models.DeliveryTouchPoint{GroupID: 20, StoreID: "s2", ID: "tp3"}
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{}, nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{noCampaign}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{}, nil)
+		gomock.InOrder(
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetDeliveryCampaignCountByGroupID(gomock.Eq(ctx), gomock.Eq(20)).Return(1, nil),
+			tx.EXPECT().Rollback().Return(nil),
+		)
+		maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(noOrphanCreatives, nil)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			assert.Empty(t, actual.Drifts)
+		}
+	})
+
+	t.Run("どのキャンペーンにも紐付かないクリエイティブは有効期限を更新する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish() // 定義したmockの処理が想定どおり呼ばれているかチェックが行われる
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		creativeRepository := mock_repository.NewMockCreativeRepository(ctrl)
+		contentRepository := mock_repository.NewMockContentRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		campaignDataRepository := mock_repository.NewMockDeliveryDataCampaignRepository(ctrl)
+		contentDataRepository := mock_repository.NewMockDeliveryDataContentRepository(ctrl)
+		creativeDataRepository := mock_repository.NewMockDeliveryDataCreativeRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+		deliveryStart := mock_usecase.NewMockDeliveryStart(ctrl)
+		deliveryEnd := mock_usecase.NewMockDeliveryEnd(ctrl)
+		maintenance := mock_usecase.NewMockMaintenance(ctrl)
+		creative := mock_usecase.NewMockCreative(ctrl)
+
+		// mockの処理を定義
+		ctx := context.Background()
+		campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(condition)).Return([]*models.Campaign{}, nil)
+		campaignDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCampaign{}, nil)
+		touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{}, nil)
+		creativeDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryDataCreative{}, nil)
+		gomock.InOrder(
+			// 対象の確認のみ行い、有効期限はDriftごとに更新する
+			maintenance.EXPECT().PurgeOrphanCreatives(gomock.Eq(ctx), gomock.Any(), gomock.Eq(true)).Return(
+				&models.CreativePurgeResult{DryRun: true, Scanned: 3, Orphans: []string{"200", "201"}}, nil),
+			creative.EXPECT().Expire(gomock.Eq(ctx), gomock.Any(), gomock.Eq(200)).Return(nil),
+			creative.EXPECT().Expire(gomock.Eq(ctx), gomock.Any(), gomock.Eq(201)).Return(errors.New("dynamodb error")),
+		)
+
+		// テストを実行する
+		reconcile := NewReconcile(logger, metrics.GetMonitor(), transactionHandler, campaignRepository,
+			creativeRepository, contentRepository, touchPointRepository,
+			campaignDataRepository, contentDataRepository, creativeDataRepository, touchPointDataRepository,
+			deliveryControlEvent, deliveryStart, deliveryEnd, maintenance, creative)
+		actual, err := reconcile.Run(ctx, true)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*models.ReconcileDrift{
+				{Kind: codes.DriftOrphanCreative, Detail: "creative_id: 200", Repaired: true},
+				{Kind: codes.DriftOrphanCreative, Detail: "creative_id: 201"},
+			}, actual.Drifts)
+		}
+	})
+}
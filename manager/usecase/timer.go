@@ -3,16 +3,40 @@ package usecase
 
 import (
 	"context"
+	"fmt"
+	"sort"
+	"sync"
 	"time"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
 )
 
 // Timer is interface
 type Timer interface {
 	ExecuteAtTime(ctx context.Context, specifiedTime time.Time, process func())
+	// Reserve 予約(キャンペーンID, 処理種別, 実行時間)を登録して指定時間に実行する
+	// 同じキャンペーン・処理種別の予約は置き換える
+	Reserve(ctx context.Context, reservation *models.Reservation, process func())
+	// Handle 再起動後に予約を読み込み直した際に実行する処理を処理種別毎に登録する
+	Handle(action string, handler func(reservation *models.Reservation))
+	// Restore 保存されている予約を読み込み直す (実行時間を過ぎているものはすぐに実行する)
+	Restore(ctx context.Context) error
+	// Pending 未実行の予約を取得する
+	Pending(ctx context.Context) ([]*models.Reservation, error)
+	// Cancel 予約を取り消す (予約がない場合は何もしない)
+	Cancel(ctx context.Context, campaignID int, action string)
 }
 
 type timer struct {
-	logger Logger
+	logger  Logger
+	mutex   sync.Mutex
+	pending map[string]*pendingReservation
+}
+
+type pendingReservation struct {
+	reservation *models.Reservation
+	cancel      context.CancelFunc
 }
 
 // NewTimer is function
@@ -20,7 +44,8 @@ func NewTimer(
 	logger Logger,
 ) Timer {
 	return &timer{
-		logger: logger,
+		logger:  logger,
+		pending: map[string]*pendingReservation{},
 	}
 }
 
@@ -43,3 +68,256 @@ func (d *timer) ExecuteAtTime(ctx context.Context, specifiedTime time.Time, proc
 		}
 	}()
 }
+
+// 予約を登録して指定時間に実行する (メモリ上のみで保持する)
+func (d *timer) Reserve(ctx context.Context, reservation *models.Reservation, process func()) {
+	key := reservationKey(reservation)
+	rctx, cancel := context.WithCancel(ctx)
+	current := &pendingReservation{reservation: reservation, cancel: cancel}
+	d.mutex.Lock()
+	if p, ok := d.pending[key]; ok {
+		// 同じ予約は置き換える
+		p.cancel()
+	}
+	d.pending[key] = current
+	d.mutex.Unlock()
+
+	remove := func() {
+		d.mutex.Lock()
+		defer d.mutex.Unlock()
+		if p, ok := d.pending[key]; ok && p == current {
+			delete(d.pending, key)
+		}
+	}
+	go func() {
+		<-rctx.Done()
+		remove()
+	}()
+	d.ExecuteAtTime(rctx, reservation.FireAt, func() {
+		defer cancel()
+		remove()
+		process()
+	})
+}
+
+// メモリ上の予約は再起動時に失われるため何もしない
+func (d *timer) Handle(action string, handler func(reservation *models.Reservation)) {
+}
+
+// メモリ上の予約は再起動時に失われるため何もしない
+func (d *timer) Restore(ctx context.Context) error {
+	return nil
+}
+
+func (d *timer) Pending(ctx context.Context) ([]*models.Reservation, error) {
+	d.mutex.Lock()
+	reservations := make([]*models.Reservation, 0, len(d.pending))
+	for _, p := range d.pending {
+		reservations = append(reservations, p.reservation)
+	}
+	d.mutex.Unlock()
+	sort.Slice(reservations, func(i, j int) bool {
+		return reservations[i].FireAt.Before(reservations[j].FireAt)
+	})
+	return reservations, nil
+}
+
+func (d *timer) Cancel(ctx context.Context, campaignID int, action string) {
+	key := reservationKey(&models.Reservation{CampaignID: campaignID, Action: action})
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	if p, ok := d.pending[key]; ok {
+		p.cancel()
+		delete(d.pending, key)
+	}
+}
+
+func reservationKey(reservation *models.Reservation) string {
+	return fmt.Sprintf("%s/%d", reservation.Action, reservation.CampaignID)
+}
+
+type leaderTimer struct {
+	timer          Timer
+	leaderElection LeaderElection
+}
+
+// NewLeaderTimer リーダーである間だけ予約を保持するTimer
+// リーダーでなくなった場合は予約をキャンセルする (新しいリーダーが開始/終了処理で拾い直す)
+func NewLeaderTimer(
+	timer Timer,
+	leaderElection LeaderElection,
+) Timer {
+	return &leaderTimer{
+		timer:          timer,
+		leaderElection: leaderElection,
+	}
+}
+
+func (l *leaderTimer) ExecuteAtTime(ctx context.Context, specifiedTime time.Time, process func()) {
+	tctx, cancel, ok := l.termContext(ctx)
+	if !ok {
+		// リーダーでない場合は予約しない
+		return
+	}
+	l.timer.ExecuteAtTime(tctx, specifiedTime, func() {
+		defer cancel()
+		process()
+	})
+}
+
+func (l *leaderTimer) Reserve(ctx context.Context, reservation *models.Reservation, process func()) {
+	tctx, cancel, ok := l.termContext(ctx)
+	if !ok {
+		// リーダーでない場合は予約しない
+		return
+	}
+	l.timer.Reserve(tctx, reservation, func() {
+		defer cancel()
+		process()
+	})
+}
+
+func (l *leaderTimer) Handle(action string, handler func(reservation *models.Reservation)) {
+	l.timer.Handle(action, handler)
+}
+
+func (l *leaderTimer) Restore(ctx context.Context) error {
+	return l.timer.Restore(ctx)
+}
+
+func (l *leaderTimer) Pending(ctx context.Context) ([]*models.Reservation, error) {
+	return l.timer.Pending(ctx)
+}
+
+// 保存された予約はリーダーでなくても取り消す
+func (l *leaderTimer) Cancel(ctx context.Context, campaignID int, action string) {
+	l.timer.Cancel(ctx, campaignID, action)
+}
+
+// termContext リーダーでなくなった時点で終了するcontextを作成する
+func (l *leaderTimer) termContext(ctx context.Context) (context.Context, context.CancelFunc, bool) {
+	termCtx := l.leaderElection.Context()
+	if termCtx.Err() != nil {
+		return nil, nil, false
+	}
+	tctx, cancel := context.WithCancel(ctx)
+	go func() {
+		select {
+		case <-termCtx.Done():
+			cancel()
+		case <-tctx.Done():
+		}
+	}()
+	return tctx, cancel, true
+}
+
+var (
+	metricTimerReservationTotal       = "timer_reservation_total"
+	metricTimerReservationTotalDesc   = "timer reservation count"
+	metricTimerReservationTotalLabels = []string{"action", "kind"}
+)
+
+type reservationTimer struct {
+	logger                Logger
+	monitor               *metrics.Monitor
+	timer                 Timer
+	reservationRepository repository.ReservationRepository
+	mutex                 sync.RWMutex
+	handlers              map[string]func(reservation *models.Reservation)
+}
+
+// NewReservationTimer 予約をRDBに保存して再起動後も実行できるようにするTimer
+// リーダーでない場合に予約を保存しないよう、NewLeaderTimerの内側で使用する
+func NewReservationTimer(
+	logger Logger,
+	monitor *metrics.Monitor,
+	timer Timer,
+	reservationRepository repository.ReservationRepository,
+) Timer {
+	monitor.Metrics.AddCounter(metricTimerReservationTotal, metricTimerReservationTotalDesc, metricTimerReservationTotalLabels)
+	return &reservationTimer{
+		logger:                logger,
+		monitor:               monitor,
+		timer:                 timer,
+		reservationRepository: reservationRepository,
+		handlers:              map[string]func(reservation *models.Reservation){},
+	}
+}
+
+func (r *reservationTimer) ExecuteAtTime(ctx context.Context, specifiedTime time.Time, process func()) {
+	r.timer.ExecuteAtTime(ctx, specifiedTime, process)
+}
+
+func (r *reservationTimer) Reserve(ctx context.Context, reservation *models.Reservation, process func()) {
+	if err := r.reservationRepository.Save(ctx, reservation); err != nil {
+		// 保存に失敗してもメモリ上の予約は行う (再起動時は開始/終了の監視で拾い直す)
+		r.monitor.Metrics.GetCounter(metricTimerReservationTotal).WithLabelValues(reservation.Action, "save_error").Inc()
+		r.logger.Error().Err(err).Int("campaign_id", reservation.CampaignID).Str("action", reservation.Action).Msg("Failed to save reservation")
+	} else {
+		r.monitor.Metrics.GetCounter(metricTimerReservationTotal).WithLabelValues(reservation.Action, "reserved").Inc()
+	}
+	r.reserve(ctx, reservation, process)
+}
+
+func (r *reservationTimer) Handle(action string, handler func(reservation *models.Reservation)) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.handlers[action] = handler
+	r.timer.Handle(action, handler)
+}
+
+func (r *reservationTimer) Restore(ctx context.Context) error {
+	reservations, err := r.reservationRepository.GetAll(ctx)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	for i := range reservations {
+		reservation := reservations[i]
+		r.mutex.RLock()
+		handler, ok := r.handlers[reservation.Action]
+		r.mutex.RUnlock()
+		if !ok {
+			r.logger.Warn().Int("campaign_id", reservation.CampaignID).Str("action", reservation.Action).Msg("Unknown reservation action")
+			continue
+		}
+		kind := "restored"
+		if reservation.FireAt.Before(now) {
+			// 実行時間を過ぎているものはすぐに実行される
+			kind = "overdue"
+		}
+		r.monitor.Metrics.GetCounter(metricTimerReservationTotal).WithLabelValues(reservation.Action, kind).Inc()
+		r.logger.Info().Int("campaign_id", reservation.CampaignID).Str("action", reservation.Action).
+			Time("fire_at", reservation.FireAt).Str("kind", kind).Msg("Restore reservation")
+		r.reserve(ctx, reservation, func() {
+			handler(reservation)
+		})
+	}
+	return nil
+}
+
+func (r *reservationTimer) Pending(ctx context.Context) ([]*models.Reservation, error) {
+	return r.reservationRepository.GetAll(ctx)
+}
+
+func (r *reservationTimer) Cancel(ctx context.Context, campaignID int, action string) {
+	r.timer.Cancel(ctx, campaignID, action)
+	if err := r.reservationRepository.Delete(ctx, campaignID, action); err != nil {
+		// 削除できなかった予約は再起動後に実行されるが、開始/終了処理でステータスを確認するため処理はされない
+		r.logger.Error().Err(err).Int("campaign_id", campaignID).Str("action", action).Msg("Failed to delete reservation")
+		return
+	}
+	r.monitor.Metrics.GetCounter(metricTimerReservationTotal).WithLabelValues(action, "cancelled").Inc()
+}
+
+// 実行後に保存した予約を削除する
+// (キャンセルされた場合は再起動後に実行できるように残しておく)
+func (r *reservationTimer) reserve(ctx context.Context, reservation *models.Reservation, process func()) {
+	r.timer.Reserve(ctx, reservation, func() {
+		process()
+		r.monitor.Metrics.GetCounter(metricTimerReservationTotal).WithLabelValues(reservation.Action, "fired").Inc()
+		if err := r.reservationRepository.Delete(context.Background(), reservation.CampaignID, reservation.Action); err != nil {
+			r.logger.Error().Err(err).Int("campaign_id", reservation.CampaignID).Str("action", reservation.Action).Msg("Failed to delete reservation")
+		}
+	})
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimer_Reserve(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("指定時間に実行され、実行後は未実行の予約から消える", func(t *testing.T) {
+		timer := NewTimer(logger)
+		ctx := context.Background()
+		reservation := models.Reservation{CampaignID: 1, Action: "start", FireAt: time.Now().Add(50 * time.Millisecond)}
+
+		executed := make(chan struct{})
+		timer.Reserve(ctx, &reservation, func() {
+			close(executed)
+		})
+		pending, err := timer.Pending(ctx)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*models.Reservation{&reservation}, pending)
+		}
+		select {
+		case <-executed:
+		case <-time.After(time.Second):
+			assert.Fail(t, "not executed")
+		}
+		assert.Eventually(t, func() bool {
+			pending, _ := timer.Pending(ctx)
+			return len(pending) == 0
+		}, time.Second, 10*time.Millisecond)
+	})
+
+	t.Run("同じ予約は置き換える", func(t *testing.T) {
+		timer := NewTimer(logger)
+		ctx := context.Background()
+		first := models.Reservation{CampaignID: 1, Action: "end", FireAt: time.Now().Add(50 * time.Millisecond)}
+		second := models.Reservation{CampaignID: 1, Action: "end", FireAt: time.Now().Add(100 * time.Millisecond)}
+
+		result := make(chan string, 2)
+		timer.Reserve(ctx, &first, func() {
+			result <- "first"
+		})
+		timer.Reserve(ctx, &second, func() {
+			result <- "second"
+		})
+		pending, err := timer.Pending(ctx)
+		if assert.NoError(t, err) {
+			assert.Equal(t, []*models.Reservation{&second}, pending)
+		}
+		select {
+		case actual := <-result:
+			assert.Equal(t, "second", actual)
+		case <-time.After(time.Second):
+			assert.Fail(t, "not executed")
+		}
+	})
+}
+
+func TestTimer_Cancel(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("取り消した予約は実行されず、未実行の予約から消える", func(t *testing.T) {
+		timer := NewTimer(logger)
+		ctx := context.Background()
+		reservation := models.Reservation{CampaignID: 1, Action: "start", FireAt: time.Now().Add(50 * time.Millisecond)}
+
+		executed := false
+		timer.Reserve(ctx, &reservation, func() {
+			executed = true
+		})
+		timer.Cancel(ctx, 1, "start")
+		// 予約がない場合は何もしない
+		timer.Cancel(ctx, 2, "start")
+		pending, err := timer.Pending(ctx)
+		if assert.NoError(t, err) {
+			assert.Empty(t, pending)
+		}
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, executed)
+	})
+}
+
+func TestReservationTimer_Reserve(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("予約を保存して、実行後に削除する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reservationRepository := mock_repository.NewMockReservationRepository(ctrl)
+		timer := NewReservationTimer(logger, metrics.GetMonitor(), NewTimer(logger), reservationRepository)
+		ctx := context.Background()
+		reservation := models.Reservation{CampaignID: 1, Action: "start", FireAt: time.Now().Add(10 * time.Millisecond)}
+
+		deleted := make(chan struct{})
+		gomock.InOrder(
+			reservationRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(&reservation)).Return(nil).Times(1),
+			reservationRepository.EXPECT().Delete(gomock.Any(), gomock.Eq(1), gomock.Eq("start")).DoAndReturn(
+				func(ctx context.Context, campaignID int, action string) error {
+					close(deleted)
+					return nil
+				}).Times(1),
+		)
+		executed := false
+		timer.Reserve(ctx, &reservation, func() {
+			executed = true
+		})
+		select {
+		case <-deleted:
+			assert.True(t, executed)
+		case <-time.After(time.Second):
+			assert.Fail(t, "not deleted")
+		}
+	})
+
+	t.Run("保存に失敗しても予約は実行する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reservationRepository := mock_repository.NewMockReservationRepository(ctrl)
+		timer := NewReservationTimer(logger, metrics.GetMonitor(), NewTimer(logger), reservationRepository)
+		ctx := context.Background()
+		reservation := models.Reservation{CampaignID: 1, Action: "end", FireAt: time.Now()}
+
+		reservationRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(&reservation)).Return(errors.New("error")).Times(1)
+		reservationRepository.EXPECT().Delete(gomock.Any(), gomock.Eq(1), gomock.Eq("end")).Return(nil).Times(1)
+		executed := make(chan struct{})
+		timer.Reserve(ctx, &reservation, func() {
+			close(executed)
+		})
+		select {
+		case <-executed:
+		case <-time.After(time.Second):
+			assert.Fail(t, "not executed")
+		}
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	t.Run("キャンセルされた場合は予約を残す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reservationRepository := mock_repository.NewMockReservationRepository(ctrl)
+		timer := NewReservationTimer(logger, metrics.GetMonitor(), NewTimer(logger), reservationRepository)
+		ctx, cancel := context.WithCancel(context.Background())
+		reservation := models.Reservation{CampaignID: 1, Action: "end", FireAt: time.Now().Add(50 * time.Millisecond)}
+
+		reservationRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(&reservation)).Return(nil).Times(1)
+		// Deleteは呼ばれない
+		executed := false
+		timer.Reserve(ctx, &reservation, func() {
+			executed = true
+		})
+		cancel()
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, executed)
+	})
+}
+
+func TestReservationTimer_Cancel(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("予約を取り消して保存した予約を削除する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reservationRepository := mock_repository.NewMockReservationRepository(ctrl)
+		timer := NewReservationTimer(logger, metrics.GetMonitor(), NewTimer(logger), reservationRepository)
+		ctx := context.Background()
+		reservation := models.Reservation{CampaignID: 1, Action: "end", FireAt: time.Now().Add(50 * time.Millisecond)}
+
+		gomock.InOrder(
+			reservationRepository.EXPECT().Save(gomock.Eq(ctx), gomock.Eq(&reservation)).Return(nil).Times(1),
+			reservationRepository.EXPECT().Delete(gomock.Eq(ctx), gomock.Eq(1), gomock.Eq("end")).Return(nil).Times(1),
+		)
+		executed := false
+		timer.Reserve(ctx, &reservation, func() {
+			executed = true
+		})
+		timer.Cancel(ctx, 1, "end")
+		time.Sleep(100 * time.Millisecond)
+		assert.False(t, executed)
+	})
+
+	t.Run("リーダーでない場合は予約を保存しない", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reservationRepository := mock_repository.NewMockReservationRepository(ctrl)
+		leaseRepository := mock_repository.NewMockLeaseRepository(ctrl)
+		// Runしていないためリーダーではない
+		leaderElection := NewLeaderElection(logger, metrics.GetMonitor(), &config.LeaderElection{Enabled: false, LeaseName: "test"}, leaseRepository)
+		timer := NewLeaderTimer(NewReservationTimer(logger, metrics.GetMonitor(), NewTimer(logger), reservationRepository), leaderElection)
+		ctx := context.Background()
+		reservation := models.Reservation{CampaignID: 1, Action: "start", FireAt: time.Now()}
+
+		// Saveは呼ばれない
+		executed := false
+		timer.Reserve(ctx, &reservation, func() {
+			executed = true
+		})
+		time.Sleep(50 * time.Millisecond)
+		assert.False(t, executed)
+	})
+}
+
+func TestReservationTimer_Restore(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+
+	t.Run("実行時間を過ぎた予約はすぐに実行し、未来の予約は指定時間に実行する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reservationRepository := mock_repository.NewMockReservationRepository(ctrl)
+		timer := NewReservationTimer(logger, metrics.GetMonitor(), NewTimer(logger), reservationRepository)
+		ctx := context.Background()
+		overdue := &models.Reservation{CampaignID: 1, Action: "start", FireAt: time.Now().Add(-time.Minute)}
+		future := &models.Reservation{CampaignID: 2, Action: "end", FireAt: time.Now().Add(100 * time.Millisecond)}
+		unknown := &models.Reservation{CampaignID: 3, Action: "unknown", FireAt: time.Now()}
+
+		reservationRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.Reservation{overdue, future, unknown}, nil).Times(1)
+		reservationRepository.EXPECT().Delete(gomock.Any(), gomock.Eq(1), gomock.Eq("start")).Return(nil).Times(1)
+		reservationRepository.EXPECT().Delete(gomock.Any(), gomock.Eq(2), gomock.Eq("end")).Return(nil).Times(1)
+
+		result := make(chan *models.Reservation, 2)
+		timer.Handle("start", func(reservation *models.Reservation) {
+			result <- reservation
+		})
+		timer.Handle("end", func(reservation *models.Reservation) {
+			result <- reservation
+		})
+		err := timer.Restore(ctx)
+		if assert.NoError(t, err) {
+			select {
+			case actual := <-result:
+				assert.Equal(t, overdue, actual)
+			case <-time.After(50 * time.Millisecond):
+				assert.Fail(t, "overdue reservation not executed")
+			}
+			select {
+			case actual := <-result:
+				assert.Equal(t, future, actual)
+			case <-time.After(time.Second):
+				assert.Fail(t, "future reservation not executed")
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+	})
+
+	t.Run("予約の取得に失敗した場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		reservationRepository := mock_repository.NewMockReservationRepository(ctrl)
+		timer := NewReservationTimer(logger, metrics.GetMonitor(), NewTimer(logger), reservationRepository)
+		ctx := context.Background()
+
+		reservationRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return(nil, errors.New("error")).Times(1)
+		err := timer.Restore(ctx)
+		assert.Error(t, err)
+	})
+}
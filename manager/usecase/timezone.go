@@ -0,0 +1,55 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"time"
+	"touchgift-job-manager/config"
+
+	"github.com/pkg/errors"
+)
+
+// Timezone 組織ごとの表示タイムゾーン
+// 内部の日時(DBの検索条件・予約等)はUTCで扱い、ログ・配信制御イベントの表示と配信時間帯の判定にのみ使用する
+type Timezone interface {
+	// Location 組織のタイムゾーンを返す (指定がない組織はデフォルトのタイムゾーン)
+	Location(orgCode string) *time.Location
+	// Format 組織のタイムゾーンでRFC3339形式(ナノ秒まで)の文字列にする
+	Format(orgCode string, t time.Time) string
+}
+
+type timezone struct {
+	defaultLocation *time.Location
+	locations       map[string]*time.Location
+}
+
+// NewTimezone is function
+// 存在しないタイムゾーンが設定されている場合はエラーを返す
+func NewTimezone(config *config.Timezone) (Timezone, error) {
+	defaultLocation, err := time.LoadLocation(config.Default)
+	if err != nil {
+		return nil, errors.Wrapf(err, "Invalid default timezone: %s", config.Default)
+	}
+	locations := make(map[string]*time.Location, len(config.ByOrg))
+	for orgCode, name := range config.ByOrg {
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			return nil, errors.Wrapf(err, "Invalid timezone. org_code: %s, timezone: %s", orgCode, name)
+		}
+		locations[orgCode] = location
+	}
+	return &timezone{
+		defaultLocation: defaultLocation,
+		locations:       locations,
+	}, nil
+}
+
+func (z *timezone) Location(orgCode string) *time.Location {
+	if location, ok := z.locations[orgCode]; ok {
+		return location
+	}
+	return z.defaultLocation
+}
+
+func (z *timezone) Format(orgCode string, t time.Time) string {
+	return t.In(z.Location(orgCode)).Format(time.RFC3339Nano)
+}
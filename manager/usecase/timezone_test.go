@@ -0,0 +1,43 @@
+package usecase
+
+import (
+	"testing"
+	"time"
+	"touchgift-job-manager/config"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// テスト用のTimezoneを作成する (デフォルトはJST)
+func NewTestTimezone(t *testing.T) Timezone {
+	timezone, err := NewTimezone(&config.Timezone{Default: "Asia/Tokyo"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return timezone
+}
+
+func TestTimezone(t *testing.T) {
+	t.Run("組織ごとのタイムゾーンで表示し、指定がない組織はデフォルトのタイムゾーンで表示する", func(t *testing.T) {
+		timezone, err := NewTimezone(&config.Timezone{
+			Default: "Asia/Tokyo",
+			ByOrg:   map[string]string{"org_ny": "America/New_York"},
+		})
+		if !assert.NoError(t, err) {
+			return
+		}
+		// JSTの日付が変わった直後
+		current := time.Date(2024, 6, 3, 15, 0, 0, 0, time.UTC)
+		assert.Equal(t, "2024-06-04T00:00:00+09:00", timezone.Format("org", current))
+		assert.Equal(t, "2024-06-03T11:00:00-04:00", timezone.Format("org_ny", current))
+		assert.Equal(t, "America/New_York", timezone.Location("org_ny").String())
+		assert.Equal(t, "Asia/Tokyo", timezone.Location("org").String())
+	})
+
+	t.Run("存在しないタイムゾーンが設定されている場合はエラーを返す", func(t *testing.T) {
+		_, err := NewTimezone(&config.Timezone{Default: "Asia/Tokyo", ByOrg: map[string]string{"org": "Invalid/Zone"}})
+		assert.Error(t, err)
+		_, err = NewTimezone(&config.Timezone{Default: "Invalid/Zone"})
+		assert.Error(t, err)
+	})
+}
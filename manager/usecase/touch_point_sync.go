@@ -0,0 +1,292 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	metricTouchPointSyncTotal       = "touch_point_sync_total"
+	metricTouchPointSyncTotalDesc   = "touch point incremental sync count"
+	metricTouchPointSyncTotalLabels = []string{"action"}
+)
+
+// TouchPointSync 配信中キャンペーンの店舗グループのタッチポイントの変更を配信データに反映する
+type TouchPointSync interface {
+	// Run 前回実行以降に変更された店舗グループの差分を登録/削除する (初回とFullSyncIntervalごとに配信中の全店舗グループ)
+	Run(ctx context.Context) (*models.TouchPointSyncResult, error)
+	// SyncOrganization 組織の配信中の全店舗グループの差分を登録/削除する (失敗したグループがある場合はエラーを返す)
+	SyncOrganization(ctx context.Context, orgCode string) (*models.TouchPointSyncResult, error)
+}
+
+type touchPointSync struct {
+	logger                   Logger
+	monitor                  *metrics.Monitor
+	config                   *config.TouchPointSync
+	transaction              repository.TransactionHandler
+	campaignRepository       repository.CampaignRepository
+	touchPointRepository     repository.TouchPointRepository
+	touchPointDataRepository repository.DeliveryDataTouchPointRepository
+	deliveryControlEvent     DeliveryControlEvent
+	// 前回正常に同期した時刻
+	lastSyncedAt time.Time
+	// 前回正常に全件を同期した時刻 (ゼロ値の場合は全件を対象にする)
+	lastFullSyncedAt time.Time
+	mutex            sync.Mutex
+}
+
+// NewTouchPointSync is function
+func NewTouchPointSync(
+	logger Logger,
+	monitor *metrics.Monitor,
+	config *config.TouchPointSync,
+	transaction repository.TransactionHandler,
+	campaignRepository repository.CampaignRepository,
+	touchPointRepository repository.TouchPointRepository,
+	touchPointDataRepository repository.DeliveryDataTouchPointRepository,
+	deliveryControlEvent DeliveryControlEvent,
+) TouchPointSync {
+	monitor.Metrics.AddCounter(metricTouchPointSyncTotal, metricTouchPointSyncTotalDesc, metricTouchPointSyncTotalLabels)
+	return &touchPointSync{
+		logger:                   logger,
+		monitor:                  monitor,
+		config:                   config,
+		transaction:              transaction,
+		campaignRepository:       campaignRepository,
+		touchPointRepository:     touchPointRepository,
+		touchPointDataRepository: touchPointDataRepository,
+		deliveryControlEvent:     deliveryControlEvent,
+	}
+}
+
+func (t *touchPointSync) Run(ctx context.Context) (*models.TouchPointSyncResult, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	startedAt := time.Now()
+
+	groups, err := t.getStartedGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	// タッチポイントや店舗の紐付けの物理削除は更新日時が残らず変更を検出できないため、定期的に全件を比較する
+	full := t.lastFullSyncedAt.IsZero() ||
+		(t.config.FullSyncInterval > 0 && startedAt.Sub(t.lastFullSyncedAt) >= t.config.FullSyncInterval)
+	if !full {
+		changed, err := t.touchPointRepository.GetChangedGroupIDs(ctx, t.lastSyncedAt.Add(-t.config.Lookback))
+		if err != nil {
+			return nil, errors.Wrap(err, "Failed to get changed store groups")
+		}
+		targets := make(map[int]*models.Campaign, len(changed))
+		for _, groupID := range changed {
+			if campaign, ok := groups[groupID]; ok {
+				targets[groupID] = campaign
+			}
+		}
+		groups = targets
+	}
+	result, err := t.syncGroups(ctx, groups)
+	if err != nil {
+		// 失敗したグループがある場合は次回も同じ期間を対象にする
+		t.logger.Error().Err(err).Msg("Failed to sync some store groups")
+		return result, nil
+	}
+	t.lastSyncedAt = startedAt
+	if full {
+		t.lastFullSyncedAt = startedAt
+	}
+	return result, nil
+}
+
+func (t *touchPointSync) SyncOrganization(ctx context.Context, orgCode string) (*models.TouchPointSyncResult, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	groups, err := t.getStartedGroups(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for groupID, campaign := range groups {
+		if campaign.OrgCode != orgCode {
+			delete(groups, groupID)
+		}
+	}
+	return t.syncGroups(ctx, groups)
+}
+
+// 配信中キャンペーンの店舗グループ (イベントにはIDが最小のキャンペーンを設定する)
+func (t *touchPointSync) getStartedGroups(ctx context.Context) (map[int]*models.Campaign, error) {
+	campaigns, err := t.campaignRepository.GetCampaignByStatus(ctx, &repository.CampaignByStatusCondition{
+		Status: []string{codes.StatusStarted},
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get started campaigns")
+	}
+	groups := map[int]*models.Campaign{}
+	for _, campaign := range campaigns {
+		if _, ok := groups[campaign.GroupID]; !ok {
+			groups[campaign.GroupID] = campaign
+		}
+	}
+	return groups, nil
+}
+
+// 店舗グループごとに差分を登録/削除する
+// 1グループの失敗で全体を止めず、失敗したグループがある場合は最後にエラーを返す
+func (t *touchPointSync) syncGroups(ctx context.Context, groups map[int]*models.Campaign) (*models.TouchPointSyncResult, error) {
+	result := models.TouchPointSyncResult{}
+	if len(groups) == 0 {
+		return &result, nil
+	}
+
+	// DynamoDBのタッチポイントはグループIDで検索できないため、全件を取得してグループごとに分ける
+	deliveryTouchPoints, err := t.touchPointDataRepository.GetAll(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "Failed to get delivery touch points")
+	}
+	actuals := map[int][]*models.DeliveryTouchPoint{}
+	for _, touchPoint := range deliveryTouchPoints {
+		if _, ok := groups[touchPoint.GroupID]; ok {
+			actuals[touchPoint.GroupID] = append(actuals[touchPoint.GroupID], touchPoint)
+		}
+	}
+
+	groupIDs := make([]int, 0, len(groups))
+	for groupID := range groups {
+		groupIDs = append(groupIDs, groupID)
+	}
+	sort.Ints(groupIDs)
+	var failed []int
+	for _, groupID := range groupIDs {
+		put, deleted, err := t.syncGroup(ctx, groups[groupID], actuals[groupID])
+		if err != nil {
+			t.logger.Error().Err(err).Int("group_id", groupID).Msg("Failed to sync touch points")
+			failed = append(failed, groupID)
+			continue
+		}
+		result.Groups++
+		result.Put += put
+		result.Deleted += deleted
+	}
+	t.logger.Info().Int("groups", result.Groups).Int("put", result.Put).Int("deleted", result.Deleted).Msg("Synced touch points")
+	if len(failed) > 0 {
+		return &result, errors.Errorf("Failed to sync store groups: %v", failed)
+	}
+	return &result, nil
+}
+
+// 店舗グループのタッチポイントをRDBと比較し、差分のみ登録/削除する
+func (t *touchPointSync) syncGroup(ctx context.Context, campaign *models.Campaign, actual []*models.DeliveryTouchPoint) (put int, deleted int, err error) {
+	var tx repository.Transaction
+	committed := false
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = errors.Errorf("panic. reason: %#v", rec)
+		}
+		if !committed && tx != nil {
+			if terr := tx.Rollback(); terr != nil {
+				t.logger.Error().Err(terr).Int("group_id", campaign.GroupID).Msg("Failed to rollback")
+			}
+		}
+	}()
+	touchPoints, err := t.touchPointRepository.GetTouchPointByGroupID(ctx, &repository.TouchPointByGroupIDCondition{
+		GroupID: campaign.GroupID,
+		Limit:   1000000,
+	})
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "Failed to get touch points")
+	}
+	putDatas, deleteDatas := diffTouchPoints(touchPoints, actual)
+	if len(putDatas) == 0 && len(deleteDatas) == 0 {
+		return 0, 0, nil
+	}
+
+	// 配信制御イベントはoutboxに登録するため、トランザクション内で登録する
+	tx, err = t.transaction.Begin(ctx)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "Failed to begin transaction")
+	}
+	// 配信終了処理と並行して処理しないようにキャンペーンをロックして、配信中のままか確認する
+	// 終了処理で削除した後に登録すると配信データが残ってしまう (次回は配信中のキャンペーンを取得し直して同期する)
+	status, err := t.campaignRepository.GetStatusForUpdate(ctx, tx, campaign.ID)
+	if err != nil {
+		return 0, 0, errors.Wrap(err, "Failed to get campaign status")
+	}
+	if status != codes.StatusStarted {
+		return 0, 0, errors.Errorf("Campaign is no longer started. campaign_id: %d, status: %s", campaign.ID, status)
+	}
+	if len(putDatas) > 0 {
+		datas := make([]models.DeliveryTouchPoint, 0, len(putDatas))
+		for _, putData := range putDatas {
+			datas = append(datas, *putData)
+		}
+		if err := t.touchPointDataRepository.PutAll(ctx, &datas); err != nil {
+			return 0, 0, errors.Wrap(err, "Failed to put touch points")
+		}
+		if err := t.deliveryControlEvent.PublishDeliveryEvents(ctx, tx, putDatas, campaign.ID, campaign.OrgCode, "PUT"); err != nil {
+			return 0, 0, err
+		}
+	}
+	if len(deleteDatas) > 0 {
+		datas := make([]models.DeliveryTouchPoint, 0, len(deleteDatas))
+		for _, deleteData := range deleteDatas {
+			datas = append(datas, *deleteData)
+		}
+		if err := t.touchPointDataRepository.DeleteAll(ctx, &datas); err != nil {
+			return 0, 0, errors.Wrap(err, "Failed to delete touch points")
+		}
+		if err := t.deliveryControlEvent.PublishDeliveryEvents(ctx, tx, deleteDatas, campaign.ID, campaign.OrgCode, "DELETE"); err != nil {
+			return 0, 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, 0, errors.Wrap(err, "Failed to commit")
+	}
+	committed = true
+	t.monitor.Metrics.GetCounter(metricTouchPointSyncTotal).WithLabelValues("PUT").Add(float64(len(putDatas)))
+	t.monitor.Metrics.GetCounter(metricTouchPointSyncTotal).WithLabelValues("DELETE").Add(float64(len(deleteDatas)))
+	t.logger.Info().Int("group_id", campaign.GroupID).Int("campaign_id", campaign.ID).
+		Int("put", len(putDatas)).Int("deleted", len(deleteDatas)).Msg("Sync touch points")
+	return len(putDatas), len(deleteDatas), nil
+}
+
+// RDBのタッチポイント(expected)と配信データ(actual)を比較し、登録/更新するものと削除するものを返す
+// タッチポイントの店舗が変わった場合は登録(上書き)する
+func diffTouchPoints(expected []*models.TouchPoint, actual []*models.DeliveryTouchPoint) (putDatas []*models.DeliveryTouchPoint, deleteDatas []*models.DeliveryTouchPoint) {
+	actualByID := make(map[string]*models.DeliveryTouchPoint, len(actual))
+	for _, touchPoint := range actual {
+		actualByID[touchPoint.ID] = touchPoint
+	}
+	// 同じグループに複数のキャンペーンがある場合は同じタッチポイントが重複して取得されるため、IDでまとめる
+	expectedIDs := make(map[string]struct{}, len(expected))
+	for _, touchPoint := range expected {
+		if _, ok := expectedIDs[touchPoint.ID]; ok {
+			continue
+		}
+		expectedIDs[touchPoint.ID] = struct{}{}
+		current, ok := actualByID[touchPoint.ID]
+		if ok && current.StoreID == touchPoint.StoreID {
+			continue
+		}
+		putDatas = append(putDatas, &models.DeliveryTouchPoint{
+			ID:      touchPoint.ID,
+			GroupID: touchPoint.GroupID,
+			StoreID: touchPoint.StoreID,
+		})
+	}
+	for _, touchPoint := range actual {
+		if _, ok := expectedIDs[touchPoint.ID]; !ok {
+			deleteDatas = append(deleteDatas, touchPoint)
+		}
+	}
+	return putDatas, deleteDatas
+}
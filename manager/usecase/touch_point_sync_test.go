@@ -0,0 +1,247 @@
+package usecase
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTouchPointSync_Run(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	ctx := context.Background()
+	syncConfig := &config.TouchPointSync{Lookback: time.Minute, FullSyncInterval: time.Hour}
+
+	campaigns := []*models.Campaign{
+		{ID: 1, GroupID: 10, OrgCode: "org", Status: codes.StatusStarted},
+		{ID: 2, GroupID: 10, OrgCode: "org", Status: codes.StatusStarted},
+		{ID: 3, GroupID: 20, OrgCode: "org", Status: codes.StatusStarted},
+	}
+	statusCondition := &repository.CampaignByStatusCondition{Status: []string{codes.StatusStarted}}
+
+	t.Run("差分のみ登録/削除し、イベントを登録する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		actual := []*models.DeliveryTouchPoint{
+			{ID: "tp1", GroupID: 10, StoreID: "s1"},
+			{ID: "tp2", GroupID: 10, StoreID: "s1"},
+			{ID: "tp3", GroupID: 10, StoreID: "s1"},
+			{ID: "tp9", GroupID: 20, StoreID: "s9"},
+			{ID: "tp8", GroupID: 30, StoreID: "s8"},
+		}
+		// 同じグループに複数のキャンペーンがあるためタッチポイントが重複する
+		expected := []*models.TouchPoint{
+			{ID: "tp1", GroupID: 10, StoreID: "s1"},
+			{ID: "tp1", GroupID: 10, StoreID: "s1"},
+			{ID: "tp2", GroupID: 10, StoreID: "s2"},
+			{ID: "tp4", GroupID: 10, StoreID: "s1"},
+		}
+		putDatas := []*models.DeliveryTouchPoint{{ID: "tp2", GroupID: 10, StoreID: "s2"}, {ID: "tp4", GroupID: 10, StoreID: "s1"}}
+		deleteDatas := []*models.DeliveryTouchPoint{{ID: "tp3", GroupID: 10, StoreID: "s1"}}
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns, nil),
+			touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return(actual, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 10, Limit: 1000000})).Return(expected, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			// 配信中のままか確認する
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1)).Return(codes.StatusStarted, nil),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{*putDatas[0], *putDatas[1]})).Return(nil),
+			deliveryControlEvent.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(putDatas), gomock.Eq(1), gomock.Eq("org"), gomock.Eq("PUT")).Return(nil),
+			touchPointDataRepository.EXPECT().DeleteAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{*deleteDatas[0]})).Return(nil),
+			deliveryControlEvent.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deleteDatas), gomock.Eq(1), gomock.Eq("org"), gomock.Eq("DELETE")).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			// 差分がないグループは何もしない
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 20, Limit: 1000000})).Return(
+				[]*models.TouchPoint{{ID: "tp9", GroupID: 20, StoreID: "s9"}}, nil),
+		)
+
+		// テストを実行する
+		touchPointSync := NewTouchPointSync(logger, metrics.GetMonitor(), syncConfig, transactionHandler,
+			campaignRepository, touchPointRepository, touchPointDataRepository, deliveryControlEvent)
+		result, err := touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.TouchPointSyncResult{Groups: 2, Put: 2, Deleted: 1}, result)
+	})
+
+	t.Run("2回目以降は変更があった配信中の店舗グループのみ同期する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+
+		gomock.InOrder(
+			// 1回目は全ての配信中の店舗グループ
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns, nil),
+			touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{}, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return([]*models.TouchPoint{}, nil).Times(2),
+			// 2回目
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns, nil),
+			touchPointRepository.EXPECT().GetChangedGroupIDs(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, since time.Time) ([]int, error) {
+					// 前回の実行時刻からLookback分さかのぼる
+					assert.WithinDuration(t, time.Now().Add(-time.Minute), since, time.Second)
+					return []int{20, 30}, nil
+				}),
+			touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{}, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 20, Limit: 1000000})).Return([]*models.TouchPoint{}, nil),
+			// 3回目は変更がないためDynamoDBを参照しない
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns, nil),
+			touchPointRepository.EXPECT().GetChangedGroupIDs(gomock.Eq(ctx), gomock.Any()).Return([]int{}, nil),
+		)
+
+		// テストを実行する
+		touchPointSync := NewTouchPointSync(logger, metrics.GetMonitor(), syncConfig, mock_repository.NewMockTransactionHandler(ctrl),
+			campaignRepository, touchPointRepository, touchPointDataRepository, mock_usecase.NewMockDeliveryControlEvent(ctrl))
+		result, err := touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, result.Groups)
+		result, err = touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Groups)
+		result, err = touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Groups)
+	})
+
+	t.Run("全件を同期してからFullSyncIntervalが経過した場合は全件を比較して物理削除を反映する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+		deliveryControlEvent := mock_usecase.NewMockDeliveryControlEvent(ctrl)
+
+		// RDBから物理削除されたタッチポイント (更新日時が残らないため変更として検出されない)
+		deleteDatas := []*models.DeliveryTouchPoint{{ID: "tp1", GroupID: 20, StoreID: "s1"}}
+		gomock.InOrder(
+			// 変更を検出せずに全件を比較する
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns[2:], nil),
+			touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return(deleteDatas, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Eq(&repository.TouchPointByGroupIDCondition{GroupID: 20, Limit: 1000000})).Return([]*models.TouchPoint{}, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(3)).Return(codes.StatusStarted, nil),
+			touchPointDataRepository.EXPECT().DeleteAll(gomock.Eq(ctx), gomock.Eq(&[]models.DeliveryTouchPoint{*deleteDatas[0]})).Return(nil),
+			deliveryControlEvent.EXPECT().PublishDeliveryEvents(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(deleteDatas), gomock.Eq(3), gomock.Eq("org"), gomock.Eq("DELETE")).Return(nil),
+			tx.EXPECT().Commit().Return(nil),
+			// 次回は変更があった店舗グループのみ
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns[2:], nil),
+			touchPointRepository.EXPECT().GetChangedGroupIDs(gomock.Eq(ctx), gomock.Any()).Return([]int{}, nil),
+		)
+
+		// テストを実行する
+		syncer := NewTouchPointSync(logger, metrics.GetMonitor(), syncConfig, transactionHandler,
+			campaignRepository, touchPointRepository, touchPointDataRepository, deliveryControlEvent).(*touchPointSync)
+		// 前回の全件の同期からFullSyncInterval経過している
+		syncer.lastSyncedAt = time.Now().Add(-time.Minute)
+		syncer.lastFullSyncedAt = time.Now().Add(-time.Hour)
+		result, err := syncer.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, &models.TouchPointSyncResult{Groups: 1, Deleted: 1}, result)
+		result, err = syncer.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Groups)
+	})
+
+	t.Run("失敗したグループがある場合は次回も同じ期間を対象にする", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns[:1], nil),
+			touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{}, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return([]*models.TouchPoint{{ID: "tp1", GroupID: 10, StoreID: "s1"}}, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1)).Return(codes.StatusStarted, nil),
+			touchPointDataRepository.EXPECT().PutAll(gomock.Eq(ctx), gomock.Any()).Return(errors.New("error")),
+			tx.EXPECT().Rollback().Return(nil),
+			// 前回が失敗したため全件を対象にする
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns[:1], nil),
+			touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{{ID: "tp1", GroupID: 10, StoreID: "s1"}}, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return([]*models.TouchPoint{{ID: "tp1", GroupID: 10, StoreID: "s1"}}, nil),
+		)
+
+		// テストを実行する
+		touchPointSync := NewTouchPointSync(logger, metrics.GetMonitor(), syncConfig, transactionHandler,
+			campaignRepository, touchPointRepository, touchPointDataRepository, mock_usecase.NewMockDeliveryControlEvent(ctrl))
+		result, err := touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Groups)
+		result, err = touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, result.Groups)
+	})
+
+	t.Run("同期中にキャンペーンが配信中でなくなった場合は登録せず、次回に配信中のキャンペーンを取得し直して同期する", func(t *testing.T) {
+		// mockを使用する準備
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		// 必要なmockを作成
+		transactionHandler := mock_repository.NewMockTransactionHandler(ctrl)
+		tx := mock_repository.NewMockTransaction(ctrl)
+		campaignRepository := mock_repository.NewMockCampaignRepository(ctrl)
+		touchPointRepository := mock_repository.NewMockTouchPointRepository(ctrl)
+		touchPointDataRepository := mock_repository.NewMockDeliveryDataTouchPointRepository(ctrl)
+
+		gomock.InOrder(
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return(campaigns[:1], nil),
+			touchPointDataRepository.EXPECT().GetAll(gomock.Eq(ctx)).Return([]*models.DeliveryTouchPoint{}, nil),
+			touchPointRepository.EXPECT().GetTouchPointByGroupID(gomock.Eq(ctx), gomock.Any()).Return([]*models.TouchPoint{{ID: "tp1", GroupID: 10, StoreID: "s1"}}, nil),
+			transactionHandler.EXPECT().Begin(gomock.Eq(ctx)).Return(tx, nil),
+			// 配信終了処理で配信データが削除された後のため登録しない
+			campaignRepository.EXPECT().GetStatusForUpdate(gomock.Eq(ctx), gomock.Eq(tx), gomock.Eq(1)).Return(codes.StatusEnded, nil),
+			tx.EXPECT().Rollback().Return(nil),
+			// 配信中のキャンペーンがなくなったため何もしない
+			campaignRepository.EXPECT().GetCampaignByStatus(gomock.Eq(ctx), gomock.Eq(statusCondition)).Return([]*models.Campaign{}, nil),
+		)
+
+		// テストを実行する
+		touchPointSync := NewTouchPointSync(logger, metrics.GetMonitor(), syncConfig, transactionHandler,
+			campaignRepository, touchPointRepository, touchPointDataRepository, mock_usecase.NewMockDeliveryControlEvent(ctrl))
+		result, err := touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Groups)
+		result, err = touchPointSync.Run(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 0, result.Groups)
+	})
+}
@@ -0,0 +1,122 @@
+//go:generate mockgen -source=$GOFILE -package=mock_$GOPACKAGE -destination=../mock/$GOPACKAGE/$GOFILE
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	"github.com/pkg/errors"
+)
+
+var (
+	metricUploadHistoryProcessTotal       = "upload_history_process_total"
+	metricUploadHistoryProcessTotalDesc   = "store/touch point upload history process count"
+	metricUploadHistoryProcessTotalLabels = []string{"kind", "result"}
+
+	uploadKinds = []string{codes.UploadKindStore, codes.UploadKindTouchPoint}
+)
+
+// UploadHistory 店舗/タッチポイントのCSVアップロードを配信中の店舗グループの配信データに反映する
+type UploadHistory interface {
+	// ProcessPending 未反映のアップロード履歴を反映し、反映した件数を返す
+	ProcessPending(ctx context.Context) (int, error)
+}
+
+type uploadHistory struct {
+	logger                  Logger
+	monitor                 *metrics.Monitor
+	config                  *config.UploadHistory
+	uploadHistoryRepository repository.UploadHistoryRepository
+	touchPointSync          TouchPointSync
+}
+
+// NewUploadHistory is function
+func NewUploadHistory(
+	logger Logger,
+	monitor *metrics.Monitor,
+	config *config.UploadHistory,
+	uploadHistoryRepository repository.UploadHistoryRepository,
+	touchPointSync TouchPointSync,
+) UploadHistory {
+	monitor.Metrics.AddCounter(metricUploadHistoryProcessTotal, metricUploadHistoryProcessTotalDesc, metricUploadHistoryProcessTotalLabels)
+	return &uploadHistory{
+		logger:                  logger,
+		monitor:                 monitor,
+		config:                  config,
+		uploadHistoryRepository: uploadHistoryRepository,
+		touchPointSync:          touchPointSync,
+	}
+}
+
+func (u *uploadHistory) ProcessPending(ctx context.Context) (int, error) {
+	processed := 0
+	for _, kind := range uploadKinds {
+		histories, err := u.uploadHistoryRepository.GetUnprocessed(ctx, &repository.UnprocessedUploadCondition{
+			Kind:        kind,
+			Since:       time.Now().UTC().Add(-u.config.Lookback),
+			MaxAttempts: u.config.MaxAttempts,
+			Limit:       u.config.BatchSize,
+		})
+		if err != nil {
+			return processed, errors.Wrapf(err, "Failed to get unprocessed upload histories. kind: %s", kind)
+		}
+		for _, history := range histories {
+			if ctx.Err() != nil {
+				return processed, ctx.Err()
+			}
+			if err := u.process(ctx, history); err != nil {
+				// 1件の失敗で全体を止めない (反映状況に記録し、次回以降に再試行する)
+				u.logger.Error().Err(err).Str("kind", history.Kind).Int("history_id", history.ID).
+					Str("xid", history.Xid).Msg("Failed to process upload history")
+				continue
+			}
+			processed++
+		}
+	}
+	return processed, nil
+}
+
+// アップロードした組織の配信中の店舗グループのタッチポイントを反映し、反映状況を記録する
+func (u *uploadHistory) process(ctx context.Context, history *models.UploadHistory) error {
+	process := &models.UploadProcess{
+		Kind:      history.Kind,
+		HistoryID: history.ID,
+		Xid:       history.Xid,
+		OrgCode:   history.OrgCode,
+		Status:    codes.UploadStatusInProgress,
+		Attempts:  history.Attempts + 1,
+	}
+	if err := u.uploadHistoryRepository.SaveProcess(ctx, process); err != nil {
+		return errors.Wrap(err, "Failed to save upload process")
+	}
+
+	result, syncErr := u.touchPointSync.SyncOrganization(ctx, history.OrgCode)
+	if result != nil {
+		process.StoreGroups = result.Groups
+		process.PutCount = result.Put
+		process.DeleteCount = result.Deleted
+	}
+	process.Status = codes.UploadStatusDone
+	process.LastError = sql.NullString{}
+	if syncErr != nil {
+		process.Status = codes.UploadStatusFailed
+		process.LastError = sql.NullString{String: syncErr.Error(), Valid: true}
+	}
+	u.monitor.Metrics.GetCounter(metricUploadHistoryProcessTotal).WithLabelValues(history.Kind, process.Status).Inc()
+	if err := u.uploadHistoryRepository.SaveProcess(ctx, process); err != nil {
+		return errors.Wrap(err, "Failed to save upload process")
+	}
+	if syncErr != nil {
+		return syncErr
+	}
+	u.logger.Info().Str("kind", history.Kind).Int("history_id", history.ID).Str("xid", history.Xid).
+		Str("org_code", history.OrgCode).Int("store_groups", process.StoreGroups).
+		Int("put", process.PutCount).Int("deleted", process.DeleteCount).Msg("Processed upload history")
+	return nil
+}
@@ -0,0 +1,120 @@
+package usecase
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+	"touchgift-job-manager/codes"
+	"touchgift-job-manager/config"
+	"touchgift-job-manager/domain/models"
+	"touchgift-job-manager/domain/repository"
+	"touchgift-job-manager/infra/metrics"
+
+	mock_repository "touchgift-job-manager/mock/repository"
+	mock_usecase "touchgift-job-manager/mock/usecase"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadHistory_ProcessPending(t *testing.T) {
+	// テスト用のLoggerを作成
+	logger := NewTestLogger(t)
+	ctx := context.Background()
+	uploadConfig := &config.UploadHistory{Lookback: time.Hour, BatchSize: 10, MaxAttempts: 5}
+
+	// 反映状況の登録内容を記録する
+	saved := func(processes *[]models.UploadProcess) func(context.Context, *models.UploadProcess) error {
+		return func(ctx context.Context, process *models.UploadProcess) error {
+			*processes = append(*processes, *process)
+			return nil
+		}
+	}
+
+	t.Run("アップロードした組織の店舗グループに反映し、反映状況を記録する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		uploadHistoryRepository := mock_repository.NewMockUploadHistoryRepository(ctrl)
+		touchPointSync := mock_usecase.NewMockTouchPointSync(ctrl)
+		var processes []models.UploadProcess
+		gomock.InOrder(
+			uploadHistoryRepository.EXPECT().GetUnprocessed(gomock.Eq(ctx), gomock.Any()).DoAndReturn(
+				func(ctx context.Context, args *repository.UnprocessedUploadCondition) ([]*models.UploadHistory, error) {
+					assert.Equal(t, codes.UploadKindStore, args.Kind)
+					assert.Equal(t, 5, args.MaxAttempts)
+					assert.Equal(t, 10, args.Limit)
+					assert.WithinDuration(t, time.Now().Add(-time.Hour), args.Since, time.Second)
+					return []*models.UploadHistory{}, nil
+				}),
+			uploadHistoryRepository.EXPECT().GetUnprocessed(gomock.Eq(ctx), gomock.Any()).Return(
+				[]*models.UploadHistory{{Kind: codes.UploadKindTouchPoint, ID: 1, Xid: "xid1", OrgCode: "org"}}, nil),
+			uploadHistoryRepository.EXPECT().SaveProcess(gomock.Eq(ctx), gomock.Any()).DoAndReturn(saved(&processes)),
+			touchPointSync.EXPECT().SyncOrganization(gomock.Eq(ctx), gomock.Eq("org")).Return(
+				&models.TouchPointSyncResult{Groups: 2, Put: 3, Deleted: 1}, nil),
+			uploadHistoryRepository.EXPECT().SaveProcess(gomock.Eq(ctx), gomock.Any()).DoAndReturn(saved(&processes)),
+		)
+
+		uploadHistory := NewUploadHistory(logger, metrics.GetMonitor(), uploadConfig, uploadHistoryRepository, touchPointSync)
+		processed, err := uploadHistory.ProcessPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		if assert.Len(t, processes, 2) {
+			assert.Equal(t, models.UploadProcess{Kind: codes.UploadKindTouchPoint, HistoryID: 1, Xid: "xid1", OrgCode: "org",
+				Status: codes.UploadStatusInProgress, Attempts: 1}, processes[0])
+			assert.Equal(t, models.UploadProcess{Kind: codes.UploadKindTouchPoint, HistoryID: 1, Xid: "xid1", OrgCode: "org",
+				Status: codes.UploadStatusDone, StoreGroups: 2, PutCount: 3, DeleteCount: 1, Attempts: 1}, processes[1])
+		}
+	})
+
+	t.Run("反映に失敗した場合は失敗を記録して次の履歴を処理する", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		uploadHistoryRepository := mock_repository.NewMockUploadHistoryRepository(ctrl)
+		touchPointSync := mock_usecase.NewMockTouchPointSync(ctrl)
+		var processes []models.UploadProcess
+		gomock.InOrder(
+			uploadHistoryRepository.EXPECT().GetUnprocessed(gomock.Eq(ctx), gomock.Any()).Return(
+				[]*models.UploadHistory{
+					{Kind: codes.UploadKindStore, ID: 1, Xid: "xid1", OrgCode: "org1", Attempts: 2},
+					{Kind: codes.UploadKindStore, ID: 2, Xid: "xid2", OrgCode: "org2"},
+				}, nil),
+			uploadHistoryRepository.EXPECT().SaveProcess(gomock.Eq(ctx), gomock.Any()).DoAndReturn(saved(&processes)),
+			touchPointSync.EXPECT().SyncOrganization(gomock.Eq(ctx), gomock.Eq("org1")).Return(
+				&models.TouchPointSyncResult{Groups: 1}, errors.New("error")),
+			uploadHistoryRepository.EXPECT().SaveProcess(gomock.Eq(ctx), gomock.Any()).DoAndReturn(saved(&processes)),
+			uploadHistoryRepository.EXPECT().SaveProcess(gomock.Eq(ctx), gomock.Any()).DoAndReturn(saved(&processes)),
+			touchPointSync.EXPECT().SyncOrganization(gomock.Eq(ctx), gomock.Eq("org2")).Return(&models.TouchPointSyncResult{}, nil),
+			uploadHistoryRepository.EXPECT().SaveProcess(gomock.Eq(ctx), gomock.Any()).DoAndReturn(saved(&processes)),
+			uploadHistoryRepository.EXPECT().GetUnprocessed(gomock.Eq(ctx), gomock.Any()).Return(
+				[]*models.UploadHistory{}, nil),
+		)
+
+		uploadHistory := NewUploadHistory(logger, metrics.GetMonitor(), uploadConfig, uploadHistoryRepository, touchPointSync)
+		processed, err := uploadHistory.ProcessPending(ctx)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, processed)
+		if assert.Len(t, processes, 4) {
+			assert.Equal(t, models.UploadProcess{Kind: codes.UploadKindStore, HistoryID: 1, Xid: "xid1", OrgCode: "org1",
+				Status: codes.UploadStatusFailed, StoreGroups: 1, Attempts: 3,
+				LastError: sql.NullString{String: "error", Valid: true}}, processes[1])
+			assert.Equal(t, codes.UploadStatusDone, processes[3].Status)
+		}
+	})
+
+	t.Run("履歴の取得に失敗した場合はエラーを返す", func(t *testing.T) {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+
+		uploadHistoryRepository := mock_repository.NewMockUploadHistoryRepository(ctrl)
+		touchPointSync := mock_usecase.NewMockTouchPointSync(ctrl)
+		uploadHistoryRepository.EXPECT().GetUnprocessed(gomock.Eq(ctx), gomock.Any()).Return(nil, errors.New("error"))
+
+		uploadHistory := NewUploadHistory(logger, metrics.GetMonitor(), uploadConfig, uploadHistoryRepository, touchPointSync)
+		_, err := uploadHistory.ProcessPending(ctx)
+		assert.Error(t, err)
+	})
+}